@@ -162,6 +162,9 @@ const (
 	tunnelFlagLocalPort       = "local-port"
 	tunnelFlagDestinationPort = "destination-port"
 
+	ftdcFlagAddress = "address"
+	ftdcFlagMetrics = "metrics"
+
 	organizationFlagSupportEmail = "support-email"
 	organizationBillingAddress   = "address"
 	organizationFlagLogoPath     = "logo-path"
@@ -3943,6 +3946,37 @@ NOTES:
 			},
 			Action: createCommandWithT[ftdcArgs](FTDCParseAction),
 		},
+		{
+			Name:  "tail-ftdc",
+			Usage: "stream live ftdc data from a machine and print selected metrics",
+			Description: `
+In order to use the tail-ftdc command, viam-server must be started with the --ftdc-stream flag.
+The address is the machine's local http address, e.g: localhost:8080. If the machine requires
+authentication, pass a machine api key with --key-id and --key.`,
+			UsageText: createUsageText(
+				"tail-ftdc", []string{ftdcFlagAddress}, true, false,
+			),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     ftdcFlagAddress,
+					Required: true,
+					Usage:    "address of the machine's web server",
+				},
+				&cli.StringSliceFlag{
+					Name:  ftdcFlagMetrics,
+					Usage: "metric name globs to print, e.g: 'proc.*,net.*'. Defaults to all metrics",
+				},
+				&cli.StringFlag{
+					Name:  loginFlagKeyID,
+					Usage: "id of the machine api key to authenticate with",
+				},
+				&cli.StringFlag{
+					Name:  loginFlagKey,
+					Usage: "machine api key to authenticate with",
+				},
+			},
+			Action: createCommandWithT[ftdcTailArgs](FTDCTailAction),
+		},
 	},
 }
 
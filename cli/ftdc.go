@@ -1,9 +1,20 @@
 package cli
 
 import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
 	"github.com/urfave/cli/v2"
+	"go.viam.com/utils"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
+	"go.viam.com/utils/rpc"
 
+	"go.viam.com/rdk/ftdc"
 	"go.viam.com/rdk/ftdc/parser"
+	"go.viam.com/rdk/logging"
 )
 
 type ftdcArgs struct {
@@ -15,3 +26,88 @@ func FTDCParseAction(c *cli.Context, args ftdcArgs) error {
 	parser.LaunchREPL(args.Path)
 	return nil
 }
+
+type ftdcTailArgs struct {
+	Address string
+	Metrics []string
+	KeyID   string
+	Key     string
+}
+
+// ftdcStreamAccessToken exchanges an api key for an access token accepted by the machine at
+// `address`.
+func ftdcStreamAccessToken(c *cli.Context, address, keyID, key string) (string, error) {
+	conn, err := rpc.DialDirectGRPC(c.Context, address, logging.NewLogger("ftdc"), rpc.WithInsecure())
+	if err != nil {
+		return "", err
+	}
+	defer utils.UncheckedErrorFunc(conn.Close)
+
+	resp, err := rpcpb.NewAuthServiceClient(conn).Authenticate(c.Context, &rpcpb.AuthenticateRequest{
+		Entity:      keyID,
+		Credentials: &rpcpb.Credentials{Type: string(rpc.CredentialsTypeAPIKey), Payload: key},
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.GetAccessToken(), nil
+}
+
+// FTDCTailAction is the cli action to stream live ftdc data from a machine and print the selected
+// metrics as they're recorded.
+func FTDCTailAction(c *cli.Context, args ftdcTailArgs) error {
+	metricRe, err := parser.MetricGlobsToRegexp(args.Metrics)
+	if err != nil {
+		return err
+	}
+
+	streamURL := args.Address
+	if !strings.Contains(streamURL, "://") {
+		streamURL = "http://" + streamURL
+	}
+	streamURL = strings.TrimSuffix(streamURL, "/") + "/debug/ftdc/stream"
+
+	req, err := http.NewRequestWithContext(c.Context, http.MethodGet, streamURL, nil)
+	if err != nil {
+		return err
+	}
+	if args.KeyID != "" || args.Key != "" {
+		accessToken, err := ftdcStreamAccessToken(c, req.URL.Host, args.KeyID, args.Key)
+		if err != nil {
+			return fmt.Errorf("unable to authenticate with api key %q: %w", args.KeyID, err)
+		}
+		req.Header.Set("Authorization", rpc.AuthorizationValuePrefixBearer+accessToken)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(resp.Body.Close)
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("unable to stream ftdc data from %s. Ensure viam-server was started with --ftdc-stream. "+
+			"Status: %s %s", streamURL, resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	logger := logging.NewLogger("ftdc")
+	logger.SetLevel(logging.ERROR)
+	return ftdc.ParseStream(resp.Body, logger, func(flatDatum ftdc.FlatDatum) error {
+		timestamp := flatDatum.ConvertedTime().Format(time.RFC3339Nano)
+		for _, reading := range flatDatum.Readings {
+			if metricRe != nil && !metricRe.MatchString(reading.MetricName) {
+				continue
+			}
+
+			var value any = reading.RawValue
+			if value == nil {
+				value = reading.Value
+			}
+			printf(c.App.Writer, "%s %s=%v", timestamp, reading.MetricName, value)
+		}
+		return nil
+	})
+}
@@ -27,8 +27,10 @@ import (
 type operation struct {
 	// targetInputs is in radians.
 	targetInputs []float64
-	done         bool
-	stopped      bool
+	// speed overrides the speed of the arm for this operation when positive.
+	speed   float64
+	done    bool
+	stopped bool
 }
 
 func (op operation) isMoving() bool {
@@ -204,6 +206,11 @@ func (sa *simulatedArm) updateForTime(now time.Time) {
 		return
 	}
 
+	speed := sa.speed
+	if sa.operation.speed > 0 {
+		speed = sa.operation.speed
+	}
+
 	// Find the speed each joint needs to move to finish at the same time.
 	modifiedSpeeds := make([]float64, len(sa.currInputs))
 	for jointIdx, currJointInp := range sa.currInputs {
@@ -217,7 +224,7 @@ func (sa *simulatedArm) updateForTime(now time.Time) {
 
 		// I.e: if we only need to move 1/4 the distance as the `maxDist`, we will travel at 1/4 *
 		// `sa.speed`.
-		modifiedSpeeds[jointIdx] = speedAdjustment * sa.speed
+		modifiedSpeeds[jointIdx] = speedAdjustment * speed
 	}
 
 	for jointIdx, currJointInp := range sa.currInputs {
@@ -290,6 +297,11 @@ func (sa *simulatedArm) JointPositions(
 func (sa *simulatedArm) MoveToJointPositions(
 	ctx context.Context, target []referenceframe.Input, extra map[string]interface{},
 ) error {
+	return sa.moveToJointPositions(ctx, target, 0)
+}
+
+// moveToJointPositions moves to target at the given speed, or the speed of the arm if it is zero.
+func (sa *simulatedArm) moveToJointPositions(ctx context.Context, target []referenceframe.Input, speed float64) error {
 	if err := arm.CheckDesiredJointPositions(ctx, sa, target); err != nil {
 		return err
 	}
@@ -297,6 +309,7 @@ func (sa *simulatedArm) MoveToJointPositions(
 	sa.mu.Lock()
 	sa.operation = operation{
 		targetInputs: target,
+		speed:        speed,
 		done:         false,
 		stopped:      false,
 	}
@@ -342,10 +355,31 @@ func (sa *simulatedArm) MoveThroughJointPositions(
 	ctx context.Context,
 	positions [][]referenceframe.Input,
 	_ *arm.MoveOptions,
-	_ map[string]interface{},
+	extra map[string]interface{},
 ) error {
-	for _, goal := range positions {
-		if err := sa.MoveToJointPositions(ctx, goal, nil); err != nil {
+	timing, err := arm.TrajectoryTimingFromExtra(extra, len(positions))
+	if err != nil {
+		return err
+	}
+	var lastTime time.Duration
+	for idx, goal := range positions {
+		// With a timed trajectory, each goal is reached when the trajectory says so by moving at the speed
+		// which covers the distance to it in time.
+		var speed float64
+		if timing != nil {
+			sa.mu.Lock()
+			var maxDist float64
+			for jointIdx, currJointInp := range sa.currInputs {
+				if jointIdx < len(goal) {
+					maxDist = math.Max(maxDist, math.Abs(goal[jointIdx]-currJointInp))
+				}
+			}
+			sa.mu.Unlock()
+			segment := max(timing.Times[idx]-lastTime, time.Millisecond)
+			lastTime = timing.Times[idx]
+			speed = maxDist / segment.Seconds()
+		}
+		if err := sa.moveToJointPositions(ctx, goal, speed); err != nil {
 			return err
 		}
 	}
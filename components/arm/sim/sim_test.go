@@ -181,3 +181,72 @@ func TestTimeSimulation(t *testing.T) {
 	err = simArm.MoveToJointPositions(ctx, []float64{1, -2, 0, 0, 0, 0}, nil)
 	test.That(t, err, test.ShouldBeNil)
 }
+
+func TestTrajectoryTiming(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	resConf := resource.Config{
+		Name:  "arm",
+		API:   arm.API,
+		Model: Model,
+		ConvertedAttributes: &Config{
+			Model: "lite6",
+			Speed: 1.0, // radians per second
+		},
+	}
+
+	simArmI, err := NewArm(ctx, nil, resConf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	simArm := simArmI.(*simulatedArm)
+
+	// The first position is reached after 4 seconds, half the speed of the arm, and the second one
+	// a second later, twice the speed of the arm.
+	timing := &arm.TrajectoryTiming{
+		Times:      []time.Duration{4 * time.Second, 5 * time.Second},
+		Velocities: [][]float64{{0.5, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 0, 0}},
+	}
+	moveFuture := make(chan struct{})
+	go func() {
+		err := simArm.MoveThroughJointPositions(ctx, [][]float64{{2, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 0, 0}},
+			nil, timing.ToExtra(map[string]interface{}{"other": true}))
+		test.That(t, err, test.ShouldBeNil)
+		close(moveFuture)
+	}()
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		isMoving, err := simArm.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, isMoving, test.ShouldBeTrue)
+	})
+	clock := simArm.lastUpdated.Add(2 * time.Second)
+	simArm.updateForTime(clock)
+	currInputs, err := simArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, currInputs, test.ShouldResemble, []float64{1, 0, 0, 0, 0, 0})
+
+	clock = clock.Add(2 * time.Second)
+	simArm.updateForTime(clock)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		simArm.mu.Lock()
+		defer simArm.mu.Unlock()
+		test.That(tb, simArm.operation.isMoving(), test.ShouldBeTrue)
+		test.That(tb, simArm.operation.targetInputs, test.ShouldResemble, []float64{0, 0, 0, 0, 0, 0})
+	})
+	simArm.updateForTime(clock.Add(500 * time.Millisecond))
+	currInputs, err = simArm.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, currInputs, test.ShouldResemble, []float64{1, 0, 0, 0, 0, 0})
+
+	simArm.updateForTime(clock.Add(time.Second))
+	select {
+	case <-moveFuture:
+	case <-time.After(time.Second):
+		t.Fatal("Background goroutine calling `MoveThroughJointPositions` has not returned.")
+	}
+
+	// timing which doesn't match the positions is rejected
+	err = simArm.MoveThroughJointPositions(ctx, [][]float64{{0, 0, 0, 0, 0, 0}}, nil, timing.ToExtra(nil))
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,102 @@
+package arm
+
+import (
+	"fmt"
+	"time"
+)
+
+// TrajectoryTimingExtraKey is the key in the extra of MoveThroughJointPositions under which the timing of the
+// positions is given to arms which can follow a timed trajectory. Arms which can't ignore it.
+const TrajectoryTimingExtraKey = "trajectory_timing"
+
+// TrajectoryTiming is when an arm should reach each of the positions given to MoveThroughJointPositions,
+// measured from the start of the motion, and the velocity of each joint there, in radians or mm per second.
+type TrajectoryTiming struct {
+	Times      []time.Duration
+	Velocities [][]float64
+}
+
+// ToExtra returns a copy of extra with the timing added, in a form which survives being sent over gRPC.
+func (timing *TrajectoryTiming) ToExtra(extra map[string]interface{}) map[string]interface{} {
+	withTiming := make(map[string]interface{}, len(extra)+1)
+	for k, v := range extra {
+		withTiming[k] = v
+	}
+	times := make([]interface{}, 0, len(timing.Times))
+	for _, t := range timing.Times {
+		times = append(times, t.Seconds())
+	}
+	velocities := make([]interface{}, 0, len(timing.Velocities))
+	for _, step := range timing.Velocities {
+		stepVelocities := make([]interface{}, 0, len(step))
+		for _, v := range step {
+			stepVelocities = append(stepVelocities, v)
+		}
+		velocities = append(velocities, stepVelocities)
+	}
+	withTiming[TrajectoryTimingExtraKey] = map[string]interface{}{
+		"times_sec":  times,
+		"velocities": velocities,
+	}
+	return withTiming
+}
+
+// TrajectoryTimingFromExtra returns the timing of the given number of positions in the extra of
+// MoveThroughJointPositions, or nil if there is none.
+func TrajectoryTimingFromExtra(extra map[string]interface{}, positions int) (*TrajectoryTiming, error) {
+	raw, ok := extra[TrajectoryTimingExtraKey]
+	if !ok {
+		return nil, nil
+	}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected %s to be an object but got %T", TrajectoryTimingExtraKey, raw)
+	}
+	times, err := floatsFromExtra(fields["times_sec"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s times: %w", TrajectoryTimingExtraKey, err)
+	}
+	rawVelocities, ok := fields["velocities"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected %s velocities to be a list but got %T", TrajectoryTimingExtraKey, fields["velocities"])
+	}
+	if len(times) != positions || len(rawVelocities) != positions {
+		return nil, fmt.Errorf("%s has %d times and %d velocities for %d positions",
+			TrajectoryTimingExtraKey, len(times), len(rawVelocities), positions)
+	}
+
+	timing := &TrajectoryTiming{
+		Times:      make([]time.Duration, 0, positions),
+		Velocities: make([][]float64, 0, positions),
+	}
+	for i, t := range times {
+		if i > 0 && t < times[i-1] {
+			return nil, fmt.Errorf("%s times must not decrease", TrajectoryTimingExtraKey)
+		}
+		timing.Times = append(timing.Times, time.Duration(t*float64(time.Second)))
+	}
+	for _, raw := range rawVelocities {
+		velocities, err := floatsFromExtra(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s velocities: %w", TrajectoryTimingExtraKey, err)
+		}
+		timing.Velocities = append(timing.Velocities, velocities)
+	}
+	return timing, nil
+}
+
+func floatsFromExtra(raw interface{}) ([]float64, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list but got %T", raw)
+	}
+	floats := make([]float64, 0, len(list))
+	for _, v := range list {
+		f, ok := v.(float64)
+		if !ok {
+			return nil, fmt.Errorf("expected a number but got %T", v)
+		}
+		floats = append(floats, f)
+	}
+	return floats, nil
+}
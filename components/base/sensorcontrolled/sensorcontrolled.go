@@ -28,6 +28,7 @@ const (
 	typeAngVel         = "angular_velocity"
 	defaultControlFreq = 10 // Hz
 	getPID             = "get_tuned_pid"
+	getTuningResults   = "get_tuning_results"
 )
 
 var (
@@ -42,6 +43,12 @@ type Config struct {
 	Base              string              `json:"base"`
 	ControlParameters []control.PIDConfig `json:"control_parameters,omitempty"`
 	ControlFreq       float64             `json:"control_frequency_hz,omitempty"`
+
+	// optional conditioning of the linear and angular PID outputs, see control.Options
+	AntiWindup      string                     `json:"anti_windup,omitempty"`
+	OutputDeadband  float64                    `json:"output_deadband,omitempty"`
+	OutputRateLimit float64                    `json:"output_rate_limit,omitempty"`
+	FeedForward     *control.FeedForwardConfig `json:"feed_forward,omitempty"`
 }
 
 // Validate validates all parts of the sensor controlled base config.
@@ -62,6 +69,12 @@ func (cfg *Config) Validate(path string) ([]string, []string, error) {
 			return nil, nil, resource.NewConfigValidationError(path,
 				errors.New("control_parameters type must be 'linear_velocity' or 'angular_velocity'"))
 		}
+		if err := control.ValidateTuneMethod(pidConf.TuneMethod); err != nil {
+			return nil, nil, resource.NewConfigValidationError(path, err)
+		}
+	}
+	if err := control.ValidateOutputConditioning(cfg.AntiWindup, cfg.OutputDeadband, cfg.OutputRateLimit); err != nil {
+		return nil, nil, resource.NewConfigValidationError(path, err)
 	}
 
 	return deps, nil, nil
@@ -89,6 +102,7 @@ type sensorBase struct {
 	loop              *control.Loop
 	configPIDVals     []control.PIDConfig
 	tunedVals         *[]control.PIDConfig
+	tuningResults     *[]control.TuningResult
 	controlFreq       float64
 }
 
@@ -108,6 +122,7 @@ func createSensorBase(
 	sb := &sensorBase{
 		logger:        logger,
 		tunedVals:     &[]control.PIDConfig{{}, {}},
+		tuningResults: &[]control.TuningResult{},
 		configPIDVals: []control.PIDConfig{{}, {}},
 		Named:         conf.ResourceName().AsNamed(),
 		opMgr:         operation.NewSingleOperationManager(),
@@ -275,8 +290,7 @@ func (sb *sensorBase) DoCommand(ctx context.Context, req map[string]interface{})
 
 	sb.mu.Lock()
 	defer sb.mu.Unlock()
-	ok := req[getPID].(bool)
-	if ok {
+	if ok, _ := req[getPID].(bool); ok {
 		var respStr string
 		for _, pidConf := range *sb.tunedVals {
 			if !pidConf.NeedsAutoTuning() {
@@ -285,6 +299,9 @@ func (sb *sensorBase) DoCommand(ctx context.Context, req map[string]interface{})
 		}
 		resp[getPID] = respStr
 	}
+	if ok, _ := req[getTuningResults].(bool); ok {
+		resp[getTuningResults] = control.TuningResultsToMaps(*sb.tuningResults)
+	}
 
 	return resp, nil
 }
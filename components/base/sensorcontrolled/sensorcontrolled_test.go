@@ -353,6 +353,7 @@ func TestSensorBaseWithVelocitiesSensor(t *testing.T) {
 	deps, _ := msDependencies(t, []string{"setvel1"})
 	// generate a config with a non default freq
 	cfg := sBaseTestConfig([]string{"setvel1"}, 100, typeLinVel, typeAngVel)
+	cfg.ConvertedAttributes.(*Config).OutputDeadband = 1
 
 	b, err := createSensorBase(ctx, deps, cfg, logger)
 	test.That(t, err, test.ShouldBeNil)
@@ -365,6 +366,7 @@ func TestSensorBaseWithVelocitiesSensor(t *testing.T) {
 	loopFreq, err := sb.loop.Frequency(ctx)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, loopFreq, test.ShouldEqual, 100)
+	test.That(t, sb.blockNames["deadband"], test.ShouldHaveLength, 2)
 	test.That(t, sb.Close(ctx), test.ShouldBeNil)
 }
 
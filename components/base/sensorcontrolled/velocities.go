@@ -66,6 +66,10 @@ func (sb *sensorBase) setupControlLoop(linear, angular control.PIDConfig) error
 		SensorFeedback2DVelocityControl: true,
 		LoopFrequency:                   sb.controlFreq,
 		ControllableType:                "base_name",
+		AntiWindup:                      sb.conf.AntiWindup,
+		OutputDeadband:                  sb.conf.OutputDeadband,
+		OutputRateLimit:                 sb.conf.OutputRateLimit,
+		FeedForward:                     sb.conf.FeedForward,
 	}
 
 	// check if either linear or angular need to be tuned
@@ -86,6 +90,7 @@ func (sb *sensorBase) setupControlLoop(linear, angular control.PIDConfig) error
 	sb.loop = pl.ControlLoop
 	sb.blockNames = pl.BlockNames
 	sb.tunedVals = pl.TunedVals
+	sb.tuningResults = pl.TuningResults
 
 	return nil
 }
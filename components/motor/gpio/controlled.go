@@ -18,7 +18,10 @@ import (
 	rdkutils "go.viam.com/rdk/utils"
 )
 
-const getPID = "get_tuned_pid"
+const (
+	getPID           = "get_tuned_pid"
+	getTuningResults = "get_tuning_results"
+)
 
 // SetState sets the state of the motor for the built-in control loop.
 func (cm *controlledMotor) SetState(ctx context.Context, state []*control.Signal) error {
@@ -57,14 +60,19 @@ func (cm *controlledMotor) setupControlLoop(conf *Config) error {
 	options := control.Options{
 		PositionControlUsingTrapz: true,
 		LoopFrequency:             100.0,
+		AntiWindup:                conf.ControlParameters.AntiWindup,
+		OutputDeadband:            conf.ControlParameters.OutputDeadband,
+		OutputRateLimit:           conf.ControlParameters.OutputRateLimit,
+		FeedForward:               conf.ControlParameters.FeedForward,
 	}
 
 	// convert the motor config ControlParameters to the control.PIDConfig structure for use in setup_control.go
 	cm.configPIDVals = []control.PIDConfig{{
-		Type: "",
-		P:    conf.ControlParameters.P,
-		I:    conf.ControlParameters.I,
-		D:    conf.ControlParameters.D,
+		Type:       "",
+		P:          conf.ControlParameters.P,
+		I:          conf.ControlParameters.I,
+		D:          conf.ControlParameters.D,
+		TuneMethod: conf.ControlParameters.TuneMethod,
 	}}
 
 	// auto tune motor if all ControlParameters are 0
@@ -82,6 +90,7 @@ func (cm *controlledMotor) setupControlLoop(conf *Config) error {
 	cm.loop = pl.ControlLoop
 	cm.blockNames = pl.BlockNames
 	cm.tunedVals = pl.TunedVals
+	cm.tuningResults = pl.TuningResults
 
 	return nil
 }
@@ -126,6 +135,7 @@ func setupMotorWithControls(
 		logger:           logger,
 		opMgr:            operation.NewSingleOperationManager(),
 		tunedVals:        &[]control.PIDConfig{{}},
+		tuningResults:    &[]control.TuningResult{},
 		ticksPerRotation: tpr,
 		maxRPM:           maxRPM,
 		real:             m,
@@ -163,6 +173,7 @@ type controlledMotor struct {
 	loop              *control.Loop
 	configPIDVals     []control.PIDConfig
 	tunedVals         *[]control.PIDConfig
+	tuningResults     *[]control.TuningResult
 }
 
 // SetPower sets the percentage of power the motor should employ between -1 and 1.
@@ -398,14 +409,16 @@ func (cm *controlledMotor) DoCommand(ctx context.Context, req map[string]interfa
 
 	cm.mu.Lock()
 	defer cm.mu.Unlock()
-	ok := req[getPID].(bool)
-	if ok {
+	if ok, _ := req[getPID].(bool); ok {
 		var respStr string
 		if !(*cm.tunedVals)[0].NeedsAutoTuning() {
 			respStr += (*cm.tunedVals)[0].String()
 		}
 		resp[getPID] = respStr
 	}
+	if ok, _ := req[getTuningResults].(bool); ok {
+		resp[getTuningResults] = control.TuningResultsToMaps(*cm.tuningResults)
+	}
 
 	return resp, nil
 }
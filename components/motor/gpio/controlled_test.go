@@ -118,4 +118,13 @@ func TestControlledMotorCreation(t *testing.T) {
 	resp, err = cm.DoCommand(context.Background(), req)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, resp, test.ShouldResemble, emptyMap)
+
+	// test DoCommand returning the structured tuning results
+	tuningResult := control.TuningResult{Block: "PID", Method: "imcPI", P: 0.1, I: 2.0, Converged: true}
+	cm.tuningResults = &[]control.TuningResult{tuningResult}
+	resp, err = cm.DoCommand(context.Background(), map[string]interface{}{"get_tuning_results": true})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldResemble, map[string]interface{}{
+		"get_tuning_results": []interface{}{tuningResult.ToMap()},
+	})
 }
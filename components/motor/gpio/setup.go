@@ -8,6 +8,7 @@ import (
 	"go.viam.com/rdk/components/board"
 	"go.viam.com/rdk/components/encoder"
 	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/control"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 )
@@ -106,9 +107,16 @@ func (conf *PinConfig) MotorType(path string) (MotorType, error) {
 }
 
 type motorPIDConfig struct {
-	P float64 `json:"p"`
-	I float64 `json:"i"`
-	D float64 `json:"d"`
+	P          float64 `json:"p"`
+	I          float64 `json:"i"`
+	D          float64 `json:"d"`
+	TuneMethod string  `json:"tune_method,omitempty"`
+
+	// optional conditioning of the PID output, see control.Options
+	AntiWindup      string                     `json:"anti_windup,omitempty"`
+	OutputDeadband  float64                    `json:"output_deadband,omitempty"`
+	OutputRateLimit float64                    `json:"output_rate_limit,omitempty"`
+	FeedForward     *control.FeedForwardConfig `json:"feed_forward,omitempty"`
 }
 
 // Config describes the configuration of a motor.
@@ -150,6 +158,16 @@ func (conf *Config) Validate(path string) ([]string, []string, error) {
 	} else if conf.MaxRPM <= 0 {
 		return nil, nil, resource.NewConfigValidationFieldRequiredError(path, "max_rpm")
 	}
+
+	if conf.ControlParameters != nil {
+		if err := control.ValidateTuneMethod(conf.ControlParameters.TuneMethod); err != nil {
+			return nil, nil, resource.NewConfigValidationError(path, err)
+		}
+		if err := control.ValidateOutputConditioning(conf.ControlParameters.AntiWindup,
+			conf.ControlParameters.OutputDeadband, conf.ControlParameters.OutputRateLimit); err != nil {
+			return nil, nil, resource.NewConfigValidationError(path, err)
+		}
+	}
 	return deps, nil, nil
 }
 
@@ -150,6 +150,21 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErrText: resource.NewConfigValidationError("test/path", errors.New("ticks_per_rotation should be positive or zero")).Error(),
 		},
+		{
+			name: "invalid anti windup",
+			config: Config{
+				BoardName: "board1",
+				Pins: PinConfig{
+					A:   "pin1",
+					B:   "pin2",
+					PWM: "pwm1",
+				},
+				Encoder:           "encoder1",
+				TicksPerRotation:  100,
+				ControlParameters: &motorPIDConfig{P: 1, AntiWindup: "bogus"},
+			},
+			wantErrText: "anti_windup should be one of",
+		},
 	}
 
 	for _, tt := range tests {
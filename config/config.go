@@ -43,9 +43,17 @@ type Config struct {
 	MaintenanceConfig *MaintenanceConfig
 	Jobs              []JobConfig
 	Tracing           TracingConfig
+	Templates         []Template
 
 	ConfigFilePath string
 
+	// OverlayFilePath is the path of a local overlay file applied on top of the config, see Overlay.
+	OverlayFilePath string
+
+	// SecretStore is where ${secret:name} placeholders are looked up when the config is processed.
+	// Defaults to the secrets directory under ~/.viam.
+	SecretStore SecretStore
+
 	// AllowInsecureCreds is used to have all connections allow insecure
 	// downgrades and send credentials over plaintext. This is an option
 	// a user must pass via command line arguments.
@@ -80,6 +88,19 @@ type Config struct {
 	// should be turned off. Defaults to false.
 	DisableLogDeduplication bool
 
+	// templateOrigins holds the path of the template instance each component and service expanded
+	// from a template came from, keyed by "components/name" or "services/name".
+	templateOrigins map[string]string
+
+	// secretAttributes holds the paths, as JSON pointers into the attributes, of each component and
+	// service's attributes which were resolved from file and secret placeholders, keyed like
+	// templateOrigins. They are redacted where the config is logged.
+	secretAttributes map[string][]string
+
+	// overlaid holds the components and services changed or removed by a config overlay, keyed by
+	// "components/name" or "services/name".
+	overlaid map[string]bool
+
 	// toCache stores the JSON marshalled version of the config to be cached. It should be a copy of
 	// the config pulled from cloud with minor changes.
 	// This version is kept because the config is changed as it moves through the system.
@@ -139,6 +160,7 @@ type configData struct {
 	DisableLogDeduplication bool                          `json:"disable_log_deduplication"`
 	Jobs                    []JobConfig                   `json:"jobs,omitempty"`
 	Tracing                 TracingConfig                 `json:"tracing,omitempty"`
+	Templates               []Template                    `json:"templates,omitempty"`
 }
 
 // AppValidationStatus refers to the.
@@ -183,6 +205,7 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		}
 		seenJobs[c.Jobs[idx].Name] = struct{}{}
 	}
+	c.Jobs = removeJobsWithInvalidDependencies(c.Jobs, logger)
 	seenModules := make(map[string]struct{})
 	for idx := range len(c.Modules) {
 		if err := c.Modules[idx].Validate(fmt.Sprintf("%s.%d", "modules", idx)); err != nil {
@@ -233,7 +256,8 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		// was registered during resource model registration. If no converter but a typed struct was registered, the RDK provides a
 		// default converter. For modular resources, since lookup will fail as no converter or a typed struct is registered, implicit
 		// dependencies are gathered during robot reconfiguration itself.
-		requiredDeps, optionalDeps, err := c.Components[idx].Validate(fmt.Sprintf("%s.%d", "components", idx), resource.APITypeComponentName)
+		requiredDeps, optionalDeps, err := c.Components[idx].Validate(
+			c.resourcePath("components", idx, c.Components[idx].Name), resource.APITypeComponentName)
 		if err != nil {
 			resLogger := logger.Sublogger(c.Components[idx].ResourceName().String())
 			resLogger.Errorw("Component config error; starting robot without component", "name", c.Components[idx].Name, "error", err.Error())
@@ -243,7 +267,8 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		}
 	}
 	for idx := range len(c.Services) {
-		requiredDeps, optionalDeps, err := c.Services[idx].Validate(fmt.Sprintf("%s.%d", "services", idx), resource.APITypeServiceName)
+		requiredDeps, optionalDeps, err := c.Services[idx].Validate(
+			c.resourcePath("services", idx, c.Services[idx].Name), resource.APITypeServiceName)
 		if err != nil {
 			resLogger := logger.Sublogger(c.Services[idx].ResourceName().String())
 			resLogger.Errorw("Service config error; starting robot without service", "name", c.Services[idx].Name, "error", err.Error())
@@ -324,6 +349,7 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	c.DisableLogDeduplication = conf.DisableLogDeduplication
 	c.Jobs = conf.Jobs
 	c.Tracing = conf.Tracing
+	c.Templates = conf.Templates
 
 	return nil
 }
@@ -359,6 +385,7 @@ func (c Config) MarshalJSON() ([]byte, error) {
 		DisableLogDeduplication: c.DisableLogDeduplication,
 		Jobs:                    c.Jobs,
 		Tracing:                 c.Tracing,
+		Templates:               c.Templates,
 	})
 }
 
@@ -1350,6 +1377,102 @@ type JobConfigData struct {
 	Method           string              `json:"method"`
 	Command          map[string]any      `json:"command,omitempty"`
 	LogConfiguration *resource.LogConfig `json:"log_configuration,omitempty"`
+	// Retries is the number of times a failed run is retried before it is recorded as a failure.
+	Retries int `json:"retries,omitempty"`
+	// RetryBackoff is how long to wait before the first retry, doubling for each retry after it.
+	RetryBackoff string `json:"retry_backoff,omitempty"`
+	// Timeout bounds how long each attempt of a run can take.
+	Timeout string `json:"timeout,omitempty"`
+	// DependsOn names jobs which must all succeed before this job runs. A job with dependencies
+	// runs after them instead of on a schedule.
+	DependsOn []string `json:"depends_on,omitempty"`
+	// MaxConcurrent is the number of runs of this job allowed at the same time, 1 if unset.
+	MaxConcurrent int `json:"max_concurrent,omitempty"`
+	// Trigger is an event which runs this job, instead of a schedule.
+	Trigger *JobTriggerConfig `json:"trigger,omitempty"`
+}
+
+// Job trigger types.
+const (
+	// JobTriggerStartup runs a job once when it is added, which for jobs in the config the
+	// robot starts with is on startup.
+	JobTriggerStartup = "startup"
+	// JobTriggerResourceReconfigured runs a job when its resource is reconfigured.
+	JobTriggerResourceReconfigured = "resource_reconfigured"
+	// JobTriggerResourceUnhealthy runs a job when its resource becomes unhealthy.
+	JobTriggerResourceUnhealthy = "resource_unhealthy"
+	// JobTriggerSensorReading runs a job when a reading of its sensor starts matching a predicate.
+	JobTriggerSensorReading = "sensor_reading"
+	// JobTriggerCaptureFileSealed runs a job when a data capture file is completed.
+	JobTriggerCaptureFileSealed = "capture_file_sealed"
+)
+
+var (
+	jobTriggerTypes = []string{
+		JobTriggerStartup,
+		JobTriggerResourceReconfigured,
+		JobTriggerResourceUnhealthy,
+		JobTriggerSensorReading,
+		JobTriggerCaptureFileSealed,
+	}
+	jobTriggerOperators = []string{"==", "!=", ">", ">=", "<", "<="}
+)
+
+// JobTriggerConfig describes the event which runs a job.
+type JobTriggerConfig struct {
+	Type string `json:"type"`
+	// Resource is the name of the resource whose events run the job, the job's resource if unset.
+	// For capture_file_sealed triggers it is the component the data was captured from, and files
+	// from any component run the job if it is unset.
+	Resource string `json:"resource,omitempty"`
+	// Path, Operator and Value are the predicate of a sensor_reading trigger. Path is the
+	// dot separated keys of a reading, which is compared to Value with Operator. The job runs
+	// when the predicate becomes true, not for as long as it stays true.
+	Path     string `json:"path,omitempty"`
+	Operator string `json:"operator,omitempty"`
+	Value    any    `json:"value,omitempty"`
+	// PollInterval is how often a sensor_reading trigger reads the sensor, 1s if unset.
+	PollInterval string `json:"poll_interval,omitempty"`
+	// Debounce drops events which happen within this long of the last one which ran the job.
+	Debounce string `json:"debounce,omitempty"`
+}
+
+// Validate checks that the trigger is well formed.
+func (tc *JobTriggerConfig) Validate(path string) error {
+	if tc.Type == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "type")
+	}
+	if !slices.Contains(jobTriggerTypes, tc.Type) {
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("unknown trigger type %q, must be one of %s", tc.Type, strings.Join(jobTriggerTypes, ", ")))
+	}
+	if tc.Type == JobTriggerSensorReading {
+		if tc.Path == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "path")
+		}
+		if !slices.Contains(jobTriggerOperators, tc.Operator) {
+			return resource.NewConfigValidationError(path,
+				errors.Errorf("unknown operator %q, must be one of %s", tc.Operator, strings.Join(jobTriggerOperators, " ")))
+		}
+		if tc.Value == nil {
+			return resource.NewConfigValidationFieldRequiredError(path, "value")
+		}
+	}
+	if tc.PollInterval != "" {
+		interval, err := time.ParseDuration(tc.PollInterval)
+		if err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid poll_interval"))
+		}
+		if interval <= 0 {
+			return resource.NewConfigValidationError(path, errors.New("poll_interval must be positive"))
+		}
+	}
+	if tc.Debounce != "" {
+		if _, err := time.ParseDuration(tc.Debounce); err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid debounce"))
+		}
+	}
+	return nil
 }
 
 // MarshalJSON marshals out this config.
@@ -1381,15 +1504,142 @@ func (jc *JobConfig) Validate(path string) error {
 	if jc.Resource == "" {
 		return resource.NewConfigValidationFieldRequiredError(path, "resource")
 	}
-	if jc.Schedule == "" {
+	if jc.Schedule == "" && len(jc.DependsOn) == 0 && jc.Trigger == nil {
 		return resource.NewConfigValidationFieldRequiredError(path, "schedule")
 	}
+	if jc.Schedule != "" && len(jc.DependsOn) != 0 {
+		return resource.NewConfigValidationError(path,
+			errors.New("a job with depends_on runs after its dependencies and cannot also have a schedule"))
+	}
+	if jc.Trigger != nil {
+		if jc.Schedule != "" || len(jc.DependsOn) != 0 {
+			return resource.NewConfigValidationError(path,
+				errors.New("a job with a trigger runs on its events and cannot also have a schedule or depends_on"))
+		}
+		if err := jc.Trigger.Validate(path + ".trigger"); err != nil {
+			return err
+		}
+	}
+	if slices.Contains(jc.DependsOn, jc.Name) {
+		return resource.NewConfigValidationError(path, errors.New("a job cannot depend on itself"))
+	}
+	if jc.Retries < 0 {
+		return resource.NewConfigValidationError(path, errors.New("retries cannot be negative"))
+	}
+	if jc.RetryBackoff != "" {
+		if _, err := time.ParseDuration(jc.RetryBackoff); err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid retry_backoff"))
+		}
+	}
+	if jc.Timeout != "" {
+		timeout, err := time.ParseDuration(jc.Timeout)
+		if err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid timeout"))
+		}
+		if timeout <= 0 {
+			return resource.NewConfigValidationError(path, errors.New("timeout must be positive"))
+		}
+	}
+	if jc.MaxConcurrent < 0 {
+		return resource.NewConfigValidationError(path, errors.New("max_concurrent cannot be negative"))
+	}
+	if jc.MaxConcurrent > 1 && strings.ToLower(jc.Schedule) == "continuous" {
+		return resource.NewConfigValidationError(path, errors.New("a continuous job cannot have a max_concurrent above 1"))
+	}
 	// At this point, the schedule could still be invalid (not a golang duration string or a
 	// cron expression). Such errors will be caught later, when the job manager will try to
 	// schedule the job and parse this field. The error will be displayed to the user.
 	return nil
 }
 
+// removeJobsWithInvalidDependencies logs and removes the jobs which would never run: the ones
+// which depend on a job that is not in jobs, the ones whose dependencies form a cycle, and the
+// ones which depend on any of those.
+func removeJobsWithInvalidDependencies(jobs []JobConfig, logger logging.Logger) []JobConfig {
+	deps := make(map[string][]string, len(jobs))
+	for _, jc := range jobs {
+		deps[jc.Name] = jc.DependsOn
+	}
+
+	invalid := make(map[string]error)
+	for _, jc := range jobs {
+		for _, dep := range jc.DependsOn {
+			if _, exists := deps[dep]; !exists {
+				invalid[jc.Name] = errors.Errorf("depends on job %q which is not in the robot config", dep)
+				break
+			}
+		}
+	}
+
+	// findCycle returns the dependencies leading from the job back to itself, if any, with a
+	// depth first search.
+	findCycle := func(start string) []string {
+		visited := make(map[string]bool)
+		var path []string
+		var visit func(name string) bool
+		visit = func(name string) bool {
+			path = append(path, name)
+			for _, dep := range deps[name] {
+				if dep == start {
+					path = append(path, dep)
+					return true
+				}
+				if !visited[dep] {
+					visited[dep] = true
+					if visit(dep) {
+						return true
+					}
+				}
+			}
+			path = path[:len(path)-1]
+			return false
+		}
+		if visit(start) {
+			return path
+		}
+		return nil
+	}
+	for _, jc := range jobs {
+		if _, ok := invalid[jc.Name]; ok {
+			continue
+		}
+		if cycle := findCycle(jc.Name); cycle != nil {
+			invalid[jc.Name] = errors.Errorf("job dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	// jobs which depend on jobs that never run never run either
+	for changed := true; changed; {
+		changed = false
+		for _, jc := range jobs {
+			if _, ok := invalid[jc.Name]; ok {
+				continue
+			}
+			for _, dep := range jc.DependsOn {
+				if _, ok := invalid[dep]; ok {
+					invalid[jc.Name] = errors.Errorf("depends on job %q which cannot run", dep)
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	if len(invalid) == 0 {
+		return jobs
+	}
+
+	valid := make([]JobConfig, 0, len(jobs)-len(invalid))
+	for idx, jc := range jobs {
+		if err, ok := invalid[jc.Name]; ok {
+			err = resource.NewConfigValidationError(fmt.Sprintf("%s.%d", "jobs", idx), err)
+			logger.Errorw("Jobs config error; starting robot without job", "name", jc.Name, "error", err.Error())
+			continue
+		}
+		valid = append(valid, jc)
+	}
+	return valid
+}
+
 // Equals checks if the two configs are deeply equal to each other.
 func (jc JobConfig) Equals(other JobConfig) bool {
 	return reflect.DeepEqual(jc, other)
@@ -470,6 +470,46 @@ func TestConfigEnsure(t *testing.T) {
 	test.That(t, invalidAuthConfig.Ensure(false, logger), test.ShouldBeNil)
 }
 
+func TestConfigEnsureJobDependencies(t *testing.T) {
+	logger, logs := logging.NewObservedTestLogger(t)
+	job := func(name string, dependsOn ...string) config.JobConfig {
+		jc := config.JobConfig{config.JobConfigData{Name: name, Method: "DoCommand", Resource: "sensor"}}
+		if len(dependsOn) == 0 {
+			jc.Schedule = "1m"
+		}
+		jc.DependsOn = dependsOn
+		return jc
+	}
+	jobNames := func(cfg config.Config) []string {
+		var names []string
+		for _, jc := range cfg.Jobs {
+			names = append(names, jc.Name)
+		}
+		return names
+	}
+
+	cfg := config.Config{Jobs: []config.JobConfig{job("calibrate"), job("measure", "calibrate"), job("report", "measure")}}
+	test.That(t, cfg.Ensure(false, logger), test.ShouldBeNil)
+	test.That(t, jobNames(cfg), test.ShouldResemble, []string{"calibrate", "measure", "report"})
+
+	// jobs with unknown dependencies are dropped, along with the jobs depending on them
+	cfg = config.Config{Jobs: []config.JobConfig{job("calibrate"), job("measure", "calbrate"), job("report", "measure")}}
+	test.That(t, cfg.Ensure(false, logger), test.ShouldBeNil)
+	test.That(t, jobNames(cfg), test.ShouldResemble, []string{"calibrate"})
+	test.That(t, logs.FilterMessageSnippet("Jobs config error").FilterFieldKey("error").Len(), test.ShouldEqual, 2)
+	test.That(t, logs.FilterMessageSnippet("Jobs config error").All()[0].ContextMap()["error"],
+		test.ShouldContainSubstring, `depends on job "calbrate"`)
+
+	// jobs whose dependencies form a cycle are dropped
+	cfg = config.Config{Jobs: []config.JobConfig{
+		job("calibrate"), job("measure", "calibrate", "report"), job("report", "measure"), job("clean", "calibrate"),
+	}}
+	test.That(t, cfg.Ensure(false, logger), test.ShouldBeNil)
+	test.That(t, jobNames(cfg), test.ShouldResemble, []string{"calibrate", "clean"})
+	test.That(t, logs.FilterMessageSnippet("Jobs config error").All()[2].ContextMap()["error"],
+		test.ShouldContainSubstring, "measure -> report -> measure")
+}
+
 func TestConfigEnsurePartialStart(t *testing.T) {
 	logger, logs := logging.NewObservedTestLogger(t)
 	var emptyConfig config.Config
@@ -1285,6 +1325,155 @@ func TestJobsConfig(t *testing.T) {
 			},
 			shouldFailValidation: false,
 		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:          "my_name",
+					Schedule:      "1m",
+					Method:        "my_method",
+					Resource:      "my_resource",
+					Retries:       3,
+					RetryBackoff:  "5s",
+					Timeout:       "30s",
+					MaxConcurrent: 2,
+				},
+			},
+			shouldFailValidation: false,
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:      "my_name",
+					Method:    "my_method",
+					Resource:  "my_resource",
+					DependsOn: []string{"other"},
+				},
+			},
+			shouldFailValidation: false,
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:      "my_name",
+					Schedule:  "1m",
+					Method:    "my_method",
+					Resource:  "my_resource",
+					DependsOn: []string{"other"},
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           "cannot also have a schedule",
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:      "my_name",
+					Method:    "my_method",
+					Resource:  "my_resource",
+					DependsOn: []string{"my_name"},
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           "cannot depend on itself",
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:     "my_name",
+					Schedule: "1m",
+					Method:   "my_method",
+					Resource: "my_resource",
+					Timeout:  "soon",
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           "invalid timeout",
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:     "my_name",
+					Schedule: "1m",
+					Method:   "my_method",
+					Resource: "my_resource",
+					Retries:  -1,
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           "retries cannot be negative",
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:          "my_name",
+					Schedule:      "continuous",
+					Method:        "my_method",
+					Resource:      "my_resource",
+					MaxConcurrent: 2,
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           "max_concurrent",
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:     "my_name",
+					Method:   "my_method",
+					Resource: "my_resource",
+					Trigger: &config.JobTriggerConfig{
+						Type:     config.JobTriggerSensorReading,
+						Path:     "temp.celsius",
+						Operator: ">=",
+						Value:    50,
+						Debounce: "10s",
+					},
+				},
+			},
+			shouldFailValidation: false,
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:     "my_name",
+					Schedule: "1m",
+					Method:   "my_method",
+					Resource: "my_resource",
+					Trigger:  &config.JobTriggerConfig{Type: config.JobTriggerStartup},
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           "cannot also have a schedule",
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:     "my_name",
+					Method:   "my_method",
+					Resource: "my_resource",
+					Trigger:  &config.JobTriggerConfig{Type: "on_tuesdays"},
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           `unknown trigger type "on_tuesdays"`,
+		},
+		{
+			config: config.JobConfig{
+				config.JobConfigData{
+					Name:     "my_name",
+					Method:   "my_method",
+					Resource: "my_resource",
+					Trigger: &config.JobTriggerConfig{
+						Type:     config.JobTriggerSensorReading,
+						Path:     "temp.celsius",
+						Operator: "~",
+						Value:    50,
+					},
+				},
+			},
+			shouldFailValidation: true,
+			expRespErr:           `unknown operator "~"`,
+		},
 	}
 
 	for _, jt := range jobsTests {
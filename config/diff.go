@@ -27,6 +27,9 @@ type Diff struct {
 	JobsEqual           bool
 	PrettyDiff          string
 	UnmodifiedResources []resource.Config
+	// Overlaid are the added, modified, and removed resources which a config overlay changed or
+	// removed on either side of the diff.
+	Overlaid []resource.Name
 }
 
 // ModifiedConfigDiff is the modificative different between two configs.
@@ -102,9 +105,36 @@ func DiffConfigs(left, right Config, revealSensitiveConfigDiffs bool) (_ *Diff,
 	tracingDifferent := diffTracing(&left, &right)
 	diff.TracingEqual = !tracingDifferent
 
+	diff.Overlaid = diffOverlaid(&left, &right, &diff)
+
 	return &diff, nil
 }
 
+// diffOverlaid returns the resources in the diff which a config overlay changed or removed in left or right.
+func diffOverlaid(left, right *Config, diff *Diff) []resource.Name {
+	if len(left.overlaid) == 0 && len(right.overlaid) == 0 {
+		return nil
+	}
+	var overlaid []resource.Name
+	for _, section := range []struct {
+		key   string
+		confs [][]resource.Config
+	}{
+		{"components", [][]resource.Config{diff.Added.Components, diff.Modified.Components, diff.Removed.Components}},
+		{"services", [][]resource.Config{diff.Added.Services, diff.Modified.Services, diff.Removed.Services}},
+	} {
+		for _, confs := range section.confs {
+			for _, conf := range confs {
+				key := section.key + "/" + conf.Name
+				if left.overlaid[key] || right.overlaid[key] {
+					overlaid = append(overlaid, conf.ResourceName())
+				}
+			}
+		}
+	}
+	return overlaid
+}
+
 func diffTracing(left, right *Config) bool {
 	return left.Tracing != right.Tracing
 }
@@ -118,6 +148,9 @@ func prettyDiff(left, right Config) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// the placeholder origins aren't JSON, so they are taken from the configs before cloning
+	leftSecrets, rightSecrets := left.secretAttributes, right.secretAttributes
+	leftModules, rightModules := left.Modules, right.Modules
 	var leftClone, rightClone Config
 	if err := json.Unmarshal(leftMd, &leftClone); err != nil {
 		return "", err
@@ -128,8 +161,8 @@ func prettyDiff(left, right Config) (string, error) {
 	left = leftClone
 	right = rightClone
 
-	mask := "******"
-	sanitizeConfig := func(conf *Config) {
+	mask := secretsMask
+	sanitizeConfig := func(conf *Config, secretAttributes map[string][]string, modules []Module) {
 		// Note(erd): keep in mind this will destroy the actual pretty diffing of these which
 		// is fine because we aren't considering pretty diff changes to these fields at this level
 		// of the stack.
@@ -173,9 +206,15 @@ func prettyDiff(left, right Config) (string, error) {
 				rem.Auth.SignalingCreds.Payload = mask
 			}
 		}
+		for i := range conf.Modules {
+			if i < len(modules) {
+				conf.Modules[i].Environment = modules[i].RedactEnvironment(conf.Modules[i].Environment)
+			}
+		}
+		redactSecretAttributes(conf, secretAttributes)
 	}
-	sanitizeConfig(&left)
-	sanitizeConfig(&right)
+	sanitizeConfig(&left, leftSecrets, leftModules)
+	sanitizeConfig(&right, rightSecrets, rightModules)
 
 	leftMd, err = json.MarshalIndent(left, "", " ")
 	if err != nil {
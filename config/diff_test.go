@@ -695,6 +695,91 @@ func TestDiffSanitize(t *testing.T) {
 	}
 }
 
+func TestDiffRedactsPlaceholderSecrets(t *testing.T) {
+	// a short secret which occurs elsewhere in the config is only redacted where it was resolved
+	secrets := config.MapSecretStore{"password": "hunter2", "quoted": `say "hi"`, "short": "e"}
+	newConfig := func(password string) config.Config {
+		return config.Config{
+			SecretStore: secrets,
+			Components: []resource.Config{
+				{
+					Name:  "foo",
+					API:   arm.API,
+					Model: fakeModel,
+					Attributes: utils.AttributeMap{
+						"password": password,
+						"quoted":   "${secret:quoted}",
+						"plain":    "visible",
+					},
+				},
+			},
+		}
+	}
+	left := newConfig("old")
+	right := newConfig("${secret:password}")
+	test.That(t, left.ReplacePlaceholders(), test.ShouldBeNil)
+	test.That(t, right.ReplacePlaceholders(), test.ShouldBeNil)
+	test.That(t, right.Components[0].Attributes["password"], test.ShouldEqual, "hunter2")
+
+	diff, err := config.DiffConfigs(left, right, true)
+	test.That(t, err, test.ShouldBeNil)
+	diffStr := diff.String()
+	test.That(t, diffStr, test.ShouldContainSubstring, "old")
+	test.That(t, diffStr, test.ShouldNotContainSubstring, "hunter2")
+	test.That(t, diffStr, test.ShouldNotContainSubstring, "hi")
+
+	withShort := newConfig("old")
+	withShort.Components = append(withShort.Components, resource.Config{
+		Name:       "bar",
+		API:        arm.API,
+		Model:      fakeModel,
+		Attributes: utils.AttributeMap{"short": "${secret:short}", "plain": "seen here"},
+	})
+	test.That(t, withShort.ReplacePlaceholders(), test.ShouldBeNil)
+	diff, err = config.DiffConfigs(left, withShort, true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.String(), test.ShouldContainSubstring, `"seen here"`)
+	test.That(t, diff.String(), test.ShouldContainSubstring, `"short": "******"`)
+
+	// the secret is still redacted once the placeholder is removed from the new config
+	diff, err = config.DiffConfigs(right, newConfig("plain"), true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.String(), test.ShouldNotContainSubstring, "hunter2")
+	test.That(t, diff.String(), test.ShouldContainSubstring, "plain")
+
+	// typed secrets are redacted even though they are formatted differently once parsed, while
+	// unrelated values equal to a secret are not
+	typedSecrets := config.MapSecretStore{"rate": "1.50", "enabled": "true"}
+	withTyped := config.Config{
+		SecretStore: typedSecrets,
+		Components: []resource.Config{
+			{
+				Name:  "foo",
+				API:   arm.API,
+				Model: fakeModel,
+				Attributes: utils.AttributeMap{
+					"rate":    "${secret:rate|number}",
+					"enabled": "${secret:enabled|bool}",
+					"nested":  map[string]interface{}{"list": []interface{}{"${secret:rate}", "visible"}},
+					"debug":   true,
+					"speed":   1.5,
+				},
+			},
+		},
+	}
+	test.That(t, withTyped.ReplacePlaceholders(), test.ShouldBeNil)
+	test.That(t, withTyped.Components[0].Attributes["rate"], test.ShouldEqual, 1.5)
+	diff, err = config.DiffConfigs(config.Config{}, withTyped, true)
+	test.That(t, err, test.ShouldBeNil)
+	diffStr = diff.String()
+	test.That(t, diffStr, test.ShouldContainSubstring, `"rate": "******"`)
+	test.That(t, diffStr, test.ShouldContainSubstring, `"enabled": "******"`)
+	test.That(t, diffStr, test.ShouldNotContainSubstring, "1.50")
+	test.That(t, diffStr, test.ShouldContainSubstring, `"debug": true`)
+	test.That(t, diffStr, test.ShouldContainSubstring, `"speed": 1.5`)
+	test.That(t, diffStr, test.ShouldContainSubstring, `"visible"`)
+}
+
 func modifiedConfigDiffValidate(c *config.ModifiedConfigDiff) error {
 	for idx := 0; idx < len(c.Remotes); idx++ {
 		if _, _, err := c.Remotes[idx].Validate(fmt.Sprintf("%s.%d", "remotes", idx)); err != nil {
@@ -67,6 +67,9 @@ type Module struct {
 	alreadyValidated bool
 	cachedErr        error
 
+	// secretEnvironment holds the environment variables resolved from file and secret placeholders.
+	secretEnvironment map[string]bool
+
 	// LocalVersion is an in-process fake version used for local module change management.
 	LocalVersion string
 }
@@ -145,6 +148,19 @@ func (m Module) Equals(other Module) bool {
 	return reflect.DeepEqual(m, other)
 }
 
+// RedactEnvironment returns a copy of env, e.g. the environment the module is started with, in which
+// the variables of the module resolved from file and secret placeholders are masked for logging.
+func (m Module) RedactEnvironment(env map[string]string) map[string]string {
+	redacted := make(map[string]string, len(env))
+	for name, value := range env {
+		if m.secretEnvironment[name] {
+			value = secretsMask
+		}
+		redacted[name] = value
+	}
+	return redacted
+}
+
 // MergeEnvVars will merge the provided environment variables with the existing Environment, with the existing Environment
 // taking priority.
 func (m *Module) MergeEnvVars(env map[string]string) {
@@ -0,0 +1,304 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/bep/debounce"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+)
+
+// An Overlay holds local changes applied on top of a config, e.g. for a technician to temporarily
+// override attributes or disable a component of a machine configured from the cloud while it is
+// offline. Resources are matched by name and each entry is a JSON merge patch (RFC 7386) of the
+// config of that resource, so a null entry removes the resource and a null field removes the field.
+//
+//	{
+//	  "components": {
+//	    "arm1": {"attributes": {"speed_degs_per_sec": 10}},
+//	    "lidar": null
+//	  }
+//	}
+type Overlay struct {
+	Components map[string]any `json:"components,omitempty"`
+	Services   map[string]any `json:"services,omitempty"`
+}
+
+// ReadOverlay reads the overlay in the file at path. It returns nil if the file doesn't exist, so
+// removing the file reverts the overlay.
+func ReadOverlay(path string) (*Overlay, error) {
+	//nolint:gosec
+	rd, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var overlay Overlay
+	if err := json.Unmarshal(rd, &overlay); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode config overlay %q", path)
+	}
+	return &overlay, nil
+}
+
+// ReadUnprocessed returns the config cfg was processed from, which overlays are applied to: the
+// config read from the cloud for a cloud config, or else the config in the file at
+// cfg.ConfigFilePath.
+func ReadUnprocessed(cfg *Config) (*Config, error) {
+	var md []byte
+	switch {
+	case cfg.toCache != nil:
+		md = cfg.toCache
+	case cfg.ConfigFilePath != "":
+		//nolint:gosec
+		rd, err := os.ReadFile(cfg.ConfigFilePath)
+		if err != nil {
+			return nil, err
+		}
+		md = rd
+	default:
+		return nil, errors.New("config overlays can only be applied to configs read from a file or the cloud")
+	}
+	var unprocessed Config
+	if err := json.Unmarshal(md, &unprocessed); err != nil {
+		return nil, errors.Wrap(err, "failed to decode unprocessed config")
+	}
+	return &unprocessed, nil
+}
+
+// ApplyOverlayFile applies the overlay in the file at cfg.OverlayFilePath to cfg, which was
+// processed from unprocessed. cfg is returned as is when it has no overlay file or the file doesn't
+// exist.
+func ApplyOverlayFile(cfg, unprocessed *Config, logger logging.Logger) (*Config, error) {
+	if cfg.OverlayFilePath == "" {
+		return cfg, nil
+	}
+	overlay, err := ReadOverlay(cfg.OverlayFilePath)
+	if err != nil || overlay == nil {
+		return cfg, err
+	}
+	return ApplyOverlay(cfg, unprocessed, overlay, logger)
+}
+
+// ApplyOverlay returns a copy of the processed config cfg with overlay applied to its components and
+// services. The overlay is applied to unprocessed, the config cfg was processed from, which is then
+// processed again, and the resources it changes or removes are marked as overlaid in config diffs.
+// Entries for resources which aren't in cfg are logged and ignored.
+func ApplyOverlay(cfg, unprocessed *Config, overlay *Overlay, logger logging.Logger) (*Config, error) {
+	md, err := json.Marshal(unprocessed)
+	if err != nil {
+		return nil, err
+	}
+	var expanded Config
+	if err := json.Unmarshal(md, &expanded); err != nil {
+		return nil, err
+	}
+	// templates are expanded first so their resources can be overlaid by name
+	if err := expanded.ExpandTemplates(); err != nil {
+		return nil, err
+	}
+
+	md, err = json.Marshal(&expanded)
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(md, &doc); err != nil {
+		return nil, err
+	}
+	// only the resources are taken from the processed overlay, the rest of cfg is kept as is
+	delete(doc, "cloud")
+
+	overlaid := make(map[string]bool)
+	for _, section := range []struct {
+		key     string
+		patches map[string]any
+	}{
+		{"components", overlay.Components},
+		{"services", overlay.Services},
+	} {
+		resources, _ := doc[section.key].([]any)
+		patched := make([]any, 0, len(resources))
+		matched := make(map[string]bool)
+		for _, res := range resources {
+			resDoc, _ := res.(map[string]any)
+			name, _ := resDoc["name"].(string)
+			patch, ok := section.patches[name]
+			if !ok {
+				patched = append(patched, res)
+				continue
+			}
+			matched[name] = true
+			overlaid[section.key+"/"+name] = true
+			if patch == nil {
+				logger.Infow("Config overlay removes resource", "name", name)
+				continue
+			}
+			logger.Infow("Config overlay changes resource", "name", name)
+			patched = append(patched, mergePatch(resDoc, patch))
+		}
+		for name := range section.patches {
+			if !matched[name] {
+				logger.Warnw("Config overlay refers to a resource which is not in the config", "name", name)
+			}
+		}
+		doc[section.key] = patched
+	}
+
+	md, err = json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var withOverlay Config
+	if err := json.Unmarshal(md, &withOverlay); err != nil {
+		return nil, errors.Wrap(err, "failed to decode config with overlay")
+	}
+	withOverlay.SecretStore = cfg.SecretStore
+	processed, err := processConfigLocalConfig(&withOverlay, logger)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to process config with overlay")
+	}
+
+	out := *cfg
+	out.Components = processed.Components
+	out.Services = processed.Services
+	out.secretAttributes = processed.secretAttributes
+	out.overlaid = overlaid
+	return &out, nil
+}
+
+// mergePatch applies the JSON merge patch patch to target.
+func mergePatch(target, patch any) any {
+	patchDoc, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	targetDoc, ok := target.(map[string]any)
+	if !ok {
+		targetDoc = make(map[string]any)
+	}
+	for key, value := range patchDoc {
+		if value == nil {
+			delete(targetDoc, key)
+			continue
+		}
+		targetDoc[key] = mergePatch(targetDoc[key], value)
+	}
+	return targetDoc
+}
+
+// An overlayWatcher applies the overlay file to the configs delivered by another watcher, and
+// delivers the last of them again when the overlay file changes, so the overlay is applied or
+// reverted without waiting for a new config.
+type overlayWatcher struct {
+	inner         Watcher
+	fsWatcher     *fsnotify.Watcher
+	configCh      chan *Config
+	watcherDoneCh chan struct{}
+	cancel        func()
+}
+
+// newOverlayWatcher returns an overlayWatcher applying the overlay file of base to the configs of
+// inner. base is the config the overlay is applied to until inner delivers a new one.
+func newOverlayWatcher(ctx context.Context, inner Watcher, base *Config, logger logging.Logger) (*overlayWatcher, error) {
+	overlayPath := filepath.Clean(base.OverlayFilePath)
+	// the config base was processed from is kept to apply the overlay to again when the file changes
+	baseUnprocessed, err := ReadUnprocessed(base)
+	if err != nil {
+		logger.Errorw("error reading config to apply overlay to", "error", err)
+	}
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// the directory is watched since the file may not exist yet and removing it reverts the overlay
+	if err := fsWatcher.Add(filepath.Dir(overlayPath)); err != nil {
+		utils.UncheckedError(fsWatcher.Close())
+		return nil, err
+	}
+	configCh := make(chan *Config)
+	watcherDoneCh := make(chan struct{})
+	cancelCtx, cancel := context.WithCancel(ctx)
+	utils.ManagedGo(func() {
+		debounced := debounce.New(time.Millisecond * 500)
+		overlayChanged := make(chan struct{}, 1)
+		send := func(cfg *Config) {
+			select {
+			case <-cancelCtx.Done():
+			case configCh <- cfg:
+			}
+		}
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			case cfg := <-inner.Config():
+				cfg.OverlayFilePath = base.OverlayFilePath
+				unprocessed, err := ReadUnprocessed(cfg)
+				if err != nil {
+					logger.Errorw("error reading config to apply overlay to, using the config without it", "error", err)
+					base, baseUnprocessed = cfg, nil
+					send(cfg)
+					continue
+				}
+				base, baseUnprocessed = cfg, unprocessed
+				overlaid, err := ApplyOverlayFile(cfg, unprocessed, logger)
+				if err != nil {
+					logger.Errorw("error applying config overlay, using the config without it", "error", err)
+					overlaid = cfg
+				}
+				send(overlaid)
+			case event := <-fsWatcher.Events:
+				if filepath.Clean(event.Name) != overlayPath {
+					continue
+				}
+				debounced(func() {
+					select {
+					case overlayChanged <- struct{}{}:
+					default:
+					}
+				})
+			case <-overlayChanged:
+				if baseUnprocessed == nil {
+					logger.Warn("Config overlay file changed but the config it applies to could not be read")
+					continue
+				}
+				logger.Info("Config overlay file changed. Reapplying the config.")
+				overlaid, err := ApplyOverlayFile(base, baseUnprocessed, logger)
+				if err != nil {
+					logger.Errorw("error applying config overlay", "error", err)
+					continue
+				}
+				send(overlaid)
+			}
+		}
+	}, func() {
+		close(watcherDoneCh)
+	})
+	return &overlayWatcher{
+		inner:         inner,
+		fsWatcher:     fsWatcher,
+		configCh:      configCh,
+		watcherDoneCh: watcherDoneCh,
+		cancel:        cancel,
+	}, nil
+}
+
+func (w *overlayWatcher) Config() <-chan *Config {
+	return w.configCh
+}
+
+func (w *overlayWatcher) Close() error {
+	w.cancel()
+	<-w.watcherDoneCh
+	return multierr.Combine(w.fsWatcher.Close(), w.inner.Close())
+}
@@ -0,0 +1,142 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
+)
+
+// overlayTestConfig returns a processed config along with the config it was processed from.
+func overlayTestConfig(t *testing.T) (*config.Config, *config.Config) {
+	t.Helper()
+	logger := logging.NewTestLogger(t)
+	unprocessed := &config.Config{
+		Components: []resource.Config{
+			{
+				API:   arm.API,
+				Name:  "arm1",
+				Model: resource.NewModel("acme", "test", "arm"),
+				Attributes: rutils.AttributeMap{
+					"speed":  10.0,
+					"limits": map[string]interface{}{"min": 0.0, "max": 90.0},
+				},
+			},
+			{
+				API:   arm.API,
+				Name:  "arm2",
+				Model: resource.NewModel("acme", "test", "arm"),
+			},
+		},
+	}
+	cfg, err := unprocessed.CopyOnlyPublicFields()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.ProcessLocal(logger), test.ShouldBeNil)
+	return cfg, unprocessed
+}
+
+func TestApplyOverlay(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	cfg, unprocessed := overlayTestConfig(t)
+
+	overlay := &config.Overlay{
+		Components: map[string]any{
+			"arm1": map[string]any{
+				"attributes": map[string]any{
+					"speed":  5.0,
+					"limits": map[string]any{"max": nil},
+				},
+			},
+			"arm2":    nil,
+			"missing": map[string]any{"attributes": map[string]any{"speed": 1.0}},
+		},
+	}
+	overlaid, err := config.ApplyOverlay(cfg, unprocessed, overlay, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(overlaid.Components), test.ShouldEqual, 1)
+	test.That(t, overlaid.Components[0].Name, test.ShouldEqual, "arm1")
+	test.That(t, overlaid.Components[0].Attributes, test.ShouldResemble, rutils.AttributeMap{
+		"speed":  5.0,
+		"limits": map[string]interface{}{"min": 0.0},
+	})
+	// the original config is left alone
+	test.That(t, len(cfg.Components), test.ShouldEqual, 2)
+	test.That(t, cfg.Components[0].Attributes["speed"], test.ShouldEqual, 10.0)
+
+	diff, err := config.DiffConfigs(*cfg, *overlaid, true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(diff.Modified.Components), test.ShouldEqual, 1)
+	test.That(t, diff.Modified.Components[0].Name, test.ShouldEqual, "arm1")
+	test.That(t, len(diff.Removed.Components), test.ShouldEqual, 1)
+	test.That(t, diff.Removed.Components[0].Name, test.ShouldEqual, "arm2")
+	test.That(t, diff.Overlaid, test.ShouldHaveLength, 2)
+	test.That(t, diff.Overlaid, test.ShouldContain, arm.Named("arm1"))
+	test.That(t, diff.Overlaid, test.ShouldContain, arm.Named("arm2"))
+	test.That(t, diff.String(), test.ShouldNotBeEmpty)
+
+	// reapplying the overlay starts from the config as it was before processing
+	again, err := config.ApplyOverlay(overlaid, unprocessed, overlay, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, again.Components, test.ShouldResemble, overlaid.Components)
+
+	// changes to the config from elsewhere are not marked as overlaid
+	changed, _ := overlayTestConfig(t)
+	changed.Components[1].Attributes = rutils.AttributeMap{"speed": 1.0}
+	test.That(t, changed.ProcessLocal(logger), test.ShouldBeNil)
+	diff, err = config.DiffConfigs(*cfg, *changed, true)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(diff.Modified.Components), test.ShouldEqual, 1)
+	test.That(t, diff.Overlaid, test.ShouldBeEmpty)
+
+	// only configs read from a file or the cloud have a config to apply an overlay to
+	_, err = config.ReadUnprocessed(cfg)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// a missing overlay file leaves the config as is
+	cfg.OverlayFilePath = filepath.Join(t.TempDir(), "overlay.json")
+	same, err := config.ApplyOverlayFile(cfg, unprocessed, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, same, test.ShouldEqual, cfg)
+
+	test.That(t, os.WriteFile(cfg.OverlayFilePath, []byte(`{"components": "arm1"}`), 0o600), test.ShouldBeNil)
+	_, err = config.ApplyOverlayFile(cfg, unprocessed, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestNewWatcherOverlay(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	_, unprocessed := overlayTestConfig(t)
+	md, err := json.Marshal(unprocessed)
+	test.That(t, err, test.ShouldBeNil)
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	test.That(t, os.WriteFile(configPath, md, 0o600), test.ShouldBeNil)
+	cfg, err := config.Read(context.Background(), configPath, logger, nil)
+	test.That(t, err, test.ShouldBeNil)
+	cfg.OverlayFilePath = filepath.Join(t.TempDir(), "overlay.json")
+
+	watcher, err := config.NewWatcher(context.Background(), cfg, logger, nil)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, watcher.Close(), test.ShouldBeNil)
+	}()
+
+	// writing the overlay applies it to the current config
+	test.That(t, os.WriteFile(cfg.OverlayFilePath, []byte(`{"components": {"arm2": null}}`), 0o600), test.ShouldBeNil)
+	newConf := <-watcher.Config()
+	test.That(t, len(newConf.Components), test.ShouldEqual, 1)
+	test.That(t, newConf.Components[0].Name, test.ShouldEqual, "arm1")
+
+	// removing it reverts the overlay
+	test.That(t, os.Remove(cfg.OverlayFilePath), test.ShouldBeNil)
+	newConf = <-watcher.Config()
+	test.That(t, len(newConf.Components), test.ShouldEqual, 2)
+}
@@ -3,12 +3,16 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"go.uber.org/multierr"
 
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/utils"
 )
 
@@ -28,6 +32,62 @@ var packagePlaceholderRegexp = regexp.MustCompile(`^packages(\.(?P<type>[^\.]+))
 // This is compatible with IEEE Std 1003.1-2018 (see basedefs/V1_chap08.html).
 var environmentPlaceholderRegexp = regexp.MustCompile(`^environment\.(?P<name>[\w:/-]+)$`)
 
+// filePlaceholderRegexp matches on file placeholders, which are replaced with the contents of a local file
+// Example strings satisfying the regex:
+// file:/run/secrets/api_key
+// file:~/.viam/token.
+var filePlaceholderRegexp = regexp.MustCompile(`^file:(?P<path>.+)$`)
+
+// secretPlaceholderRegexp matches on secret placeholders, which are replaced with a secret from the secret store
+// Example strings satisfying the regex:
+// secret:api_key
+// secret:db.password.
+var secretPlaceholderRegexp = regexp.MustCompile(`^secret:(?P<name>[\w.-]+)$`)
+
+// typedPlaceholderRegexp matches on placeholders with a type suffix, which are replaced with a typed value
+// instead of a string when they make up the whole attribute
+// Example strings satisfying the regex:
+// ${environment.PORT|number}
+// ${secret:use_tls|bool}.
+var typedPlaceholderRegexp = regexp.MustCompile(`^\$\{(?P<placeholder_key>[^\}]*)\|(?P<type>number|bool)\}$`)
+
+// A SecretStore looks up the secrets referenced by ${secret:name} placeholders.
+type SecretStore interface {
+	// Secret returns the value of the named secret or an error if it doesn't exist.
+	Secret(name string) (string, error)
+}
+
+// DirectorySecretStore is a SecretStore which keeps each secret in a file named after it in a
+// directory, e.g. a directory secrets are mounted in. Trailing newlines are not part of a secret.
+type DirectorySecretStore string
+
+// Secret returns the contents of the file named name in the directory.
+func (d DirectorySecretStore) Secret(name string) (string, error) {
+	contents, err := os.ReadFile(filepath.Join(string(d), name))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read secret %q", name)
+	}
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+// MapSecretStore is a SecretStore which keeps secrets in memory.
+type MapSecretStore map[string]string
+
+// Secret returns the secret stored under name.
+func (m MapSecretStore) Secret(name string) (string, error) {
+	value, ok := m[name]
+	if !ok {
+		return "", errors.Errorf("no secret named %q", name)
+	}
+	return value, nil
+}
+
+// defaultSecretStore is used when a config has no SecretStore.
+var defaultSecretStore = DirectorySecretStore(filepath.Join(utils.ViamDotDir, "secrets"))
+
+// secretsMask replaces values resolved from file and secret placeholders where configs are logged.
+const secretsMask = "******"
+
 // ContainsPlaceholder returns true if the passed string contains a placeholder.
 func ContainsPlaceholder(s string) bool {
 	return placeholderRegexp.MatchString(s)
@@ -37,6 +97,17 @@ func ContainsPlaceholder(s string) bool {
 func (c *Config) ReplacePlaceholders() error {
 	var allErrs, err error
 	visitor := newPlaceholderReplacementVisitor(c)
+	// each resource's secret attributes are recorded separately, so only its own fields are redacted
+	recordSecrets := func(key string, attributes utils.AttributeMap) {
+		paths := secretAttributePaths(attributes, "")
+		if len(paths) == 0 {
+			return
+		}
+		if c.secretAttributes == nil {
+			c.secretAttributes = make(map[string][]string)
+		}
+		c.secretAttributes[key] = append(c.secretAttributes[key], paths...)
+	}
 
 	for i, service := range c.Services {
 		// this nil check may seem superfluous, however, the walking & casting will transform a
@@ -44,6 +115,7 @@ func (c *Config) ReplacePlaceholders() error {
 		if service.Attributes == nil {
 			continue
 		}
+		recordSecrets("services/"+service.Name, service.Attributes)
 		c.Services[i].Attributes, err = walkTypedAttributes(visitor, service.Attributes)
 		allErrs = multierr.Append(allErrs, err)
 	}
@@ -52,6 +124,7 @@ func (c *Config) ReplacePlaceholders() error {
 		if component.Attributes == nil {
 			continue
 		}
+		recordSecrets("components/"+component.Name, component.Attributes)
 		c.Components[i].Attributes, err = walkTypedAttributes(visitor, component.Attributes)
 		allErrs = multierr.Append(allErrs, err)
 	}
@@ -62,12 +135,130 @@ func (c *Config) ReplacePlaceholders() error {
 		for envName, envVal := range module.Environment {
 			c.Modules[i].Environment[envName], err = visitor.replacePlaceholders(envVal)
 			allErrs = multierr.Append(allErrs, err)
+			if visitor.resolvedSecret {
+				if c.Modules[i].secretEnvironment == nil {
+					c.Modules[i].secretEnvironment = make(map[string]bool)
+				}
+				c.Modules[i].secretEnvironment[envName] = true
+			}
 		}
 	}
 
 	return multierr.Append(visitor.AllErrors, allErrs)
 }
 
+// secretAttributePaths returns the paths, as JSON pointers below path, of the string values in data
+// with a file or secret placeholder. Replacing placeholders keeps the shape of the attributes, so
+// the values resolved from them are found at the same paths afterwards.
+func secretAttributePaths(data interface{}, path string) []string {
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.Map:
+		var paths []string
+		iter := v.MapRange()
+		for iter.Next() {
+			if iter.Key().Kind() != reflect.String {
+				continue
+			}
+			paths = append(paths, secretAttributePaths(iter.Value().Interface(), appendAttributePath(path, iter.Key().String()))...)
+		}
+		return paths
+	case reflect.Slice, reflect.Array:
+		var paths []string
+		for i := 0; i < v.Len(); i++ {
+			paths = append(paths, secretAttributePaths(v.Index(i).Interface(), appendAttributePath(path, strconv.Itoa(i)))...)
+		}
+		return paths
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return secretAttributePaths(v.Elem().Interface(), path)
+	case reflect.String:
+		if containsSecretPlaceholder(v.String()) {
+			return []string{path}
+		}
+	}
+	return nil
+}
+
+// containsSecretPlaceholder returns true if the string has a file or secret placeholder.
+func containsSecretPlaceholder(s string) bool {
+	for _, matches := range placeholderRegexp.FindAllStringSubmatch(s, -1) {
+		placeholderKey := matches[placeholderRegexp.SubexpIndex("placeholder_key")]
+		if typed := typedPlaceholderRegexp.FindStringSubmatch(matches[0]); typed != nil {
+			placeholderKey = typed[typedPlaceholderRegexp.SubexpIndex("placeholder_key")]
+		}
+		if filePlaceholderRegexp.MatchString(placeholderKey) || secretPlaceholderRegexp.MatchString(placeholderKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// attributePathEscaper escapes a key for use as a segment of a JSON pointer (RFC 6901).
+var attributePathEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// attributePathUnescaper reverses attributePathEscaper.
+var attributePathUnescaper = strings.NewReplacer("~1", "/", "~0", "~")
+
+func appendAttributePath(path, key string) string {
+	return path + "/" + attributePathEscaper.Replace(key)
+}
+
+// redactSecretAttributes masks the attributes of conf's components and services which were
+// resolved from file and secret placeholders, found at the paths in secretAttributes which is
+// keyed like Config.secretAttributes. Values elsewhere are logged as they are, even if they happen
+// to equal a secret.
+func redactSecretAttributes(conf *Config, secretAttributes map[string][]string) {
+	redact := func(prefix string, confs []resource.Config) {
+		for _, resConf := range confs {
+			if resConf.Attributes == nil {
+				continue
+			}
+			for _, path := range secretAttributes[prefix+resConf.Name] {
+				maskAttributePath(resConf.Attributes, path)
+			}
+		}
+	}
+	redact("components/", conf.Components)
+	redact("services/", conf.Services)
+}
+
+// maskAttributePath replaces the value at the given JSON pointer in attributes with secretsMask,
+// if there is one.
+func maskAttributePath(attributes utils.AttributeMap, path string) {
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	var parent interface{} = map[string]interface{}(attributes)
+	for i, segment := range segments {
+		segment = attributePathUnescaper.Replace(segment)
+		last := i == len(segments)-1
+		switch node := parent.(type) {
+		case map[string]interface{}:
+			if _, ok := node[segment]; !ok {
+				return
+			}
+			if last {
+				node[segment] = secretsMask
+				return
+			}
+			parent = node[segment]
+		case []interface{}:
+			idx, err := strconv.Atoi(segment)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return
+			}
+			if last {
+				node[idx] = secretsMask
+				return
+			}
+			parent = node[idx]
+		default:
+			return
+		}
+	}
+}
+
 func walkTypedAttributes[T any](visitor *placeholderReplacementVisitor, attributes T) (T, error) {
 	var asIfc interface{} = attributes
 	if walker, ok := asIfc.(utils.Walker); ok {
@@ -88,6 +279,10 @@ func walkTypedAttributes[T any](visitor *placeholderReplacementVisitor, attribut
 type placeholderReplacementVisitor struct {
 	// Map of packageName -> packageConfig
 	packages map[string]PackageConfig
+	// Store secret placeholders are looked up in
+	secretStore SecretStore
+	// Whether the last string replaced had a file or secret placeholder
+	resolvedSecret bool
 	// Accumulation of all that occurred during traversal
 	AllErrors error
 }
@@ -100,9 +295,15 @@ func newPlaceholderReplacementVisitor(cfg *Config) *placeholderReplacementVisito
 		packages[config.Name] = config
 	}
 
+	secretStore := cfg.SecretStore
+	if secretStore == nil {
+		secretStore = defaultSecretStore
+	}
+
 	return &placeholderReplacementVisitor{
-		packages:  packages,
-		AllErrors: nil,
+		packages:    packages,
+		secretStore: secretStore,
+		AllErrors:   nil,
 	}
 }
 
@@ -133,6 +334,18 @@ func (v *placeholderReplacementVisitor) Visit(data interface{}) (interface{}, er
 	if t.Kind() == reflect.Ptr {
 		return &withReplacedRefs, nil
 	}
+	// A typed placeholder making up the whole string is replaced with a value of its type.
+	if matches := typedPlaceholderRegexp.FindStringSubmatch(s); matches != nil && err == nil {
+		switch matches[typedPlaceholderRegexp.SubexpIndex("type")] {
+		case "number":
+			// validated by replacePlaceholders
+			value, _ := strconv.ParseFloat(withReplacedRefs, 64)
+			return value, nil
+		case "bool":
+			value, _ := strconv.ParseBool(withReplacedRefs)
+			return value, nil
+		}
+	}
 	return withReplacedRefs, nil
 }
 
@@ -144,6 +357,7 @@ func (v *placeholderReplacementVisitor) Visit(data interface{}) (interface{}, er
 // so that it is easy to add additional placeholder types in the future (like environment variables).
 func (v *placeholderReplacementVisitor) replacePlaceholders(s string) (string, error) {
 	var replacementErrors error
+	v.resolvedSecret = false
 	// First, match all possible placeholders (ex: ${hello})
 	patchedStr := placeholderRegexp.ReplaceAllFunc([]byte(s), func(placeholder []byte) []byte {
 		matches := placeholderRegexp.FindSubmatch(placeholder)
@@ -152,6 +366,11 @@ func (v *placeholderReplacementVisitor) replacePlaceholders(s string) (string, e
 			return placeholder
 		}
 		placeholderKey := matches[placeholderRegexp.SubexpIndex("placeholder_key")]
+		var valueType string
+		if typed := typedPlaceholderRegexp.FindSubmatch(placeholder); typed != nil {
+			placeholderKey = typed[typedPlaceholderRegexp.SubexpIndex("placeholder_key")]
+			valueType = string(typed[typedPlaceholderRegexp.SubexpIndex("type")])
+		}
 
 		var err error
 		var replacementResult string
@@ -161,13 +380,23 @@ func (v *placeholderReplacementVisitor) replacePlaceholders(s string) (string, e
 			replacementResult, err = v.replacePackagePlaceholder(string(placeholderKey))
 		case environmentPlaceholderRegexp.Match(placeholderKey):
 			replacementResult, err = v.replaceEnvironmentPlaceholder(string(placeholderKey))
+		case filePlaceholderRegexp.Match(placeholderKey):
+			replacementResult, err = v.replaceFilePlaceholder(string(placeholderKey))
+		case secretPlaceholderRegexp.Match(placeholderKey):
+			replacementResult, err = v.replaceSecretPlaceholder(string(placeholderKey))
 		default:
 			err = errors.Errorf("invalid placeholder %q", string(placeholder))
 		}
+		if err == nil {
+			err = checkPlaceholderType(string(placeholder), replacementResult, valueType)
+		}
 		if err != nil {
 			replacementErrors = multierr.Append(replacementErrors, err)
 			return placeholder
 		}
+		if filePlaceholderRegexp.Match(placeholderKey) || secretPlaceholderRegexp.Match(placeholderKey) {
+			v.resolvedSecret = true
+		}
 		return []byte(replacementResult)
 	})
 
@@ -212,3 +441,51 @@ func (v *placeholderReplacementVisitor) replaceEnvironmentPlaceholder(toReplace
 	}
 	return value, nil
 }
+
+func (v *placeholderReplacementVisitor) replaceFilePlaceholder(toReplace string) (string, error) {
+	matches := filePlaceholderRegexp.FindStringSubmatch(toReplace)
+	if matches == nil {
+		return toReplace, errors.Errorf("failed to find substring matches for %q", toReplace)
+	}
+	path, err := utils.ExpandHomeDir(matches[filePlaceholderRegexp.SubexpIndex("path")])
+	if err != nil {
+		return toReplace, err
+	}
+	//nolint:gosec
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return toReplace, errors.Wrapf(err, "failed to read file for placeholder %q", toReplace)
+	}
+	// files usually end in a newline which isn't part of the value, e.g. secrets written with echo
+	return strings.TrimRight(string(contents), "\r\n"), nil
+}
+
+func (v *placeholderReplacementVisitor) replaceSecretPlaceholder(toReplace string) (string, error) {
+	matches := secretPlaceholderRegexp.FindStringSubmatch(toReplace)
+	if matches == nil {
+		return toReplace, errors.Errorf("failed to find substring matches for %q", toReplace)
+	}
+	name := matches[secretPlaceholderRegexp.SubexpIndex("name")]
+	value, err := v.secretStore.Secret(name)
+	if err != nil {
+		return toReplace, errors.Wrapf(err, "failed to look up secret for placeholder %q", toReplace)
+	}
+	return value, nil
+}
+
+// checkPlaceholderType returns an error if the value of a typed placeholder doesn't parse as its type.
+func checkPlaceholderType(placeholder, value, valueType string) error {
+	var err error
+	switch valueType {
+	case "":
+		return nil
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return errors.Errorf("placeholder %q should be a %s", placeholder, valueType)
+	}
+	return nil
+}
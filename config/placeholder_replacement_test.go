@@ -204,4 +204,89 @@ func TestPlaceholderReplacement(t *testing.T) {
 		err = cfg.ReplacePlaceholders()
 		test.That(t, fmt.Sprint(err), test.ShouldContainSubstring, "VIAM_UNDEFINED_TEST_VAR")
 	})
+	t.Run("file and secret placeholder replacement", func(t *testing.T) {
+		secretPath := filepath.Join(t.TempDir(), "api_key")
+		test.That(t, os.WriteFile(secretPath, []byte("file-secret\n"), 0o600), test.ShouldBeNil)
+		cfg := &config.Config{
+			SecretStore: config.MapSecretStore{"db.password": "hunter2"},
+			Components: []resource.Config{
+				{
+					Attributes: utils.AttributeMap{
+						"key":      "${file:" + secretPath + "}",
+						"password": "user:${secret:db.password}",
+					},
+				},
+			},
+			Modules: []config.Module{
+				{
+					Environment: map[string]string{
+						"DB_PASSWORD": "${secret:db.password}",
+					},
+				},
+			},
+		}
+		err := cfg.ReplacePlaceholders()
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, cfg.Components[0].Attributes["key"], test.ShouldEqual, "file-secret")
+		test.That(t, cfg.Components[0].Attributes["password"], test.ShouldEqual, "user:hunter2")
+		test.That(t, cfg.Modules[0].Environment["DB_PASSWORD"], test.ShouldEqual, "hunter2")
+		test.That(t, cfg.Modules[0].RedactEnvironment(map[string]string{"DB_PASSWORD": "hunter2", "OTHER": "hunter2"}),
+			test.ShouldResemble, map[string]string{"DB_PASSWORD": "******", "OTHER": "hunter2"})
+
+		// secrets can also be kept in a directory
+		dir := t.TempDir()
+		test.That(t, os.WriteFile(filepath.Join(dir, "token"), []byte("dir-secret\n"), 0o600), test.ShouldBeNil)
+		cfg = &config.Config{
+			SecretStore: config.DirectorySecretStore(dir),
+			Components: []resource.Config{
+				{
+					Attributes: utils.AttributeMap{
+						"token":   "${secret:token}",
+						"missing": "${secret:missing}",
+						"file":    "${file:" + filepath.Join(dir, "missing") + "}",
+					},
+				},
+			},
+		}
+		err = cfg.ReplacePlaceholders()
+		test.That(t, fmt.Sprint(err), test.ShouldContainSubstring, "secret:missing")
+		test.That(t, fmt.Sprint(err), test.ShouldContainSubstring, "failed to read file")
+		test.That(t, cfg.Components[0].Attributes["token"], test.ShouldEqual, "dir-secret")
+		test.That(t, cfg.Components[0].Attributes["missing"], test.ShouldEqual, "${secret:missing}")
+	})
+	t.Run("typed placeholder replacement", func(t *testing.T) {
+		t.Setenv("VIAM_TEST_PORT", "8080")
+		t.Setenv("VIAM_TEST_TLS", "true")
+		cfg := &config.Config{
+			Components: []resource.Config{
+				{
+					Attributes: utils.AttributeMap{
+						"port":        "${environment.VIAM_TEST_PORT|number}",
+						"tls":         "${environment.VIAM_TEST_TLS|bool}",
+						"address":     "localhost:${environment.VIAM_TEST_PORT|number}",
+						"untyped":     "${environment.VIAM_TEST_PORT}",
+						"not_a_bool":  "${environment.VIAM_TEST_PORT|bool}",
+						"nested_list": []interface{}{"${environment.VIAM_TEST_PORT|number}"},
+					},
+				},
+			},
+			Modules: []config.Module{
+				{
+					Environment: map[string]string{
+						"PORT": "${environment.VIAM_TEST_PORT|number}",
+					},
+				},
+			},
+		}
+		err := cfg.ReplacePlaceholders()
+		test.That(t, fmt.Sprint(err), test.ShouldContainSubstring, "should be a bool")
+		attrMap := cfg.Components[0].Attributes
+		test.That(t, attrMap["port"], test.ShouldEqual, 8080.0)
+		test.That(t, attrMap["tls"], test.ShouldEqual, true)
+		test.That(t, attrMap["address"], test.ShouldEqual, "localhost:8080")
+		test.That(t, attrMap["untyped"], test.ShouldEqual, "8080")
+		test.That(t, attrMap["not_a_bool"], test.ShouldEqual, "${environment.VIAM_TEST_PORT|bool}")
+		test.That(t, attrMap["nested_list"], test.ShouldResemble, []interface{}{8080.0})
+		test.That(t, cfg.Modules[0].Environment["PORT"], test.ShouldEqual, "8080")
+	})
 }
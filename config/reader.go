@@ -189,6 +189,7 @@ func readFromCloud(
 	}
 
 	// process the config
+	unprocessedConfig.SecretStore = originalCfg.SecretStore
 	cfg, err := processConfigFromCloud(unprocessedConfig, logger)
 	if err != nil {
 		// If we cannot process the config from the cache we should clear it.
@@ -480,6 +481,11 @@ func additionalModuleEnvVars(cloud *Cloud, auth AuthConfig, tracing TracingConfi
 // If any part of this function errors, the function will exit and no part of the new config will be returned
 // until it is corrected.
 func processConfig(unprocessedConfig *Config, fromCloud bool, logger logging.Logger) (*Config, error) {
+	// Templates are expanded first so their resources are validated and processed like any other.
+	if err := unprocessedConfig.ExpandTemplates(); err != nil {
+		return nil, err
+	}
+
 	// Ensure validates the config but also substitutes in some defaults. Implicit dependencies for builtin resource
 	// models are not filled in until attributes are converted.
 	if err := unprocessedConfig.Ensure(fromCloud, logger); err != nil {
@@ -498,6 +504,9 @@ func processConfig(unprocessedConfig *Config, fromCloud bool, logger logging.Log
 	// to pass it along manually. ConfigFilePath needs to be preserved so the correct config watcher can
 	// be instantiated later in the flow.
 	cfg.ConfigFilePath = unprocessedConfig.ConfigFilePath
+	cfg.OverlayFilePath = unprocessedConfig.OverlayFilePath
+	cfg.SecretStore = unprocessedConfig.SecretStore
+	cfg.templateOrigins = unprocessedConfig.templateOrigins
 
 	// replacement can happen in resource attributes and in the module config. look at config/placeholder_replace.go
 	// for available substitution types.
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// templateParamRegexp matches on the parameters of a template
+// Example strings satisfying the regex:
+// {{index}}
+// {{ pin }}.
+var templateParamRegexp = regexp.MustCompile(`\{\{\s*(?P<name>\w+)\s*\}\}`)
+
+// A Template is a block of resources which is repeated once per value in ForEach, for configs
+// with many identical resources, e.g. motor and encoder pairs. Each string in the resources can
+// refer to the parameters of the instance with {{name}}. When a value is an object its fields are
+// the parameters, otherwise the value is the parameter "value". The position of the value in
+// ForEach is always the parameter "index". A string which is only a parameter is replaced with
+// the value of the parameter as is, so numbers and bools keep their type.
+//
+//	{
+//	  "name": "motors",
+//	  "for_each": [{"side": "left", "pin": 11}, {"side": "right", "pin": 13}],
+//	  "components": [{"name": "motor-{{side}}", "api": "rdk:component:motor", "model": "gpio",
+//	    "attributes": {"pins": {"pwm": "{{pin}}"}}}]
+//	}
+type Template struct {
+	Name       string           `json:"name"`
+	ForEach    []any            `json:"for_each"`
+	Components []map[string]any `json:"components,omitempty"`
+	Services   []map[string]any `json:"services,omitempty"`
+}
+
+// ExpandTemplates appends the resources of every instance of every template to the components
+// and services of the config and removes the templates. Validation errors of the expanded resources
+// have the path of the template instance which produced them.
+func (c *Config) ExpandTemplates() error {
+	for idx, tmpl := range c.Templates {
+		path := fmt.Sprintf("%s.%d", "templates", idx)
+		if tmpl.Name == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "name")
+		}
+		for instanceIdx, value := range tmpl.ForEach {
+			instancePath := fmt.Sprintf("%s.%s.%d", path, "for_each", instanceIdx)
+			params := map[string]any{"index": instanceIdx}
+			if fields, ok := value.(map[string]any); ok {
+				for name, field := range fields {
+					params[name] = field
+				}
+			} else {
+				params["value"] = value
+			}
+
+			components, err := expandTemplateResources(
+				tmpl.Components, params, instancePath, "components", resource.APITypeComponentName)
+			if err != nil {
+				return errors.Wrapf(err, "error expanding template %q", tmpl.Name)
+			}
+			for resIdx, conf := range components {
+				c.addTemplateOrigin("components", conf.Name, fmt.Sprintf("%s.%s.%d", instancePath, "components", resIdx))
+			}
+			c.Components = append(c.Components, components...)
+
+			services, err := expandTemplateResources(
+				tmpl.Services, params, instancePath, "services", resource.APITypeServiceName)
+			if err != nil {
+				return errors.Wrapf(err, "error expanding template %q", tmpl.Name)
+			}
+			for resIdx, conf := range services {
+				c.addTemplateOrigin("services", conf.Name, fmt.Sprintf("%s.%s.%d", instancePath, "services", resIdx))
+			}
+			c.Services = append(c.Services, services...)
+		}
+	}
+	c.Templates = nil
+	return nil
+}
+
+func (c *Config) addTemplateOrigin(section, name, path string) {
+	if c.templateOrigins == nil {
+		c.templateOrigins = make(map[string]string)
+	}
+	c.templateOrigins[section+"/"+name] = path
+}
+
+// resourcePath returns the path used in validation errors of a component or service, which is the
+// template instance which produced it for resources from templates.
+func (c *Config) resourcePath(section string, idx int, name string) string {
+	if origin, ok := c.templateOrigins[section+"/"+name]; ok {
+		return origin
+	}
+	return fmt.Sprintf("%s.%d", section, idx)
+}
+
+func expandTemplateResources(
+	blocks []map[string]any, params map[string]any, instancePath, section, apiType string,
+) ([]resource.Config, error) {
+	confs := make([]resource.Config, 0, len(blocks))
+	for idx, block := range blocks {
+		path := fmt.Sprintf("%s.%s.%d", instancePath, section, idx)
+		expanded, err := substituteTemplateParams(block, params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error validating %q", path)
+		}
+		md, err := json.Marshal(expanded)
+		if err != nil {
+			return nil, err
+		}
+		var conf resource.Config
+		if err := json.Unmarshal(md, &conf); err != nil {
+			return nil, errors.Wrapf(err, "error validating %q", path)
+		}
+		conf.AdjustPartialNames(apiType)
+		confs = append(confs, conf)
+	}
+	return confs, nil
+}
+
+// substituteTemplateParams returns a copy of value with the parameters in all of its strings replaced.
+func substituteTemplateParams(value any, params map[string]any) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return substituteTemplateString(v, params)
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, field := range v {
+			newKey, err := substituteTemplateString(key, params)
+			if err != nil {
+				return nil, err
+			}
+			newField, err := substituteTemplateParams(field, params)
+			if err != nil {
+				return nil, err
+			}
+			out[fmt.Sprint(newKey)] = newField
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, elem := range v {
+			newElem, err := substituteTemplateParams(elem, params)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = newElem
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+func substituteTemplateString(s string, params map[string]any) (any, error) {
+	if matches := templateParamRegexp.FindStringSubmatch(s); matches != nil && matches[0] == s {
+		value, ok := params[matches[1]]
+		if !ok {
+			return nil, errors.Errorf("template has no parameter %q", matches[1])
+		}
+		return value, nil
+	}
+	var err error
+	replaced := templateParamRegexp.ReplaceAllStringFunc(s, func(param string) string {
+		name := templateParamRegexp.FindStringSubmatch(param)[1]
+		value, ok := params[name]
+		if !ok {
+			err = errors.Errorf("template has no parameter %q", name)
+			return param
+		}
+		if f, ok := value.(float64); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64)
+		}
+		return fmt.Sprint(value)
+	})
+	return replaced, err
+}
@@ -0,0 +1,115 @@
+package config_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	rutils "go.viam.com/rdk/utils"
+)
+
+func TestExpandTemplates(t *testing.T) {
+	cfg := &config.Config{
+		Components: []resource.Config{{Name: "base", API: motor.API, Model: fakeModel}},
+		Templates: []config.Template{
+			{
+				Name: "pairs",
+				ForEach: []any{
+					map[string]any{"side": "left", "pin": 11.0},
+					map[string]any{"side": "right", "pin": 13.0},
+				},
+				Components: []map[string]any{
+					{
+						"name":  "encoder-{{side}}",
+						"api":   "rdk:component:encoder",
+						"model": "fake",
+					},
+					{
+						"name":       "motor-{{side}}",
+						"api":        "rdk:component:motor",
+						"model":      "fake",
+						"depends_on": []any{"encoder-{{side}}"},
+						"attributes": map[string]any{
+							"encoder": "encoder-{{ side }}",
+							"pin":     "{{pin}}",
+							"label":   "{{side}} motor on pin {{pin}}, #{{index}}",
+						},
+					},
+				},
+			},
+			{
+				Name:    "cameras",
+				ForEach: []any{"front", "back"},
+				Services: []map[string]any{
+					{"name": "detector-{{value}}", "api": "rdk:service:vision", "model": "fake"},
+				},
+			},
+		},
+	}
+	test.That(t, cfg.ExpandTemplates(), test.ShouldBeNil)
+	test.That(t, cfg.Templates, test.ShouldBeNil)
+
+	names := []string{}
+	for _, conf := range cfg.Components {
+		names = append(names, conf.Name)
+	}
+	test.That(t, names, test.ShouldResemble, []string{"base", "encoder-left", "motor-left", "encoder-right", "motor-right"})
+	test.That(t, cfg.Components[1].API, test.ShouldResemble, encoder.API)
+	test.That(t, cfg.Components[1].Model, test.ShouldResemble, fakeModel)
+	test.That(t, cfg.Components[4].DependsOn, test.ShouldResemble, []string{"encoder-right"})
+	test.That(t, cfg.Components[4].Attributes, test.ShouldResemble, rutils.AttributeMap{
+		"encoder": "encoder-right",
+		"pin":     13.0,
+		"label":   "right motor on pin 13, #1",
+	})
+	test.That(t, len(cfg.Services), test.ShouldEqual, 2)
+	test.That(t, cfg.Services[0].Name, test.ShouldEqual, "detector-front")
+	test.That(t, cfg.Services[1].Name, test.ShouldEqual, "detector-back")
+
+	cfg = &config.Config{Templates: []config.Template{{ForEach: []any{1.0}}}}
+	test.That(t, cfg.ExpandTemplates(), test.ShouldBeError,
+		resource.NewConfigValidationFieldRequiredError("templates.0", "name"))
+
+	cfg = &config.Config{Templates: []config.Template{{
+		Name:       "typo",
+		ForEach:    []any{1.0},
+		Components: []map[string]any{{"name": "motor-{{vaule}}"}},
+	}}}
+	err := cfg.ExpandTemplates()
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `template "typo"`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "templates.0.for_each.0.components.0")
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no parameter "vaule"`)
+}
+
+func TestTemplateValidationErrors(t *testing.T) {
+	logger, logs := logging.NewObservedTestLogger(t)
+	cfgText := `{
+		"templates": [{
+			"name": "motors",
+			"for_each": ["left", ""],
+			"components": [{"name": "{{value}}", "api": "rdk:component:motor", "model": "fake"}]
+		}]
+	}`
+	cfg, err := config.FromReader(context.Background(), "", strings.NewReader(cfgText), logger, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(cfg.Components), test.ShouldEqual, 2)
+
+	// the second motor has no name, the error points at the instance which produced it
+	found := false
+	for _, entry := range logs.All() {
+		for _, field := range entry.Context {
+			if field.Key == "error" && strings.Contains(field.String, `"templates.0.for_each.1.components.0"`) {
+				found = true
+			}
+		}
+	}
+	test.That(t, found, test.ShouldBeTrue)
+}
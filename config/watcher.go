@@ -8,6 +8,7 @@ import (
 
 	"github.com/bep/debounce"
 	"github.com/fsnotify/fsnotify"
+	"go.uber.org/multierr"
 	"go.viam.com/utils"
 	"go.viam.com/utils/rpc"
 
@@ -26,18 +27,30 @@ type Watcher interface {
 }
 
 // NewWatcher returns an optimally selected Watcher based on the
-// given config.
+// given config. When the config has an overlay file, the overlay is
+// applied to every config delivered.
 func NewWatcher(ctx context.Context, config *Config, logger logging.Logger, conn rpc.ClientConn) (Watcher, error) {
 	if err := config.Ensure(false, logger); err != nil {
 		return nil, err
 	}
+	var watcher Watcher = noopWatcher{}
 	if config.Cloud != nil {
-		return newCloudWatcher(ctx, config, logger, conn), nil
+		watcher = newCloudWatcher(ctx, config, logger, conn)
+	} else if config.ConfigFilePath != "" {
+		fsWatcher, err := newFSWatcher(ctx, config.ConfigFilePath, logger, conn)
+		if err != nil {
+			return nil, err
+		}
+		watcher = fsWatcher
 	}
-	if config.ConfigFilePath != "" {
-		return newFSWatcher(ctx, config.ConfigFilePath, logger, conn)
+	if config.OverlayFilePath != "" {
+		overlayWatcher, err := newOverlayWatcher(ctx, watcher, config, logger)
+		if err != nil {
+			return nil, multierr.Combine(err, watcher.Close())
+		}
+		return overlayWatcher, nil
 	}
-	return noopWatcher{}, nil
+	return watcher, nil
 }
 
 // A cloudWatcher periodically fetches new configs from the cloud.
@@ -22,6 +22,9 @@ const (
 	blockEncoderToRPM               controlBlockType = "encoderToRpm"
 	blockEndpoint                   controlBlockType = "endpoint"
 	blockFilter                     controlBlockType = "filter"
+	blockSaturation                 controlBlockType = "saturation"
+	blockDeadband                   controlBlockType = "deadband"
+	blockFeedForward                controlBlockType = "feedforward"
 )
 
 // BlockConfig configuration of a given block.
@@ -107,6 +110,24 @@ func (l *Loop) createBlock(cfg BlockConfig, logger logging.Logger) (Block, error
 			return nil, err
 		}
 		return b, nil
+	case blockSaturation:
+		b, err := newSaturation(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	case blockDeadband:
+		b, err := newDeadband(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
+	case blockFeedForward:
+		b, err := newFeedForward(cfg, logger)
+		if err != nil {
+			return nil, err
+		}
+		return b, nil
 	}
 	return nil, errors.Errorf("unsupported block type %s", t)
 }
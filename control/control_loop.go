@@ -41,6 +41,8 @@ type Loop struct {
 	cancel                  context.CancelFunc
 	running                 atomic.Bool
 	pidBlocks               []*basicPID
+	tuneMu                  sync.Mutex
+	tuneLevel               int
 }
 
 // NewLoop construct a new control loop for a specific endpoint.
@@ -82,6 +84,7 @@ func createLoop(logger logging.Logger, cfg Config, m Controllable) (*Loop, error
 			b.ins = append(b.ins, blockDep.outs[len(blockDep.outs)-1])
 		}
 	}
+	l.startCascadeTuning()
 	for _, b := range l.blocks {
 		if len(b.blk.Config(l.cancelCtx).DependsOn) == 0 || b.blk.Config(l.cancelCtx).Type == blockEndpoint {
 			waitCh := make(chan struct{})
@@ -118,7 +121,6 @@ func createLoop(logger logging.Logger, cfg Config, m Controllable) (*Loop, error
 				close(waitCh)
 				for {
 					sw := []*Signal{}
-					s := []*Signal{}
 					for _, c := range b.ins {
 						r, ok := <-c
 						if !ok {
@@ -137,17 +139,7 @@ func createLoop(logger logging.Logger, cfg Config, m Controllable) (*Loop, error
 						}
 						// TODO(npmenard) do we want to support multidimentional signals?
 					}
-					if strings.Contains(b.blk.Config(l.cancelCtx).Name, "PID") {
-						if strings.Contains(b.blk.Config(l.cancelCtx).Name, "ang") {
-							s = append(s, sw[1])
-						} else {
-							s = append(s, sw[0])
-						}
-					} else {
-						s = sw
-					}
-
-					v, ok := b.blk.Next(l.cancelCtx, s, l.dt)
+					v, ok := b.blk.Next(l.cancelCtx, blockInputs(b.blk.Config(l.cancelCtx).Name, sw), l.dt)
 					if ok {
 						for _, out := range b.outs {
 							out <- v
@@ -161,6 +153,18 @@ func createLoop(logger logging.Logger, cfg Config, m Controllable) (*Loop, error
 	return &l, nil
 }
 
+// blockInputs selects the inputs of a block from the signals of its dependencies. A PID block only
+// takes the signal it controls, the second one when its name says it's the angular PID of a base.
+func blockInputs(name string, sw []*Signal) []*Signal {
+	if strings.Contains(name, "PID") {
+		if strings.Contains(name, "ang") {
+			return []*Signal{sw[1]}
+		}
+		return []*Signal{sw[0]}
+	}
+	return sw
+}
+
 // OutputAt returns the Signal at the block name, error when the block doesn't exist.
 func (l *Loop) OutputAt(ctx context.Context, name string) ([]*Signal, error) {
 	blk, ok := l.blocks[name]
@@ -318,6 +322,7 @@ func (l *Loop) Resume() {
 				return
 			}
 		}
+		l.startCascadeTuning()
 	}
 	l.running.Store(true)
 }
@@ -371,3 +376,12 @@ func (l *Loop) GetTuning(ctx context.Context) bool {
 	}
 	return false
 }
+
+// TuningResults returns the results of the PID signals auto-tuned so far, ordered by block and signal.
+func (l *Loop) TuningResults() []TuningResult {
+	var results []TuningResult
+	for _, b := range l.pidBlocks {
+		results = append(results, b.TuningResults()...)
+	}
+	return results
+}
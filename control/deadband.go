@@ -0,0 +1,88 @@
+package control
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// deadband outputs zero while its input is within width of zero, which stops a loop from hunting
+// around a set point it can't hold exactly. When continuous is set, inputs outside the band are
+// shifted towards zero by width so the output has no jump at the edge of the band.
+type deadband struct {
+	mu         sync.Mutex
+	cfg        BlockConfig
+	y          []*Signal
+	width      float64
+	continuous bool
+	logger     logging.Logger
+}
+
+func newDeadband(config BlockConfig, logger logging.Logger) (Block, error) {
+	d := &deadband{cfg: config, logger: logger}
+	if err := d.reset(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (b *deadband) Next(ctx context.Context, x []*Signal, dt time.Duration) ([]*Signal, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(x) != 1 {
+		return b.y, false
+	}
+	in := x[0].GetSignalValueAt(0)
+	out := 0.0
+	if math.Abs(in) > b.width {
+		out = in
+		if b.continuous {
+			out -= math.Copysign(b.width, in)
+		}
+	}
+	b.y[0].SetSignalValueAt(0, out)
+	return b.y, true
+}
+
+func (b *deadband) reset() error {
+	if !b.cfg.Attribute.Has("width") {
+		return errors.Errorf("deadband block %s doesn't have a width field", b.cfg.Name)
+	}
+	if len(b.cfg.DependsOn) != 1 {
+		return errors.Errorf("invalid number of inputs for deadband block %s expected 1 got %d", b.cfg.Name, len(b.cfg.DependsOn))
+	}
+	b.width = b.cfg.Attribute["width"].(float64)
+	if b.width < 0 {
+		return errors.Errorf("deadband block %s width can't be negative", b.cfg.Name)
+	}
+	b.continuous = b.cfg.Attribute.Bool("continuous", false)
+	b.y = make([]*Signal, 1)
+	b.y[0] = makeSignal(b.cfg.Name, b.cfg.Type)
+	return nil
+}
+
+func (b *deadband) Reset(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reset()
+}
+
+func (b *deadband) UpdateConfig(ctx context.Context, config BlockConfig) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = config
+	return b.reset()
+}
+
+func (b *deadband) Output(ctx context.Context) []*Signal {
+	return b.y
+}
+
+func (b *deadband) Config(ctx context.Context) BlockConfig {
+	return b.cfg
+}
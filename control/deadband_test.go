@@ -0,0 +1,113 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+func TestDeadbandConfig(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	for _, c := range []struct {
+		conf BlockConfig
+		err  string
+	}{
+		{
+			BlockConfig{
+				Name: "Deadband1",
+				Type: "deadband",
+				Attribute: utils.AttributeMap{
+					"width": 0.5,
+				},
+				DependsOn: []string{"A"},
+			},
+			"",
+		},
+		{
+			BlockConfig{
+				Name: "Deadband1",
+				Type: "deadband",
+				Attribute: utils.AttributeMap{
+					"widht": 0.5,
+				},
+				DependsOn: []string{"A"},
+			},
+			"deadband block Deadband1 doesn't have a width field",
+		},
+		{
+			BlockConfig{
+				Name: "Deadband1",
+				Type: "deadband",
+				Attribute: utils.AttributeMap{
+					"width": -0.5,
+				},
+				DependsOn: []string{"A"},
+			},
+			"deadband block Deadband1 width can't be negative",
+		},
+		{
+			BlockConfig{
+				Name: "Deadband1",
+				Type: "deadband",
+				Attribute: utils.AttributeMap{
+					"width": 0.5,
+				},
+				DependsOn: []string{"A", "B"},
+			},
+			"invalid number of inputs for deadband block Deadband1 expected 1 got 2",
+		},
+	} {
+		b, err := newDeadband(c.conf, logger)
+		if c.err == "" {
+			s := b.(*deadband)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(s.y), test.ShouldEqual, 1)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldResemble, c.err)
+		}
+	}
+}
+
+func TestDeadbandNext(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	for _, continuous := range []bool{false, true} {
+		c := BlockConfig{
+			Name: "Deadband1",
+			Type: "deadband",
+			Attribute: utils.AttributeMap{
+				"width":      0.5,
+				"continuous": continuous,
+			},
+			DependsOn: []string{"A"},
+		}
+		d, err := newDeadband(c, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		signals := []*Signal{makeSignal("A", blockSum)}
+		for _, tc := range []struct {
+			in, out, continuousOut float64
+		}{
+			{0.0, 0.0, 0.0},
+			{0.3, 0.0, 0.0},
+			{-0.5, 0.0, 0.0},
+			{2.0, 2.0, 1.5},
+			{-1.5, -1.5, -1.0},
+		} {
+			signals[0].SetSignalValueAt(0, tc.in)
+			out, ok := d.Next(ctx, signals, time.Millisecond)
+			test.That(t, ok, test.ShouldBeTrue)
+			if continuous {
+				test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, tc.continuousOut)
+			} else {
+				test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, tc.out)
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package control
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// feedForward computes the output needed to follow its input set point from a model of the plant,
+// ks*sign(r) + kv*r + ka*dr/dt, so that a PID summed with it only has to correct the model error.
+// ks overcomes static friction, kv is the output per unit of set point and ka the output per unit
+// of set point change per second.
+type feedForward struct {
+	mu     sync.Mutex
+	cfg    BlockConfig
+	y      []*Signal
+	ks     float64
+	kv     float64
+	ka     float64
+	prev   float64
+	primed bool
+	logger logging.Logger
+}
+
+func newFeedForward(config BlockConfig, logger logging.Logger) (Block, error) {
+	f := &feedForward{cfg: config, logger: logger}
+	if err := f.reset(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b *feedForward) Next(ctx context.Context, x []*Signal, dt time.Duration) ([]*Signal, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(x) != 1 {
+		return b.y, false
+	}
+	r := x[0].GetSignalValueAt(0)
+	out := b.kv * r
+	if r != 0 {
+		out += math.Copysign(b.ks, r)
+	}
+	if b.primed && dt > 0 {
+		out += b.ka * (r - b.prev) / dt.Seconds()
+	}
+	b.prev = r
+	b.primed = true
+	b.y[0].SetSignalValueAt(0, out)
+	return b.y, true
+}
+
+func (b *feedForward) reset() error {
+	if !b.cfg.Attribute.Has("ks") && !b.cfg.Attribute.Has("kv") && !b.cfg.Attribute.Has("ka") {
+		return errors.Errorf("feedforward block %s should have a ks, kv or ka field", b.cfg.Name)
+	}
+	if len(b.cfg.DependsOn) != 1 {
+		return errors.Errorf("invalid number of inputs for feedforward block %s expected 1 got %d", b.cfg.Name, len(b.cfg.DependsOn))
+	}
+	b.ks = b.cfg.Attribute.Float64("ks", 0)
+	b.kv = b.cfg.Attribute.Float64("kv", 0)
+	b.ka = b.cfg.Attribute.Float64("ka", 0)
+	b.prev = 0
+	b.primed = false
+	b.y = make([]*Signal, 1)
+	b.y[0] = makeSignal(b.cfg.Name, b.cfg.Type)
+	return nil
+}
+
+func (b *feedForward) Reset(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reset()
+}
+
+func (b *feedForward) UpdateConfig(ctx context.Context, config BlockConfig) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = config
+	return b.reset()
+}
+
+func (b *feedForward) Output(ctx context.Context) []*Signal {
+	return b.y
+}
+
+func (b *feedForward) Config(ctx context.Context) BlockConfig {
+	return b.cfg
+}
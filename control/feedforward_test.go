@@ -0,0 +1,105 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+func TestFeedForwardConfig(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	for _, c := range []struct {
+		conf BlockConfig
+		err  string
+	}{
+		{
+			BlockConfig{
+				Name: "FeedForward1",
+				Type: "feedforward",
+				Attribute: utils.AttributeMap{
+					"kv": 0.5,
+					"ks": 10.0,
+				},
+				DependsOn: []string{"A"},
+			},
+			"",
+		},
+		{
+			BlockConfig{
+				Name: "FeedForward1",
+				Type: "feedforward",
+				Attribute: utils.AttributeMap{
+					"gain": 0.5,
+				},
+				DependsOn: []string{"A"},
+			},
+			"feedforward block FeedForward1 should have a ks, kv or ka field",
+		},
+		{
+			BlockConfig{
+				Name: "FeedForward1",
+				Type: "feedforward",
+				Attribute: utils.AttributeMap{
+					"kv": 0.5,
+				},
+				DependsOn: []string{"A", "B"},
+			},
+			"invalid number of inputs for feedforward block FeedForward1 expected 1 got 2",
+		},
+	} {
+		b, err := newFeedForward(c.conf, logger)
+		if c.err == "" {
+			s := b.(*feedForward)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(s.y), test.ShouldEqual, 1)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldResemble, c.err)
+		}
+	}
+}
+
+func TestFeedForwardNext(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	c := BlockConfig{
+		Name: "FeedForward1",
+		Type: "feedforward",
+		Attribute: utils.AttributeMap{
+			"ks": 10.0,
+			"kv": 0.5,
+			"ka": 0.1,
+		},
+		DependsOn: []string{"A"},
+	}
+	f, err := newFeedForward(c, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	signals := []*Signal{makeSignal("A", blockConstant)}
+	dt := 100 * time.Millisecond
+
+	// no output to hold a zero set point
+	out, ok := f.Next(ctx, signals, dt)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, 0)
+
+	// accelerating to 100 in 0.1s adds 0.1 * 1000
+	signals[0].SetSignalValueAt(0, 100)
+	out, _ = f.Next(ctx, signals, dt)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldAlmostEqual, 10+50+100)
+
+	// holding 100
+	out, _ = f.Next(ctx, signals, dt)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldAlmostEqual, 10+50)
+
+	// static friction opposes the direction of motion
+	signals[0].SetSignalValueAt(0, -100)
+	test.That(t, f.Reset(ctx), test.ShouldBeNil)
+	out, _ = f.Next(ctx, signals, dt)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldAlmostEqual, -10-50)
+}
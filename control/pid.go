@@ -4,6 +4,7 @@ import (
 	"context"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -29,6 +30,15 @@ type basicPID struct {
 	// MIMO gains + state
 	PIDSets []*PIDConfig
 	tuners  []*pidTuner
+	results []*TuningResult
+
+	// cascade tuning state, see Loop.startCascadeTuning. tuneLevel and onTuned are written while
+	// holding both mu and the loop's tuneMu, so either is enough to read them. mu is always taken
+	// after tuneMu, never the other way around.
+	tuneLevel  int
+	tuningHeld atomic.Bool
+	tuned      atomic.Bool
+	onTuned    func()
 
 	// used by both
 	y        []*Signal
@@ -36,8 +46,25 @@ type basicPID struct {
 	limUp    float64 `default:"255.0"`
 	satLimLo float64
 	limLo    float64
+
+	antiWindup     antiWindupMethod
+	antiWindupGain float64
 }
 
+// antiWindupMethod selects how the integral is kept from winding up while the output saturates.
+type antiWindupMethod string
+
+const (
+	// antiWindupClamping only clamps the integral to [int_sat_lim_lo, int_sat_lim_up].
+	antiWindupClamping antiWindupMethod = "clamping"
+	// antiWindupConditional also stops integrating while the output is saturated and the error
+	// would drive it further into saturation.
+	antiWindupConditional antiWindupMethod = "conditional"
+	// antiWindupBackCalculation also bleeds the integral by anti_windup_gain, I/P by default, times
+	// the amount the output was saturated by.
+	antiWindupBackCalculation antiWindupMethod = "back_calculation"
+)
+
 // GetTuning returns whether the PID block is currently tuning any signals.
 func (p *basicPID) GetTuning() bool {
 	// using locks to prevent reading from tuners while the object is being modified
@@ -61,9 +88,22 @@ func (p *basicPID) getTuning() bool {
 // setPoint is the desired value, measured is the measured value.
 // Returns false when the output is invalid (the integral is saturating) in this case continue to use the last valid value.
 func (p *basicPID) Next(ctx context.Context, x []*Signal, dt time.Duration) ([]*Signal, bool) {
+	var onTuned func()
 	p.mu.Lock()
-	defer p.mu.Unlock()
-	if p.getTuning() {
+	defer func() {
+		p.mu.Unlock()
+		// onTuned takes the loop's tuneMu, which is held while taking mu, so only call it once mu
+		// is released
+		if onTuned != nil {
+			onTuned()
+		}
+	}()
+	if p.getTuning() && p.tuningHeld.Load() {
+		// an inner block of the cascade is still tuning, leave this one's output at zero until then
+		for i := 0; i < len(p.PIDSets); i++ {
+			p.y[0].SetSignalValueAt(i, 0)
+		}
+	} else if p.getTuning() {
 		// Multi Input/Output Implementation
 
 		// For each PID Set and its respective Tuner Object, Step through an iteration of tuning until done.
@@ -82,6 +122,16 @@ func (p *basicPID) Next(ctx context.Context, x []*Signal, dt time.Duration) ([]*
 					i, p.PIDSets[i].P, p.PIDSets[i].I, p.PIDSets[i].D)
 				p.logger.CInfof(ctx, "You must MANUALLY ADD p, i and d gains to the robot config to use the values after tuning\n\n")
 				p.tuners[i].tuning = false
+				result := p.tuners[i].result(p.cfg.Name, i)
+				result.Type = p.PIDSets[i].Type
+				p.results[i] = &result
+				if !result.Converged {
+					p.logger.CWarnf(ctx, "tuning signal %v of %s did not converge, the calculated gains should not be used", i, p.cfg.Name)
+				}
+				if !p.getTuning() {
+					p.tuned.Store(true)
+					onTuned = p.onTuned
+				}
 			}
 			p.y[0].SetSignalValueAt(i, out)
 			// return early to only step this signal
@@ -100,6 +150,7 @@ func (p *basicPID) Next(ctx context.Context, x []*Signal, dt time.Duration) ([]*
 func calculateSignalValue(p *basicPID, x []*Signal, dt time.Duration, sIndex int) float64 {
 	dtS := dt.Seconds()
 	pvError := x[0].GetSignalValueAt(sIndex)
+	prevInt := p.PIDSets[sIndex].int
 	p.PIDSets[sIndex].int += p.PIDSets[sIndex].I * pvError * dtS
 
 	switch {
@@ -110,14 +161,32 @@ func calculateSignalValue(p *basicPID, x []*Signal, dt time.Duration, sIndex int
 	default:
 	}
 	deriv := (pvError - p.PIDSets[sIndex].signalErr) / dtS
-	output := p.PIDSets[sIndex].P*pvError + p.PIDSets[sIndex].int + p.PIDSets[sIndex].D*deriv
+	unsaturated := p.PIDSets[sIndex].P*pvError + p.PIDSets[sIndex].int + p.PIDSets[sIndex].D*deriv
 	p.PIDSets[sIndex].signalErr = pvError
+	output := unsaturated
 	if output > p.limUp {
 		output = p.limUp
 	} else if output < p.limLo {
 		output = p.limLo
 	}
 
+	switch p.antiWindup {
+	case antiWindupConditional:
+		// undo this step's integration if it pushed further into saturation
+		integrated := pvError * p.PIDSets[sIndex].I
+		if (unsaturated > p.limUp && integrated > 0) || (unsaturated < p.limLo && integrated < 0) {
+			p.PIDSets[sIndex].int = prevInt
+		}
+	case antiWindupBackCalculation:
+		gain := p.antiWindupGain
+		if gain == 0 && p.PIDSets[sIndex].P != 0 {
+			// by default track the saturated output with the integral time constant P/I
+			gain = p.PIDSets[sIndex].I / p.PIDSets[sIndex].P
+		}
+		p.PIDSets[sIndex].int += gain * (output - unsaturated) * dtS
+	case antiWindupClamping:
+	}
+
 	return output
 }
 
@@ -134,6 +203,7 @@ func (p *basicPID) reset() error {
 		}
 		if len(p.PIDSets) > 0 {
 			p.tuners = make([]*pidTuner, len(p.PIDSets))
+			p.results = make([]*TuningResult, len(p.PIDSets))
 			for i := 0; i < len(p.PIDSets); i++ {
 				p.PIDSets[i].int = 0
 				p.PIDSets[i].signalErr = 0
@@ -171,6 +241,22 @@ func (p *basicPID) reset() error {
 		p.limLo = p.cfg.Attribute["limit_lo"].(float64)
 	}
 
+	p.antiWindup = antiWindupClamping
+	if p.cfg.Attribute.Has("anti_windup") {
+		p.antiWindup = antiWindupMethod(p.cfg.Attribute["anti_windup"].(string))
+	}
+	switch p.antiWindup {
+	case antiWindupClamping, antiWindupConditional:
+	case antiWindupBackCalculation:
+		p.antiWindupGain = p.cfg.Attribute.Float64("anti_windup_gain", 0)
+		if p.antiWindupGain < 0 {
+			return errors.Errorf("pid block %s anti_windup_gain can't be negative", p.cfg.Name)
+		}
+	default:
+		return errors.Errorf("pid block %s anti_windup should be one of %s, %s or %s, got %s", p.cfg.Name,
+			antiWindupClamping, antiWindupConditional, antiWindupBackCalculation, p.antiWindup)
+	}
+
 	for i := 0; i < len(p.PIDSets); i++ {
 		// Create a Tuner object for our PID set. Across all Tuner objects, they share global
 		// values (limUp, limLo, ssR, tuneMethod, stepPct). The only values that differ are P,I,D.
@@ -189,6 +275,15 @@ func (p *basicPID) reset() error {
 			if p.cfg.Attribute.Has("tune_method") {
 				tuneMethod = tuneCalcMethod(p.cfg.Attribute["tune_method"].(string))
 			}
+			if err := ValidateTuneMethod(string(tuneMethod)); err != nil {
+				return errors.Wrapf(err, "tuner pid block %s", p.cfg.Name)
+			}
+
+			// the desired closed loop time constant for the IMC methods, in seconds
+			var imcLambda float64
+			if p.cfg.Attribute.Has("tune_imc_lambda") {
+				imcLambda = p.cfg.Attribute["tune_imc_lambda"].(float64)
+			}
 
 			p.tuners[i] = &pidTuner{
 				limUp:      p.limUp,
@@ -196,6 +291,7 @@ func (p *basicPID) reset() error {
 				ssRValue:   ssrVal,
 				tuneMethod: tuneMethod,
 				stepPct:    tuneStepPct,
+				imcLambda:  imcLambda,
 				kP:         p.PIDSets[i].P,
 				kI:         p.PIDSets[i].I,
 				kD:         p.PIDSets[i].D,
@@ -216,10 +312,24 @@ func (p *basicPID) reset() error {
 	// the length of the signal[] array is lengthened to accommodate multiple outputs.
 	p.y = make([]*Signal, 1)
 	p.y[0] = makeSignals(p.cfg.Name, p.cfg.Type, len(p.PIDSets))
+	p.tuned.Store(!p.getTuning())
 
 	return nil
 }
 
+// TuningResults returns the results of the signals of this block tuned so far.
+func (p *basicPID) TuningResults() []TuningResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var results []TuningResult
+	for _, r := range p.results {
+		if r != nil {
+			results = append(results, *r)
+		}
+	}
+	return results
+}
+
 func (p *basicPID) Reset(ctx context.Context) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -253,6 +363,8 @@ const (
 	tuneMethodCohenCoonsPID              tuneCalcMethod = "cohenCoonsPID"
 	tuneMethodTyreusLuybenPI             tuneCalcMethod = "tyreusLuybenPI"
 	tuneMethodTyreusLuybenPID            tuneCalcMethod = "tyreusLuybenPID"
+	tuneMethodIMCPI                      tuneCalcMethod = "imcPI"
+	tuneMethodIMCPID                     tuneCalcMethod = "imcPID"
 )
 
 const (
@@ -289,6 +401,15 @@ type pidTuner struct {
 	ccT3         time.Duration
 	out          float64
 	tuning       bool
+	imcLambda    float64
+
+	// identified process model, kept for the tuning result
+	kU          float64
+	pU          float64
+	processGain float64
+	tau         float64
+	deadTime    float64
+	converged   bool
 }
 
 // reference for computation: https://en.wikipedia.org/wiki/Ziegler%E2%80%93Nichols_method#cite_note-1
@@ -303,6 +424,12 @@ func (p *pidTuner) computeGains() {
 	d := 0.5 * stepPwr
 	kU := (4 * d) / (math.Pi * a)
 	pU := (p.tC * 2.0).Seconds()
+	if p.tuneMethod.isStepResponse() {
+		p.identifyStepModel(stepPwr)
+	} else {
+		p.kU = kU
+		p.pU = pU
+	}
 	switch p.tuneMethod {
 	case tuneMethodZiegerNicholsPI:
 		p.kP = 0.4545 * kU
@@ -333,27 +460,52 @@ func (p *pidTuner) computeGains() {
 		p.kI = 0.2066 * (kU / pU)
 		p.kD = 0.0721 * kU * pU
 	case tuneMethodCohenCoonsPI:
-		t1 := (p.ccT2.Seconds() - math.Log(2.0)*p.ccT3.Seconds()) / (1.0 - math.Log(2.0))
-		tau := p.ccT3.Seconds() - t1
-		tauD := t1
-		K := (p.avgSpeedSS / stepPwr)
-		r := tauD / tau
-		p.kP = (1.0 / (K * r)) * (0.9 + r/12)
-		p.kI = p.kP / (tauD) * (30 + 3*r) / (9 + 20*r)
+		r := p.deadTime / p.tau
+		p.kP = (1.0 / (p.processGain * r)) * (0.9 + r/12)
+		p.kI = p.kP / (p.deadTime) * (30 + 3*r) / (9 + 20*r)
 	case tuneMethodCohenCoonsPID:
-		t1 := (p.ccT2.Seconds() - math.Log(2.0)*p.ccT3.Seconds()) / (1.0 - math.Log(2.0))
-		tau := p.ccT3.Seconds() - t1
-		tauD := t1
-		K := (p.avgSpeedSS / stepPwr)
-		r := tauD / tau
-		p.kP = (1.0 / (K * r)) * (4.0/3.0 + r/4)
-		p.kI = p.kP / (tauD) * (32 + 6*r) / (13 + 8*r)
-		p.kD = p.kP / (4 * tauD / (11 + 2*r))
+		r := p.deadTime / p.tau
+		p.kP = (1.0 / (p.processGain * r)) * (4.0/3.0 + r/4)
+		p.kI = p.kP / (p.deadTime) * (32 + 6*r) / (13 + 8*r)
+		p.kD = p.kP / (4 * p.deadTime / (11 + 2*r))
+	case tuneMethodIMCPI, tuneMethodIMCPID:
+		p.kP, p.kI, p.kD = imcGains(p.tuneMethod, p.processGain, p.tau, p.deadTime, p.imcLambda)
 	default: // ziegler nichols PI is the default
 		p.kP = 0.4545 * kU
 		p.kI = 0.5454 * (kU / pU)
 		p.kD = 0.0
 	}
+	p.converged = p.modelUsable() && gainsUsable(p.kP, p.kI, p.kD)
+}
+
+// identifyStepModel fits a first order plus dead time model to the step response, using the times
+// at which it reached 50% and 63.2% of its steady state.
+func (p *pidTuner) identifyStepModel(stepPwr float64) {
+	p.deadTime = (p.ccT2.Seconds() - math.Log(2.0)*p.ccT3.Seconds()) / (1.0 - math.Log(2.0))
+	p.tau = p.ccT3.Seconds() - p.deadTime
+	p.processGain = p.avgSpeedSS / stepPwr
+}
+
+func (p *pidTuner) result(block string, signal int) TuningResult {
+	r := TuningResult{
+		Block:       block,
+		Signal:      signal,
+		Method:      string(p.tuneMethod),
+		P:           p.kP,
+		I:           p.kI,
+		D:           p.kD,
+		Converged:   p.converged,
+		CompletedAt: time.Now(),
+	}
+	if p.tuneMethod.isStepResponse() {
+		r.ProcessGain = p.processGain
+		r.TimeConstantSec = p.tau
+		r.DeadTimeSec = p.deadTime
+	} else {
+		r.UltimateGain = p.kU
+		r.UltimatePeriodSec = p.pU
+	}
+	return r
 }
 
 func pidTunerFindTCat(speeds []float64, times []time.Time, speed float64) time.Duration {
@@ -393,7 +545,7 @@ func (p *pidTuner) pidTunerStep(pv float64, logger logging.Logger) (float64, boo
 				p.avgSpeedSS += p.stepRsp[len(p.stepRsp)-6]
 			}
 			p.avgSpeedSS /= 5
-			if p.tuneMethod == tuneMethodCohenCoonsPI || p.tuneMethod == tuneMethodCohenCoonsPID {
+			if p.tuneMethod.isStepResponse() {
 				p.out = 0.0
 				p.ccT2 = pidTunerFindTCat(p.stepRsp, p.stepRespT, 0.5*p.avgSpeedSS)
 				p.ccT3 = pidTunerFindTCat(p.stepRsp, p.stepRespT, 0.632*p.avgSpeedSS)
@@ -451,6 +603,7 @@ func (p *pidTuner) reset() error {
 	p.kI = 0.0
 	p.kD = 0.0
 	p.kP = 0.0
+	p.converged = false
 	p.pPeakH = []float64{}
 	p.pPeakL = []float64{}
 	return nil
@@ -213,3 +213,55 @@ func TestPIDMultiTuner(t *testing.T) {
 		pid.tuners[signalIndex].tuning = false
 	}
 }
+
+func TestPIDAntiWindup(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	newPIDWithAntiWindup := func(method string) (*basicPID, error) {
+		b, err := loop.newPID(BlockConfig{
+			Name: "PID1",
+			Attribute: utils.AttributeMap{
+				"PIDSets":        []*PIDConfig{{P: 1, I: 1}},
+				"limit_up":       10.0,
+				"limit_lo":       -10.0,
+				"int_sat_lim_up": 100.0,
+				"int_sat_lim_lo": -100.0,
+				"anti_windup":    method,
+			},
+			Type:      "PID",
+			DependsOn: []string{"A"},
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		return b.(*basicPID), nil
+	}
+	_, err := newPIDWithAntiWindup("reset")
+	test.That(t, err, test.ShouldBeError,
+		"pid block PID1 anti_windup should be one of clamping, conditional or back_calculation, got reset")
+
+	// an error the output can't correct for 10 seconds
+	for _, tc := range []struct {
+		method      string
+		expectedInt float64
+	}{
+		{"clamping", 100},
+		{"conditional", 0},
+		// the integral settles where integrating the error, 20 * 0.1, is balanced by the back
+		// calculation, (10 - (20 + 2 + int)) * 0.1
+		{"back_calculation", 8},
+	} {
+		t.Run(tc.method, func(t *testing.T) {
+			pid, err := newPIDWithAntiWindup(tc.method)
+			test.That(t, err, test.ShouldBeNil)
+			s := []*Signal{makeSignal("A", blockSum)}
+			s[0].SetSignalValueAt(0, 20)
+			for i := 0; i < 100; i++ {
+				out, ok := pid.Next(ctx, s, 100*time.Millisecond)
+				test.That(t, ok, test.ShouldBeTrue)
+				test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, 10)
+			}
+			test.That(t, pid.PIDSets[0].int, test.ShouldAlmostEqual, tc.expectedInt, 0.01)
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package control
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Plant is a model of the process a control loop drives, for simulating the loop offline. A plant
+// has one output per signal of the loop's endpoint, e.g. one for a motor and two, linear and
+// angular, for a sensor controlled base.
+type Plant interface {
+	// Output returns the current outputs of the plant, as read by the endpoint.
+	Output() []float64
+
+	// Step applies inputs, as set by the endpoint, for dt.
+	Step(inputs []float64, dt time.Duration)
+}
+
+// FirstOrderPlant models each channel as dy/dt = (Gain*u(t-DeadTime) - y) / TimeConstant, which
+// approximates the velocity of a DC motor driven by a power. When Integrating is set the output is
+// the integral of y instead, e.g. the position of that motor.
+type FirstOrderPlant struct {
+	Gain         float64
+	TimeConstant time.Duration
+	DeadTime     time.Duration
+	Integrating  bool
+
+	delay  inputDelay
+	y      []float64
+	output []float64
+}
+
+// NewFirstOrderPlant returns a FirstOrderPlant with channels outputs, all starting at 0.
+func NewFirstOrderPlant(channels int, gain float64, timeConstant, deadTime time.Duration, integrating bool) *FirstOrderPlant {
+	return &FirstOrderPlant{
+		Gain:         gain,
+		TimeConstant: timeConstant,
+		DeadTime:     deadTime,
+		Integrating:  integrating,
+		y:            make([]float64, channels),
+		output:       make([]float64, channels),
+	}
+}
+
+// Output returns the current outputs of the plant.
+func (p *FirstOrderPlant) Output() []float64 {
+	return append([]float64{}, p.output...)
+}
+
+// Step applies inputs for dt.
+func (p *FirstOrderPlant) Step(inputs []float64, dt time.Duration) {
+	u := p.delay.push(inputs, p.DeadTime, dt)
+	dtS := dt.Seconds()
+	for i := range p.y {
+		target := p.Gain * valueAt(u, i)
+		if p.TimeConstant <= 0 {
+			p.y[i] = target
+		} else {
+			// exact discretization, so large steps don't go unstable
+			p.y[i] = target + (p.y[i]-target)*math.Exp(-dtS/p.TimeConstant.Seconds())
+		}
+		if p.Integrating {
+			p.output[i] += p.y[i] * dtS
+		} else {
+			p.output[i] = p.y[i]
+		}
+	}
+}
+
+// SecondOrderPlant models each channel as
+// d²y/dt² + 2*DampingRatio*NaturalFrequency*dy/dt + NaturalFrequency^2*y = Gain*NaturalFrequency^2*u(t-DeadTime),
+// e.g. a motor with a compliant coupling to its load. When Integrating is set the output is the
+// integral of y instead.
+type SecondOrderPlant struct {
+	Gain             float64
+	NaturalFrequency float64 // rad/s
+	DampingRatio     float64
+	DeadTime         time.Duration
+	Integrating      bool
+
+	delay  inputDelay
+	y      []float64
+	dy     []float64
+	output []float64
+}
+
+// NewSecondOrderPlant returns a SecondOrderPlant with channels outputs, all starting at rest at 0.
+func NewSecondOrderPlant(
+	channels int, gain, naturalFrequency, dampingRatio float64, deadTime time.Duration, integrating bool,
+) *SecondOrderPlant {
+	return &SecondOrderPlant{
+		Gain:             gain,
+		NaturalFrequency: naturalFrequency,
+		DampingRatio:     dampingRatio,
+		DeadTime:         deadTime,
+		Integrating:      integrating,
+		y:                make([]float64, channels),
+		dy:               make([]float64, channels),
+		output:           make([]float64, channels),
+	}
+}
+
+// Output returns the current outputs of the plant.
+func (p *SecondOrderPlant) Output() []float64 {
+	return append([]float64{}, p.output...)
+}
+
+// secondOrderSubsteps is how many integration steps are taken per Step, to keep the semi-implicit
+// Euler integration accurate at control loop rates.
+const secondOrderSubsteps = 10
+
+// Step applies inputs for dt.
+func (p *SecondOrderPlant) Step(inputs []float64, dt time.Duration) {
+	u := p.delay.push(inputs, p.DeadTime, dt)
+	h := dt.Seconds() / secondOrderSubsteps
+	wn2 := p.NaturalFrequency * p.NaturalFrequency
+	for i := range p.y {
+		for j := 0; j < secondOrderSubsteps; j++ {
+			ddy := wn2*(p.Gain*valueAt(u, i)-p.y[i]) - 2*p.DampingRatio*p.NaturalFrequency*p.dy[i]
+			p.dy[i] += ddy * h
+			p.y[i] += p.dy[i] * h
+			if p.Integrating {
+				p.output[i] += p.y[i] * h
+			}
+		}
+		if !p.Integrating {
+			p.output[i] = p.y[i]
+		}
+	}
+}
+
+// PlantSample is the outputs of a plant recorded at a time since the start of the recording.
+type PlantSample struct {
+	Time    time.Duration
+	Outputs []float64
+}
+
+// ReplayPlant plays back recorded outputs, e.g. encoder positions captured while a motor ran, in
+// simulated time. It is open loop, the inputs are ignored, so it's for checking how a loop reacts
+// to real measurements and noise rather than whether it would have corrected them.
+type ReplayPlant struct {
+	samples []PlantSample
+	elapsed time.Duration
+}
+
+// NewReplayPlant returns a ReplayPlant playing back samples.
+func NewReplayPlant(samples []PlantSample) (*ReplayPlant, error) {
+	if len(samples) == 0 {
+		return nil, errors.New("a replay plant needs at least one sample")
+	}
+	sorted := append([]PlantSample{}, samples...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time < sorted[j].Time })
+	return &ReplayPlant{samples: sorted}, nil
+}
+
+// Output returns the recorded outputs, linearly interpolated at the current simulated time and
+// held after the last sample.
+func (p *ReplayPlant) Output() []float64 {
+	next := sort.Search(len(p.samples), func(i int) bool { return p.samples[i].Time > p.elapsed })
+	if next == 0 {
+		return append([]float64{}, p.samples[0].Outputs...)
+	}
+	if next == len(p.samples) {
+		return append([]float64{}, p.samples[next-1].Outputs...)
+	}
+	prev, nextSample := p.samples[next-1], p.samples[next]
+	frac := float64(p.elapsed-prev.Time) / float64(nextSample.Time-prev.Time)
+	out := make([]float64, len(prev.Outputs))
+	for i := range out {
+		out[i] = prev.Outputs[i] + frac*(valueAt(nextSample.Outputs, i)-prev.Outputs[i])
+	}
+	return out
+}
+
+// Step advances the simulated time by dt.
+func (p *ReplayPlant) Step(inputs []float64, dt time.Duration) {
+	p.elapsed += dt
+}
+
+// ReadPlantSamplesCSV reads samples from CSV with the time in seconds in the first column and one
+// column per output after it. A first row which doesn't parse as numbers is treated as a header.
+func ReadPlantSamplesCSV(r io.Reader) ([]PlantSample, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	var samples []PlantSample
+	for i, record := range records {
+		if len(record) < 2 {
+			return nil, errors.Errorf("row %d should have a time and at least one output", i+1)
+		}
+		values := make([]float64, len(record))
+		for j, field := range record {
+			values[j], err = strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				break
+			}
+		}
+		if err != nil {
+			if i == 0 {
+				continue
+			}
+			return nil, errors.Wrapf(err, "row %d", i+1)
+		}
+		samples = append(samples, PlantSample{
+			Time:    time.Duration(values[0] * float64(time.Second)),
+			Outputs: values[1:],
+		})
+	}
+	return samples, nil
+}
+
+// inputDelay holds inputs back by a dead time.
+type inputDelay struct {
+	queue [][]float64
+}
+
+func (d *inputDelay) push(inputs []float64, deadTime, dt time.Duration) []float64 {
+	steps := 0
+	if dt > 0 {
+		steps = int(math.Round(float64(deadTime) / float64(dt)))
+	}
+	d.queue = append(d.queue, append([]float64{}, inputs...))
+	if len(d.queue) <= steps {
+		// nothing has made it through the dead time yet
+		return nil
+	}
+	u := d.queue[0]
+	d.queue = d.queue[1:]
+	return u
+}
+
+func valueAt(values []float64, i int) float64 {
+	if i < len(values) {
+		return values[i]
+	}
+	return 0
+}
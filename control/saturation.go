@@ -0,0 +1,102 @@
+package control
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// saturation limits its input to [limit_lo, limit_up] and, when max_rate is set, limits how fast
+// its output can change to max_rate units per second.
+type saturation struct {
+	mu      sync.Mutex
+	cfg     BlockConfig
+	y       []*Signal
+	limUp   float64
+	limLo   float64
+	maxRate float64
+	primed  bool
+	logger  logging.Logger
+}
+
+func newSaturation(config BlockConfig, logger logging.Logger) (Block, error) {
+	s := &saturation{cfg: config, logger: logger}
+	if err := s.reset(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (b *saturation) Next(ctx context.Context, x []*Signal, dt time.Duration) ([]*Signal, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(x) != 1 {
+		return b.y, false
+	}
+	out := math.Max(b.limLo, math.Min(b.limUp, x[0].GetSignalValueAt(0)))
+	if b.maxRate > 0 && b.primed {
+		prev := b.y[0].GetSignalValueAt(0)
+		maxStep := b.maxRate * dt.Seconds()
+		out = math.Max(prev-maxStep, math.Min(prev+maxStep, out))
+	}
+	b.primed = true
+	b.y[0].SetSignalValueAt(0, out)
+	return b.y, true
+}
+
+func (b *saturation) reset() error {
+	if !b.cfg.Attribute.Has("limit_up") && !b.cfg.Attribute.Has("limit_lo") && !b.cfg.Attribute.Has("max_rate") {
+		return errors.Errorf("saturation block %s should have a limit_up, limit_lo or max_rate field", b.cfg.Name)
+	}
+	if len(b.cfg.DependsOn) != 1 {
+		return errors.Errorf("invalid number of inputs for saturation block %s expected 1 got %d", b.cfg.Name, len(b.cfg.DependsOn))
+	}
+	b.limUp = math.Inf(1)
+	if b.cfg.Attribute.Has("limit_up") {
+		b.limUp = b.cfg.Attribute["limit_up"].(float64)
+	}
+	b.limLo = math.Inf(-1)
+	if b.cfg.Attribute.Has("limit_lo") {
+		b.limLo = b.cfg.Attribute["limit_lo"].(float64)
+	}
+	if b.limLo > b.limUp {
+		return errors.Errorf("saturation block %s limit_lo should be less than limit_up", b.cfg.Name)
+	}
+	b.maxRate = 0
+	if b.cfg.Attribute.Has("max_rate") {
+		b.maxRate = b.cfg.Attribute["max_rate"].(float64)
+	}
+	if b.maxRate < 0 {
+		return errors.Errorf("saturation block %s max_rate can't be negative", b.cfg.Name)
+	}
+	b.primed = false
+	b.y = make([]*Signal, 1)
+	b.y[0] = makeSignal(b.cfg.Name, b.cfg.Type)
+	return nil
+}
+
+func (b *saturation) Reset(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.reset()
+}
+
+func (b *saturation) UpdateConfig(ctx context.Context, config BlockConfig) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = config
+	return b.reset()
+}
+
+func (b *saturation) Output(ctx context.Context) []*Signal {
+	return b.y
+}
+
+func (b *saturation) Config(ctx context.Context) BlockConfig {
+	return b.cfg
+}
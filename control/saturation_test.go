@@ -0,0 +1,138 @@
+package control
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+func TestSaturationConfig(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	for _, c := range []struct {
+		conf BlockConfig
+		err  string
+	}{
+		{
+			BlockConfig{
+				Name: "Saturation1",
+				Type: "saturation",
+				Attribute: utils.AttributeMap{
+					"limit_up": 10.0,
+					"limit_lo": -10.0,
+				},
+				DependsOn: []string{"A"},
+			},
+			"",
+		},
+		{
+			BlockConfig{
+				Name: "Saturation1",
+				Type: "saturation",
+				Attribute: utils.AttributeMap{
+					"max_rate": 5.0,
+				},
+				DependsOn: []string{"A"},
+			},
+			"",
+		},
+		{
+			BlockConfig{
+				Name:      "Saturation1",
+				Type:      "saturation",
+				Attribute: utils.AttributeMap{},
+				DependsOn: []string{"A"},
+			},
+			"saturation block Saturation1 should have a limit_up, limit_lo or max_rate field",
+		},
+		{
+			BlockConfig{
+				Name: "Saturation1",
+				Type: "saturation",
+				Attribute: utils.AttributeMap{
+					"limit_up": -10.0,
+					"limit_lo": 10.0,
+				},
+				DependsOn: []string{"A"},
+			},
+			"saturation block Saturation1 limit_lo should be less than limit_up",
+		},
+		{
+			BlockConfig{
+				Name: "Saturation1",
+				Type: "saturation",
+				Attribute: utils.AttributeMap{
+					"max_rate": -1.0,
+				},
+				DependsOn: []string{"A"},
+			},
+			"saturation block Saturation1 max_rate can't be negative",
+		},
+		{
+			BlockConfig{
+				Name: "Saturation1",
+				Type: "saturation",
+				Attribute: utils.AttributeMap{
+					"limit_up": 10.0,
+				},
+				DependsOn: []string{"A", "B"},
+			},
+			"invalid number of inputs for saturation block Saturation1 expected 1 got 2",
+		},
+	} {
+		b, err := newSaturation(c.conf, logger)
+		if c.err == "" {
+			s := b.(*saturation)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, len(s.y), test.ShouldEqual, 1)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldResemble, c.err)
+		}
+	}
+}
+
+func TestSaturationNext(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	c := BlockConfig{
+		Name: "Saturation1",
+		Type: "saturation",
+		Attribute: utils.AttributeMap{
+			"limit_up": 10.0,
+			"limit_lo": -5.0,
+			"max_rate": 100.0,
+		},
+		DependsOn: []string{"A"},
+	}
+	s, err := newSaturation(c, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	signals := []*Signal{makeSignal("A", blockGain)}
+	dt := 10 * time.Millisecond
+	// the first output is only limited by the bounds
+	signals[0].SetSignalValueAt(0, 3.0)
+	out, ok := s.Next(ctx, signals, dt)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, 3.0)
+
+	// after that the output can change by at most 100 * 0.01 per step
+	signals[0].SetSignalValueAt(0, 50.0)
+	for _, expected := range []float64{4, 5, 6, 7, 8, 9, 10, 10} {
+		out, ok = s.Next(ctx, signals, dt)
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, out[0].GetSignalValueAt(0), test.ShouldAlmostEqual, expected)
+	}
+	signals[0].SetSignalValueAt(0, -50.0)
+	out, _ = s.Next(ctx, signals, dt)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldAlmostEqual, 9)
+
+	// resetting forgets the previous output
+	test.That(t, s.Reset(ctx), test.ShouldBeNil)
+	out, _ = s.Next(ctx, signals, dt)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, -5.0)
+}
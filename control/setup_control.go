@@ -3,6 +3,8 @@ package control
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -24,6 +26,7 @@ const (
 	rPiGain                 = 0.00392157
 	defaultControllableType = "motor_name"
 	defaultDerivativeType   = "backward1st1"
+	defaultTuneMethod       = string(tuneMethodZiegerNicholsPI)
 )
 
 var (
@@ -38,6 +41,7 @@ type PIDLoop struct {
 	BlockNames              map[string][]string
 	PIDVals                 []PIDConfig
 	TunedVals               *[]PIDConfig
+	TuningResults           *[]TuningResult
 	ControlConf             *Config
 	ControlLoop             *Loop
 	Options                 Options
@@ -53,6 +57,9 @@ type PIDConfig struct {
 	I    float64 `json:"i"`
 	D    float64 `json:"d"`
 
+	// TuneMethod selects the auto-tuning method used when P, I and D are all 0.
+	TuneMethod string `json:"tune_method,omitempty"`
+
 	// PID block specific values
 	// these are integral sum and signalErr for the pid signal
 	int       float64
@@ -94,6 +101,49 @@ type Options struct {
 	// ControllableType is the type of component the control loop will be set up for,
 	// currently a base or motor
 	ControllableType string
+
+	// AntiWindup is the anti-windup method of the PID blocks: clamping (the default),
+	// conditional or back_calculation
+	AntiWindup string
+
+	// OutputDeadband adds a deadband block of this width after each PID block, which stops
+	// the component from hunting around zero
+	OutputDeadband float64
+
+	// OutputRateLimit adds a saturation block after each PID block which limits how fast its
+	// output can change, in output units per second
+	OutputRateLimit float64
+
+	// FeedForward adds a feedforward block from the set point of each PID block to its output
+	FeedForward *FeedForwardConfig
+}
+
+// FeedForwardConfig holds the gains of the feedforward blocks added to a control loop, see Options.
+type FeedForwardConfig struct {
+	// KS is the output which overcomes static friction
+	KS float64 `json:"ks,omitempty"`
+	// KV is the output per unit of set point
+	KV float64 `json:"kv,omitempty"`
+	// KA is the output per unit of set point change per second
+	KA float64 `json:"ka,omitempty"`
+}
+
+// ValidateOutputConditioning returns an error if the anti-windup method, output deadband or output
+// rate limit of a control loop are invalid.
+func ValidateOutputConditioning(antiWindup string, outputDeadband, outputRateLimit float64) error {
+	switch antiWindupMethod(antiWindup) {
+	case "", antiWindupClamping, antiWindupConditional, antiWindupBackCalculation:
+	default:
+		return errors.Errorf("anti_windup should be one of %s, %s or %s, got %s",
+			antiWindupClamping, antiWindupConditional, antiWindupBackCalculation, antiWindup)
+	}
+	if outputDeadband < 0 {
+		return errors.New("output_deadband can't be negative")
+	}
+	if outputRateLimit < 0 {
+		return errors.New("output_rate_limit can't be negative")
+	}
+	return nil
 }
 
 // SetupPIDControlConfig creates a control config.
@@ -105,13 +155,14 @@ func SetupPIDControlConfig(
 	logger logging.Logger,
 ) (*PIDLoop, error) {
 	pidLoop := &PIDLoop{
-		Controllable: c,
-		PIDVals:      pidVals,
-		TunedVals:    &[]PIDConfig{{}, {}},
-		logger:       logger,
-		Options:      options,
-		ControlConf:  &Config{},
-		ControlLoop:  nil,
+		Controllable:  c,
+		PIDVals:       pidVals,
+		TunedVals:     &[]PIDConfig{{}, {}},
+		TuningResults: &[]TuningResult{},
+		logger:        logger,
+		Options:       options,
+		ControlConf:   &Config{},
+		ControlLoop:   nil,
 	}
 
 	// set controlConf as either an optional custom config, or as the default control config
@@ -175,6 +226,7 @@ func (p *PIDLoop) TunePIDLoop(ctx context.Context, cancelFunc context.CancelFunc
 			tunedPID := p.ControlLoop.GetPIDVals(0)
 			tunedPID.Type = p.PIDVals[0].Type
 			(*p.TunedVals)[0] = tunedPID
+			p.recordTuningResults(0)
 
 			p.ControlLoop.Stop()
 			p.ControlLoop = nil
@@ -218,6 +270,7 @@ func (p *PIDLoop) tuneSinglePIDBlock(ctx context.Context, blockIndex, pidIndex i
 	tunedPID := p.ControlLoop.GetPIDVals(pidIndex)
 	tunedPID.Type = p.PIDVals[pidIndex].Type
 	(*p.TunedVals)[pidIndex] = tunedPID
+	p.recordTuningResults(pidIndex)
 
 	p.ControlLoop.Stop()
 	p.ControlLoop = nil
@@ -228,6 +281,14 @@ func (p *PIDLoop) tuneSinglePIDBlock(ctx context.Context, blockIndex, pidIndex i
 	return nil
 }
 
+// recordTuningResults stores the results of the loop that just finished tuning the PIDVals at pidIndex.
+func (p *PIDLoop) recordTuningResults(pidIndex int) {
+	for _, result := range p.ControlLoop.TuningResults() {
+		result.Type = p.PIDVals[pidIndex].Type
+		*p.TuningResults = append(*p.TuningResults, result)
+	}
+}
+
 func (p *PIDLoop) createControlLoopConfig(pidVals []PIDConfig, componentName string) {
 	// create basic control config
 	controllableType := defaultControllableType
@@ -251,6 +312,8 @@ func (p *PIDLoop) createControlLoopConfig(pidVals []PIDConfig, componentName str
 		p.addSensorFeedbackVelocityControl(pidVals[1])
 	}
 
+	p.addOutputConditioning()
+
 	// assign block names
 	p.BlockNames = make(map[string][]string, len(p.ControlConf.Blocks))
 	for _, b := range p.ControlConf.Blocks {
@@ -290,7 +353,7 @@ func (p *PIDLoop) basicControlConfig(endpointName string, pidVals PIDConfig, con
 					"PIDSets":        []*PIDConfig{&pidVals},
 					"limit_lo":       -255.0,
 					"limit_up":       255.0,
-					"tune_method":    "ziegerNicholsPI",
+					"tune_method":    tuneMethodOrDefault(pidVals.TuneMethod),
 					"tune_ssr_value": 2.0,
 					"tune_step_pct":  0.35,
 				},
@@ -394,7 +457,7 @@ func (p *PIDLoop) addSensorFeedbackVelocityControl(angularPIDVals PIDConfig) {
 			"int_sat_lim_up": 255.0,
 			"limit_lo":       -255.0,
 			"limit_up":       255.0,
-			"tune_method":    "ziegerNicholsPI",
+			"tune_method":    tuneMethodOrDefault(angularPIDVals.TuneMethod),
 			"tune_ssr_value": 2.0,
 			"tune_step_pct":  0.35,
 		},
@@ -421,6 +484,98 @@ func (p *PIDLoop) addSensorFeedbackVelocityControl(angularPIDVals PIDConfig) {
 	p.ControlConf.Blocks[4].DependsOn = []string{"linear_gain", "angular_gain"}
 }
 
+// addOutputConditioning sets the anti-windup method of the PID blocks and adds the feedforward,
+// deadband and rate limit blocks after them. The new blocks are appended so the indexes of the
+// existing blocks don't change.
+func (p *PIDLoop) addOutputConditioning() {
+	var pidNames []string
+	for _, b := range p.ControlConf.Blocks {
+		if b.Type != blockPID {
+			continue
+		}
+		if p.Options.AntiWindup != "" {
+			b.Attribute["anti_windup"] = p.Options.AntiWindup
+		}
+		pidNames = append(pidNames, b.Name)
+	}
+	if p.Options.FeedForward == nil && p.Options.OutputDeadband == 0 && p.Options.OutputRateLimit == 0 {
+		return
+	}
+
+	for _, pidName := range pidNames {
+		// e.g. linear_PID is followed by linear_feedforward_sum, linear_deadband and linear_rate_limit
+		prefix := strings.TrimSuffix(pidName, "PID")
+		var conditioning []BlockConfig
+		last := pidName
+		if ff := p.Options.FeedForward; ff != nil {
+			conditioning = append(conditioning,
+				BlockConfig{
+					Name: prefix + "feedforward",
+					Type: blockFeedForward,
+					Attribute: rdkutils.AttributeMap{
+						"ks": ff.KS,
+						"kv": ff.KV,
+						"ka": ff.KA,
+					},
+					DependsOn: []string{p.setPointBlock(prefix)},
+				},
+				BlockConfig{
+					Name: prefix + "feedforward_sum",
+					Type: blockSum,
+					Attribute: rdkutils.AttributeMap{
+						"sum_string": "++",
+					},
+					DependsOn: []string{last, prefix + "feedforward"},
+				})
+			last = prefix + "feedforward_sum"
+		}
+		if p.Options.OutputDeadband != 0 {
+			conditioning = append(conditioning, BlockConfig{
+				Name: prefix + "deadband",
+				Type: blockDeadband,
+				Attribute: rdkutils.AttributeMap{
+					"width":      p.Options.OutputDeadband,
+					"continuous": true,
+				},
+				DependsOn: []string{last},
+			})
+			last = prefix + "deadband"
+		}
+		if p.Options.OutputRateLimit != 0 {
+			conditioning = append(conditioning, BlockConfig{
+				Name: prefix + "rate_limit",
+				Type: blockSaturation,
+				Attribute: rdkutils.AttributeMap{
+					"max_rate": p.Options.OutputRateLimit,
+				},
+				DependsOn: []string{last},
+			})
+			last = prefix + "rate_limit"
+		}
+
+		// blocks which took the PID output now take the conditioned output
+		for _, b := range p.ControlConf.Blocks {
+			for j, dep := range b.DependsOn {
+				if dep == pidName {
+					b.DependsOn[j] = last
+				}
+			}
+		}
+		p.ControlConf.Blocks = append(p.ControlConf.Blocks, conditioning...)
+	}
+}
+
+// setPointBlock returns the name of the block whose output the PID block with the given name prefix
+// follows, which is the trapezoidal velocity profile when the loop controls position.
+func (p *PIDLoop) setPointBlock(prefix string) string {
+	for _, b := range p.ControlConf.Blocks {
+		if b.Type == blockSum && slices.Contains(b.DependsOn, prefix+"trapz") {
+			return prefix + "trapz"
+		}
+	}
+	return prefix + "set_point"
+}
+
 // StartControlLoop starts a PID control loop.
 func (p *PIDLoop) StartControlLoop() error {
 	loop, err := NewLoop(p.logger, *p.ControlConf, p.Controllable)
@@ -497,6 +652,13 @@ func TunedPIDErr(name string, tunedVals []PIDConfig) error {
 	return fmt.Errorf(`%v has been tuned, please copy the following control values into your config: %v`, name, tunedStr)
 }
 
+func tuneMethodOrDefault(method string) string {
+	if method == "" {
+		return defaultTuneMethod
+	}
+	return method
+}
+
 func (conf PIDConfig) String() string {
 	return fmt.Sprintf(`{"p": %v, "i": %v, "d": %v, "type": "%v"}`, conf.P, conf.I, conf.D, conf.Type)
 }
@@ -0,0 +1,92 @@
+package control
+
+import (
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestSetupOutputConditioning(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	pidVals := []PIDConfig{{Type: "linear_velocity", P: 1, I: 1}, {Type: "angular_velocity", P: 1, I: 1}}
+	pl, err := SetupPIDControlConfig(pidVals, "base", Options{
+		SensorFeedback2DVelocityControl: true,
+		ControllableType:                "base_name",
+		AntiWindup:                      "back_calculation",
+		OutputDeadband:                  2,
+		OutputRateLimit:                 500,
+	}, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	blocks := map[string]BlockConfig{}
+	for _, b := range pl.ControlConf.Blocks {
+		blocks[b.Name] = b
+	}
+	for _, prefix := range []string{"linear_", "angular_"} {
+		test.That(t, blocks[prefix+"PID"].Attribute["anti_windup"], test.ShouldEqual, "back_calculation")
+		test.That(t, blocks[prefix+"deadband"].Type, test.ShouldEqual, blockDeadband)
+		test.That(t, blocks[prefix+"deadband"].DependsOn, test.ShouldResemble, []string{prefix + "PID"})
+		test.That(t, blocks[prefix+"rate_limit"].Type, test.ShouldEqual, blockSaturation)
+		test.That(t, blocks[prefix+"rate_limit"].DependsOn, test.ShouldResemble, []string{prefix + "deadband"})
+		test.That(t, blocks[prefix+"gain"].DependsOn, test.ShouldResemble, []string{prefix + "rate_limit"})
+	}
+	test.That(t, pl.BlockNames[string(blockDeadband)], test.ShouldHaveLength, 2)
+
+	loop, err := NewLoop(logger, *pl.ControlConf, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, loop.Start(), test.ShouldBeNil)
+	loop.Stop()
+}
+
+func TestSetupFeedForward(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	pl, err := SetupPIDControlConfig([]PIDConfig{{P: 1, I: 1}}, "motor", Options{
+		PositionControlUsingTrapz: true,
+		FeedForward:               &FeedForwardConfig{KV: 0.5},
+		OutputDeadband:            2,
+	}, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	blocks := map[string]BlockConfig{}
+	for _, b := range pl.ControlConf.Blocks {
+		blocks[b.Name] = b
+	}
+	// a position loop follows the trapezoidal velocity profile
+	test.That(t, blocks["feedforward"].Type, test.ShouldEqual, blockFeedForward)
+	test.That(t, blocks["feedforward"].DependsOn, test.ShouldResemble, []string{"trapz"})
+	test.That(t, blocks["feedforward"].Attribute["kv"], test.ShouldEqual, 0.5)
+	test.That(t, blocks["feedforward_sum"].DependsOn, test.ShouldResemble, []string{"PID", "feedforward"})
+	test.That(t, blocks["deadband"].DependsOn, test.ShouldResemble, []string{"feedforward_sum"})
+	test.That(t, blocks["gain"].DependsOn, test.ShouldResemble, []string{"deadband"})
+
+	loop, err := NewLoop(logger, *pl.ControlConf, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, loop.Start(), test.ShouldBeNil)
+	loop.Stop()
+
+	// a velocity loop follows its set point
+	pl, err = SetupPIDControlConfig([]PIDConfig{{P: 1, I: 1}, {P: 1, I: 1}}, "base", Options{
+		SensorFeedback2DVelocityControl: true,
+		ControllableType:                "base_name",
+		FeedForward:                     &FeedForwardConfig{KS: 1},
+	}, nil, logger)
+	test.That(t, err, test.ShouldBeNil)
+	for _, b := range pl.ControlConf.Blocks {
+		if b.Type == blockFeedForward {
+			prefix := strings.TrimSuffix(b.Name, "feedforward")
+			test.That(t, b.DependsOn, test.ShouldResemble, []string{prefix + "set_point"})
+		}
+	}
+	test.That(t, pl.BlockNames[string(blockFeedForward)], test.ShouldHaveLength, 2)
+}
+
+func TestValidateOutputConditioning(t *testing.T) {
+	test.That(t, ValidateOutputConditioning("", 0, 0), test.ShouldBeNil)
+	test.That(t, ValidateOutputConditioning("back_calculation", 1, 100), test.ShouldBeNil)
+	test.That(t, ValidateOutputConditioning("bogus", 0, 0).Error(), test.ShouldContainSubstring, "anti_windup")
+	test.That(t, ValidateOutputConditioning("", -1, 0).Error(), test.ShouldContainSubstring, "output_deadband")
+	test.That(t, ValidateOutputConditioning("", 0, -1).Error(), test.ShouldContainSubstring, "output_rate_limit")
+}
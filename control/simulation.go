@@ -0,0 +1,285 @@
+package control
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// SetPointChange changes the value of a constant block, typically the set point of the loop, at a
+// time into a simulation.
+type SetPointChange struct {
+	At    time.Duration
+	Block string
+	Value float64
+}
+
+// SimulationConfig describes an offline run of a control loop against a plant model.
+type SimulationConfig struct {
+	Loop     Config
+	Plant    Plant
+	Duration time.Duration
+	// SetPointChanges are applied at the first tick at or after their time.
+	SetPointChanges []SetPointChange
+	// Targets are the values each plant output should settle at, step metrics are computed for
+	// each of them.
+	Targets []float64
+}
+
+// StepMetrics summarizes how a plant output responded to a step of its set point.
+type StepMetrics struct {
+	// Rose is whether the output got 90% of the way to its target, RiseTime is only set if it did.
+	Rose bool
+	// RiseTime is the time taken to go from 10% to 90% of the way to the target.
+	RiseTime time.Duration
+	// Overshoot is how far past the target the output went, in percent of the step.
+	Overshoot float64
+	// SteadyStateError is the distance between the target and the mean output over the last 10%
+	// of the run.
+	SteadyStateError float64
+}
+
+// SimulationResult holds everything recorded during a simulation, one entry per tick.
+type SimulationResult struct {
+	Times []time.Duration
+	// Signals holds the output of every block, as the values of all of its signals.
+	Signals map[string][][]float64
+	// PlantInputs are the values the endpoint set on the plant.
+	PlantInputs [][]float64
+	// PlantOutputs are the values the endpoint read from the plant.
+	PlantOutputs [][]float64
+	// Metrics are the step metrics of each plant output against its target.
+	Metrics []StepMetrics
+}
+
+// simulatedControllable connects the endpoint of a simulated loop to its plant.
+type simulatedControllable struct {
+	plant  Plant
+	inputs []float64
+}
+
+func (c *simulatedControllable) SetState(ctx context.Context, state []*Signal) error {
+	c.inputs = signalValues(state)
+	return nil
+}
+
+func (c *simulatedControllable) State(ctx context.Context) ([]float64, error) {
+	return c.plant.Output(), nil
+}
+
+// Simulate runs the control loop described by cfg against cfg.Plant in simulated time, so a run of
+// any duration completes as fast as the blocks can be computed and always gives the same result.
+// Each tick the endpoint reads the plant, every block is computed in dependency order, the
+// endpoint sets the plant inputs and the plant is stepped by the loop period. Inputs are held when
+// the endpoint isn't set during a tick.
+// PID autotuning times the step response with the wall clock, so PID blocks should be given gains.
+func Simulate(ctx context.Context, logger logging.Logger, cfg SimulationConfig) (*SimulationResult, error) {
+	if cfg.Plant == nil {
+		return nil, errors.New("a simulation needs a plant")
+	}
+	if cfg.Duration <= 0 {
+		return nil, errors.New("a simulation needs a positive duration")
+	}
+	ctr := &simulatedControllable{plant: cfg.Plant}
+	l, err := createLoop(logger, cfg.Loop, ctr)
+	if err != nil {
+		return nil, err
+	}
+	// the blocks are driven below rather than by the loop's workers
+	for _, c := range l.ts {
+		close(c)
+	}
+	l.cancel()
+	l.activeBackgroundWorkers.Wait()
+
+	order, err := l.simulationOrder()
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range cfg.SetPointChanges {
+		if _, ok := l.blocks[change.Block]; !ok {
+			return nil, errors.Errorf("cannot change the set point of %s, the block does not exist", change.Block)
+		}
+	}
+
+	steps := int(cfg.Duration / l.dt)
+	res := &SimulationResult{Signals: make(map[string][][]float64)}
+	applied := make([]bool, len(cfg.SetPointChanges))
+	for step := 0; step < steps; step++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		now := time.Duration(step) * l.dt
+		for i, change := range cfg.SetPointChanges {
+			if applied[i] || change.At > now {
+				continue
+			}
+			if err := UpdateConstantBlock(ctx, change.Block, change.Value, l); err != nil {
+				return nil, err
+			}
+			applied[i] = true
+		}
+
+		outputs := make(map[string][]*Signal)
+		read := false
+		for _, name := range order {
+			b := l.blocks[name]
+			bcfg := b.blk.Config(ctx)
+			var x []*Signal
+			if b.blockType == blockEndpoint && !read {
+				// the endpoint's first appearance reads the plant
+				read = true
+			} else if len(bcfg.DependsOn) != 0 {
+				sw := []*Signal{}
+				ready := true
+				for _, dep := range bcfg.DependsOn {
+					r, ok := outputs[dep]
+					if !ok {
+						ready = false
+						break
+					}
+					for _, s := range r {
+						if s != nil {
+							sw = append(sw, s)
+						}
+					}
+				}
+				if !ready {
+					continue
+				}
+				x = blockInputs(name, sw)
+			}
+			if v, ok := b.blk.Next(ctx, x, l.dt); ok {
+				outputs[name] = v
+			}
+		}
+
+		res.Times = append(res.Times, now)
+		res.PlantOutputs = append(res.PlantOutputs, cfg.Plant.Output())
+		res.PlantInputs = append(res.PlantInputs, append([]float64{}, ctr.inputs...))
+		for name, b := range l.blocks {
+			res.Signals[name] = append(res.Signals[name], signalValues(b.blk.Output(ctx)))
+		}
+		cfg.Plant.Step(ctr.inputs, l.dt)
+	}
+
+	for i, target := range cfg.Targets {
+		output := make([]float64, len(res.PlantOutputs))
+		for j, o := range res.PlantOutputs {
+			output[j] = valueAt(o, i)
+		}
+		res.Metrics = append(res.Metrics, ComputeStepMetrics(res.Times, output, target))
+	}
+	return res, nil
+}
+
+// simulationOrder returns the order to compute the blocks of the loop in during a tick. The
+// endpoint appears twice, first to read the plant and last to set it, which breaks the feedback
+// cycle through it; any other cycle is an error.
+func (l *Loop) simulationOrder() ([]string, error) {
+	var endpointName string
+	order := []string{}
+	done := make(map[string]bool)
+	for _, bcfg := range l.cfg.Blocks {
+		if bcfg.Type == blockEndpoint {
+			endpointName = bcfg.Name
+			order = append(order, bcfg.Name)
+			done[bcfg.Name] = true
+		}
+	}
+	for len(done) < len(l.cfg.Blocks) {
+		progressed := false
+		for _, bcfg := range l.cfg.Blocks {
+			if done[bcfg.Name] {
+				continue
+			}
+			ready := true
+			for _, dep := range bcfg.DependsOn {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				order = append(order, bcfg.Name)
+				done[bcfg.Name] = true
+				progressed = true
+			}
+		}
+		if !progressed {
+			return nil, errors.New("cannot simulate a loop with a cycle which doesn't go through the endpoint")
+		}
+	}
+	if endpointName != "" {
+		order = append(order, endpointName)
+	}
+	return order, nil
+}
+
+// ComputeStepMetrics computes the step metrics of output, sampled at times, against target. The
+// step is taken to start from the first sample.
+func ComputeStepMetrics(times []time.Duration, output []float64, target float64) StepMetrics {
+	var m StepMetrics
+	if len(output) == 0 || len(times) != len(output) {
+		return m
+	}
+	initial := output[0]
+	span := target - initial
+	if span == 0 {
+		m.SteadyStateError = math.Abs(target - mean(output[len(output)-steadyStateSamples(len(output)):]))
+		return m
+	}
+	// progress is how far along the step the output is, 0 at the start and 1 at the target
+	progress := func(v float64) float64 { return (v - initial) / span }
+
+	var t10 time.Duration
+	reached10 := false
+	peak := 0.0
+	for i, v := range output {
+		p := progress(v)
+		if !reached10 && p >= 0.1 {
+			t10 = times[i]
+			reached10 = true
+		}
+		if !m.Rose && p >= 0.9 {
+			m.RiseTime = times[i] - t10
+			m.Rose = true
+		}
+		peak = math.Max(peak, p)
+	}
+	m.Overshoot = math.Max(0, peak-1) * 100
+	m.SteadyStateError = math.Abs(target - mean(output[len(output)-steadyStateSamples(len(output)):]))
+	return m
+}
+
+// steadyStateSamples is the number of samples at the end of a run, 10% of them, the steady state
+// error is averaged over.
+func steadyStateSamples(n int) int {
+	return int(math.Max(1, math.Ceil(float64(n)/10)))
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// signalValues flattens the values of signals.
+func signalValues(signals []*Signal) []float64 {
+	values := []float64{}
+	for _, s := range signals {
+		if s == nil {
+			continue
+		}
+		for i := 0; i < s.dimension; i++ {
+			values = append(values, s.GetSignalValueAt(i))
+		}
+	}
+	return values
+}
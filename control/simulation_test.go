@@ -0,0 +1,176 @@
+package control
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+func velocityLoopConfig(p, i float64) Config {
+	return Config{
+		Blocks: []BlockConfig{
+			{
+				Name:      "set_point",
+				Type:      "constant",
+				Attribute: utils.AttributeMap{"constant_val": 0.0},
+			},
+			{
+				Name:      "sum",
+				Type:      "sum",
+				Attribute: utils.AttributeMap{"sum_string": "+-"},
+				DependsOn: []string{"set_point", "endpoint"},
+			},
+			{
+				Name: "PID",
+				Type: "PID",
+				Attribute: utils.AttributeMap{
+					"PIDSets":  []*PIDConfig{{P: p, I: i}},
+					"limit_up": 100.0,
+					"limit_lo": -100.0,
+				},
+				DependsOn: []string{"sum"},
+			},
+			{
+				Name:      "endpoint",
+				Type:      "endpoint",
+				Attribute: utils.AttributeMap{"motor_name": "motor"},
+				DependsOn: []string{"PID"},
+			},
+		},
+		Frequency: 100,
+	}
+}
+
+func TestFirstOrderPlant(t *testing.T) {
+	plant := NewFirstOrderPlant(1, 2, 100*time.Millisecond, 20*time.Millisecond, false)
+	dt := 10 * time.Millisecond
+	plant.Step([]float64{1}, dt)
+	plant.Step([]float64{1}, dt)
+	// the input hasn't made it through the dead time yet
+	test.That(t, plant.Output()[0], test.ShouldEqual, 0)
+	for i := 0; i < 10; i++ {
+		plant.Step([]float64{1}, dt)
+	}
+	// 63.2% of the way after one time constant
+	test.That(t, plant.Output()[0], test.ShouldAlmostEqual, 2*0.632, 0.01)
+
+	plant = NewFirstOrderPlant(1, 2, 0, 0, true)
+	plant.Step([]float64{1}, time.Second)
+	plant.Step([]float64{1}, time.Second)
+	test.That(t, plant.Output()[0], test.ShouldAlmostEqual, 4)
+}
+
+func TestSecondOrderPlant(t *testing.T) {
+	// an undamped plant oscillates between 0 and twice its steady state
+	plant := NewSecondOrderPlant(1, 1, 2*3.14159, 0, 0, false)
+	peak := 0.0
+	for i := 0; i < 100; i++ {
+		plant.Step([]float64{1}, 10*time.Millisecond)
+		if out := plant.Output()[0]; out > peak {
+			peak = out
+		}
+	}
+	test.That(t, peak, test.ShouldAlmostEqual, 2, 0.05)
+
+	plant = NewSecondOrderPlant(2, 3, 10, 1, 0, false)
+	for i := 0; i < 300; i++ {
+		plant.Step([]float64{1, -1}, 10*time.Millisecond)
+	}
+	test.That(t, plant.Output()[0], test.ShouldAlmostEqual, 3, 0.001)
+	test.That(t, plant.Output()[1], test.ShouldAlmostEqual, -3, 0.001)
+}
+
+func TestReplayPlant(t *testing.T) {
+	_, err := NewReplayPlant(nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	samples, err := ReadPlantSamplesCSV(strings.NewReader("time_s,position\n0,0\n0.1,10\n0.2,30\n"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, samples, test.ShouldResemble, []PlantSample{
+		{Time: 0, Outputs: []float64{0}},
+		{Time: 100 * time.Millisecond, Outputs: []float64{10}},
+		{Time: 200 * time.Millisecond, Outputs: []float64{30}},
+	})
+	_, err = ReadPlantSamplesCSV(strings.NewReader("0,0\n0.1,ten\n"))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	plant, err := NewReplayPlant(samples)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, plant.Output(), test.ShouldResemble, []float64{0})
+	plant.Step(nil, 150*time.Millisecond)
+	test.That(t, plant.Output()[0], test.ShouldAlmostEqual, 20)
+	plant.Step(nil, time.Second)
+	test.That(t, plant.Output(), test.ShouldResemble, []float64{30})
+}
+
+func TestComputeStepMetrics(t *testing.T) {
+	times := []time.Duration{0, time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second, 5 * time.Second}
+	m := ComputeStepMetrics(times, []float64{0, 5, 9, 12, 10, 10}, 10)
+	test.That(t, m.Rose, test.ShouldBeTrue)
+	test.That(t, m.RiseTime, test.ShouldEqual, time.Second)
+	test.That(t, m.Overshoot, test.ShouldAlmostEqual, 20)
+	test.That(t, m.SteadyStateError, test.ShouldAlmostEqual, 0)
+
+	// a step down that falls short
+	m = ComputeStepMetrics(times, []float64{10, 8, 6, 5, 5, 5}, 0)
+	test.That(t, m.Rose, test.ShouldBeFalse)
+	test.That(t, m.Overshoot, test.ShouldEqual, 0)
+	test.That(t, m.SteadyStateError, test.ShouldAlmostEqual, 5)
+}
+
+func TestSimulate(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	_, err := Simulate(ctx, logger, SimulationConfig{Loop: velocityLoopConfig(1, 1), Duration: time.Second})
+	test.That(t, err, test.ShouldBeError, "a simulation needs a plant")
+
+	simulate := func() *SimulationResult {
+		res, err := Simulate(ctx, logger, SimulationConfig{
+			Loop:            velocityLoopConfig(0.2, 5),
+			Plant:           NewFirstOrderPlant(1, 2, 200*time.Millisecond, 0, false),
+			Duration:        5 * time.Second,
+			SetPointChanges: []SetPointChange{{At: 0, Block: "set_point", Value: 50}},
+			Targets:         []float64{50},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		return res
+	}
+	res := simulate()
+	test.That(t, len(res.Times), test.ShouldEqual, 500)
+	test.That(t, res.Times[499], test.ShouldEqual, 4990*time.Millisecond)
+	for _, name := range []string{"set_point", "sum", "PID", "endpoint"} {
+		test.That(t, len(res.Signals[name]), test.ShouldEqual, 500)
+	}
+	test.That(t, res.Signals["set_point"][0], test.ShouldResemble, []float64{50})
+	test.That(t, res.Signals["sum"][0][0], test.ShouldEqual, 50)
+	test.That(t, res.PlantOutputs[0], test.ShouldResemble, []float64{0})
+	test.That(t, res.PlantInputs[0], test.ShouldResemble, res.Signals["PID"][0])
+
+	test.That(t, len(res.Metrics), test.ShouldEqual, 1)
+	test.That(t, res.Metrics[0].Rose, test.ShouldBeTrue)
+	test.That(t, res.Metrics[0].RiseTime, test.ShouldBeBetween, 0, time.Second)
+	test.That(t, res.Metrics[0].SteadyStateError, test.ShouldBeLessThan, 0.5)
+
+	// runs are deterministic, so a gain change can be checked against a previous run
+	test.That(t, simulate(), test.ShouldResemble, res)
+
+	// a proportional only loop has a steady state error
+	res, err = Simulate(ctx, logger, SimulationConfig{
+		Loop:            velocityLoopConfig(0.5, 0),
+		Plant:           NewFirstOrderPlant(1, 2, 200*time.Millisecond, 0, false),
+		Duration:        5 * time.Second,
+		SetPointChanges: []SetPointChange{{At: time.Second, Block: "set_point", Value: 50}},
+		Targets:         []float64{50},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res.Signals["set_point"][99], test.ShouldResemble, []float64{0})
+	test.That(t, res.Signals["set_point"][100], test.ShouldResemble, []float64{50})
+	test.That(t, res.Metrics[0].SteadyStateError, test.ShouldAlmostEqual, 25, 0.1)
+}
@@ -0,0 +1,232 @@
+package control
+
+import (
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TuningResult is the outcome of auto-tuning one signal of a PID block. Besides the gains it holds
+// the process model the gains were computed from, so that tuning runs can be compared and the gains
+// can be written back into the config of the tuned component.
+type TuningResult struct {
+	Block  string `json:"block"`
+	Signal int    `json:"signal"`
+	// Type is the type of the tuned PIDConfig, e.g. linear_velocity for a sensor controlled base.
+	Type   string  `json:"type,omitempty"`
+	Method string  `json:"tune_method"`
+	P      float64 `json:"p"`
+	I      float64 `json:"i"`
+	D      float64 `json:"d"`
+
+	// UltimateGain and UltimatePeriodSec are identified by the relay methods.
+	UltimateGain      float64 `json:"ultimate_gain,omitempty"`
+	UltimatePeriodSec float64 `json:"ultimate_period_sec,omitempty"`
+
+	// ProcessGain, TimeConstantSec and DeadTimeSec describe the first order plus dead time model
+	// identified by the step response methods.
+	ProcessGain     float64 `json:"process_gain,omitempty"`
+	TimeConstantSec float64 `json:"time_constant_sec,omitempty"`
+	DeadTimeSec     float64 `json:"dead_time_sec,omitempty"`
+
+	// Converged is false when the process never settled or the identified model was unusable,
+	// in which case the gains should not be used.
+	Converged   bool      `json:"converged"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// PIDConfig returns the tuned gains in the form used by the control_parameters of motors and bases.
+func (r TuningResult) PIDConfig() PIDConfig {
+	return PIDConfig{Type: r.Type, P: r.P, I: r.I, D: r.D}
+}
+
+// ToMap returns the result as a map, e.g. for a DoCommand response.
+func (r TuningResult) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"block":               r.Block,
+		"signal":              r.Signal,
+		"type":                r.Type,
+		"tune_method":         r.Method,
+		"p":                   r.P,
+		"i":                   r.I,
+		"d":                   r.D,
+		"ultimate_gain":       r.UltimateGain,
+		"ultimate_period_sec": r.UltimatePeriodSec,
+		"process_gain":        r.ProcessGain,
+		"time_constant_sec":   r.TimeConstantSec,
+		"dead_time_sec":       r.DeadTimeSec,
+		"converged":           r.Converged,
+		"completed_at":        r.CompletedAt.Format(time.RFC3339Nano),
+	}
+}
+
+// TuningResultsToMaps converts results with ToMap, e.g. for a DoCommand response.
+func TuningResultsToMaps(results []TuningResult) []interface{} {
+	ret := make([]interface{}, 0, len(results))
+	for _, r := range results {
+		ret = append(ret, r.ToMap())
+	}
+	return ret
+}
+
+var tuneMethods = map[tuneCalcMethod]bool{
+	tuneMethodZiegerNicholsPI:            true,
+	tuneMethodZiegerNicholsPID:           true,
+	tuneMethodZiegerNicholsPD:            true,
+	tuneMethodZiegerNicholsSomeOvershoot: true,
+	tuneMethodZiegerNicholsNoOvershoot:   true,
+	tuneMethodCohenCoonsPI:               true,
+	tuneMethodCohenCoonsPID:              true,
+	tuneMethodTyreusLuybenPI:             true,
+	tuneMethodTyreusLuybenPID:            true,
+	tuneMethodIMCPI:                      true,
+	tuneMethodIMCPID:                     true,
+}
+
+// ValidateTuneMethod returns an error if method is not a supported auto-tuning method. An empty
+// method selects the default.
+func ValidateTuneMethod(method string) error {
+	if method == "" || tuneMethods[tuneCalcMethod(method)] {
+		return nil
+	}
+	return errors.Errorf("unknown tune_method %q", method)
+}
+
+// isStepResponse returns true for the methods which compute gains from a model identified by the
+// open loop step response rather than from relay oscillations.
+func (m tuneCalcMethod) isStepResponse() bool {
+	switch m {
+	case tuneMethodCohenCoonsPI, tuneMethodCohenCoonsPID, tuneMethodIMCPI, tuneMethodIMCPID:
+		return true
+	case tuneMethodZiegerNicholsPI, tuneMethodZiegerNicholsPID, tuneMethodZiegerNicholsPD,
+		tuneMethodZiegerNicholsSomeOvershoot, tuneMethodZiegerNicholsNoOvershoot,
+		tuneMethodTyreusLuybenPI, tuneMethodTyreusLuybenPID:
+	}
+	return false
+}
+
+// imcGains returns the internal model control gains for a first order plus dead time process.
+// lambda is the desired closed loop time constant, larger values give a slower but more robust loop.
+// reference for computation: Rivera, Morari & Skogestad, "Internal Model Control: PID Controller Design", 1986.
+func imcGains(method tuneCalcMethod, processGain, tau, deadTime, lambda float64) (kP, kI, kD float64) {
+	if lambda <= 0 {
+		lambda = math.Max(deadTime, 0.2*tau)
+	}
+	if method == tuneMethodIMCPI {
+		kP = tau / (processGain * (lambda + deadTime))
+		return kP, kP / tau, 0
+	}
+	tauI := tau + deadTime/2
+	tauD := tau * deadTime / (2*tau + deadTime)
+	kP = tauI / (processGain * (lambda + deadTime/2))
+	return kP, kP / tauI, kP * tauD
+}
+
+// gainsUsable returns false when the gains can't come from a stable loop: a non finite gain,
+// a non positive proportional gain or a negative integral or derivative gain.
+func gainsUsable(kP, kI, kD float64) bool {
+	for _, g := range []float64{kP, kI, kD} {
+		if math.IsNaN(g) || math.IsInf(g, 0) {
+			return false
+		}
+	}
+	return kP > 0 && kI >= 0 && kD >= 0
+}
+
+// modelUsable returns false when the identified process model is not physically meaningful, e.g.
+// a step response with a negative dead time or an oscillation that was never measured.
+func (p *pidTuner) modelUsable() bool {
+	if p.tuneMethod.isStepResponse() {
+		return p.processGain > 0 && p.tau > 0 && p.deadTime > 0
+	}
+	return p.kU > 0 && p.pU > 0
+}
+
+// pidCascadeLevels returns, for each PID block, the number of other PID blocks its output passes
+// through before reaching an endpoint. The inner blocks of a cascade have the lowest levels.
+func (l *Loop) pidCascadeLevels() map[*basicPID]int {
+	consumers := map[string][]string{}
+	for name, b := range l.blocks {
+		for _, dep := range b.blk.Config(l.cancelCtx).DependsOn {
+			consumers[dep] = append(consumers[dep], name)
+		}
+	}
+
+	levels := make(map[*basicPID]int, len(l.pidBlocks))
+	for _, pid := range l.pidBlocks {
+		visited := map[string]bool{pid.cfg.Name: true}
+		queue := []string{pid.cfg.Name}
+		for len(queue) > 0 {
+			name := queue[0]
+			queue = queue[1:]
+			// the endpoint feeds the measurement back into the loop, so stop there
+			if b, ok := l.blocks[name]; ok && b.blockType == blockEndpoint {
+				continue
+			}
+			for _, consumer := range consumers[name] {
+				if visited[consumer] {
+					continue
+				}
+				visited[consumer] = true
+				queue = append(queue, consumer)
+				if l.blocks[consumer].blockType == blockPID {
+					levels[pid]++
+				}
+			}
+		}
+	}
+	return levels
+}
+
+// startCascadeTuning holds every PID block which needs tuning except the innermost ones. When all
+// the blocks of a level are tuned, the blocks of the next level out are released. Tuning the inner
+// loops first means the outer loops are tuned against the closed inner loops they will drive.
+func (l *Loop) startCascadeTuning() {
+	l.tuneMu.Lock()
+	defer l.tuneMu.Unlock()
+	levels := l.pidCascadeLevels()
+	for _, b := range l.pidBlocks {
+		b.mu.Lock()
+		b.tuneLevel = levels[b]
+		b.onTuned = l.pidBlockTuned
+		b.tuningHeld.Store(!b.tuned.Load())
+		b.mu.Unlock()
+	}
+	l.tuneLevel = -1
+	l.advanceTuningLevel()
+}
+
+func (l *Loop) pidBlockTuned() {
+	l.tuneMu.Lock()
+	defer l.tuneMu.Unlock()
+	l.advanceTuningLevel()
+}
+
+// advanceTuningLevel releases the next level of PID blocks once the current level is tuned.
+func (l *Loop) advanceTuningLevel() {
+	next := -1
+	for _, b := range l.pidBlocks {
+		if b.tuned.Load() {
+			continue
+		}
+		if b.tuneLevel == l.tuneLevel {
+			return
+		}
+		if b.tuneLevel > l.tuneLevel && (next == -1 || b.tuneLevel < next) {
+			next = b.tuneLevel
+		}
+	}
+	if next == -1 {
+		return
+	}
+	l.tuneLevel = next
+	for _, b := range l.pidBlocks {
+		if b.tuneLevel == next {
+			if b.tuningHeld.Load() {
+				l.logger.Infof("starting to tune PID block %s", b.cfg.Name)
+			}
+			b.tuningHeld.Store(false)
+		}
+	}
+}
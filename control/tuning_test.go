@@ -0,0 +1,279 @@
+package control
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+func TestValidateTuneMethod(t *testing.T) {
+	test.That(t, ValidateTuneMethod(""), test.ShouldBeNil)
+	test.That(t, ValidateTuneMethod("ziegerNicholsPI"), test.ShouldBeNil)
+	test.That(t, ValidateTuneMethod("cohenCoonsPID"), test.ShouldBeNil)
+	test.That(t, ValidateTuneMethod("imcPID"), test.ShouldBeNil)
+	test.That(t, ValidateTuneMethod("imcPIDD"), test.ShouldBeError, `unknown tune_method "imcPIDD"`)
+
+	logger := logging.NewTestLogger(t)
+	_, err := loop.newPID(BlockConfig{
+		Name: "PID",
+		Attribute: utils.AttributeMap{
+			"PIDSets":     []*PIDConfig{{}},
+			"tune_method": "bangBang",
+		},
+		Type:      "PID",
+		DependsOn: []string{"A"},
+	}, logger)
+	test.That(t, err, test.ShouldBeError, `tuner pid block PID: unknown tune_method "bangBang"`)
+}
+
+func TestStepResponseTuning(t *testing.T) {
+	// a step response which reached 50% of its steady state after 0.5s and 63.2% after 0.7s
+	newTuner := func(method tuneCalcMethod) *pidTuner {
+		tuner := &pidTuner{
+			tuneMethod: method,
+			limUp:      100,
+			stepPct:    0.5,
+			avgSpeedSS: 100,
+			ccT2:       500 * time.Millisecond,
+			ccT3:       700 * time.Millisecond,
+		}
+		test.That(t, tuner.reset(), test.ShouldBeNil)
+		return tuner
+	}
+	deadTime := (0.5 - math.Log(2.0)*0.7) / (1.0 - math.Log(2.0))
+	tau := 0.7 - deadTime
+
+	tuner := newTuner(tuneMethodIMCPI)
+	tuner.computeGains()
+	test.That(t, tuner.processGain, test.ShouldAlmostEqual, 2)
+	test.That(t, tuner.deadTime, test.ShouldAlmostEqual, deadTime)
+	test.That(t, tuner.tau, test.ShouldAlmostEqual, tau)
+	// the default closed loop time constant is the larger of the dead time and a fifth of tau
+	lambda := math.Max(deadTime, 0.2*tau)
+	test.That(t, tuner.kP, test.ShouldAlmostEqual, tau/(2*(lambda+deadTime)))
+	test.That(t, tuner.kI, test.ShouldAlmostEqual, tuner.kP/tau)
+	test.That(t, tuner.kD, test.ShouldEqual, 0)
+
+	tuner = newTuner(tuneMethodIMCPID)
+	tuner.imcLambda = 0.25
+	tuner.computeGains()
+	tauI := tau + deadTime/2
+	test.That(t, tuner.kP, test.ShouldAlmostEqual, tauI/(2*(0.25+deadTime/2)))
+	test.That(t, tuner.kI, test.ShouldAlmostEqual, tuner.kP/tauI)
+	test.That(t, tuner.kD, test.ShouldAlmostEqual, tuner.kP*tau*deadTime/(2*tau+deadTime))
+
+	result := tuner.result("PID", 1)
+	test.That(t, result.Block, test.ShouldEqual, "PID")
+	test.That(t, result.Signal, test.ShouldEqual, 1)
+	test.That(t, result.Method, test.ShouldEqual, "imcPID")
+	test.That(t, result.Converged, test.ShouldBeTrue)
+	test.That(t, result.ProcessGain, test.ShouldAlmostEqual, 2)
+	test.That(t, result.TimeConstantSec, test.ShouldAlmostEqual, tau)
+	test.That(t, result.DeadTimeSec, test.ShouldAlmostEqual, deadTime)
+	test.That(t, result.UltimateGain, test.ShouldEqual, 0)
+	test.That(t, result.PIDConfig(), test.ShouldResemble, PIDConfig{P: tuner.kP, I: tuner.kI, D: tuner.kD})
+
+	// Cohen-Coon uses the same model, and a step response which never rose can't be used
+	tuner = newTuner(tuneMethodCohenCoonsPI)
+	tuner.computeGains()
+	test.That(t, tuner.deadTime, test.ShouldAlmostEqual, deadTime)
+	test.That(t, tuner.converged, test.ShouldBeTrue)
+	tuner = newTuner(tuneMethodCohenCoonsPI)
+	tuner.ccT2, tuner.ccT3 = 0, 0
+	tuner.computeGains()
+	test.That(t, tuner.result("PID", 0).Converged, test.ShouldBeFalse)
+
+	// a step response which reached 50% of its steady state too early gives a negative dead time
+	tuner = newTuner(tuneMethodIMCPI)
+	tuner.ccT2 = 300 * time.Millisecond
+	tuner.computeGains()
+	test.That(t, tuner.deadTime, test.ShouldBeLessThan, 0)
+	test.That(t, tuner.converged, test.ShouldBeFalse)
+
+	// a process which never moved has no gain
+	tuner = newTuner(tuneMethodIMCPID)
+	tuner.avgSpeedSS = 0
+	tuner.computeGains()
+	test.That(t, tuner.converged, test.ShouldBeFalse)
+}
+
+func TestGainsUsable(t *testing.T) {
+	test.That(t, gainsUsable(1, 0.5, 0), test.ShouldBeTrue)
+	test.That(t, gainsUsable(0, 0.5, 0), test.ShouldBeFalse)
+	test.That(t, gainsUsable(-1, 0.5, 0), test.ShouldBeFalse)
+	test.That(t, gainsUsable(1, -0.5, 0), test.ShouldBeFalse)
+	test.That(t, gainsUsable(1, 0.5, -0.1), test.ShouldBeFalse)
+	test.That(t, gainsUsable(math.Inf(1), 0.5, 0), test.ShouldBeFalse)
+	test.That(t, gainsUsable(1, math.NaN(), 0), test.ShouldBeFalse)
+}
+
+func TestPIDTuningResult(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	l := Loop{logger: logger}
+	b, err := l.newPID(BlockConfig{
+		Name: "PID",
+		Attribute: utils.AttributeMap{
+			"PIDSets":        []*PIDConfig{{Type: "linear_velocity"}},
+			"limit_up":       255.0,
+			"tune_method":    "imcPI",
+			"tune_ssr_value": 2.0,
+		},
+		Type:      "PID",
+		DependsOn: []string{"A"},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	pid := b.(*basicPID)
+	var tuned bool
+	pid.onTuned = func() { tuned = true }
+	test.That(t, pid.TuningResults(), test.ShouldBeEmpty)
+
+	s := []*Signal{makeSignals("A", "sum", 1)}
+	dt := 10 * time.Millisecond
+	// rise to a steady state, after which the step response methods stop driving the process
+	for i := 0; i < 21; i++ {
+		s[0].SetSignalValueAt(0, math.Min(s[0].GetSignalValueAt(0)+5, 100))
+		pid.Next(ctx, s, dt)
+		// the tuner times the step response with the wall clock
+		time.Sleep(time.Millisecond)
+	}
+	for i := 0; i < 30 && pid.tuners[0].currentPhase != end; i++ {
+		s[0].SetSignalValueAt(0, 100)
+		pid.Next(ctx, s, dt)
+	}
+	test.That(t, pid.tuners[0].currentPhase, test.ShouldEqual, end)
+	test.That(t, pid.GetTuning(), test.ShouldBeTrue)
+
+	// tuning completes once the process has stopped
+	s[0].SetSignalValueAt(0, 0)
+	pid.Next(ctx, s, dt)
+	test.That(t, pid.GetTuning(), test.ShouldBeFalse)
+	test.That(t, tuned, test.ShouldBeTrue)
+	results := pid.TuningResults()
+	test.That(t, len(results), test.ShouldEqual, 1)
+	test.That(t, results[0].Block, test.ShouldEqual, "PID")
+	test.That(t, results[0].Type, test.ShouldEqual, "linear_velocity")
+	test.That(t, results[0].Method, test.ShouldEqual, "imcPI")
+	test.That(t, results[0].ProcessGain, test.ShouldAlmostEqual, pid.tuners[0].avgSpeedSS/(255*0.35))
+	test.That(t, results[0].Converged, test.ShouldBeTrue)
+	test.That(t, results[0].P, test.ShouldEqual, pid.PIDSets[0].P)
+	test.That(t, results[0].I, test.ShouldEqual, pid.PIDSets[0].I)
+}
+
+func TestCascadeTuning(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	pidAttributes := func() utils.AttributeMap {
+		return utils.AttributeMap{
+			"PIDSets":        []*PIDConfig{{}},
+			"limit_up":       255.0,
+			"limit_lo":       -255.0,
+			"tune_ssr_value": 2.0,
+		}
+	}
+	// position_PID drives the set point of velocity_PID, which drives the motor
+	cfg := Config{
+		Blocks: []BlockConfig{
+			{
+				Name:      "set_point",
+				Type:      "constant",
+				Attribute: utils.AttributeMap{"constant_val": 0.0},
+			},
+			{
+				Name:      "position_sum",
+				Type:      "sum",
+				Attribute: utils.AttributeMap{"sum_string": "+-"},
+				DependsOn: []string{"set_point", "endpoint"},
+			},
+			{
+				Name:      "position_PID",
+				Type:      "PID",
+				Attribute: pidAttributes(),
+				DependsOn: []string{"position_sum"},
+			},
+			{
+				Name:      "velocity_sum",
+				Type:      "sum",
+				Attribute: utils.AttributeMap{"sum_string": "+-"},
+				DependsOn: []string{"position_PID", "derivative"},
+			},
+			{
+				Name:      "derivative",
+				Type:      "derivative",
+				Attribute: utils.AttributeMap{"derive_type": "backward1st1"},
+				DependsOn: []string{"endpoint"},
+			},
+			{
+				Name:      "velocity_PID",
+				Type:      "PID",
+				Attribute: pidAttributes(),
+				DependsOn: []string{"velocity_sum"},
+			},
+			{
+				Name:      "endpoint",
+				Type:      "endpoint",
+				Attribute: utils.AttributeMap{"motor_name": "motor"},
+				DependsOn: []string{"velocity_PID"},
+			},
+		},
+		Frequency: 20.0,
+	}
+	cLoop, err := createLoop(logger, cfg, nil)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, cLoop.Start(), test.ShouldBeNil)
+		cLoop.Stop()
+	}()
+
+	outer := cLoop.blocks["position_PID"].blk.(*basicPID)
+	inner := cLoop.blocks["velocity_PID"].blk.(*basicPID)
+	test.That(t, outer.tuneLevel, test.ShouldEqual, 1)
+	test.That(t, inner.tuneLevel, test.ShouldEqual, 0)
+	test.That(t, cLoop.GetTuning(ctx), test.ShouldBeTrue)
+
+	// the inner loop is tuned first while the outer loop leaves its set point at zero
+	test.That(t, inner.tuningHeld.Load(), test.ShouldBeFalse)
+	test.That(t, outer.tuningHeld.Load(), test.ShouldBeTrue)
+	s := []*Signal{makeSignals("position_sum", "sum", 1)}
+	s[0].SetSignalValueAt(0, 50)
+	out, ok := outer.Next(ctx, s, cLoop.dt)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, 0)
+	test.That(t, outer.tuners[0].currentPhase, test.ShouldEqual, begin)
+
+	// once the inner loop is tuned, the outer loop starts tuning
+	inner.mu.Lock()
+	inner.tuners[0].tuning = false
+	inner.mu.Unlock()
+	inner.tuned.Store(true)
+	inner.onTuned()
+	test.That(t, outer.tuningHeld.Load(), test.ShouldBeFalse)
+	out, _ = outer.Next(ctx, s, cLoop.dt)
+	test.That(t, out[0].GetSignalValueAt(0), test.ShouldEqual, 255.0*0.35)
+	test.That(t, outer.tuners[0].currentPhase, test.ShouldEqual, step)
+
+	// a block finishing tuning while cascade tuning restarts doesn't deadlock
+	inner.mu.Lock()
+	inner.tuners[0].tuning = true
+	inner.tuners[0].currentPhase = end
+	inner.mu.Unlock()
+	inner.tuned.Store(false)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		inner.Next(ctx, []*Signal{makeSignals("velocity_sum", "sum", 1)}, cLoop.dt)
+	}()
+	cLoop.startCascadeTuning()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("PID block and cascade tuning deadlocked")
+	}
+	test.That(t, inner.tuned.Load(), test.ShouldBeTrue)
+}
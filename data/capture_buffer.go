@@ -16,9 +16,14 @@ type CaptureBufferedWriter interface {
 }
 
 // CaptureBuffer is a persistent queue of SensorData backed by a series of *data.CaptureFile.
+// Each completed capture file is accompanied by a CaptureIndex of its readings' offsets and times,
+// which QueryCaptureDir uses to read time ranges without scanning every file.
 type CaptureBuffer struct {
-	Directory          string
-	MetaData           *v1.DataCaptureMetadata
+	Directory string
+	MetaData  *v1.DataCaptureMetadata
+	// NotifySealed, if set, is called with every capture file the buffer completes. It must not
+	// block, as it is called while writing.
+	NotifySealed       func(SealedCaptureFile)
 	nextFile           *CaptureFile
 	lock               sync.Mutex
 	maxCaptureFileSize int64
@@ -55,7 +60,7 @@ func (b *CaptureBuffer) WriteBinary(items []*v1.SensorData) error {
 		}
 	}
 
-	binFile, err := NewCaptureFile(b.Directory, b.MetaData)
+	binFile, err := b.newCaptureFile()
 	if err != nil {
 		return err
 	}
@@ -85,7 +90,7 @@ func (b *CaptureBuffer) WriteTabular(item *v1.SensorData) error {
 	}
 
 	if b.nextFile == nil {
-		nextFile, err := NewCaptureFile(b.Directory, b.MetaData)
+		nextFile, err := b.newCaptureFile()
 		if err != nil {
 			return err
 		}
@@ -94,7 +99,7 @@ func (b *CaptureBuffer) WriteTabular(item *v1.SensorData) error {
 		if err := b.nextFile.Close(); err != nil {
 			return err
 		}
-		nextFile, err := NewCaptureFile(b.Directory, b.MetaData)
+		nextFile, err := b.newCaptureFile()
 		if err != nil {
 			return err
 		}
@@ -108,6 +113,15 @@ func (b *CaptureBuffer) WriteTabular(item *v1.SensorData) error {
 	return nil
 }
 
+func (b *CaptureBuffer) newCaptureFile() (*CaptureFile, error) {
+	f, err := NewCaptureFile(b.Directory, b.MetaData)
+	if err != nil {
+		return nil, err
+	}
+	f.onSealed = b.NotifySealed
+	return f, nil
+}
+
 // IsBinary returns true when the *v1.SensorData is of type binary.
 func IsBinary(item *v1.SensorData) bool {
 	if item == nil {
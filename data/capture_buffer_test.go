@@ -261,7 +261,7 @@ func TestCaptureBufferReader(t *testing.T) {
 				}
 				test.That(t, b.WriteTabular(msg), test.ShouldBeNil)
 				test.That(t, b.Flush(), test.ShouldBeNil)
-				dirEntries, err := os.ReadDir(b.Path())
+				dirEntries, err := readCaptureDir(b.Path())
 				test.That(t, err, test.ShouldBeNil)
 				test.That(t, len(dirEntries), test.ShouldEqual, 1)
 				test.That(t, filepath.Ext(dirEntries[0].Name()), test.ShouldResemble, CompletedCaptureFileExt)
@@ -308,7 +308,7 @@ func TestCaptureBufferReader(t *testing.T) {
 				}
 				test.That(t, b.WriteTabular(msg3), test.ShouldBeNil)
 
-				dirEntries2, err := os.ReadDir(b.Path())
+				dirEntries2, err := readCaptureDir(b.Path())
 				test.That(t, err, test.ShouldBeNil)
 				// msg 2 and msg 3 should be in the newly written capture file
 				test.That(t, len(dirEntries2), test.ShouldEqual, 2)
@@ -322,7 +322,7 @@ func TestCaptureBufferReader(t *testing.T) {
 
 				test.That(t, b.Flush(), test.ShouldBeNil)
 
-				dirEntries3, err := os.ReadDir(b.Path())
+				dirEntries3, err := readCaptureDir(b.Path())
 				test.That(t, err, test.ShouldBeNil)
 				test.That(t, len(dirEntries3), test.ShouldEqual, 2)
 				hasProgFile = false
@@ -402,7 +402,7 @@ func TestCaptureBufferReader(t *testing.T) {
 			}
 			test.That(t, b.WriteTabular(msg), test.ShouldBeError, errInvalidTabularSensorData)
 			test.That(t, b.Flush(), test.ShouldBeNil)
-			dirEntries, err := os.ReadDir(b.Path())
+			dirEntries, err := readCaptureDir(b.Path())
 			test.That(t, err, test.ShouldBeNil)
 			// no data written
 			test.That(t, len(dirEntries), test.ShouldEqual, 0)
@@ -508,14 +508,14 @@ func TestCaptureBufferReader(t *testing.T) {
 
 				// flushing before Write() doesn't create any files
 				test.That(t, b.Flush(), test.ShouldBeNil)
-				firstDirEntries, err := os.ReadDir(b.Path())
+				firstDirEntries, err := readCaptureDir(b.Path())
 				test.That(t, err, test.ShouldBeNil)
 				test.That(t, firstDirEntries, test.ShouldBeEmpty)
 
 				// flushing after this error occures, behaves the same as if no write had occurred
 				// current behavior is likely a bug
 				test.That(t, b.Flush(), test.ShouldBeNil)
-				firstDirEntries, err = os.ReadDir(b.Path())
+				firstDirEntries, err = readCaptureDir(b.Path())
 				test.That(t, err, test.ShouldBeNil)
 				test.That(t, len(firstDirEntries), test.ShouldEqual, 0)
 
@@ -533,7 +533,7 @@ func TestCaptureBufferReader(t *testing.T) {
 				}}
 				test.That(t, b.WriteBinary(msg), test.ShouldBeNil)
 				test.That(t, b.Flush(), test.ShouldBeNil)
-				secondDirEntries, err := os.ReadDir(b.Path())
+				secondDirEntries, err := readCaptureDir(b.Path())
 				test.That(t, err, test.ShouldBeNil)
 				test.That(t, len(secondDirEntries), test.ShouldEqual, 1)
 				newFileName := secondDirEntries[0].Name()
@@ -582,7 +582,7 @@ func TestCaptureBufferReader(t *testing.T) {
 				// Every binary data written becomes a new data capture file
 				test.That(t, b.WriteBinary(msg4), test.ShouldBeNil)
 				test.That(t, b.Flush(), test.ShouldBeNil)
-				thirdDirEntries, err := os.ReadDir(b.Path())
+				thirdDirEntries, err := readCaptureDir(b.Path())
 				test.That(t, err, test.ShouldBeNil)
 				test.That(t, len(thirdDirEntries), test.ShouldEqual, 3)
 
@@ -671,7 +671,7 @@ func TestCaptureBufferReader(t *testing.T) {
 		}}
 		test.That(t, b.WriteBinary(msg), test.ShouldBeNil)
 		test.That(t, b.Flush(), test.ShouldBeNil)
-		dirEntries, err := os.ReadDir(b.Path())
+		dirEntries, err := readCaptureDir(b.Path())
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, len(dirEntries), test.ShouldEqual, 1)
 		test.That(t, filepath.Ext(dirEntries[0].Name()), test.ShouldResemble, CompletedCaptureFileExt)
@@ -748,12 +748,27 @@ func TestCaptureBufferReader(t *testing.T) {
 
 		test.That(t, b.WriteBinary(msg), test.ShouldBeError, errInvalidBinarySensorData)
 		test.That(t, b.Flush(), test.ShouldBeNil)
-		dirEntries, err := os.ReadDir(b.Path())
+		dirEntries, err := readCaptureDir(b.Path())
 		test.That(t, err, test.ShouldBeNil)
 		test.That(t, len(dirEntries), test.ShouldEqual, 0)
 	})
 }
 
+// readCaptureDir returns the entries of dir, excluding capture file indexes.
+func readCaptureDir(dir string) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var ret []os.DirEntry
+	for _, entry := range entries {
+		if !IsCaptureIndexFile(entry.Name()) {
+			ret = append(ret, entry)
+		}
+	}
+	return ret, nil
+}
+
 func getCaptureFiles(dir string) (dcFiles, progFiles []string) {
 	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -779,3 +794,40 @@ func TestIsBinary(t *testing.T) {
 	test.That(t, IsBinary(&v1.SensorData{Data: &v1.SensorData_Struct{}}), test.ShouldBeFalse)
 	test.That(t, IsBinary(&v1.SensorData{Data: &v1.SensorData_Binary{}}), test.ShouldBeTrue)
 }
+
+func TestCaptureBufferNotifySealed(t *testing.T) {
+	dir := t.TempDir()
+	md := &v1.DataCaptureMetadata{
+		ComponentName: "sensor",
+		Type:          v1.DataType_DATA_TYPE_TABULAR_SENSOR,
+	}
+	var sealed []SealedCaptureFile
+	b := NewCaptureBuffer(dir, md, 1)
+	b.NotifySealed = func(f SealedCaptureFile) {
+		sealed = append(sealed, f)
+	}
+
+	reading := &v1.SensorData{Data: &v1.SensorData_Struct{Struct: &structpb.Struct{}}}
+	test.That(t, b.WriteTabular(reading), test.ShouldBeNil)
+	test.That(t, sealed, test.ShouldBeEmpty)
+	// the file is over the maximum size, so the next reading completes it
+	test.That(t, b.WriteTabular(reading), test.ShouldBeNil)
+	test.That(t, len(sealed), test.ShouldEqual, 1)
+	test.That(t, filepath.Ext(sealed[0].Path), test.ShouldEqual, CompletedCaptureFileExt)
+	test.That(t, sealed[0].Metadata.GetComponentName(), test.ShouldEqual, "sensor")
+	test.That(t, b.Flush(), test.ShouldBeNil)
+	test.That(t, len(sealed), test.ShouldEqual, 2)
+
+	// closing a completed file that was opened for reading does not seal it again
+	file, err := os.Open(sealed[0].Path)
+	test.That(t, err, test.ShouldBeNil)
+	read, err := ReadCaptureFile(file)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, read.Close(), test.ShouldBeNil)
+
+	// nor are files written by anything but the buffer reported
+	f, err := NewCaptureFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+	test.That(t, len(sealed), test.ShouldEqual, 2)
+}
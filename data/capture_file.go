@@ -12,6 +12,7 @@ import (
 	"github.com/matttproud/golang_protobuf_extensions/pbutil"
 	"github.com/pkg/errors"
 	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils"
 	"google.golang.org/protobuf/types/known/anypb"
 
 	"go.viam.com/rdk/resource"
@@ -52,6 +53,12 @@ type CaptureFile struct {
 	initialReadOffset int64
 	readOffset        int64
 	writeOffset       int64
+
+	// index records the offset and time of each SensorData written. It is persisted alongside the
+	// file when the file is closed.
+	index CaptureIndex
+	// onSealed, if set, is called once the file is completed.
+	onSealed func(SealedCaptureFile)
 }
 
 // ReadCaptureFile creates a File struct from a passed os.File previously constructed using NewFile.
@@ -104,6 +111,7 @@ func NewCaptureFile(dir string, md *v1.DataCaptureMetadata) (*CaptureFile, error
 		writer:            bufio.NewWriter(f),
 		file:              f,
 		size:              int64(n),
+		metadata:          md,
 		initialReadOffset: int64(n),
 		readOffset:        int64(n),
 		writeOffset:       int64(n),
@@ -149,6 +157,7 @@ func (f *CaptureFile) WriteNext(data *v1.SensorData) error {
 	if err != nil {
 		return err
 	}
+	f.index.add(f.writeOffset, data)
 	f.size += int64(n)
 	f.writeOffset += int64(n)
 	return nil
@@ -188,13 +197,33 @@ func (f *CaptureFile) Close() error {
 		return err
 	}
 
+	// The index is written before the rename below, so a completed capture file is never read
+	// without the index it was written with. The index only speeds up local queries, files without
+	// one are scanned. So failing to write it is not an error.
+	if len(f.index.Entries) > 0 {
+		utils.UncheckedError(writeCaptureIndex(f.file.Name(), &f.index))
+	}
+
 	// Rename file to indicate that it is done being written.
+	sealing := filepath.Ext(f.file.Name()) == InProgressCaptureFileExt
 	withoutExt := strings.TrimSuffix(f.file.Name(), filepath.Ext(f.file.Name()))
 	newName := withoutExt + CompletedCaptureFileExt
 	if err := f.file.Close(); err != nil {
 		return err
 	}
-	return os.Rename(f.file.Name(), newName)
+	if err := os.Rename(f.file.Name(), newName); err != nil {
+		return err
+	}
+	if sealing && f.onSealed != nil {
+		f.onSealed(SealedCaptureFile{Path: newName, Metadata: f.metadata})
+	}
+	return nil
+}
+
+// SealedCaptureFile describes a capture file which was completed and will no longer be written to.
+type SealedCaptureFile struct {
+	Path     string
+	Metadata *v1.DataCaptureMetadata
 }
 
 // Delete deletes the file.
@@ -204,7 +233,10 @@ func (f *CaptureFile) Delete() error {
 	if err := f.file.Close(); err != nil {
 		return err
 	}
-	return os.Remove(f.GetPath())
+	if err := os.Remove(f.GetPath()); err != nil {
+		return err
+	}
+	return RemoveCaptureIndex(f.GetPath())
 }
 
 // BuildCaptureMetadata builds a DataCaptureMetadata object and returns error if
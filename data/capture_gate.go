@@ -0,0 +1,96 @@
+package data
+
+import (
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// CaptureGate limits a collector to writing the readings captured around the moments it is opened,
+// e.g. when a sensor reading crosses a threshold. While closed, the readings of the last preWindow
+// are held in memory and the rest are dropped. When opened, the held readings are written and every
+// reading is written until postWindow has elapsed since the gate was last opened.
+type CaptureGate struct {
+	clock      clock.Clock
+	preWindow  time.Duration
+	postWindow time.Duration
+
+	mu        sync.Mutex
+	openUntil time.Time
+	held      []heldCaptureResult
+}
+
+type heldCaptureResult struct {
+	capturedAt time.Time
+	result     CaptureResult
+}
+
+// NewCaptureGate returns a closed CaptureGate.
+func NewCaptureGate(preWindow, postWindow time.Duration, clk clock.Clock) *CaptureGate {
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &CaptureGate{clock: clk, preWindow: preWindow, postWindow: postWindow}
+}
+
+// Open opens the gate until the post window has elapsed. Opening an open gate extends the time it
+// stays open.
+func (g *CaptureGate) Open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if openUntil := g.clock.Now().Add(g.postWindow); openUntil.After(g.openUntil) {
+		g.openUntil = openUntil
+	}
+}
+
+// IsOpen returns true if readings captured now are written.
+func (g *CaptureGate) IsOpen() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.isOpen(g.clock.Now())
+}
+
+func (g *CaptureGate) isOpen(now time.Time) bool {
+	return !now.After(g.openUntil)
+}
+
+// wantsReadings returns false when a reading captured now would be dropped, so the collector can
+// skip capturing it.
+func (g *CaptureGate) wantsReadings() bool {
+	return g.preWindow > 0 || g.IsOpen()
+}
+
+// admit returns the readings that should be written now that result was captured. That is
+// result along with any held readings when the gate is open, and nothing otherwise.
+func (g *CaptureGate) admit(result CaptureResult) []CaptureResult {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.clock.Now()
+	g.dropExpired(now)
+	if g.isOpen(now) {
+		ret := make([]CaptureResult, 0, len(g.held)+1)
+		for _, held := range g.held {
+			ret = append(ret, held.result)
+		}
+		g.held = nil
+		return append(ret, result)
+	}
+
+	if g.preWindow <= 0 {
+		return nil
+	}
+	g.held = append(g.held, heldCaptureResult{capturedAt: now, result: result})
+	return nil
+}
+
+// dropExpired drops the held readings captured before the pre window.
+func (g *CaptureGate) dropExpired(now time.Time) {
+	cutoff := now.Add(-g.preWindow)
+	expired := 0
+	for expired < len(g.held) && g.held[expired].capturedAt.Before(cutoff) {
+		expired++
+	}
+	// Shift rather than reslice so the backing array doesn't grow without bound.
+	g.held = append(g.held[:0], g.held[expired:]...)
+}
@@ -0,0 +1,103 @@
+package data
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"go.viam.com/rdk/logging"
+)
+
+func gateTestResult(n int) CaptureResult {
+	return CaptureResult{
+		Type:       CaptureTypeTabular,
+		Timestamps: Timestamps{TimeRequested: time.Unix(int64(n), 0)},
+	}
+}
+
+func admittedSeconds(results []CaptureResult) []int64 {
+	ret := []int64{}
+	for _, res := range results {
+		ret = append(ret, res.TimeRequested.Unix())
+	}
+	return ret
+}
+
+func TestCaptureGate(t *testing.T) {
+	mockClock := clock.NewMock()
+	gate := NewCaptureGate(2*time.Second, 3*time.Second, mockClock)
+	test.That(t, gate.IsOpen(), test.ShouldBeFalse)
+
+	// While closed, only the readings of the last 2 seconds are held.
+	for n := 0; n < 5; n++ {
+		test.That(t, gate.admit(gateTestResult(n)), test.ShouldBeEmpty)
+		mockClock.Add(time.Second)
+	}
+
+	// Opening writes the held readings along with the next one.
+	gate.Open()
+	test.That(t, gate.IsOpen(), test.ShouldBeTrue)
+	test.That(t, admittedSeconds(gate.admit(gateTestResult(5))), test.ShouldResemble, []int64{3, 4, 5})
+	mockClock.Add(3 * time.Second)
+	test.That(t, admittedSeconds(gate.admit(gateTestResult(8))), test.ShouldResemble, []int64{8})
+
+	// The gate closes once the post window elapses.
+	mockClock.Add(time.Second)
+	test.That(t, gate.IsOpen(), test.ShouldBeFalse)
+	test.That(t, gate.admit(gateTestResult(9)), test.ShouldBeEmpty)
+
+	// Opening an open gate extends the post window.
+	gate.Open()
+	mockClock.Add(2 * time.Second)
+	gate.Open()
+	mockClock.Add(2 * time.Second)
+	test.That(t, gate.IsOpen(), test.ShouldBeTrue)
+	test.That(t, admittedSeconds(gate.admit(gateTestResult(13))), test.ShouldResemble, []int64{13})
+}
+
+func TestCollectorWithGate(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	mockClock := clock.NewMock()
+	target := newSignalingBuffer(ctx, t.TempDir())
+	interval := 5 * time.Millisecond
+	gate := NewCaptureGate(0, interval, mockClock)
+
+	var captures atomic.Int64
+	c, err := NewCollector(func(ctx context.Context, _ map[string]*anypb.Any) (CaptureResult, error) {
+		captures.Add(1)
+		return dummyStructReading, nil
+	}, CollectorParams{
+		DataType:      CaptureTypeTabular,
+		ComponentName: "testComponent",
+		Interval:      interval,
+		Target:        target,
+		QueueSize:     queueSize,
+		BufferSize:    bufferSize,
+		Logger:        logging.NewTestLogger(t),
+		Clock:         mockClock,
+		Gate:          gate,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	c.Collect()
+	defer c.Close()
+
+	// Without a pre window, nothing is captured while the gate is closed.
+	mockClock.Add(interval)
+	mockClock.Add(interval)
+	test.That(t, captures.Load(), test.ShouldEqual, 0)
+
+	gate.Open()
+	mockClock.Add(interval)
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for data to be written")
+	case <-target.wrote:
+	}
+	test.That(t, captures.Load(), test.ShouldEqual, 1)
+}
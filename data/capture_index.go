@@ -0,0 +1,300 @@
+package data
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/matttproud/golang_protobuf_extensions/pbutil"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils"
+)
+
+// CaptureIndexFileExt defines the file extension for the index written alongside each completed
+// data capture file. It includes the capture file extension so that it does not match arbitrary
+// files synced from additional sync paths.
+const CaptureIndexFileExt = CompletedCaptureFileExt + ".idx"
+
+// CaptureIndexEntry locates a single SensorData message within a capture file.
+type CaptureIndexEntry struct {
+	// Offset is the byte offset of the length delimited SensorData message.
+	Offset int64 `json:"offset"`
+	// TimeNs is the time the SensorData was requested in nanoseconds since the epoch.
+	TimeNs int64 `json:"time_ns"`
+}
+
+// CaptureIndex describes where each SensorData message of a capture file is and when it was
+// captured. It allows reading the messages within a time window without scanning the file.
+type CaptureIndex struct {
+	StartTimeNs int64               `json:"start_time_ns"`
+	EndTimeNs   int64               `json:"end_time_ns"`
+	Entries     []CaptureIndexEntry `json:"entries"`
+}
+
+func (idx *CaptureIndex) add(offset int64, data *v1.SensorData) {
+	timeNs := sensorDataTimeNs(data)
+	if len(idx.Entries) == 0 || timeNs < idx.StartTimeNs {
+		idx.StartTimeNs = timeNs
+	}
+	if len(idx.Entries) == 0 || timeNs > idx.EndTimeNs {
+		idx.EndTimeNs = timeNs
+	}
+	idx.Entries = append(idx.Entries, CaptureIndexEntry{Offset: offset, TimeNs: timeNs})
+}
+
+// sensorDataTimeNs returns the time the SensorData was requested, falling back to the time it
+// was received.
+func sensorDataTimeNs(data *v1.SensorData) int64 {
+	md := data.GetMetadata()
+	switch {
+	case md.GetTimeRequested() != nil:
+		return md.GetTimeRequested().AsTime().UnixNano()
+	case md.GetTimeReceived() != nil:
+		return md.GetTimeReceived().AsTime().UnixNano()
+	default:
+		return 0
+	}
+}
+
+// IsCaptureIndexFile returns whether the file at path is the index of a data capture file.
+func IsCaptureIndexFile(path string) bool {
+	return strings.HasSuffix(path, CaptureIndexFileExt)
+}
+
+// CaptureIndexPath returns the path of the index for the capture file at captureFilePath.
+func CaptureIndexPath(captureFilePath string) string {
+	return strings.TrimSuffix(captureFilePath, filepath.Ext(captureFilePath)) + CaptureIndexFileExt
+}
+
+// RemoveCaptureIndex deletes the index of the capture file at captureFilePath, if one exists.
+func RemoveCaptureIndex(captureFilePath string) error {
+	if err := os.Remove(CaptureIndexPath(captureFilePath)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// writeCaptureIndex writes idx for the capture file at captureFilePath and syncs it to disk, so that
+// a completed capture file is never paired with a partially written index.
+func writeCaptureIndex(captureFilePath string, idx *CaptureIndex) error {
+	out, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+	//nolint:gosec
+	f, err := os.OpenFile(CaptureIndexPath(captureFilePath), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(out); err != nil {
+		utils.UncheckedError(f.Close())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		utils.UncheckedError(f.Close())
+		return err
+	}
+	return f.Close()
+}
+
+// ReadCaptureIndex reads the index of the capture file at captureFilePath.
+func ReadCaptureIndex(captureFilePath string) (*CaptureIndex, error) {
+	//nolint:gosec
+	contents, err := os.ReadFile(CaptureIndexPath(captureFilePath))
+	if err != nil {
+		return nil, err
+	}
+	var idx CaptureIndex
+	if err := json.Unmarshal(contents, &idx); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse capture index of %s", captureFilePath)
+	}
+	return &idx, nil
+}
+
+// CaptureQuery selects SensorData by the time it was requested. A zero Start or End leaves that
+// end of the time window unbounded. A positive Limit caps the number of results.
+type CaptureQuery struct {
+	Start time.Time
+	End   time.Time
+	Limit int
+}
+
+func (q CaptureQuery) contains(timeNs int64) bool {
+	if !q.Start.IsZero() && timeNs < q.Start.UnixNano() {
+		return false
+	}
+	if !q.End.IsZero() && timeNs > q.End.UnixNano() {
+		return false
+	}
+	return true
+}
+
+func (q CaptureQuery) overlaps(idx *CaptureIndex) bool {
+	if !q.Start.IsZero() && idx.EndTimeNs < q.Start.UnixNano() {
+		return false
+	}
+	if !q.End.IsZero() && idx.StartTimeNs > q.End.UnixNano() {
+		return false
+	}
+	return true
+}
+
+// QueryCaptureDir returns the SensorData in the capture files of dir that fall within the query's
+// time window, ordered by time. Completed capture files are read using their index, files without
+// an index are scanned. In progress files are scanned up to the last reading flushed to disk.
+//
+// Capture files are visited from oldest to newest. When the query has a Limit, no further files are
+// read once enough results are found, so the earliest matching readings are returned.
+func QueryCaptureDir(dir string, query CaptureQuery) ([]*v1.SensorData, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ret []*v1.SensorData
+	for _, entry := range entries {
+		if query.Limit > 0 && len(ret) >= query.Limit {
+			break
+		}
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		var fileData []*v1.SensorData
+		switch filepath.Ext(path) {
+		case CompletedCaptureFileExt:
+			idx, err := ReadCaptureIndex(path)
+			if err != nil {
+				// Files written before indexing existed, or whose index failed to write, are scanned.
+				fileData, err = scanCaptureFile(path, query)
+				if err != nil {
+					return nil, err
+				}
+				break
+			}
+			if !query.overlaps(idx) {
+				continue
+			}
+			fileData, err = readIndexedCaptureFile(path, idx, query)
+			if err != nil {
+				return nil, err
+			}
+		case InProgressCaptureFileExt:
+			fileData, err = scanCaptureFile(path, query)
+			if err != nil {
+				return nil, err
+			}
+		}
+		ret = append(ret, fileData...)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return sensorDataTimeNs(ret[i]) < sensorDataTimeNs(ret[j])
+	})
+	if query.Limit > 0 && len(ret) > query.Limit {
+		ret = ret[:query.Limit]
+	}
+	return ret, nil
+}
+
+// RemoveOrphanCaptureIndex deletes the index at indexPath if the capture file it describes no longer
+// exists. The capture file an index describes is deleted before its index, so this cleans up
+// indexes whose removal was interrupted.
+func RemoveOrphanCaptureIndex(indexPath string) error {
+	withoutExt := strings.TrimSuffix(indexPath, CaptureIndexFileExt)
+	// in progress files are renamed to completed ones, so check them first to not miss a file
+	// being renamed in between
+	if fileExists(withoutExt+InProgressCaptureFileExt) || fileExists(withoutExt+CompletedCaptureFileExt) {
+		return nil
+	}
+	if err := os.Remove(indexPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// readIndexedCaptureFile reads the SensorData within the query's time window at the offsets
+// described by idx.
+func readIndexedCaptureFile(path string, idx *CaptureIndex, query CaptureQuery) ([]*v1.SensorData, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		// The file may have been synced and deleted since the directory was read.
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	//nolint:errcheck
+	defer f.Close()
+
+	var ret []*v1.SensorData
+	for _, entry := range idx.Entries {
+		if !query.contains(entry.TimeNs) {
+			continue
+		}
+		if _, err := f.Seek(entry.Offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		next := &v1.SensorData{}
+		if _, err := pbutil.ReadDelimited(f, next); err != nil {
+			return nil, errors.Wrapf(err, "failed to read indexed SensorData from %s", path)
+		}
+		ret = append(ret, next)
+	}
+	return ret, nil
+}
+
+// scanCaptureFile reads every SensorData of the capture file at path, keeping those within the
+// query's time window.
+func scanCaptureFile(path string, query CaptureQuery) ([]*v1.SensorData, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	//nolint:errcheck
+	defer f.Close()
+
+	captureFile, err := ReadCaptureFile(f)
+	if err != nil {
+		// An in progress file may not have its metadata flushed to disk yet.
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var ret []*v1.SensorData
+	for {
+		next, err := captureFile.ReadNext()
+		if err != nil {
+			// In progress files may end with a partially written message.
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				break
+			}
+			return nil, err
+		}
+		if query.contains(sensorDataTimeNs(next)) {
+			ret = append(ret, next)
+		}
+	}
+	return ret, nil
+}
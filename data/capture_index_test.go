@@ -0,0 +1,113 @@
+package data
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func sensorDataAt(ts time.Time) *v1.SensorData {
+	return &v1.SensorData{
+		Metadata: &v1.SensorMetadata{TimeRequested: timestamppb.New(ts), TimeReceived: timestamppb.New(ts)},
+		Data:     &v1.SensorData_Struct{Struct: structReading{Field1: true}.toProto()},
+	}
+}
+
+func timesOf(sensorData []*v1.SensorData) []time.Time {
+	times := make([]time.Time, 0, len(sensorData))
+	for _, datum := range sensorData {
+		times = append(times, datum.GetMetadata().GetTimeRequested().AsTime())
+	}
+	return times
+}
+
+func TestQueryCaptureDir(t *testing.T) {
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	minute := func(n int) time.Time {
+		return base.Add(time.Duration(n) * time.Minute)
+	}
+
+	tmpDir := t.TempDir()
+	md := &v1.DataCaptureMetadata{Type: v1.DataType_DATA_TYPE_TABULAR_SENSOR}
+	// Each file holds two readings.
+	buf := NewCaptureBuffer(tmpDir, md, 50)
+	for n := 0; n < 9; n++ {
+		test.That(t, buf.WriteTabular(sensorDataAt(minute(n))), test.ShouldBeNil)
+	}
+
+	dcFiles, progFiles := getCaptureFiles(tmpDir)
+	test.That(t, len(dcFiles), test.ShouldEqual, 4)
+	test.That(t, len(progFiles), test.ShouldEqual, 1)
+
+	// Every completed file has an index describing its readings.
+	idx, err := ReadCaptureIndex(dcFiles[0])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(idx.Entries), test.ShouldEqual, 2)
+	test.That(t, idx.StartTimeNs, test.ShouldEqual, minute(0).UnixNano())
+	test.That(t, idx.EndTimeNs, test.ShouldEqual, minute(1).UnixNano())
+
+	t.Run("time window", func(t *testing.T) {
+		res, err := QueryCaptureDir(tmpDir, CaptureQuery{Start: minute(3), End: minute(8)})
+		test.That(t, err, test.ShouldBeNil)
+		// The last reading is in the in progress file and has not been flushed to disk yet.
+		test.That(t, timesOf(res), test.ShouldResemble,
+			[]time.Time{minute(3), minute(4), minute(5), minute(6), minute(7)})
+		test.That(t, res[0].GetStruct().AsMap(), test.ShouldResemble, map[string]interface{}{"Field1": true})
+
+		test.That(t, buf.Flush(), test.ShouldBeNil)
+		res, err = QueryCaptureDir(tmpDir, CaptureQuery{Start: minute(7), End: minute(8)})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, timesOf(res), test.ShouldResemble, []time.Time{minute(7), minute(8)})
+	})
+
+	t.Run("unbounded window with limit", func(t *testing.T) {
+		res, err := QueryCaptureDir(tmpDir, CaptureQuery{End: minute(4), Limit: 3})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, timesOf(res), test.ShouldResemble, []time.Time{minute(0), minute(1), minute(2)})
+	})
+
+	t.Run("files without an index are scanned", func(t *testing.T) {
+		test.That(t, os.Remove(CaptureIndexPath(dcFiles[1])), test.ShouldBeNil)
+		res, err := QueryCaptureDir(tmpDir, CaptureQuery{Start: minute(2), End: minute(2)})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, timesOf(res), test.ShouldResemble, []time.Time{minute(2)})
+	})
+
+	t.Run("deleted files", func(t *testing.T) {
+		//nolint:gosec
+		f, err := os.Open(dcFiles[0])
+		test.That(t, err, test.ShouldBeNil)
+		captureFile, err := ReadCaptureFile(f)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, captureFile.Delete(), test.ShouldBeNil)
+		_, err = os.Stat(CaptureIndexPath(dcFiles[0]))
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+
+		// An index left behind without its capture file is left alone by queries and removed by
+		// RemoveOrphanCaptureIndex.
+		test.That(t, os.Remove(dcFiles[2]), test.ShouldBeNil)
+		res, err := QueryCaptureDir(tmpDir, CaptureQuery{End: minute(5)})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, timesOf(res), test.ShouldResemble, []time.Time{minute(2), minute(3)})
+		_, err = os.Stat(CaptureIndexPath(dcFiles[2]))
+		test.That(t, err, test.ShouldBeNil)
+
+		test.That(t, RemoveOrphanCaptureIndex(CaptureIndexPath(dcFiles[3])), test.ShouldBeNil)
+		_, err = os.Stat(CaptureIndexPath(dcFiles[3]))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, RemoveOrphanCaptureIndex(CaptureIndexPath(dcFiles[2])), test.ShouldBeNil)
+		_, err = os.Stat(CaptureIndexPath(dcFiles[2]))
+		test.That(t, os.IsNotExist(err), test.ShouldBeTrue)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		res, err := QueryCaptureDir(filepath.Join(tmpDir, "missing"), CaptureQuery{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, res, test.ShouldBeEmpty)
+	})
+}
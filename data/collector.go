@@ -82,6 +82,7 @@ type collector struct {
 	target           CaptureBufferedWriter
 	lastLoggedErrors map[string]int64
 	dataType         CaptureType
+	gate             *CaptureGate
 }
 
 // Close closes the channels backing the Collector. It should always be called before disposing of a Collector to avoid
@@ -202,6 +203,10 @@ func (c *collector) validateReadingType(t CaptureType) error {
 }
 
 func (c *collector) getAndPushNextReading() {
+	if c.gate != nil && !c.gate.wantsReadings() {
+		return
+	}
+
 	result, err := c.captureFunc(c.cancelCtx, c.params)
 
 	if c.cancelCtx.Err() != nil {
@@ -267,6 +272,7 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 		target:           params.Target,
 		clock:            c,
 		lastLoggedErrors: make(map[string]int64, 0),
+		gate:             params.Gate,
 	}, nil
 }
 
@@ -280,36 +286,50 @@ func (c *collector) writeCaptureResults() {
 		case <-c.cancelCtx.Done():
 			return
 		case msg := <-c.captureResults:
-			proto := msg.ToProto()
-
-			switch msg.Type {
-			case CaptureTypeTabular:
-				if len(proto) != 1 {
-					// This is impossible and could only happen if a future code change breaks CaptureResult.ToProto()
-					err := errors.New("tabular CaptureResult returned more than one tabular result")
-					c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write tabular data to prog file %s", c.target.Path())).Error())
-					return
-				}
-				if err := c.target.WriteTabular(proto[0]); err != nil {
-					c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write tabular data to prog file %s", c.target.Path())).Error())
-					return
-				}
-			case CaptureTypeBinary:
-				if err := c.target.WriteBinary(proto); err != nil {
-					c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write binary data to prog file %s", c.target.Path())).Error())
+			msgs := []CaptureResult{msg}
+			if c.gate != nil {
+				msgs = c.gate.admit(msg)
+			}
+			for _, msg := range msgs {
+				if !c.writeCaptureResult(msg) {
 					return
 				}
-			case CaptureTypeUnspecified:
-				c.logger.Errorf("collector returned invalid result type: %d", msg.Type)
-				return
-			default:
-				c.logger.Errorf("collector returned invalid result type: %d", msg.Type)
-				return
 			}
+		}
+	}
+}
+
+// writeCaptureResult writes msg to c.target. It returns false if the collector should stop writing.
+func (c *collector) writeCaptureResult(msg CaptureResult) bool {
+	proto := msg.ToProto()
 
-			c.maybeWriteToMongo(msg)
+	switch msg.Type {
+	case CaptureTypeTabular:
+		if len(proto) != 1 {
+			// This is impossible and could only happen if a future code change breaks CaptureResult.ToProto()
+			err := errors.New("tabular CaptureResult returned more than one tabular result")
+			c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write tabular data to prog file %s", c.target.Path())).Error())
+			return false
 		}
+		if err := c.target.WriteTabular(proto[0]); err != nil {
+			c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write tabular data to prog file %s", c.target.Path())).Error())
+			return false
+		}
+	case CaptureTypeBinary:
+		if err := c.target.WriteBinary(proto); err != nil {
+			c.logger.Error(errors.Wrap(err, fmt.Sprintf("failed to write binary data to prog file %s", c.target.Path())).Error())
+			return false
+		}
+	case CaptureTypeUnspecified:
+		c.logger.Errorf("collector returned invalid result type: %d", msg.Type)
+		return false
+	default:
+		c.logger.Errorf("collector returned invalid result type: %d", msg.Type)
+		return false
 	}
+
+	c.maybeWriteToMongo(msg)
+	return true
 }
 
 // maybeWriteToMongo will write to the mongoCollection
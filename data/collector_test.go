@@ -354,7 +354,7 @@ func getAllFiles(dir string) []os.FileInfo {
 		if err != nil {
 			return nil
 		}
-		if info.IsDir() {
+		if info.IsDir() || IsCaptureIndexFile(path) {
 			return nil
 		}
 		files = append(files, info)
@@ -24,6 +24,7 @@ type CollectorParams struct {
 	ComponentName   string
 	ComponentType   string
 	DataType        CaptureType
+	Gate            *CaptureGate
 	Interval        time.Duration
 	Logger          logging.Logger
 	MethodName      string
@@ -10,6 +10,16 @@ import (
 func main() {
 	if len(os.Args) < 2 {
 		parser.NolintPrintln("Expected an FTDC filename. E.g: go run parser.go <path-to>/viam-server.ftdc")
+		parser.NolintPrintln("Or to export FTDC data to csv, parquet or otlp json:")
+		parser.NolintPrintln("  go run parser.go export -format csv -metrics 'proc.*' -o out.csv <path-to>/viam-server.ftdc")
+		return
+	}
+
+	if os.Args[1] == "export" {
+		if err := parser.LaunchExport(os.Args[2:]); err != nil {
+			parser.NolintPrintln("Error exporting ftdc data:", err)
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -23,6 +23,119 @@ const (
 	nsInADay = 8.64e13
 )
 
+// Document type bytes. A document whose first byte has its least significant bit set is a schema
+// document. Otherwise the byte is the first byte of a metric document.
+const (
+	// legacySchemaDocument starts a schema document where every metric value is a float32.
+	legacySchemaDocument = 0x1
+	// typedSchemaDocument starts a schema document that additionally records a type tag for each
+	// metric. See `valueType`.
+	typedSchemaDocument = 0x3
+)
+
+// maxStringLen is the number of bytes of a string metric that are persisted. Longer strings are
+// truncated.
+const maxStringLen = 1024
+
+// String is a string metric. Plain `string` values returned by a `Statser` are ignored, as they
+// would otherwise be written out for every component. A `Statser` opts a metric in to being
+// recorded by returning it as a `String`.
+type String string
+
+var stringMetricType = reflect.TypeOf(String(""))
+
+// valueType is the type tag of a metric in a typed schema. It determines how a metric's value is
+// serialized in the metric documents that follow the schema.
+type valueType byte
+
+const (
+	// float32Type values are written as 4 bytes. This is the only type legacy schemas can describe.
+	float32Type valueType = 'f'
+	// float64Type values are written as 8 bytes.
+	float64Type valueType = 'd'
+	// int64Type values are written as 8 bytes.
+	int64Type valueType = 'i'
+	// uint64Type values are written as 8 bytes.
+	uint64Type valueType = 'u'
+	// boolType values are written as a single byte of 0 or 1.
+	boolType valueType = 'b'
+	// stringType values are written as a 2 byte length followed by that many bytes.
+	stringType valueType = 's'
+)
+
+// typeOf returns the type tag for a flattened metric value. Flattened values are always one of
+// float32, float64, int64, uint64, bool or string.
+func typeOf(value any) valueType {
+	switch value.(type) {
+	case float64:
+		return float64Type
+	case int64:
+		return int64Type
+	case uint64:
+		return uint64Type
+	case bool:
+		return boolType
+	case string:
+		return stringType
+	default:
+		return float32Type
+	}
+}
+
+// asFloat32 lossily converts a flattened metric value into a float32. Strings are represented as 0.
+func asFloat32(value any) float32 {
+	switch val := value.(type) {
+	case float32:
+		return val
+	case float64:
+		return float32(val)
+	case int64:
+		return float32(val)
+	case uint64:
+		return float32(val)
+	case bool:
+		if val {
+			return 1
+		}
+		return 0
+	default:
+		return 0
+	}
+}
+
+// zeroValue returns the value of a metric of type `typ` that the parser and writer agree was "prior"
+// to the first metric document following a schema change.
+func zeroValue(typ valueType) any {
+	switch typ {
+	case float64Type:
+		return float64(0)
+	case int64Type:
+		return int64(0)
+	case uint64Type:
+		return uint64(0)
+	case boolType:
+		return false
+	case stringType:
+		return ""
+	case float32Type:
+	}
+	return float32(0)
+}
+
+// valueChanged returns whether `curr` must be written out given the `prev` value of a metric.
+func valueChanged(prev, curr any) bool {
+	prevFloat, prevIsFloat := prev.(float32)
+	currFloat, currIsFloat := curr.(float32)
+	if prevIsFloat && currIsFloat {
+		// When using floating point numbers, it's customary to avoid `== 0` and `!= 0`. And instead
+		// compare to some small (epsilon) value.
+		return math.Abs(float64(currFloat-prevFloat)) > epsilon
+	}
+
+	// All other types are compared exactly. We want them to round-trip losslessly.
+	return prev != curr
+}
+
 type schema struct {
 	// A `Datum`s data is a map[string]any. Even if two datum's have maps with the same keys, we do
 	// not assume ranging over the map will yield the same order. Thus we explicitly write down an
@@ -34,21 +147,36 @@ type schema struct {
 	// fieldOrder is flattened list of strings representing individual metrics. Fields use a
 	// dot-notation to represent structure/nesting. E.g: "leftMotor.PowerPct".
 	fieldOrder []string
+
+	// fieldTypes is parallel to `fieldOrder` and describes how each metric value is serialized.
+	fieldTypes []valueType
+}
+
+// typedSchemaJSON is the JSON payload of a typed schema document.
+type typedSchemaJSON struct {
+	Fields []string `json:"fields"`
+	// Types is a string with one type tag character per field.
+	Types string `json:"types"`
 }
 
-// writeSchema writes down names for metrics in the form of a json array. All subsequent calls to
-// `writeDatum` will assume this "header" representation until the next call to `writeSchema`. A
-// full description of the file format is recorded in `doc.go`.
+// writeSchema writes down names and types for metrics in the form of a json object. All subsequent
+// calls to `writeDatum` will assume this "header" representation until the next call to
+// `writeSchema`. A full description of the file format is recorded in `doc.go`.
 func writeSchema(schema *schema, output io.Writer) error {
 	// New schema byte
-	if _, err := output.Write([]byte{0x1}); err != nil {
+	if _, err := output.Write([]byte{typedSchemaDocument}); err != nil {
 		return fmt.Errorf("Error writing schema bit: %w", err)
 	}
 
+	types := make([]byte, len(schema.fieldTypes))
+	for idx, typ := range schema.fieldTypes {
+		types[idx] = byte(typ)
+	}
+
 	encoder := json.NewEncoder(output)
 	// `json.Encoder.Encode` assumes it convenient to append a newline character at the very
 	// end. This newline has been included in the format specification. Parsers must read over that.
-	if err := encoder.Encode(schema.fieldOrder); err != nil {
+	if err := encoder.Encode(typedSchemaJSON{Fields: schema.fieldOrder, Types: string(types)}); err != nil {
 		return fmt.Errorf("Error writing schema: %w", err)
 	}
 
@@ -60,22 +188,22 @@ func writeSchema(schema *schema, output io.Writer) error {
 //
 // This may only call this when `len(curr) > 0`. `prev` may be nil or empty. If `prev` is non-empty,
 // `len(prev)` must equal `len(curr)`.
-func writeDatum(time int64, prev, curr []float32, output io.Writer) error {
+func writeDatum(time int64, prev, curr []any, output io.Writer) error {
 	numPts := len(curr)
 	if len(prev) != 0 && numPts != len(prev) {
 		return fmt.Errorf("Bad input sizes. Prev: %v Curr: %v", len(prev), len(curr))
 	}
 
 	// We first have to calculate the "diff bits".
-	diffs := make([]float32, numPts)
-	if len(prev) == 0 {
-		// If there was no previous reading to compare against, assume it was all zeroes.
-		copy(diffs, curr)
-	} else {
-		for idx := range curr {
-			// We record the difference in the current reading compared to the previous reading for
-			// each metric.
-			diffs[idx] = curr[idx] - prev[idx]
+	diffs := make([]bool, numPts)
+	for idx := range curr {
+		if len(prev) == 0 {
+			// If there was no previous reading to compare against, assume it was all zeroes.
+			diffs[idx] = valueChanged(zeroValue(typeOf(curr[idx])), curr[idx])
+		} else {
+			// We record whether the current reading differs from the previous reading for each
+			// metric.
+			diffs[idx] = valueChanged(prev[idx], curr[idx])
 		}
 	}
 
@@ -93,16 +221,14 @@ func writeDatum(time int64, prev, curr []float32, output io.Writer) error {
 	// Now that we've calculated the diffs, and know how many bytes we need to represent the diff
 	// (and metric document identifier), we create a byte array to bitwise-or into.
 	diffBits := make([]byte, numBytes)
-	for diffIdx := range diffs {
+	for diffIdx, changed := range diffs {
 		// Leading bit is the "schema change" bit. For a "data header", the "schema bit" value is 0.
 		// Start "diff bits" at index 1.
 		bitIdx := diffIdx + 1
 		byteIdx := bitIdx / 8
 		bitOffset := bitIdx % 8
 
-		// When using floating point numbers, it's customary to avoid `== 0` and `!= 0`. And instead
-		// compare to some small (epsilon) value.
-		if math.Abs(float64(diffs[diffIdx])) > epsilon {
+		if changed {
 			diffBits[byteIdx] |= (1 << bitOffset)
 		}
 	}
@@ -117,9 +243,9 @@ func writeDatum(time int64, prev, curr []float32, output io.Writer) error {
 	}
 
 	// Write out values for metrics that changed across reading.
-	for idx, diff := range diffs {
-		if math.Abs(float64(diff)) > epsilon {
-			if err := binary.Write(output, binary.BigEndian, curr[idx]); err != nil {
+	for idx, changed := range diffs {
+		if changed {
+			if err := writeValue(curr[idx], output); err != nil {
 				return fmt.Errorf("Error writing values: %w", err)
 			}
 		}
@@ -128,6 +254,71 @@ func writeDatum(time int64, prev, curr []float32, output io.Writer) error {
 	return nil
 }
 
+// writeValue writes a single metric value in the encoding described by its type tag.
+func writeValue(value any, output io.Writer) error {
+	switch val := value.(type) {
+	case bool:
+		var asByte byte
+		if val {
+			asByte = 1
+		}
+		_, err := output.Write([]byte{asByte})
+		return err
+	case string:
+		if len(val) > maxStringLen {
+			val = val[:maxStringLen]
+		}
+		//nolint:gosec // The length is capped at `maxStringLen`.
+		if err := binary.Write(output, binary.BigEndian, uint16(len(val))); err != nil {
+			return err
+		}
+		_, err := io.WriteString(output, val)
+		return err
+	case float32, float64, int64, uint64:
+		return binary.Write(output, binary.BigEndian, val)
+	default:
+		return fmt.Errorf("unsupported metric value type: %T", value)
+	}
+}
+
+// readValue reads a single metric value of the given type.
+func readValue(reader io.Reader, typ valueType) (any, error) {
+	switch typ {
+	case float32Type:
+		var val float32
+		err := binary.Read(reader, binary.BigEndian, &val)
+		return val, err
+	case float64Type:
+		var val float64
+		err := binary.Read(reader, binary.BigEndian, &val)
+		return val, err
+	case int64Type:
+		var val int64
+		err := binary.Read(reader, binary.BigEndian, &val)
+		return val, err
+	case uint64Type:
+		var val uint64
+		err := binary.Read(reader, binary.BigEndian, &val)
+		return val, err
+	case boolType:
+		var val byte
+		err := binary.Read(reader, binary.BigEndian, &val)
+		return val == 1, err
+	case stringType:
+		var length uint16
+		if err := binary.Read(reader, binary.BigEndian, &length); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, length)
+		if _, err := io.ReadFull(reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf), nil
+	default:
+		return nil, fmt.Errorf("unknown metric type tag: %q", byte(typ))
+	}
+}
+
 func isNumeric(kind reflect.Kind) bool {
 	return kind == reflect.Bool ||
 		kind == reflect.Int ||
@@ -148,7 +339,9 @@ func flattenPtr(inp reflect.Value) reflect.Value {
 	return inp
 }
 
-func flatten(value reflect.Value) ([]string, []float32, error) {
+// flatten returns the metric names and values of a stats object. Values are one of float32,
+// float64, int64, uint64, bool or string.
+func flatten(value reflect.Value) ([]string, []any, error) {
 	value = flattenPtr(value)
 
 	// why is the default case not sufficient to be considered exhaustive?
@@ -161,13 +354,13 @@ func flatten(value reflect.Value) ([]string, []float32, error) {
 	default:
 		// We can get here, for example, if a struct member is typed as an `any`, but the value is
 		// nil. More antagonistically, this also catches weird types such as channels.
-		return []string{}, []float32{}, nil
+		return []string{}, []any{}, nil
 	}
 }
 
 type mapSorter struct {
 	fields []string
-	values []float32
+	values []any
 }
 
 func (ms mapSorter) Len() int {
@@ -183,16 +376,37 @@ func (ms mapSorter) Swap(left, right int) {
 	ms.values[left], ms.values[right] = ms.values[right], ms.values[left]
 }
 
+// flattenValue returns the flattened representation of a terminal value (e.g: numbers and strings)
+// and whether `value` was a terminal value.
+func flattenValue(value reflect.Value) (any, bool) {
+	switch {
+	case value.CanUint():
+		return value.Uint(), true
+	case value.CanInt():
+		return value.Int(), true
+	case value.Kind() == reflect.Float32:
+		return float32(value.Float()), true
+	case value.CanFloat():
+		return value.Float(), true
+	case value.Kind() == reflect.Bool:
+		return value.Bool(), true
+	case value.Kind() == reflect.String && value.Type() == stringMetricType:
+		return value.String(), true
+	default:
+		return nil, false
+	}
+}
+
 // flattenMap must be passed in a map where the keys are explicitly typed as strings. The values can
 // be any terminal type (e.g: numbers) or more maps of strings.
-func flattenMap(mValue reflect.Value) ([]string, []float32, error) {
+func flattenMap(mValue reflect.Value) ([]string, []any, error) {
 	if mValue.Type().Key().Kind() != reflect.String {
 		// We ignore types we refuse to serialize into ftdc.
-		return []string{}, []float32{}, nil
+		return []string{}, []any{}, nil
 	}
 
 	fields := make([]string, 0)
-	numbers := make([]float32, 0)
+	numbers := make([]any, 0)
 
 	// Map iteration order is not predictable. This means that consecutive calls to a `Statser` that
 	// returns a map may yield: {"X": 1, "Y": 2} for one stat followed by {"Y": 2, "X": 1}. That
@@ -202,23 +416,13 @@ func flattenMap(mValue reflect.Value) ([]string, []float32, error) {
 		key := iter.Key()
 		value := flattenPtr(iter.Value())
 
-		switch {
-		case value.CanUint():
-			fields = append(fields, key.String())
-			numbers = append(numbers, float32(value.Uint()))
-		case value.CanInt():
-			fields = append(fields, key.String())
-			numbers = append(numbers, float32(value.Int()))
-		case value.CanFloat():
+		if flat, isTerminal := flattenValue(value); isTerminal {
 			fields = append(fields, key.String())
-			numbers = append(numbers, float32(value.Float()))
-		case value.Kind() == reflect.Bool:
-			fields = append(fields, key.String())
-			if value.Bool() {
-				numbers = append(numbers, 1)
-			} else {
-				numbers = append(numbers, 0)
-			}
+			numbers = append(numbers, flat)
+			continue
+		}
+
+		switch {
 		case value.Kind() == reflect.Struct ||
 			value.Kind() == reflect.Pointer ||
 			value.Kind() == reflect.Interface ||
@@ -236,7 +440,7 @@ func flattenMap(mValue reflect.Value) ([]string, []float32, error) {
 			return nil, nil, fmt.Errorf("A numeric type was forgotten to be included. Kind: %v", value.Kind())
 		default:
 			// Getting the keys for a structure will ignore these types. Such as the antagonistic
-			// `channel`, or `string`. We follow suit in ignoring these types.
+			// `channel`. We follow suit in ignoring these types.
 		}
 	}
 
@@ -247,12 +451,12 @@ func flattenMap(mValue reflect.Value) ([]string, []float32, error) {
 	return fields, numbers, nil
 }
 
-func flattenStruct(value reflect.Value) ([]string, []float32, error) {
+func flattenStruct(value reflect.Value) ([]string, []any, error) {
 	value = flattenPtr(value)
 	rType := value.Type()
 
 	var fields []string
-	var numbers []float32
+	var numbers []any
 	// Use reflection to walk the member fields of an individual set of metric readings. We rely
 	// on reflection always walking fields in the same order.
 	//
@@ -262,24 +466,13 @@ func flattenStruct(value reflect.Value) ([]string, []float32, error) {
 	// pointer to each structure and walk out index to pull out the relevant numbers.
 	for memberIdx := 0; memberIdx < value.NumField(); memberIdx++ {
 		rField := flattenPtr(value.Field(memberIdx))
-		switch {
-		case rField.CanUint():
-			fields = append(fields, rType.Field(memberIdx).Name)
-			numbers = append(numbers, float32(rField.Uint()))
-		case rField.CanInt():
+		if flat, isTerminal := flattenValue(rField); isTerminal {
 			fields = append(fields, rType.Field(memberIdx).Name)
-			numbers = append(numbers, float32(rField.Int()))
-		case rField.CanFloat():
-			fields = append(fields, rType.Field(memberIdx).Name)
-			numbers = append(numbers, float32(rField.Float()))
-		case rField.Kind() == reflect.Bool:
-			if rField.Bool() {
-				fields = append(fields, rType.Field(memberIdx).Name)
-				numbers = append(numbers, 1)
-			} else {
-				fields = append(fields, rType.Field(memberIdx).Name)
-				numbers = append(numbers, 0)
-			}
+			numbers = append(numbers, flat)
+			continue
+		}
+
+		switch {
 		case rField.Kind() == reflect.Struct ||
 			rField.Kind() == reflect.Pointer ||
 			rField.Kind() == reflect.Interface ||
@@ -299,7 +492,7 @@ func flattenStruct(value reflect.Value) ([]string, []float32, error) {
 			return nil, nil, fmt.Errorf("A numeric type was forgotten to be included. Kind: %v", rField.Kind())
 		default:
 			// Getting the keys for a structure will ignore these types. Such as the antagonistic
-			// `channel`, or `string`. We follow suit in ignoring these types.
+			// `channel`. We follow suit in ignoring these types.
 		}
 	}
 
@@ -332,7 +525,13 @@ type FlatDatum struct {
 // Reading is a "fully qualified" metric name paired with a value.
 type Reading struct {
 	MetricName string
-	Value      float32
+	// Value is the metric value converted to a float32. The conversion may lose precision. String
+	// metrics have a Value of 0.
+	Value float32
+	// RawValue is the metric value exactly as it was recorded. It is one of float32, float64, int64,
+	// uint64, bool or string. Files written before typed schemas were introduced only contain
+	// float32 values.
+	RawValue any
 }
 
 // ConvertedTime turns the `Time` int64 value in nanoseconds since the epoch into a `time.Time`
@@ -382,10 +581,32 @@ func ParseWithLogger(rawReader io.Reader, logger logging.Logger) (
 	retErr error,
 ) {
 	ret = make([]FlatDatum, 0)
+	lastTimestampRead, retErr = parse(rawReader, logger, func(flatDatum FlatDatum) error {
+		ret = append(ret, flatDatum)
+		return nil
+	})
+
+	return
+}
+
+// ParseStream reads FTDC documents from `rawReader` as they become available and calls `onDatum`
+// for each datum read. This is suitable for live streams of FTDC data, such as those produced by
+// `FTDC.Subscribe`. ParseStream returns when `rawReader` is exhausted, when the data is malformed
+// or when `onDatum` returns an error.
+func ParseStream(rawReader io.Reader, logger logging.Logger, onDatum func(FlatDatum) error) error {
+	_, err := parse(rawReader, logger, onDatum)
+	return err
+}
 
+// parse reads FTDC documents from `rawReader`, calling `onDatum` for every datum. It returns the
+// last timestamp that was read.
+func parse(rawReader io.Reader, logger logging.Logger, onDatum func(FlatDatum) error) (
+	lastTimestampRead int64,
+	retErr error,
+) {
 	// prevValues are the previous values used for producing the diff bits. This is overwritten when
 	// a new metrics reading is made. and nilled out when the schema changes.
-	var prevValues []float32
+	var prevValues []any
 
 	// bufio's Reader allows for peeking and potentially better control over how much data to read
 	// from disk at a time.
@@ -406,22 +627,27 @@ func ParseWithLogger(rawReader io.Reader, logger logging.Logger) (
 		}
 
 		// If the first bit of the first byte is `1`, the next block of data is a schema
-		// document. The rest of the bits (for diffing) are irrelevant and will be zero. Thus the
-		// check against `0x1`.
-		if peek[0] == 0x1 {
+		// document. The remaining bits identify the kind of schema document: `0x1` for a legacy
+		// schema and `0x3` for a typed schema.
+		if peek[0]&0x1 == 0x1 {
+			if peek[0] != legacySchemaDocument && peek[0] != typedSchemaDocument {
+				retErr = fmt.Errorf("unknown FTDC document type: %#x", peek[0])
+				return
+			}
+
 			//nolint
 			//
 			// Justifying the nolint: if `Peek(1)` does not return an error, `ReadByte` must not be
 			// able to return an error.
 			//
-			// Consume the 0x1 byte.
-			_, _ = reader.ReadByte()
+			// Consume the document type byte.
+			docType, _ := reader.ReadByte()
 
 			// Read json and position the cursor at the next FTDC document. The JSON reader may
 			// "over-read", so `readSchema` assembles a new reader positioned at the right spot. The
-			// schema bytes themselves are expected to be a list of strings, e.g: `["metricName1",
-			// "metricName2"]`.
-			schema, reader = readSchema(reader)
+			// legacy schema bytes themselves are expected to be a list of strings, e.g:
+			// `["metricName1", "metricName2"]`.
+			schema, reader = readSchema(reader, docType == typedSchemaDocument)
 			logger.Debugw("Schema bit", "parsedSchema", schema)
 
 			// We cannot diff against values from the old schema.
@@ -462,7 +688,7 @@ func ParseWithLogger(rawReader io.Reader, logger logging.Logger) (
 		}
 		lastTimestampRead = dataTime
 
-		// Read the payload. There will be one float32 value for each diff bit set to `1`, i.e:
+		// Read the payload. There will be one value for each diff bit set to `1`, i.e:
 		// `len(diffedFields)`.
 		data, err := readData(reader, schema, diffedFieldsIndexes, prevValues)
 		if err != nil {
@@ -476,13 +702,16 @@ func ParseWithLogger(rawReader io.Reader, logger logging.Logger) (
 		// `data`.
 		prevValues = data
 
-		// Construct a `Datum` that hydrates/merged the full set of float32 metrics with the metric
-		// names as written in the most recent schema document.
-		ret = append(ret, FlatDatum{
+		// Construct a `Datum` that hydrates/merged the full set of metrics with the metric names as
+		// written in the most recent schema document.
+		flatDatum := FlatDatum{
 			Time:     dataTime,
 			Readings: schema.Zip(data),
-		})
-		logger.Debugw("Hydrated data", "data", ret[len(ret)-1].Readings)
+		}
+		logger.Debugw("Hydrated data", "data", flatDatum.Readings)
+		if retErr = onDatum(flatDatum); retErr != nil {
+			return
+		}
 	}
 
 	return
@@ -498,11 +727,12 @@ func flatDatumsToDatums(inp []FlatDatum) []datum {
 }
 
 // readSchema expects to be positioned on the beginning of a json list data type (a left square
-// bracket `[`) and consumes bytes until that list (of strings) is complete.
+// bracket `[`) and consumes bytes until that list (of strings) is complete. When `typed` is true,
+// readSchema instead expects a json object with the list of fields and their type tags.
 //
 // readSchema returns the described schema and a new reader that's positioned on the first byte of
 // the next ftdc document.
-func readSchema(reader *bufio.Reader) (*schema, *bufio.Reader) {
+func readSchema(reader *bufio.Reader, typed bool) (*schema, *bufio.Reader) {
 	decoder := json.NewDecoder(reader)
 	if !decoder.More() {
 		panic("no json")
@@ -512,8 +742,27 @@ func readSchema(reader *bufio.Reader) (*schema, *bufio.Reader) {
 	// list of strings. We use dots (`.`) to signify nesting. Metric names with dots will result in
 	// an ambiguous parsing.
 	var fields []string
-	if err := decoder.Decode(&fields); err != nil {
-		panic(err)
+	var fieldTypes []valueType
+	if typed {
+		var typedSchema typedSchemaJSON
+		if err := decoder.Decode(&typedSchema); err != nil {
+			panic(err)
+		}
+		if len(typedSchema.Types) != len(typedSchema.Fields) {
+			panic("mismatched number of schema fields and types")
+		}
+		fields = typedSchema.Fields
+		for idx := 0; idx < len(typedSchema.Types); idx++ {
+			fieldTypes = append(fieldTypes, valueType(typedSchema.Types[idx]))
+		}
+	} else {
+		if err := decoder.Decode(&fields); err != nil {
+			panic(err)
+		}
+		// Legacy schemas only describe float32 values.
+		for range fields {
+			fieldTypes = append(fieldTypes, float32Type)
+		}
 	}
 
 	// The JSON decoder can consume bytes from the input `reader` that are beyond the end of the
@@ -546,6 +795,7 @@ func readSchema(reader *bufio.Reader) (*schema, *bufio.Reader) {
 
 	return &schema{
 		fieldOrder: fields,
+		fieldTypes: fieldTypes,
 		mapOrder:   mapOrder,
 	}, retReader
 }
@@ -592,15 +842,15 @@ func readDiffBits(reader *bufio.Reader, schema *schema) ([]int, error) {
 }
 
 // readData returns the "hydrated" metrics for a data reading. For example, if there are ten metrics
-// and none of them changed, the returned []float32 will be identical to `prevValues`. `prevValues`
+// and none of them changed, the returned []any will be identical to `prevValues`. `prevValues`
 // is the post-hydration list and consequently matches the `schema.fieldOrder` size.
-func readData(reader *bufio.Reader, schema *schema, diffedFields []int, prevValues []float32) ([]float32, error) {
+func readData(reader *bufio.Reader, schema *schema, diffedFields []int, prevValues []any) ([]any, error) {
 	if prevValues != nil && len(prevValues) != len(schema.fieldOrder) {
 		return nil, fmt.Errorf("Parser error. Mismatched `prevValues` and schema size. PrevValues: %d Schema: %d",
 			len(prevValues), len(schema.fieldOrder))
 	}
 
-	var ret []float32
+	var ret []any
 
 	// For each metric in the schema:
 	for dataIdx := 0; dataIdx < len(schema.fieldOrder); dataIdx++ {
@@ -616,16 +866,17 @@ func readData(reader *bufio.Reader, schema *schema, diffedFields []int, prevValu
 		if diffFromPrev {
 			// If the metric existed, it's because there was a fresh reading in the input
 			// `reader`. Parse the value from the `reader`.
-			ret = append(ret, 0.0)
-			if err := binary.Read(reader, binary.BigEndian, &ret[dataIdx]); err != nil {
+			value, err := readValue(reader, schema.fieldTypes[dataIdx])
+			if err != nil {
 				return nil, err
 			}
+			ret = append(ret, value)
 		} else {
 			// Otherwise, the metric did not change. Use the previous value.
 			if prevValues == nil {
-				// The parser and writer agree that the `prevValues` is `0.0` for all metrics
-				// following a schema change.
-				ret = append(ret, 0.0)
+				// The parser and writer agree that the `prevValues` is the zero value for all
+				// metrics following a schema change.
+				ret = append(ret, zeroValue(schema.fieldTypes[dataIdx]))
 			} else {
 				ret = append(ret, prevValues[dataIdx])
 			}
@@ -635,14 +886,18 @@ func readData(reader *bufio.Reader, schema *schema, diffedFields []int, prevValu
 	return ret, nil
 }
 
-// Hydrate takes the input []float slice of `data` and matches those to their corresponding metric
+// Hydrate takes the input slice of `data` and matches those to their corresponding metric
 // names. Returning a two layer map. The top-level map is keyed on a "system" (corresponding to an
 // `FTDC.Add` call) and the lower level map corresponds to the keys and values struct a `Stats` call
 // returns. There's no business requirement that nested structures only be represented as two
 // layers. It's just this way for simplicity of the type system and implementation. And right now,
 // only tests are concerned with the advantage of `Hydrate`ing data.
-func (schema *schema) Hydrate(data []float32) map[string]any {
-	return hydrate(schema.fieldOrder, data)
+func (schema *schema) Hydrate(data []any) map[string]any {
+	values := make([]float32, len(data))
+	for idx, value := range data {
+		values[idx] = asFloat32(value)
+	}
+	return hydrate(schema.fieldOrder, values)
 }
 
 func hydrate(fullyQualifiedMetricNames []string, values []float32) map[string]any {
@@ -668,10 +923,10 @@ func hydrate(fullyQualifiedMetricNames []string, values []float32) map[string]an
 // Zip walks the schema and input `data` as parallel arrays and pairs up the metric names with their
 // corresponding reading. The metric names are "fully qualified" with their statser "system"
 // name. Using dots as delimiters representing the original structure.
-func (schema *schema) Zip(data []float32) []Reading {
+func (schema *schema) Zip(data []any) []Reading {
 	ret := make([]Reading, len(schema.fieldOrder))
 	for fieldIdx, metricName := range schema.fieldOrder {
-		ret[fieldIdx] = Reading{metricName, data[fieldIdx]}
+		ret[fieldIdx] = Reading{metricName, asFloat32(data[fieldIdx]), data[fieldIdx]}
 	}
 
 	return ret
@@ -2,7 +2,10 @@ package ftdc
 
 import (
 	"bytes"
+	"encoding/binary"
+	"math"
 	"reflect"
+	"strings"
 	"testing"
 
 	"go.viam.com/test"
@@ -274,7 +277,8 @@ func TestNestedReflectionParity(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	// For convenience, the number values match the field name.
 	test.That(t, values, test.ShouldResemble,
-		[]float32{1, 3, 4, 6, 7, 9, 11, 12, 13, 17})
+		[]any{float32(1), float32(3), float32(4), float32(6), float32(7), float32(9),
+			float32(11), float32(12), float32(13), float32(17)})
 }
 
 type nestsAny struct {
@@ -292,7 +296,7 @@ func TestNestedAny(t *testing.T) {
 
 	_, values, err := flatten(reflect.ValueOf(stat))
 	logger.Info("Values:", values, "Err:", err)
-	test.That(t, values, test.ShouldResemble, []float32{10, 5})
+	test.That(t, values, test.ShouldResemble, []any{float32(10), int64(5)})
 
 	stat = nestsAny{10, nil}
 	fields, _, err = flatten(reflect.ValueOf(stat))
@@ -301,7 +305,7 @@ func TestNestedAny(t *testing.T) {
 
 	_, values, err = flatten(reflect.ValueOf(stat))
 	logger.Info("Values:", values, "Err:", err)
-	test.That(t, values, test.ShouldResemble, []float32{10})
+	test.That(t, values, test.ShouldResemble, []any{float32(10)})
 }
 
 func TestWeirdStats(t *testing.T) {
@@ -326,7 +330,7 @@ func TestWeirdStats(t *testing.T) {
 
 	_, values, err := flatten(reflect.ValueOf(stat))
 	logger.Info("Values:", values, " Err:", err)
-	test.That(t, values, test.ShouldResemble, []float32{10, 1})
+	test.That(t, values, test.ShouldResemble, []any{float32(10), true})
 }
 
 func TestNilNestedStats(t *testing.T) {
@@ -340,7 +344,7 @@ func TestNilNestedStats(t *testing.T) {
 
 	_, values, err := flatten(reflect.ValueOf(stat))
 	logger.Info("Values:", values, " Err:", err)
-	test.That(t, values, test.ShouldResemble, []float32{10})
+	test.That(t, values, test.ShouldResemble, []any{float32(10)})
 }
 
 func TestFlattenMaps(t *testing.T) {
@@ -351,7 +355,7 @@ func TestFlattenMaps(t *testing.T) {
 	keys, values, err := flatten(reflect.ValueOf(mp))
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, keys, test.ShouldResemble, []string{"X"})
-	test.That(t, values, test.ShouldResemble, []float32{42.0})
+	test.That(t, values, test.ShouldResemble, []any{int64(42)})
 
 	mp["Y"] = struct {
 		Foo int
@@ -363,7 +367,7 @@ func TestFlattenMaps(t *testing.T) {
 	// While iterating maps happens in a non-deterministic order, `flatten` will sort the outputs in
 	// ascending key order.
 	test.That(t, keys, test.ShouldResemble, []string{"X", "Y.Bar", "Y.Foo"})
-	test.That(t, values, test.ShouldResemble, []float32{42.0, 20.0, 10.0})
+	test.That(t, values, test.ShouldResemble, []any{int64(42), int64(20), int64(10)})
 }
 
 func TestFlattenTheWorld(t *testing.T) {
@@ -391,7 +395,7 @@ func TestFlattenTheWorld(t *testing.T) {
 	// While iterating maps happens in a non-deterministic order, `flatten` will sort the outputs in
 	// ascending key order.
 	test.That(t, keys, test.ShouldResemble, []string{"X", "Y.Bar", "Y.mp2.eli", "Y.mp2.patriots", "Z.zelda"})
-	test.That(t, values, test.ShouldResemble, []float32{42.0, 5.0, 2.0, 0.0, 64.0})
+	test.That(t, values, test.ShouldResemble, []any{int64(42), int64(5), int64(2), int64(0), int64(64)})
 
 	mp["Z"] = struct {
 		Foo int
@@ -401,5 +405,86 @@ func TestFlattenTheWorld(t *testing.T) {
 	keys, values, err = flatten(reflect.ValueOf(mp))
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, keys, test.ShouldResemble, []string{"X", "Y.Bar", "Y.mp2.eli", "Y.mp2.patriots", "Z.Bar", "Z.Foo"})
-	test.That(t, values, test.ShouldResemble, []float32{42.0, 5.0, 2.0, 0.0, 20.0, 10.0})
+	test.That(t, values, test.ShouldResemble, []any{int64(42), int64(5), int64(2), int64(0), int64(20), int64(10)})
+}
+
+type typedStats struct {
+	Ticks    int64
+	Counter  uint64
+	Lat      float64
+	Ratio    float32
+	Enabled  bool
+	State    String
+	Optional any
+}
+
+// TestCustomFormatRoundtripTyped tests that int64, uint64, float64, bool and string metrics
+// round-trip exactly, including when the type of a metric changes.
+func TestCustomFormatRoundtripTyped(t *testing.T) {
+	serializedData := bytes.NewBuffer(nil)
+
+	logger := logging.NewTestLogger(t)
+	ftdc := NewWithWriter(serializedData, logger.Sublogger("ftdc"))
+
+	inputs := []typedStats{
+		{math.MaxInt64 - 1, math.MaxUint64, 40.712812345678, 0.5, true, "idle", 1},
+		// Only `Ticks` and `State` change.
+		{math.MaxInt64, math.MaxUint64, 40.712812345678, 0.5, true, "moving", 1},
+		// The type of `Optional` changes, which requires a new schema.
+		{math.MaxInt64, math.MaxUint64, -74.006012345678, 0.5, false, String(strings.Repeat("a", maxStringLen+1)), 2.5},
+	}
+	for idx, input := range inputs {
+		test.That(t, ftdc.writeDatum(datum{Time: int64(idx), Data: map[string]any{"s1": input}}), test.ShouldBeNil)
+	}
+
+	flatDatums, lastTimestampRead, err := Parse(serializedData)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lastTimestampRead, test.ShouldEqual, 2)
+	test.That(t, len(flatDatums), test.ShouldEqual, 3)
+
+	for idx, input := range inputs {
+		expected := []any{
+			input.Ticks, input.Counter, input.Lat, input.Ratio, input.Enabled, string(input.State), input.Optional,
+		}
+		if len(input.State) > maxStringLen {
+			expected[5] = string(input.State[:maxStringLen])
+		}
+		if optional, ok := input.Optional.(int); ok {
+			expected[6] = int64(optional)
+		}
+
+		readings := flatDatums[idx].Readings
+		test.That(t, len(readings), test.ShouldEqual, len(expected))
+		for readingIdx, reading := range readings {
+			test.That(t, reading.RawValue, test.ShouldResemble, expected[readingIdx])
+			test.That(t, reading.Value, test.ShouldEqual, asFloat32(expected[readingIdx]))
+		}
+	}
+}
+
+// TestParseLegacySchema tests that files written with the float32-only schema document remain
+// readable.
+func TestParseLegacySchema(t *testing.T) {
+	serializedData := bytes.NewBuffer(nil)
+	serializedData.WriteByte(legacySchemaDocument)
+	serializedData.WriteString(`["s1.Foo","s1.Bar"]` + "\n")
+
+	// First metric document: both values differ from the implicit zeroes.
+	serializedData.WriteByte(0b0000_0110)
+	test.That(t, binary.Write(serializedData, binary.BigEndian, int64(10)), test.ShouldBeNil)
+	test.That(t, binary.Write(serializedData, binary.BigEndian, float32(1.5)), test.ShouldBeNil)
+	test.That(t, binary.Write(serializedData, binary.BigEndian, float32(2)), test.ShouldBeNil)
+
+	// Second metric document: only `Bar` changed.
+	serializedData.WriteByte(0b0000_0100)
+	test.That(t, binary.Write(serializedData, binary.BigEndian, int64(11)), test.ShouldBeNil)
+	test.That(t, binary.Write(serializedData, binary.BigEndian, float32(3)), test.ShouldBeNil)
+
+	flatDatums, lastTimestampRead, err := Parse(serializedData)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lastTimestampRead, test.ShouldEqual, 11)
+	test.That(t, flatDatums, test.ShouldResemble, []FlatDatum{
+		{Time: 10, Readings: []Reading{{"s1.Foo", 1.5, float32(1.5)}, {"s1.Bar", 2, float32(2)}}},
+		{Time: 11, Readings: []Reading{{"s1.Foo", 1.5, float32(1.5)}, {"s1.Bar", 3, float32(3)}}},
+	})
 }
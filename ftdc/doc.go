@@ -100,4 +100,45 @@
 //
 // A parser can read a single byte and look at the least significant bit to determine which path to
 // take.
+//
+// # Typed schemas
+//
+// The format above is lossy: every value is written as a 32-bit float and non-numeric values are
+// dropped. A second kind of schema document, the "typed schema", records a type tag for each
+// metric such that integers, doubles, booleans and short strings round-trip exactly. Writers only
+// produce typed schemas. Parsers accept both, so older files remain readable.
+//
+// typed_schema =
+//
+//	typed_schema_identifier : 0x03 (a full byte of value 3)
+//	schema : <JSON object with "fields" (array of strings) and "types" (one character per field)>
+//
+// Because the least significant bit is set, a parser reading a typed schema identifier still knows
+// it's looking at a schema document. The second bit distinguishes the typed schema from the legacy
+// one. E.g:
+//
+// 0000 0011 {"fields":["motor.powerPct","motor.pos","gps.lat","gps.long"],"types":"fidd"}\n
+// 7       0
+//
+// The metric documents following a typed schema are laid out identically to those following a
+// legacy schema. Only the encoding of each value that differs is determined by its type tag:
+//
+//	'f' : float32, 4 bytes
+//	'd' : float64, 8 bytes
+//	'i' : int64, 8 bytes
+//	'u' : uint64, 8 bytes
+//	'b' : bool, 1 byte of value 0 or 1
+//	's' : string, a uint16 length followed by that many bytes. Strings are truncated to 1024 bytes.
+//	      Only metrics of type `ftdc.String` are recorded; plain strings are ignored.
+//
+// Following a typed schema, the initial metric reading assumes a prior value of the zero value of
+// each type (`0`, `false` or the empty string). A change in a metric's type is a schema change.
+//
+// # Live streams
+//
+// `FTDC.Subscribe` streams datums as they're recorded using the same file format. Each subscriber
+// is sent a schema document before its first metric document, so a stream read from any point is
+// a valid FTDC file. Subscribers that fall behind miss datums, and later metric documents are
+// diffed against the last one the subscriber received. `ParseStream` reads such streams
+// incrementally.
 package ftdc
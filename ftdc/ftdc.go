@@ -1,6 +1,7 @@
 package ftdc
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -96,10 +97,10 @@ type FTDC struct {
 	// The schema used describe how new Datums are serialized.
 	currSchema *schema
 	// The serialization format compares new metrics to the prior metric reading to determine what
-	// to write. `prevFlatData` is the field used to create a diff that's serialized. For
-	// simplicity, all metrics are massaged into a 32-bit float. See `custom_format.go` for a more
+	// to write. `prevFlatData` is the field used to create a diff that's serialized. Each metric is
+	// one of float32, float64, int64, uint64, bool or string. See `custom_format.go` for a more
 	// detailed description.
-	prevFlatData []float32
+	prevFlatData []any
 
 	readStatsWorker  *utils.StoppableWorkers
 	datumCh          chan datum
@@ -119,10 +120,24 @@ type FTDC struct {
 	// ftdcDir controls where FTDC data files will be written.
 	ftdcDir string
 
+	// subscribersMu protects the `subscribers` member. Subscribers are added and removed by
+	// clients streaming FTDC data while the `statsWriter` goroutine publishes to them.
+	subscribersMu sync.Mutex
+	subscribers   []*subscriber
+
 	uploader *uploader
 	logger   logging.Logger
 }
 
+// subscriber receives a live copy of the datums FTDC writes. Each subscriber tracks the schema and
+// values it was last sent. Such that its stream of bytes is a self-contained FTDC "file" regardless
+// of when it subscribed or whether it missed datums.
+type subscriber struct {
+	dataCh       chan []byte
+	currSchema   *schema
+	prevFlatData []any
+}
+
 // New creates a new *FTDC. This FTDC object will write FTDC formatted files into the input
 // `ftdcDirectory`.
 func New(ftdcDirectory string, logger logging.Logger) *FTDC {
@@ -210,6 +225,82 @@ func (ftdc *FTDC) Remove(name string) {
 	ftdc.logger.Warnw("Did not find statser to remove", "name", name)
 }
 
+// Subscribe registers a new live consumer of FTDC data. Every datum written from now on is sent on
+// the returned channel, serialized in the FTDC file format. The first message always begins with a
+// schema document and later messages contain a new schema document whenever the schema
+// changes. Concatenating the messages yields bytes that can be read with `Parse`.
+//
+// A subscriber that does not keep up will miss datums rather than slow down FTDC. The returned
+// function unsubscribes and closes the channel. The channel is also closed when FTDC stops.
+func (ftdc *FTDC) Subscribe() (<-chan []byte, func()) {
+	sub := &subscriber{
+		dataCh: make(chan []byte, 20),
+	}
+
+	ftdc.subscribersMu.Lock()
+	ftdc.subscribers = append(ftdc.subscribers, sub)
+	ftdc.subscribersMu.Unlock()
+
+	unsubscribe := func() {
+		ftdc.subscribersMu.Lock()
+		defer ftdc.subscribersMu.Unlock()
+
+		for idx, existing := range ftdc.subscribers {
+			if existing == sub {
+				ftdc.subscribers = slices.Delete(ftdc.subscribers, idx, idx+1)
+				close(sub.dataCh)
+				return
+			}
+		}
+	}
+
+	return sub.dataCh, unsubscribe
+}
+
+// publish serializes a datum for each subscriber. Subscribers are diffed against the values they
+// last received, so a dropped message does not corrupt the stream.
+func (ftdc *FTDC) publish(time int64, schema *schema, flatData []any) {
+	ftdc.subscribersMu.Lock()
+	defer ftdc.subscribersMu.Unlock()
+
+	for _, sub := range ftdc.subscribers {
+		var buf bytes.Buffer
+		prevFlatData := sub.prevFlatData
+		if sub.currSchema != schema {
+			if err := writeSchema(schema, &buf); err != nil {
+				ftdc.logger.Warnw("Error serializing ftdc schema for subscriber", "err", err)
+				continue
+			}
+			prevFlatData = nil
+		}
+
+		if err := writeDatum(time, prevFlatData, flatData, &buf); err != nil {
+			ftdc.logger.Warnw("Error serializing ftdc datum for subscriber", "err", err)
+			continue
+		}
+
+		select {
+		case sub.dataCh <- buf.Bytes():
+			sub.currSchema = schema
+			sub.prevFlatData = flatData
+		default:
+			// The subscriber is behind. Drop this datum. The next one will be diffed against what
+			// the subscriber last received.
+		}
+	}
+}
+
+// closeSubscribers closes and removes all subscribers. It is called when FTDC stops writing data.
+func (ftdc *FTDC) closeSubscribers() {
+	ftdc.subscribersMu.Lock()
+	defer ftdc.subscribersMu.Unlock()
+
+	for _, sub := range ftdc.subscribers {
+		close(sub.dataCh)
+	}
+	ftdc.subscribers = nil
+}
+
 // Start spins off the background goroutine for collecting + writing FTDC data. It's normal for tests
 // to _not_ call `Start`. Tests can simulate the same functionality by calling `constructDatum` and `writeDatum`.
 func (ftdc *FTDC) Start() {
@@ -246,6 +337,7 @@ func (ftdc *FTDC) statsWriter() {
 		if ftdc.currOutputFile != nil {
 			utils.UncheckedError(ftdc.currOutputFile.Close())
 		}
+		ftdc.closeSubscribers()
 		close(ftdc.outputWorkerDone)
 	}()
 
@@ -325,21 +417,21 @@ func (ftdc *FTDC) constructDatum() datum {
 
 // walk accepts a datum and the previous schema and will return:
 // - the new schema. If the schema is unchanged, this will be the same pointer value as `previousSchema`.
-// - the flattened float32 data points.
+// - the flattened data points.
 // - an error. All errors (for now) are terminal -- the input datum cannot be output.
-func walk(datum map[string]any, previousSchema *schema) (*schema, []float32, error) {
+func walk(datum map[string]any, previousSchema *schema) (*schema, []any, error) {
 	schemaChanged := false
 
 	var (
 		fields         []string
-		values         []float32
+		values         []any
 		iterationOrder []string
 	)
 
 	// In the steady state, we will have an existing schema. Use that for a `datum` iteration order.
 	if previousSchema != nil {
 		fields = make([]string, 0, len(previousSchema.fieldOrder))
-		values = make([]float32, 0, len(previousSchema.fieldOrder))
+		values = make([]any, 0, len(previousSchema.fieldOrder))
 		iterationOrder = previousSchema.mapOrder
 	} else {
 		// If this is the first data point, we'll walk the map in... map order.
@@ -416,9 +508,19 @@ func walk(datum map[string]any, previousSchema *schema) (*schema, []float32, err
 		schemaChanged = true
 	}
 
+	// Similarly, a metric whose type changed (e.g: an `any` field going from an int to a float)
+	// needs a new schema to be serialized correctly.
+	fieldTypes := make([]valueType, len(values))
+	for idx, value := range values {
+		fieldTypes[idx] = typeOf(value)
+	}
+	if previousSchema != nil && !slices.Equal(previousSchema.fieldTypes, fieldTypes) {
+		schemaChanged = true
+	}
+
 	// If the schema changed, return a new schema object with the updated schema.
 	if schemaChanged {
-		return &schema{datumMapOrder, fields}, values, nil
+		return &schema{datumMapOrder, fields, fieldTypes}, values, nil
 	}
 
 	return previousSchema, values, nil
@@ -455,6 +557,7 @@ func (ftdc *FTDC) writeDatum(datum datum) error {
 		return err
 	}
 	ftdc.prevFlatData = flatData
+	ftdc.publish(datum.Time, ftdc.currSchema, flatData)
 
 	return nil
 }
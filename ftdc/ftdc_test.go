@@ -218,7 +218,7 @@ func TestNestedStructs(t *testing.T) {
 
 	_, values, err := walk(datum.Data, nil)
 	test.That(t, err, test.ShouldBeNil)
-	test.That(t, values, test.ShouldResemble, []float32{1, 2})
+	test.That(t, values, test.ShouldResemble, []any{int64(1), int64(2)})
 
 	err = ftdc.writeDatum(datum)
 	test.That(t, err, test.ShouldBeNil)
@@ -482,3 +482,62 @@ func getFTDCFiles(t *testing.T, dir string, logger logging.Logger) []fs.FileInfo
 
 	return ret
 }
+
+// TestSubscribe asserts that subscribers receive a self-contained FTDC stream. Even when they
+// subscribe after data was written, the schema changes or they fall behind and miss datums.
+func TestSubscribe(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+
+	ftdc := NewWithWriter(bytes.NewBuffer(nil), logger.Sublogger("ftdc"))
+	statser := foo{}
+	ftdc.Add("foo", &statser)
+
+	// Data written before subscribing is not streamed.
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+
+	dataCh, unsubscribe := ftdc.Subscribe()
+	streamed := bytes.NewBuffer(nil)
+	drain := func() {
+		for {
+			select {
+			case data := <-dataCh:
+				streamed.Write(data)
+			default:
+				return
+			}
+		}
+	}
+
+	// Write more datums than the subscriber channel can hold without reading. The datums that do
+	// not fit are dropped.
+	for idx := 1; idx <= 25; idx++ {
+		statser.x = idx
+		test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	}
+	drain()
+
+	// A schema change is streamed after the subscriber catches up.
+	ftdc.Add("mock", &mockStatser{stats: struct{ Z int }{7}})
+	statser.x = 100
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+	drain()
+
+	var datums []FlatDatum
+	err := ParseStream(streamed, logger, func(flatDatum FlatDatum) error {
+		datums = append(datums, flatDatum)
+		return nil
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(datums), test.ShouldEqual, 21)
+
+	test.That(t, datums[0].asDatum().Data["foo"], test.ShouldResemble, map[string]float32{"X": 1, "Y": 0})
+	test.That(t, datums[19].asDatum().Data["foo"], test.ShouldResemble, map[string]float32{"X": 20, "Y": 0})
+	test.That(t, datums[20].asDatum().Data["foo"], test.ShouldResemble, map[string]float32{"X": 100, "Y": 0})
+	test.That(t, datums[20].asDatum().Data["mock"], test.ShouldResemble, map[string]float32{"Z": 7})
+
+	// Unsubscribing closes the channel and stops publishing.
+	unsubscribe()
+	_, ok := <-dataCh
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, ftdc.writeDatum(ftdc.constructDatum()), test.ShouldBeNil)
+}
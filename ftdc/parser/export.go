@@ -0,0 +1,339 @@
+package parser
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	commonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcev1 "go.opentelemetry.io/proto/otlp/resource/v1"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/internal/otlpfile"
+	"go.viam.com/rdk/logging"
+)
+
+// Export formats supported by `Export`.
+const (
+	ExportFormatCSV     = "csv"
+	ExportFormatParquet = "parquet"
+	ExportFormatOTLP    = "otlp"
+)
+
+// ExportOptions selects which readings `Export` writes out and in what format.
+type ExportOptions struct {
+	// Format is one of `ExportFormatCSV`, `ExportFormatParquet` or `ExportFormatOTLP`.
+	Format string
+	// Start and End bound the time range of exported datums (inclusive). A zero value leaves the
+	// respective end of the range unbounded.
+	Start time.Time
+	End   time.Time
+	// MetricGlobs selects which metrics are exported. A `*` matches any sequence of characters
+	// (including dots and slashes) and a `?` matches any single character. An empty list exports
+	// all metrics.
+	MetricGlobs []string
+}
+
+// exportRow is a single reading in the "long" (time, metric, value) shape every export format
+// uses. Long rows are robust to the schema changes that FTDC files contain.
+type exportRow struct {
+	time     int64
+	metric   string
+	rawValue any
+}
+
+// MetricGlobsToRegexp compiles metric globs into a single anchored regular expression. It returns a
+// nil regular expression when there are no globs, meaning all metrics are selected.
+func MetricGlobsToRegexp(globs []string) (*regexp.Regexp, error) {
+	if len(globs) == 0 {
+		return nil, nil
+	}
+
+	alternatives := make([]string, 0, len(globs))
+	for _, glob := range globs {
+		var pattern strings.Builder
+		for _, ch := range glob {
+			switch ch {
+			case '*':
+				pattern.WriteString(".*")
+			case '?':
+				pattern.WriteString(".")
+			default:
+				pattern.WriteString(regexp.QuoteMeta(string(ch)))
+			}
+		}
+		alternatives = append(alternatives, pattern.String())
+	}
+
+	return regexp.Compile("^(" + strings.Join(alternatives, "|") + ")$")
+}
+
+// selectRows flattens the datums within the option's time range into rows for the metrics matching
+// the option's globs.
+func selectRows(data []ftdc.FlatDatum, options ExportOptions) ([]exportRow, error) {
+	metricRe, err := MetricGlobsToRegexp(options.MetricGlobs)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []exportRow
+	for _, datum := range data {
+		if !options.Start.IsZero() && datum.Time < options.Start.UnixNano() {
+			continue
+		}
+		if !options.End.IsZero() && datum.Time > options.End.UnixNano() {
+			continue
+		}
+
+		for _, reading := range datum.Readings {
+			if metricRe != nil && !metricRe.MatchString(reading.MetricName) {
+				continue
+			}
+
+			rawValue := reading.RawValue
+			if rawValue == nil {
+				rawValue = reading.Value
+			}
+			rows = append(rows, exportRow{datum.Time, reading.MetricName, rawValue})
+		}
+	}
+
+	return rows, nil
+}
+
+// Export writes the readings of `data` selected by `options` to `output`.
+func Export(data []ftdc.FlatDatum, options ExportOptions, output io.Writer) error {
+	rows, err := selectRows(data, options)
+	if err != nil {
+		return err
+	}
+
+	switch options.Format {
+	case ExportFormatCSV:
+		return exportCSV(rows, output)
+	case ExportFormatParquet:
+		return exportParquet(rows, output)
+	case ExportFormatOTLP:
+		return exportOTLP(rows, output)
+	default:
+		return fmt.Errorf("unknown export format %q. Expected one of %q, %q or %q",
+			options.Format, ExportFormatCSV, ExportFormatParquet, ExportFormatOTLP)
+	}
+}
+
+// formatValue formats a reading's value without losing precision.
+func formatValue(value any) string {
+	switch val := value.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case uint64:
+		return strconv.FormatUint(val, 10)
+	case bool:
+		return strconv.FormatBool(val)
+	case string:
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+func exportCSV(rows []exportRow, output io.Writer) error {
+	writer := csv.NewWriter(output)
+	if err := writer.Write([]string{"time", "time_ns", "metric", "value"}); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if err := writer.Write([]string{
+			time.Unix(0, row.time).UTC().Format(time.RFC3339Nano),
+			strconv.FormatInt(row.time, 10),
+			row.metric,
+			formatValue(row.rawValue),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// parquetRow is the parquet schema for exported readings. Exactly one of the value columns is set
+// for each row, depending on the type of the recorded metric.
+type parquetRow struct {
+	Time        time.Time `parquet:"time,timestamp(nanosecond)"`
+	Metric      string    `parquet:"metric,dict"`
+	Value       *float64  `parquet:"value,optional"`
+	IntValue    *int64    `parquet:"int_value,optional"`
+	BoolValue   *bool     `parquet:"bool_value,optional"`
+	StringValue *string   `parquet:"string_value,optional"`
+}
+
+func toParquetRow(row exportRow) parquetRow {
+	ret := parquetRow{Time: time.Unix(0, row.time).UTC(), Metric: row.metric}
+	switch val := row.rawValue.(type) {
+	case float32:
+		asFloat := float64(val)
+		ret.Value = &asFloat
+	case float64:
+		ret.Value = &val
+	case int64:
+		ret.IntValue = &val
+	case uint64:
+		if val <= math.MaxInt64 {
+			asInt := int64(val)
+			ret.IntValue = &asInt
+		} else {
+			asFloat := float64(val)
+			ret.Value = &asFloat
+		}
+	case bool:
+		ret.BoolValue = &val
+	case string:
+		ret.StringValue = &val
+	}
+
+	return ret
+}
+
+func exportParquet(rows []exportRow, output io.Writer) error {
+	parquetRows := make([]parquetRow, len(rows))
+	for idx, row := range rows {
+		parquetRows[idx] = toParquetRow(row)
+	}
+
+	writer := parquet.NewGenericWriter[parquetRow](output)
+	if _, err := writer.Write(parquetRows); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+// exportOTLP writes each metric as an OTLP gauge. OTLP metrics are numeric, thus string readings
+// are omitted.
+func exportOTLP(rows []exportRow, output io.Writer) error {
+	var metrics []*metricsv1.Metric
+	metricsByName := make(map[string]*metricsv1.Metric)
+	for _, row := range rows {
+		dataPoint := &metricsv1.NumberDataPoint{
+			//nolint:gosec // FTDC times are nanoseconds since the epoch and not negative.
+			TimeUnixNano: uint64(row.time),
+		}
+		switch val := row.rawValue.(type) {
+		case float32:
+			dataPoint.Value = &metricsv1.NumberDataPoint_AsDouble{AsDouble: float64(val)}
+		case float64:
+			dataPoint.Value = &metricsv1.NumberDataPoint_AsDouble{AsDouble: val}
+		case int64:
+			dataPoint.Value = &metricsv1.NumberDataPoint_AsInt{AsInt: val}
+		case uint64:
+			dataPoint.Value = &metricsv1.NumberDataPoint_AsDouble{AsDouble: float64(val)}
+		case bool:
+			var asInt int64
+			if val {
+				asInt = 1
+			}
+			dataPoint.Value = &metricsv1.NumberDataPoint_AsInt{AsInt: asInt}
+		default:
+			continue
+		}
+
+		metric, exists := metricsByName[row.metric]
+		if !exists {
+			metric = &metricsv1.Metric{
+				Name: row.metric,
+				Data: &metricsv1.Metric_Gauge{Gauge: &metricsv1.Gauge{}},
+			}
+			metricsByName[row.metric] = metric
+			metrics = append(metrics, metric)
+		}
+		gauge := metric.GetGauge()
+		gauge.DataPoints = append(gauge.DataPoints, dataPoint)
+	}
+
+	return otlpfile.WriteMetricsJSON(output, &metricsv1.MetricsData{
+		ResourceMetrics: []*metricsv1.ResourceMetrics{{
+			Resource: &resourcev1.Resource{
+				Attributes: []*commonv1.KeyValue{{
+					Key:   "service.name",
+					Value: &commonv1.AnyValue{Value: &commonv1.AnyValue_StringValue{StringValue: "viam-server"}},
+				}},
+			},
+			ScopeMetrics: []*metricsv1.ScopeMetrics{{
+				Scope:   &commonv1.InstrumentationScope{Name: "go.viam.com/rdk/ftdc"},
+				Metrics: metrics,
+			}},
+		}},
+	})
+}
+
+// LaunchExport parses the `export` subcommand arguments and writes the selected FTDC data to the
+// requested output file.
+func LaunchExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := flags.String("format", ExportFormatCSV, "output format: csv, parquet or otlp")
+	start := flags.String("start", "", "only export datums at or after this UTC time. E.g: 2024-09-24T18:00:00")
+	end := flags.String("end", "", "only export datums at or before this UTC time. E.g: 2024-09-24T18:30:00")
+	metrics := flags.String("metrics", "", "comma-separated metric name globs. E.g: 'proc.*,*.ElapsedTimeSecs'")
+	outputPath := flags.String("o", "", "output file. Defaults to stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("expected an FTDC filename or directory. E.g: export -format csv <path-to>/viam-server.ftdc")
+	}
+
+	options := ExportOptions{Format: *format}
+	var err error
+	if *start != "" {
+		if options.Start, err = parseStringAsTime(*start); err != nil {
+			return err
+		}
+	}
+	if *end != "" {
+		if options.End, err = parseStringAsTime(*end); err != nil {
+			return err
+		}
+	}
+	if *metrics != "" {
+		options.MetricGlobs = strings.Split(*metrics, ",")
+	}
+
+	logger := logging.NewLogger("parser")
+	data, _, err := getFTDCData(filepath.Clean(flags.Arg(0)), logger)
+	if err != nil {
+		return err
+	}
+
+	if *outputPath == "" {
+		return Export(data, options, os.Stdout)
+	}
+
+	outputFile, err := os.Create(*outputPath)
+	if err != nil {
+		return err
+	}
+	if err := Export(data, options, outputFile); err != nil {
+		utils.UncheckedError(outputFile.Close())
+		return err
+	}
+	// Parquet writes its footer on close, so a failed close means a truncated file.
+	return outputFile.Close()
+}
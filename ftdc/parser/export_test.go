@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"go.viam.com/test"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/ftdc"
+)
+
+func exportTestData() []ftdc.FlatDatum {
+	return []ftdc.FlatDatum{
+		{Time: 1e9, Readings: []ftdc.Reading{
+			{MetricName: "proc.ElapsedTimeSecs", Value: 1, RawValue: int64(1)},
+			{MetricName: "gps.lat", Value: 40.712812, RawValue: 40.712812345678},
+			{MetricName: "net/eth0.up", Value: 1, RawValue: true},
+			{MetricName: "arm.state", Value: 0, RawValue: "moving"},
+		}},
+		{Time: 2e9, Readings: []ftdc.Reading{
+			{MetricName: "proc.ElapsedTimeSecs", Value: 2, RawValue: int64(2)},
+			{MetricName: "gps.lat", Value: 40.7, RawValue: 40.7},
+			{MetricName: "net/eth0.up", Value: 1, RawValue: true},
+			// A reading parsed from a legacy file.
+			{MetricName: "arm.power", Value: 0.5},
+		}},
+		{Time: 3e9, Readings: []ftdc.Reading{
+			{MetricName: "proc.ElapsedTimeSecs", Value: 3, RawValue: int64(3)},
+		}},
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	err := Export(exportTestData(), ExportOptions{
+		Format:      ExportFormatCSV,
+		End:         time.Unix(2, 0),
+		MetricGlobs: []string{"gps.*", "net/*.up", "arm.*"},
+	}, output)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, output.String(), test.ShouldEqual, `time,time_ns,metric,value
+1970-01-01T00:00:01Z,1000000000,gps.lat,40.712812345678
+1970-01-01T00:00:01Z,1000000000,net/eth0.up,true
+1970-01-01T00:00:01Z,1000000000,arm.state,moving
+1970-01-01T00:00:02Z,2000000000,gps.lat,40.7
+1970-01-01T00:00:02Z,2000000000,net/eth0.up,true
+1970-01-01T00:00:02Z,2000000000,arm.power,0.5
+`)
+}
+
+func TestExportParquet(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	err := Export(exportTestData(), ExportOptions{
+		Format: ExportFormatParquet,
+		Start:  time.Unix(2, 0),
+	}, output)
+	test.That(t, err, test.ShouldBeNil)
+
+	rows, err := parquet.Read[parquetRow](bytes.NewReader(output.Bytes()), int64(output.Len()))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(rows), test.ShouldEqual, 5)
+
+	test.That(t, rows[0].Time, test.ShouldEqual, time.Unix(2, 0).UTC())
+	test.That(t, rows[0].Metric, test.ShouldEqual, "proc.ElapsedTimeSecs")
+	test.That(t, *rows[0].IntValue, test.ShouldEqual, 2)
+	test.That(t, rows[0].Value, test.ShouldBeNil)
+	test.That(t, *rows[1].Value, test.ShouldEqual, 40.7)
+	test.That(t, *rows[2].BoolValue, test.ShouldBeTrue)
+	test.That(t, *rows[3].Value, test.ShouldEqual, 0.5)
+	test.That(t, rows[4].Time, test.ShouldEqual, time.Unix(3, 0).UTC())
+}
+
+func TestExportOTLP(t *testing.T) {
+	output := bytes.NewBuffer(nil)
+	err := Export(exportTestData(), ExportOptions{
+		Format:      ExportFormatOTLP,
+		MetricGlobs: []string{"proc.ElapsedTime?ecs", "arm.*"},
+	}, output)
+	test.That(t, err, test.ShouldBeNil)
+
+	var metricsData metricsv1.MetricsData
+	test.That(t, protojson.Unmarshal(output.Bytes(), &metricsData), test.ShouldBeNil)
+	metrics := metricsData.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	// The string `arm.state` metric cannot be represented as an OTLP metric.
+	test.That(t, len(metrics), test.ShouldEqual, 2)
+
+	test.That(t, metrics[0].Name, test.ShouldEqual, "proc.ElapsedTimeSecs")
+	dataPoints := metrics[0].GetGauge().DataPoints
+	test.That(t, len(dataPoints), test.ShouldEqual, 3)
+	test.That(t, dataPoints[2].TimeUnixNano, test.ShouldEqual, 3e9)
+	test.That(t, dataPoints[2].GetAsInt(), test.ShouldEqual, 3)
+
+	test.That(t, metrics[1].Name, test.ShouldEqual, "arm.power")
+	test.That(t, metrics[1].GetGauge().DataPoints[0].GetAsDouble(), test.ShouldEqual, 0.5)
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	err := Export(exportTestData(), ExportOptions{Format: "xml"}, bytes.NewBuffer(nil))
+	test.That(t, err, test.ShouldNotBeNil)
+}
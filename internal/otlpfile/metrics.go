@@ -0,0 +1,23 @@
+package otlpfile
+
+import (
+	"io"
+
+	metricsv1 "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// WriteMetricsJSON writes metrics to w using the OTLP file exporter format: one JSON encoded
+// MetricsData message per line. Files written this way can be loaded by the OpenTelemetry
+// collector's otlpjsonfile receiver.
+func WriteMetricsJSON(w io.Writer, metrics *metricsv1.MetricsData) error {
+	out, err := protojson.Marshal(metrics)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(out); err != nil {
+		return err
+	}
+	_, err = w.Write([]byte{'\n'})
+	return err
+}
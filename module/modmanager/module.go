@@ -305,7 +305,8 @@ func (m *module) startProcess(
 	defer checkTicker.Stop()
 
 	m.logger.CInfow(ctx, "Starting up module", "module", m.cfg.Name, "tcp_mode", tcpMode)
-	rutils.LogViamEnvVariables("Starting module with following Viam environment variables", moduleEnvironment, m.logger)
+	rutils.LogViamEnvVariables("Starting module with following Viam environment variables",
+		m.cfg.RedactEnvironment(moduleEnvironment), m.logger)
 
 	ctxTimeout, cancel := context.WithTimeout(ctx, rutils.GetModuleStartupTimeout(m.logger))
 	defer cancel()
@@ -138,9 +138,25 @@ func (req *PlanRequest) validatePlanRequest() error {
 	if req.Constraints == nil {
 		req.Constraints = &motionplan.Constraints{}
 	}
+
+	alg := req.PlannerOptions.PlanningAlgorithm()
+	if err := alg.validate(); err != nil {
+		return err
+	}
+	// RRT* only checks constraints along the edges of its trees, and never finds edges meeting topological constraints
+	if alg == RRTStar && hasTopoConstraints(req.Constraints) {
+		return NewAlgAndConstraintMismatchErr(string(alg))
+	}
 	return nil
 }
 
+// hasTopoConstraints returns whether the constraints restrict the path the frames take, rather than only what may collide.
+func hasTopoConstraints(constraints *motionplan.Constraints) bool {
+	return constraints != nil && (len(constraints.LinearConstraint) > 0 ||
+		len(constraints.PseudolinearConstraint) > 0 ||
+		len(constraints.OrientationConstraint) > 0)
+}
+
 // PlanFrameMotion plans a motion to destination for a given frame with no frame system. It will create a new FS just for the plan.
 // WorldState is not supported in the absence of a real frame system.
 func PlanFrameMotion(ctx context.Context,
@@ -151,6 +167,22 @@ func PlanFrameMotion(ctx context.Context,
 	constraints *motionplan.Constraints,
 	planningOpts map[string]interface{},
 ) ([][]referenceframe.Input, error) {
+	plan, err := planFrameMotion(ctx, logger, dst, f, seed, constraints, planningOpts)
+	if err != nil {
+		return nil, err
+	}
+	return plan.Trajectory().GetFrameInputs(f.Name())
+}
+
+// planFrameMotion is PlanFrameMotion returning the whole plan.
+func planFrameMotion(ctx context.Context,
+	logger logging.Logger,
+	dst spatialmath.Pose,
+	f referenceframe.Frame,
+	seed []referenceframe.Input,
+	constraints *motionplan.Constraints,
+	planningOpts map[string]interface{},
+) (motionplan.Plan, error) {
 	// ephemerally create a framesystem containing just the frame for the solve
 	fs := referenceframe.NewEmptyFrameSystem("")
 	if err := fs.AddFrame(f, fs.World()); err != nil {
@@ -169,10 +201,7 @@ func PlanFrameMotion(ctx context.Context,
 		Constraints:    constraints,
 		PlannerOptions: planOpts,
 	})
-	if err != nil {
-		return nil, err
-	}
-	return plan.Trajectory().GetFrameInputs(f.Name())
+	return plan, err
 }
 
 // PlanMeta is meta data about plan generation.
@@ -180,6 +209,11 @@ type PlanMeta struct {
 	Duration       time.Duration
 	Partial        bool
 	GoalsProcessed int
+	// Cached is true when the plan was reused from a PlanCache rather than planned.
+	Cached bool
+
+	// the index in the trajectory at which each processed goal is reached
+	goalIndexes []int
 }
 
 // PlanMotion plans a motion from a provided plan request.
@@ -229,14 +263,53 @@ func PlanMotion(ctx context.Context, parentLogger logging.Logger, request *PlanR
 
 	meta.GoalsProcessed = goalsProcessed
 
-	t, err := motionplan.NewSimplePlanFromTrajectory(trajAsInps, request.FrameSystem)
+	t, err := newTimedPlan(ctx, logger, trajAsInps, request.FrameSystem)
 	if err != nil {
 		return nil, meta, err
 	}
-
 	return t, meta, nil
 }
 
+// newTimedPlan makes the plan following a trajectory, timed if the frames moving have dynamic limits.
+func newTimedPlan(
+	ctx context.Context, logger logging.Logger, trajAsInps []*referenceframe.LinearInputs, fs *referenceframe.FrameSystem,
+) (*motionplan.SimplePlan, error) {
+	t, err := motionplan.NewSimplePlanFromTrajectory(trajAsInps, fs)
+	if err != nil {
+		return nil, err
+	}
+	limits := dynamicLimits(fs)
+	if len(limits) == 0 {
+		return t, nil
+	}
+	timed, err := motionplan.ParameterizeTrajectory(t.Trajectory(), limits)
+	if err != nil {
+		logger.CDebugf(ctx, "unable to time plan: %v", err)
+	} else {
+		t.SetTimedTrajectory(timed)
+	}
+	return t, nil
+}
+
+// dynamicLimits returns the dynamic limits of the inputs of each frame in the frame system which has any.
+func dynamicLimits(fs *referenceframe.FrameSystem) map[string][]referenceframe.DynamicLimit {
+	limits := map[string][]referenceframe.DynamicLimit{}
+	for _, name := range fs.FrameNames() {
+		limiter, ok := fs.Frame(name).(referenceframe.DynamicLimiter)
+		if !ok {
+			continue
+		}
+		frameLimits := limiter.DynamicLimits()
+		for _, limit := range frameLimits {
+			if limit != (referenceframe.DynamicLimit{}) {
+				limits[name] = frameLimits
+				break
+			}
+		}
+	}
+	return limits
+}
+
 var defaultArmPlannerOptions = &motionplan.Constraints{
 	LinearConstraint: []motionplan.LinearConstraint{},
 }
@@ -259,11 +332,24 @@ func MoveArm(ctx context.Context, logger logging.Logger, a arm.Arm, dst spatialm
 		return err
 	}
 
-	plan, err := PlanFrameMotion(ctx, logger, dst, model, inputs, defaultArmPlannerOptions, nil)
+	plan, err := planFrameMotion(ctx, logger, dst, model, inputs, defaultArmPlannerOptions, nil)
 	if err != nil {
 		return err
 	}
-	return a.MoveThroughJointPositions(ctx, plan, nil, nil)
+	positions, err := plan.Trajectory().GetFrameInputs(model.Name())
+	if err != nil {
+		return err
+	}
+	// arms which can follow a timed trajectory are given the timing of the plan, when it could be timed
+	var extra map[string]interface{}
+	if timedPlan, ok := plan.(motionplan.TimedPlan); ok && len(timedPlan.TimedTrajectory()) > 0 {
+		times, velocities, err := timedPlan.TimedTrajectory().GetFrameTiming(model.Name())
+		if err != nil {
+			return err
+		}
+		extra = (&arm.TrajectoryTiming{Times: times, Velocities: velocities}).ToExtra(nil)
+	}
+	return a.MoveThroughJointPositions(ctx, positions, nil, extra)
 }
 
 // ReadRequestFromFile reads a PlanRequest from a json file.
@@ -782,6 +782,37 @@ func TestArmConstraintSpecificationSolve(t *testing.T) {
 	}
 }
 
+func TestCartesianMotion(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	fs := frame.NewEmptyFrameSystem("")
+	x, err := frame.ParseModelJSONFile(utils.ResolveFile("components/arm/fake/kinematics/xarm6.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(x, fs.World()), test.ShouldBeNil)
+
+	start, err := x.Transform(home6)
+	test.That(t, err, test.ShouldBeNil)
+	goal := spatialmath.NewPose(start.Point().Add(r3.Vector{Y: 150, Z: -100}), start.Orientation())
+
+	opt := NewBasicPlannerOptions()
+	opt.PlanningAlgorithmSettings.Algorithm = Cartesian
+	plan, _, err := PlanMotion(context.Background(), logger, &PlanRequest{
+		FrameSystem:    fs,
+		Goals:          []*PlanState{{poses: frame.FrameSystemPoses{x.Name(): frame.NewPoseInFrame(frame.World, goal)}}},
+		StartState:     &PlanState{structuredConfiguration: frame.FrameSystemInputs{x.Name(): home6}},
+		PlannerOptions: opt,
+	})
+	test.That(t, err, test.ShouldBeNil)
+
+	// every waypoint is on the line to the goal, a step apart
+	poses, err := plan.Path().GetFramePoses(x.Name())
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(poses), test.ShouldBeGreaterThanOrEqualTo, int(start.Point().Distance(goal.Point())/defaultStepSizeMM))
+	for _, pose := range poses {
+		test.That(t, spatialmath.DistToLineSegment(start.Point(), goal.Point(), pose.Point()), test.ShouldBeLessThan, 5)
+	}
+	test.That(t, spatialmath.PoseAlmostCoincidentEps(poses[len(poses)-1], goal, 5), test.ShouldBeTrue)
+}
+
 func TestValidatePlanRequest(t *testing.T) {
 	t.Parallel()
 	type testCase struct {
@@ -893,6 +924,46 @@ func TestValidatePlanRequest(t *testing.T) {
 				PlannerOptions: nil,
 			},
 		},
+		{
+			name: "unknown planning algorithm - fail",
+			request: &PlanRequest{
+				FrameSystem: fs,
+				Goals:       validGoal,
+				StartState: &PlanState{structuredConfiguration: map[string][]frame.Input{
+					"frame1": {},
+					"frame2": {0},
+				}},
+				PlannerOptions: &PlannerOptions{PlanningAlgorithmSettings: AlgorithmSettings{Algorithm: "rrt"}},
+			},
+			expectedErr: errors.New(`unknown planning algorithm "rrt"`),
+		},
+		{
+			name: "rrt* with topological constraints - fail",
+			request: &PlanRequest{
+				FrameSystem: fs,
+				Goals:       validGoal,
+				StartState: &PlanState{structuredConfiguration: map[string][]frame.Input{
+					"frame1": {},
+					"frame2": {0},
+				}},
+				PlannerOptions: &PlannerOptions{PlanningAlgorithmSettings: AlgorithmSettings{Algorithm: RRTStar}},
+				Constraints:    &motionplan.Constraints{LinearConstraint: []motionplan.LinearConstraint{{}}},
+			},
+			expectedErr: NewAlgAndConstraintMismatchErr(string(RRTStar)),
+		},
+		{
+			name: "cartesian with topological constraints",
+			request: &PlanRequest{
+				FrameSystem: fs,
+				Goals:       validGoal,
+				StartState: &PlanState{structuredConfiguration: map[string][]frame.Input{
+					"frame1": {},
+					"frame2": {0},
+				}},
+				PlannerOptions: &PlannerOptions{PlanningAlgorithmSettings: AlgorithmSettings{Algorithm: Cartesian}},
+				Constraints:    &motionplan.Constraints{LinearConstraint: []motionplan.LinearConstraint{{}}},
+			},
+		},
 	}
 
 	testFn := func(t *testing.T, tc testCase) {
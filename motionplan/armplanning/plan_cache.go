@@ -0,0 +1,381 @@
+package armplanning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// Inputs which differ by less than this many radians or mm give requests the same cache key.
+	cacheInputResolution = 1e-3
+	// Poses which differ by less than this many mm or degrees give requests the same cache key.
+	cachePoseResolution = 0.1
+
+	planCacheFileExt = ".json"
+)
+
+// cachedPlan is a plan stored in a PlanCache, and the form it is written to disk in.
+type cachedPlan struct {
+	Trajectory motionplan.Trajectory `json:"trajectory"`
+	// the index in the trajectory at which each goal of the request is reached
+	GoalIndexes []int `json:"goal_indexes"`
+
+	lastUsed time.Time
+}
+
+// PlanCache stores the plans made for requests so that repeating a request reuses its plan instead of planning
+// again. Requests with the same frame system, start configuration, goals, world state, constraints and planner
+// options share a plan, as long as the plan still starts at the start configuration, reaches the goals and meets
+// the constraints of the request when it is repeated; otherwise it is planned again.
+type PlanCache struct {
+	size   int
+	dir    string
+	logger logging.Logger
+
+	mu    sync.Mutex
+	plans map[string]*cachedPlan
+}
+
+// NewPlanCache returns a PlanCache holding up to size plans, evicting the least recently used plan when full.
+// If dir is not empty the plans are also written to it, and the plans already written to it are loaded, so that
+// they are kept across restarts.
+func NewPlanCache(size int, dir string, logger logging.Logger) (*PlanCache, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("plan cache size must be positive, got %d", size)
+	}
+	c := &PlanCache{
+		size:   size,
+		dir:    dir,
+		logger: logger,
+		plans:  map[string]*cachedPlan{},
+	}
+	if dir == "" {
+		return c, nil
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != planCacheFileExt {
+			continue
+		}
+		key := strings.TrimSuffix(entry.Name(), planCacheFileExt)
+		plan, err := readCachedPlan(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			logger.Warnw("removing unreadable cached plan", "file", entry.Name(), "error", err)
+			c.removeFile(key)
+			continue
+		}
+		c.plans[key] = plan
+	}
+	c.evict()
+	logger.Debugf("loaded %d cached plans from %s", len(c.plans), dir)
+	return c, nil
+}
+
+// PlanMotion returns the plan stored for the request if it is still valid, or else plans with PlanMotion and
+// stores the plan. Partial plans aren't stored.
+func (c *PlanCache) PlanMotion(ctx context.Context, logger logging.Logger, request *PlanRequest) (motionplan.Plan, *PlanMeta, error) {
+	start := time.Now()
+	if err := request.validatePlanRequest(); err != nil {
+		return PlanMotion(ctx, logger, request)
+	}
+	key, err := request.cacheKey()
+	if err != nil {
+		return nil, &PlanMeta{}, err
+	}
+
+	c.mu.Lock()
+	cached, ok := c.plans[key]
+	c.mu.Unlock()
+	if ok {
+		traj, err := cached.validate(ctx, logger, request)
+		if err == nil {
+			plan, err := newTimedPlan(ctx, logger, traj, request.FrameSystem)
+			if err != nil {
+				return nil, &PlanMeta{}, err
+			}
+			c.touch(key, cached)
+			return plan, &PlanMeta{
+				Duration:       time.Since(start),
+				GoalsProcessed: len(request.Goals),
+				Cached:         true,
+				goalIndexes:    cached.GoalIndexes,
+			}, nil
+		}
+		logger.CDebugf(ctx, "planning again as the cached plan is no longer valid: %v", err)
+		c.remove(key)
+	}
+
+	plan, meta, err := PlanMotion(ctx, logger, request)
+	if err != nil || meta.Partial {
+		return plan, meta, err
+	}
+	c.put(key, &cachedPlan{Trajectory: plan.Trajectory(), GoalIndexes: meta.goalIndexes})
+	return plan, meta, nil
+}
+
+// Len returns the number of plans in the cache.
+func (c *PlanCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.plans)
+}
+
+func (c *PlanCache) put(key string, plan *cachedPlan) {
+	plan.lastUsed = time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.plans[key] = plan
+	c.evict()
+	if c.dir == "" {
+		return
+	}
+	if err := writeCachedPlan(filepath.Join(c.dir, key+planCacheFileExt), plan); err != nil {
+		c.logger.Warnw("couldn't write cached plan", "error", err)
+	}
+}
+
+func (c *PlanCache) touch(key string, plan *cachedPlan) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	plan.lastUsed = time.Now()
+	if c.dir != "" {
+		// the modification time orders the plans by use when loading them
+		//nolint:errcheck
+		os.Chtimes(filepath.Join(c.dir, key+planCacheFileExt), plan.lastUsed, plan.lastUsed)
+	}
+}
+
+func (c *PlanCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.plans, key)
+	c.removeFile(key)
+}
+
+// evict removes the least recently used plans until the cache isn't over its size. It must be called with mu held.
+func (c *PlanCache) evict() {
+	if len(c.plans) <= c.size {
+		return
+	}
+	keys := make([]string, 0, len(c.plans))
+	for key := range c.plans {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return c.plans[keys[i]].lastUsed.Before(c.plans[keys[j]].lastUsed) })
+	for _, key := range keys[:len(keys)-c.size] {
+		delete(c.plans, key)
+		c.removeFile(key)
+	}
+}
+
+func (c *PlanCache) removeFile(key string) {
+	if c.dir == "" {
+		return
+	}
+	if err := os.Remove(filepath.Join(c.dir, key+planCacheFileExt)); err != nil && !os.IsNotExist(err) {
+		c.logger.Warnw("couldn't remove cached plan", "error", err)
+	}
+}
+
+func readCachedPlan(path string) (*cachedPlan, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	plan := &cachedPlan{}
+	if err := json.NewDecoder(f).Decode(plan); err != nil {
+		return nil, err
+	}
+	if len(plan.Trajectory) == 0 || len(plan.GoalIndexes) == 0 {
+		return nil, errors.New("cached plan is empty")
+	}
+	plan.lastUsed = info.ModTime()
+	return plan, nil
+}
+
+func writeCachedPlan(path string, plan *cachedPlan) error {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+	// write to a temporary file first so that a crash never leaves a partially written plan behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		utils.UncheckedError(tmp.Close())
+		utils.UncheckedError(os.Remove(tmp.Name()))
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		utils.UncheckedError(os.Remove(tmp.Name()))
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// validate checks that the cached plan is a valid plan for the request, and returns its trajectory starting at the
+// start configuration of the request and ending at any goal configurations of the request exactly.
+func (cached *cachedPlan) validate(
+	ctx context.Context, logger logging.Logger, request *PlanRequest,
+) ([]*referenceframe.LinearInputs, error) {
+	if len(cached.GoalIndexes) != len(request.Goals) || cached.GoalIndexes[len(cached.GoalIndexes)-1] != len(cached.Trajectory)-1 {
+		return nil, errors.New("cached plan is for a different number of goals")
+	}
+	traj := make([]*referenceframe.LinearInputs, 0, len(cached.Trajectory))
+	for _, step := range cached.Trajectory {
+		traj = append(traj, step.ToLinearInputs())
+	}
+
+	// the inputs in the key are rounded, so the plan is moved to start and end exactly where requested
+	if err := snapInputs(traj[0], request.StartState.structuredConfiguration); err != nil {
+		return nil, fmt.Errorf("cached plan doesn't start at the start configuration: %w", err)
+	}
+	for i, goal := range request.Goals {
+		if err := snapInputs(traj[cached.GoalIndexes[i]], goal.structuredConfiguration); err != nil {
+			return nil, fmt.Errorf("cached plan doesn't reach goal %d: %w", i, err)
+		}
+	}
+
+	pc, err := newPlanContext(ctx, logger, request, &PlanMeta{})
+	if err != nil {
+		return nil, err
+	}
+	from := 0
+	for i, goal := range request.Goals {
+		to := cached.GoalIndexes[i]
+		if to < from {
+			return nil, errors.New("cached plan reaches its goals out of order")
+		}
+		goalPoses, err := goal.ComputePoses(ctx, request.FrameSystem)
+		if err != nil {
+			return nil, err
+		}
+		psc, err := newPlanSegmentContext(ctx, pc, traj[from], goalPoses)
+		if err != nil {
+			return nil, err
+		}
+		for j := from + 1; j <= to; j++ {
+			if err := psc.checkPath(ctx, traj[j-1], traj[j], true); err != nil {
+				return nil, err
+			}
+		}
+		if len(goal.structuredConfiguration) == 0 {
+			score := pc.planOpts.getGoalMetric(psc.goal)(&motionplan.StateFS{Configuration: traj[to], FS: pc.fs})
+			if score > pc.planOpts.GoalThreshold {
+				return nil, fmt.Errorf("cached plan doesn't reach goal %d, its score is %f", i, score)
+			}
+		}
+		from = to
+	}
+	return traj, nil
+}
+
+// snapInputs sets the inputs of the frames in want to their wanted values, if they are within the resolution of the
+// cache key of them.
+func snapInputs(inputs *referenceframe.LinearInputs, want referenceframe.FrameSystemInputs) error {
+	for name, wantInputs := range want {
+		have := inputs.Get(name)
+		if len(have) != len(wantInputs) {
+			return fmt.Errorf("frame %s has %d inputs, not %d", name, len(have), len(wantInputs))
+		}
+		if dist := referenceframe.InputsLinfDistance(have, wantInputs); dist > cacheInputResolution {
+			return fmt.Errorf("inputs of frame %s are %f away", name, dist)
+		}
+		inputs.Put(name, wantInputs)
+	}
+	return nil
+}
+
+// cacheKey returns the key of the plan for the request in a PlanCache. Inputs and poses are rounded, so that
+// requests which differ only by noise in the inputs or poses share a key.
+func (req *PlanRequest) cacheKey() (string, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "frame system %d\n", req.FrameSystem.Hash())
+	writeInputsKey(h, "start", req.StartState.structuredConfiguration)
+	for i, goal := range req.Goals {
+		writeInputsKey(h, fmt.Sprintf("goal %d", i), goal.structuredConfiguration)
+		names := make([]string, 0, len(goal.poses))
+		for name := range goal.poses {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pif := goal.poses[name]
+			fmt.Fprintf(h, "goal %d %s in %s %s\n", i, name, pif.Parent(), poseKey(pif.Pose()))
+		}
+	}
+	if req.WorldState != nil {
+		for _, gif := range req.WorldState.Obstacles() {
+			for _, geometry := range gif.Geometries() {
+				fmt.Fprintf(h, "obstacle %s in %s %d\n", geometry.Label(), gif.Parent(), geometry.Hash())
+			}
+		}
+		for _, lif := range req.WorldState.Transforms() {
+			fmt.Fprintf(h, "transform %s in %s %s\n", lif.Name(), lif.Parent(), poseKey(lif.Pose()))
+		}
+	}
+	for _, v := range []interface{}{req.Constraints, req.PlannerOptions} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\n", data)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeInputsKey(w io.Writer, label string, inputs referenceframe.FrameSystemInputs) {
+	names := make([]string, 0, len(inputs))
+	for name := range inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s %s", label, name)
+		for _, input := range inputs[name] {
+			fmt.Fprintf(w, " %d", int64(math.Round(input/cacheInputResolution)))
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func poseKey(pose spatialmath.Pose) string {
+	round := func(v float64) int64 { return int64(math.Round(v / cachePoseResolution)) }
+	pt := pose.Point()
+	o := pose.Orientation().OrientationVectorDegrees()
+	return fmt.Sprintf("%d %d %d %d %d %d %d",
+		round(pt.X), round(pt.Y), round(pt.Z), round(o.OX*100), round(o.OY*100), round(o.OZ*100), round(o.Theta))
+}
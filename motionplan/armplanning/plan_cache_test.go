@@ -0,0 +1,139 @@
+package armplanning
+
+import (
+	"context"
+	"math"
+	"os"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
+)
+
+func TestPlanCache(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	m, err := referenceframe.ParseModelJSONFile(rutils.ResolveFile("components/arm/fake/kinematics/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := referenceframe.NewEmptyFrameSystem("")
+	test.That(t, fs.AddFrame(m, fs.World()), test.ShouldBeNil)
+
+	request := func(start, goal []referenceframe.Input) *PlanRequest {
+		return &PlanRequest{
+			FrameSystem: fs,
+			Goals:       []*PlanState{NewPlanState(nil, referenceframe.FrameSystemInputs{m.Name(): goal})},
+			StartState:  NewPlanState(nil, referenceframe.FrameSystemInputs{m.Name(): start}),
+		}
+	}
+	goal := []referenceframe.Input{math.Pi / 2, 0, 0, 0, 0, 0}
+
+	dir := t.TempDir()
+	cache, err := NewPlanCache(2, dir, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	plan, meta, err := cache.PlanMotion(ctx, logger, request(home6, goal))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, meta.Cached, test.ShouldBeFalse)
+	test.That(t, cache.Len(), test.ShouldEqual, 1)
+
+	cachedPlan, meta, err := cache.PlanMotion(ctx, logger, request(home6, goal))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, meta.Cached, test.ShouldBeTrue)
+	test.That(t, meta.GoalsProcessed, test.ShouldEqual, 1)
+	test.That(t, cachedPlan.Trajectory(), test.ShouldResemble, plan.Trajectory())
+
+	t.Run("small changes in the start reuse the plan from the new start", func(t *testing.T) {
+		start := []referenceframe.Input{1e-4, 0, 0, 0, 0, 0}
+		plan, meta, err := cache.PlanMotion(ctx, logger, request(start, goal))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, meta.Cached, test.ShouldBeTrue)
+		test.That(t, plan.Trajectory()[0][m.Name()], test.ShouldResemble, start)
+		test.That(t, plan.Trajectory()[len(plan.Trajectory())-1][m.Name()], test.ShouldResemble, goal)
+	})
+
+	t.Run("plans are kept across restarts", func(t *testing.T) {
+		restarted, err := NewPlanCache(2, dir, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, restarted.Len(), test.ShouldEqual, 1)
+		plan, meta, err := restarted.PlanMotion(ctx, logger, request(home6, goal))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, meta.Cached, test.ShouldBeTrue)
+		test.That(t, plan.Trajectory(), test.ShouldResemble, cachedPlan.Trajectory())
+	})
+
+	t.Run("plans which are no longer valid are not reused", func(t *testing.T) {
+		req := request(home6, goal)
+		test.That(t, req.validatePlanRequest(), test.ShouldBeNil)
+		key, err := req.cacheKey()
+		test.That(t, err, test.ShouldBeNil)
+		cached := cache.plans[key]
+		test.That(t, cached, test.ShouldNotBeNil)
+
+		// the box is in the way of swinging the arm around its base
+		box, err := spatialmath.NewBox(
+			spatialmath.NewPoseFromPoint(r3.Vector{X: -439, Y: -439, Z: 162}), r3.Vector{X: 100, Y: 100, Z: 100}, "box",
+		)
+		test.That(t, err, test.ShouldBeNil)
+		req.WorldState, err = referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{box})},
+			nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = cached.validate(ctx, logger, req)
+		test.That(t, err, test.ShouldNotBeNil)
+
+		_, err = cached.validate(ctx, logger, request([]referenceframe.Input{0.1, 0, 0, 0, 0, 0}, goal))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "doesn't start at the start configuration")
+	})
+
+	t.Run("the least recently used plans are evicted", func(t *testing.T) {
+		for _, other := range [][]referenceframe.Input{{0.5, 0, 0, 0, 0, 0}, {-0.5, 0, 0, 0, 0, 0}} {
+			_, meta, err := cache.PlanMotion(ctx, logger, request(home6, other))
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, meta.Cached, test.ShouldBeFalse)
+		}
+		test.That(t, cache.Len(), test.ShouldEqual, 2)
+		files, err := os.ReadDir(dir)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, files, test.ShouldHaveLength, 2)
+
+		_, meta, err := cache.PlanMotion(ctx, logger, request(home6, goal))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, meta.Cached, test.ShouldBeFalse)
+	})
+
+	_, err = NewPlanCache(0, "", logger)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestPlanCacheKey(t *testing.T) {
+	fs := referenceframe.NewEmptyFrameSystem("")
+	frame, err := referenceframe.NewTranslationalFrame("frame", r3.Vector{X: 1}, referenceframe.Limit{Min: -100, Max: 100})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(frame, fs.World()), test.ShouldBeNil)
+
+	key := func(start float64, goal spatialmath.Pose) string {
+		req := &PlanRequest{
+			FrameSystem: fs,
+			Goals: []*PlanState{
+				NewPlanState(referenceframe.FrameSystemPoses{"frame": referenceframe.NewPoseInFrame(referenceframe.World, goal)}, nil),
+			},
+			StartState: NewPlanState(nil, referenceframe.FrameSystemInputs{"frame": {start}}),
+		}
+		test.That(t, req.validatePlanRequest(), test.ShouldBeNil)
+		k, err := req.cacheKey()
+		test.That(t, err, test.ShouldBeNil)
+		return k
+	}
+	goal := spatialmath.NewPoseFromPoint(r3.Vector{X: 10})
+	test.That(t, key(0, goal), test.ShouldEqual, key(1e-5, goal))
+	test.That(t, key(0, goal), test.ShouldEqual, key(0, spatialmath.NewPoseFromPoint(r3.Vector{X: 10.001})))
+	test.That(t, key(0, goal), test.ShouldNotEqual, key(0.1, goal))
+	test.That(t, key(0, goal), test.ShouldNotEqual, key(0, spatialmath.NewPoseFromPoint(r3.Vector{X: 11})))
+}
@@ -102,6 +102,7 @@ func (pm *planManager) planMultiWaypoint(ctx context.Context) ([]*referenceframe
 			}
 		}
 		start = to
+		pm.pc.planMeta.goalIndexes = append(pm.pc.planMeta.goalIndexes, len(linearTraj)-1)
 	}
 
 	return linearTraj, len(pm.request.Goals), nil
@@ -141,6 +142,9 @@ func (pm *planManager) planToDirectJoints(
 	}
 
 	pm.logger.Debugf("want to go to specific joint positions, but path is blocked: %v", err)
+	if pm.request.PlannerOptions.PlanningAlgorithm() == Cartesian {
+		return nil, fmt.Errorf("cartesian planning can't go around what blocks the path to joint positions: %w", err)
+	}
 	_, err = psc.checker.CheckStateFSConstraints(ctx, &motionplan.StateFS{
 		Configuration: fullConfig,
 		FS:            psc.pc.fs,
@@ -149,7 +153,7 @@ func (pm *planManager) planToDirectJoints(
 		return nil, fmt.Errorf("want to go to specific joint config but it is invalid: %w", err)
 	}
 
-	pathPlanner, err := newCBiRRTMotionPlanner(ctx, pm.pc, psc, pm.logger.Sublogger("cbirrt"))
+	pathPlanner, err := newPathPlanner(ctx, pm.pc, psc, pm.logger)
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +204,7 @@ func (pm *planManager) planSingleGoal(
 	}
 
 	pm.logger.Debugf("initRRTSolutions goalMap size: %d", len(planSeed.maps.goalMap))
-	pathPlanner, err := newCBiRRTMotionPlanner(ctx, pm.pc, psc, pm.logger.Sublogger("cbirrt"))
+	pathPlanner, err := newPathPlanner(ctx, pm.pc, psc, pm.logger)
 	if err != nil {
 		return nil, err
 	}
@@ -220,7 +224,9 @@ func (pm *planManager) generateWaypoints(ctx context.Context, start, goal refere
 ) ([]referenceframe.FrameSystemPoses, bool, error) {
 	_, span := trace.StartSpan(ctx, "generateWaypoints")
 	defer span.End()
-	if len(pm.request.Constraints.LinearConstraint) == 0 {
+	// cartesian planning follows the straight line to the goal as if there were a linear constraint
+	cartesian := pm.request.PlannerOptions.PlanningAlgorithm() == Cartesian
+	if len(pm.request.Constraints.LinearConstraint) == 0 && !cartesian {
 		return []referenceframe.FrameSystemPoses{goal}, true, nil
 	}
 
@@ -267,7 +273,20 @@ func (pm *planManager) generateWaypoints(ctx context.Context, start, goal refere
 		waypoints = append(waypoints, to)
 	}
 
-	return waypoints, tighestConstraint >= 10, nil
+	return waypoints, tighestConstraint >= 10 && !cartesian, nil
+}
+
+// pathPlanner finds paths between the start and goal configurations of rrtMaps.
+type pathPlanner interface {
+	rrtRunner(ctx context.Context, rrtMaps *rrtMaps) (*rrtSolution, error)
+}
+
+// newPathPlanner creates the pathPlanner for the planning algorithm in the planner options.
+func newPathPlanner(ctx context.Context, pc *planContext, psc *planSegmentContext, logger logging.Logger) (pathPlanner, error) {
+	if pc.planOpts.PlanningAlgorithm() == RRTStar {
+		return newRRTStarConnectMotionPlanner(ctx, pc, psc, logger.Sublogger("rrtstar"))
+	}
+	return newCBiRRTMotionPlanner(ctx, pc, psc, logger.Sublogger("cbirrt"))
 }
 
 type rrtMap map[*node]*node
@@ -44,8 +44,31 @@ const (
 	defaultIterBeforeRand = 50
 
 	defaultOptimalityMultiple = 3.0
+
+	// After RRT* finds its first solution, keep improving it for up to this many times as long as finding it took.
+	defaultTimeMultipleAfterFindingFirstSolution = 10
+)
+
+// PlanningAlgorithm is the name of an algorithm used to find paths between configurations.
+type PlanningAlgorithm string
+
+const (
+	// CBiRRT plans with the Constrained Bidirectional Rapidly-exploring Random Tree, the default.
+	CBiRRT PlanningAlgorithm = "cbirrt"
+	// RRTStar plans with RRT*-Connect, which keeps shortening the path it finds for a while after finding it.
+	// It does not support topological constraints.
+	RRTStar PlanningAlgorithm = "rrtstar"
+	// Cartesian moves the frames to their goal poses along straight lines, and fails if they can't.
+	Cartesian PlanningAlgorithm = "cartesian"
+	// UnspecifiedAlgorithm uses the default algorithm.
+	UnspecifiedAlgorithm PlanningAlgorithm = ""
 )
 
+// AlgorithmSettings selects the algorithm the planner uses.
+type AlgorithmSettings struct {
+	Algorithm PlanningAlgorithm `json:"algorithm"`
+}
+
 var defaultNumThreads = utils.MinInt(runtime.NumCPU()/2, 10)
 
 func init() {
@@ -72,6 +95,7 @@ func NewBasicPlannerOptions() *PlannerOptions {
 
 	opt.CollisionBufferMM = defaultCollisionBufferMM
 	opt.RandomSeed = defaultRandomSeed
+	opt.TimeMultipleAfterFindingFirstSolution = defaultTimeMultipleAfterFindingFirstSolution
 
 	return opt
 }
@@ -128,6 +152,12 @@ type PlannerOptions struct {
 
 	// Setting indicating that all mesh geometries should be converted into octrees.
 	MeshesAsOctrees bool `json:"meshes_as_octrees"`
+
+	// The algorithm used to find paths, cbirrt if unspecified.
+	PlanningAlgorithmSettings AlgorithmSettings `json:"planning_algorithm_settings"`
+
+	// Once RRT* has found a path, it keeps looking for shorter paths for this many times as long as it took to find it.
+	TimeMultipleAfterFindingFirstSolution int `json:"time_multiple_after_finding_first_solution"`
 }
 
 // NewPlannerOptionsFromExtra returns basic default settings updated by overridden parameters
@@ -148,6 +178,9 @@ func NewPlannerOptionsFromExtra(extra map[string]interface{}) (*PlannerOptions,
 	if opt.CollisionBufferMM < 0 {
 		return nil, errors.New("collision_buffer_mm can't be negative")
 	}
+	if err := opt.PlanningAlgorithm().validate(); err != nil {
+		return nil, err
+	}
 
 	return opt, nil
 }
@@ -185,6 +218,23 @@ func (p *PlannerOptions) SetMinScore(minScore float64) {
 	p.MinScore = minScore
 }
 
+// PlanningAlgorithm returns the algorithm the planner will use.
+func (p *PlannerOptions) PlanningAlgorithm() PlanningAlgorithm {
+	if p.PlanningAlgorithmSettings.Algorithm == UnspecifiedAlgorithm {
+		return CBiRRT
+	}
+	return p.PlanningAlgorithmSettings.Algorithm
+}
+
+func (alg PlanningAlgorithm) validate() error {
+	switch alg {
+	case CBiRRT, RRTStar, Cartesian, UnspecifiedAlgorithm:
+		return nil
+	default:
+		return fmt.Errorf("unknown planning algorithm %q", alg)
+	}
+}
+
 func (p *PlannerOptions) timeoutDuration() time.Duration {
 	return time.Duration(p.Timeout * float64(time.Second))
 }
@@ -532,3 +532,59 @@ func BenchmarkPlanningOnMeshes(b *testing.B) {
 		test.That(b, err, test.ShouldBeNil)
 	}
 }
+
+// BenchmarkPlanningAlgorithms compares the planning algorithms on the plan requests in data, reporting how far the
+// planned trajectories move in joint space and how often planning fails.
+func BenchmarkPlanningAlgorithms(b *testing.B) {
+	if IsTooSmallForCache() {
+		b.Skip()
+		return
+	}
+
+	// plan_request_sample.json only tests serialization and isn't a scene
+	scenes := []string{
+		"data/bad-sand-plan.json",
+		"data/orb-plan1.json",
+		"data/orb-plan2.json",
+		"data/pour-plan-bad.json",
+		"data/sanding-too-many-steps.json",
+		"data/spray-bad1.json",
+		"data/wine-adjust.json",
+		"data/wine-crazy-touch.json",
+		"data/wine-crazy-touch2.json",
+	}
+
+	for _, fp := range scenes {
+		for _, alg := range []PlanningAlgorithm{CBiRRT, RRTStar, Cartesian} {
+			b.Run(fmt.Sprintf("%s/%s", filepath.Base(fp), alg), func(b *testing.B) {
+				req, err := ReadRequestFromFile(fp)
+				test.That(b, err, test.ShouldBeNil)
+				if req.PlannerOptions == nil {
+					req.PlannerOptions = NewBasicPlannerOptions()
+				}
+				if alg == RRTStar && hasTopoConstraints(req.Constraints) {
+					b.Skip("rrt* does not support the constraints of this request")
+				}
+				req.PlannerOptions.PlanningAlgorithmSettings.Algorithm = alg
+				logger := newChattyMotionPlanTestLogger(b)
+
+				cost := 0.
+				failures := 0
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					req.PlannerOptions.RandomSeed = i
+					plan, _, err := PlanMotion(context.Background(), logger, req)
+					if err != nil {
+						failures++
+						continue
+					}
+					cost += plan.Trajectory().EvaluateCost(motionplan.FSConfigurationL2Distance)
+				}
+				if failures < b.N {
+					b.ReportMetric(cost/float64(b.N-failures), "joint-dist/op")
+				}
+				b.ReportMetric(float64(failures)/float64(b.N), "failures/op")
+			})
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package armplanning
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"go.viam.com/utils/trace"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// The maximum percent of a joints range of motion to move towards a sample when extending a tree.
+	rrtStarFrameStep = 0.1
+
+	// The number of neighbors considered when choosing the parent of a new node is this times the log of the size of the tree.
+	rrtStarNeighborFactor = 2 * math.E
+)
+
+// rrtStarConnectMotionPlanner is an object able to find short paths around obstacles to some goal for a given referenceframe.
+// It uses RRT*-Connect, Klemm et al 2015 https://ieeexplore.ieee.org/document/7418740
+// Like cBiRRT it grows trees from the start and the goals towards each other, but each new node is connected to whichever of
+// its neighbors gives it the cheapest path to the root of its tree, and neighbors are rewired through the new node when that
+// makes their paths cheaper. Once the trees have met it keeps growing them for a while, returning the cheapest connection.
+type rrtStarConnectMotionPlanner struct {
+	pc     *planContext
+	psc    *planSegmentContext
+	logger logging.Logger
+
+	qstep map[string][]float64
+}
+
+// newRRTStarConnectMotionPlanner creates a rrtStarConnectMotionPlanner object.
+func newRRTStarConnectMotionPlanner(ctx context.Context, pc *planContext, psc *planSegmentContext, logger logging.Logger,
+) (*rrtStarConnectMotionPlanner, error) {
+	_, span := trace.StartSpan(ctx, "newRRTStarConnectMotionPlanner")
+	defer span.End()
+	moving, _ := psc.motionChains.framesFilteredByMovingAndNonmoving()
+	qstep := map[string][]float64{}
+	for _, fName := range moving {
+		f := pc.fs.Frame(fName)
+		if f == nil || len(f.DoF()) == 0 {
+			continue
+		}
+		steps := make([]float64, 0, len(f.DoF()))
+		for _, lim := range f.DoF() {
+			_, _, jRange := lim.GoodLimits()
+			steps = append(steps, jRange*rrtStarFrameStep)
+		}
+		qstep[fName] = steps
+	}
+	if len(qstep) == 0 {
+		return nil, fmt.Errorf("no moving frames with degrees of freedom to plan for")
+	}
+	return &rrtStarConnectMotionPlanner{
+		pc:     pc,
+		psc:    psc,
+		logger: logger,
+		qstep:  qstep,
+	}, nil
+}
+
+func (mp *rrtStarConnectMotionPlanner) rrtRunner(
+	ctx context.Context,
+	rrtMaps *rrtMaps,
+) (*rrtSolution, error) {
+	ctx, span := trace.StartSpan(ctx, "rrtStarRunner")
+	defer span.End()
+
+	mp.logger.CDebugf(ctx, "starting rrt* with start map len %d and goal map len %d\n", len(rrtMaps.startMap), len(rrtMaps.goalMap))
+	if mp.pc.planOpts == nil {
+		return nil, errNoPlannerOptions
+	}
+
+	var seed *node
+	for sNode, parent := range rrtMaps.startMap {
+		if parent == nil {
+			seed = sNode
+			break
+		}
+	}
+	if seed == nil {
+		return &rrtSolution{maps: rrtMaps}, fmt.Errorf("rrt* needs a start node")
+	}
+
+	startTime := time.Now()
+	var firstSolution time.Duration
+	connections := []*nodePair{}
+
+	map1, map2 := rrtMaps.startMap, rrtMaps.goalMap
+	for i := 0; i < maxPlanIter; i++ {
+		if ctx.Err() != nil {
+			if len(connections) > 0 {
+				break
+			}
+			mp.logger.CDebugf(ctx, "RRT* timed out after %d iterations", i)
+			return &rrtSolution{maps: rrtMaps}, fmt.Errorf("rrt* timeout %w", ctx.Err())
+		}
+		if len(connections) > 0 &&
+			time.Since(startTime) > firstSolution*time.Duration(mp.pc.planOpts.TimeMultipleAfterFindingFirstSolution) {
+			break
+		}
+
+		target, err := mp.sample(seed)
+		if err != nil {
+			return &rrtSolution{maps: rrtMaps}, err
+		}
+		map1reached := mp.extend(ctx, map1, target)
+		if map1reached != nil {
+			// greedily extend the other tree towards the new node until it is reached or blocked
+			var map2reached *node
+			for j := 0; j < maxExtendIter; j++ {
+				next := mp.extend(ctx, map2, map1reached)
+				if next == nil || next == map2reached {
+					break
+				}
+				map2reached = next
+				if mp.distance(map1reached, map2reached) <= mp.pc.planOpts.InputIdentDist {
+					if len(connections) == 0 {
+						firstSolution = time.Since(startTime)
+						mp.logger.CDebugf(ctx, "RRT* found first solution after %d iterations in %v", i, firstSolution)
+					}
+					// connections are kept as pairs of the node in the start map and the node in the goal map
+					if _, ok := rrtMaps.startMap[map1reached]; ok {
+						connections = append(connections, &nodePair{map1reached, map2reached})
+					} else {
+						connections = append(connections, &nodePair{map2reached, map1reached})
+					}
+					break
+				}
+			}
+		}
+		map1, map2 = map2, map1
+	}
+	if len(connections) == 0 {
+		return &rrtSolution{maps: rrtMaps}, errPlannerFailed
+	}
+
+	// rewiring may have made a connection other than the latest the cheapest
+	var best *nodePair
+	bestCost := math.Inf(1)
+	for _, pair := range connections {
+		cost := pathCost(rrtMaps.startMap, pair.a, mp.distance) + pathCost(rrtMaps.goalMap, pair.b, mp.distance)
+		if cost < bestCost {
+			best, bestCost = pair, cost
+		}
+	}
+	mp.logger.CDebugf(ctx, "RRT* found %d solutions in %v, the cheapest costs %f", len(connections), time.Since(startTime), bestCost)
+	path := extractPath(rrtMaps.startMap, rrtMaps.goalMap, best, true)
+	return &rrtSolution{steps: path, maps: rrtMaps}, nil
+}
+
+// extend adds a node to the tree one step from its nearest node towards the target, parented to whichever of its neighbors
+// gives it the cheapest path to the root, and rewires the neighbors through it. It returns the new node, or nil if the step
+// from the nearest node doesn't meet constraints.
+func (mp *rrtStarConnectMotionPlanner) extend(ctx context.Context, tree rrtMap, target *node) *node {
+	ctx, span := trace.StartSpan(ctx, "rrtStarExtend")
+	defer span.End()
+
+	nearest := nearestNeighbor(target, tree, nodeConfigurationDistanceFunc)
+	newNode := newConfigurationNode(fixedStepInterpolation(nearest, target, mp.qstep))
+	if mp.distance(nearest, newNode) <= mp.pc.planOpts.InputIdentDist {
+		return nearest
+	}
+	if mp.psc.checkPath(ctx, nearest.inputs, newNode.inputs, true) != nil {
+		return nil
+	}
+
+	// choose the parent giving the cheapest path to the root
+	neighbors := kNearestNeighbors(newNode, tree, int(math.Ceil(rrtStarNeighborFactor*math.Log(float64(len(tree)+1)))))
+	parent := nearest
+	cost := pathCost(tree, nearest, mp.distance) + mp.distance(nearest, newNode)
+	for _, neighbor := range neighbors {
+		if neighbor == nearest {
+			continue
+		}
+		neighborCost := pathCost(tree, neighbor, mp.distance) + mp.distance(neighbor, newNode)
+		if neighborCost < cost && mp.psc.checkPath(ctx, neighbor.inputs, newNode.inputs, false) == nil {
+			parent, cost = neighbor, neighborCost
+		}
+	}
+	tree[newNode] = parent
+
+	// rewire neighbors through the new node when that is cheaper. A neighbor which is an ancestor of the new node is
+	// always cheaper already, so this never makes a cycle.
+	for _, neighbor := range neighbors {
+		if neighbor == parent || tree[neighbor] == nil {
+			continue
+		}
+		rewiredCost := cost + mp.distance(newNode, neighbor)
+		if rewiredCost < pathCost(tree, neighbor, mp.distance) &&
+			mp.psc.checkPath(ctx, newNode.inputs, neighbor.inputs, false) == nil {
+			tree[neighbor] = newNode
+		}
+	}
+	return newNode
+}
+
+// sample returns a random configuration of the moving frames, with the other frames as they are in the seed.
+func (mp *rrtStarConnectMotionPlanner) sample(seed *node) (*node, error) {
+	newInputs := referenceframe.NewLinearInputs()
+	for name, inputs := range seed.inputs.Items() {
+		if _, moving := mp.qstep[name]; !moving {
+			newInputs.Put(name, slices.Clone(inputs))
+			continue
+		}
+		newInputs.Put(name, referenceframe.RandomFrameInputs(mp.pc.fs.Frame(name), mp.pc.randseed))
+	}
+	return newConfigurationNode(newInputs), nil
+}
+
+func (mp *rrtStarConnectMotionPlanner) distance(a, b *node) float64 {
+	return mp.pc.configurationDistanceFunc(&motionplan.SegmentFS{StartConfiguration: a.inputs, EndConfiguration: b.inputs})
+}
+
+// pathCost returns the cost of the path from the root of the tree to the node.
+func pathCost(tree rrtMap, n *node, nodeDistanceFunc NodeDistanceMetric) float64 {
+	cost := 0.
+	for parent := tree[n]; parent != nil; n, parent = parent, tree[parent] {
+		cost += nodeDistanceFunc(n, parent)
+	}
+	return cost
+}
+
+// kNearestNeighbors returns the k nodes of the tree closest to the seed, closest first.
+func kNearestNeighbors(seed *node, tree rrtMap, k int) []*node {
+	type neighbor struct {
+		node *node
+		dist float64
+	}
+	neighbors := make([]neighbor, 0, len(tree))
+	for n := range tree {
+		neighbors = append(neighbors, neighbor{n, nodeConfigurationDistanceFunc(seed, n)})
+	}
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].dist < neighbors[j].dist })
+
+	nodes := make([]*node, 0, k)
+	for _, n := range neighbors[:min(k, len(neighbors))] {
+		nodes = append(nodes, n.node)
+	}
+	return nodes
+}
@@ -0,0 +1,87 @@
+package armplanning
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
+)
+
+func TestRRTStarConnect(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t).Sublogger("mp")
+	m, err := referenceframe.ParseModelJSONFile(rutils.ResolveFile("components/arm/fake/kinematics/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	fs := referenceframe.NewEmptyFrameSystem("")
+	test.That(t, fs.AddFrame(m, fs.World()), test.ShouldBeNil)
+
+	// the box is in the way of swinging the arm around its base
+	box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: -439, Y: -439, Z: 162}), r3.Vector{X: 100, Y: 100, Z: 100}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := referenceframe.NewWorldState(
+		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{box})},
+		nil,
+	)
+	test.That(t, err, test.ShouldBeNil)
+
+	start := referenceframe.FrameSystemInputs{m.Name(): home6}.ToLinearInputs()
+	goal := referenceframe.FrameSystemInputs{m.Name(): {math.Pi / 2, 0, 0, 0, 0, 0}}.ToLinearInputs()
+	goalPoses, err := goal.ComputePoses(fs)
+	test.That(t, err, test.ShouldBeNil)
+
+	opt := NewBasicPlannerOptions()
+	opt.PlanningAlgorithmSettings.Algorithm = RRTStar
+	request := &PlanRequest{
+		FrameSystem:    fs,
+		Goals:          []*PlanState{NewPlanState(nil, goal.ToFrameSystemInputs())},
+		StartState:     NewPlanState(nil, start.ToFrameSystemInputs()),
+		WorldState:     worldState,
+		PlannerOptions: opt,
+		Constraints:    &motionplan.Constraints{},
+	}
+	pc, err := newPlanContext(ctx, logger, request, &PlanMeta{})
+	test.That(t, err, test.ShouldBeNil)
+	psc, err := newPlanSegmentContext(ctx, pc, start, goalPoses)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, psc.checkPath(ctx, start, goal, true), test.ShouldNotBeNil)
+
+	planner, err := newPathPlanner(ctx, pc, psc, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, planner, test.ShouldHaveSameTypeAs, &rrtStarConnectMotionPlanner{})
+
+	maps := &rrtMaps{
+		startMap: rrtMap{&node{inputs: start}: nil},
+		goalMap:  rrtMap{&node{inputs: goal}: nil},
+		optNode:  &node{inputs: goal},
+	}
+	solution, err := planner.rrtRunner(ctx, maps)
+	test.That(t, err, test.ShouldBeNil)
+	steps := solution.steps
+	test.That(t, len(steps), test.ShouldBeGreaterThan, 2)
+	test.That(t, steps[0], test.ShouldEqual, start)
+	test.That(t, steps[len(steps)-1], test.ShouldEqual, goal)
+	for i := 1; i < len(steps); i++ {
+		test.That(t, psc.checkPath(ctx, steps[i-1], steps[i], true), test.ShouldBeNil)
+	}
+}
+
+func TestPathCost(t *testing.T) {
+	inputs := func(x float64) *node {
+		return &node{inputs: referenceframe.FrameSystemInputs{"a": {x}}.ToLinearInputs()}
+	}
+	root, a, b, c := inputs(0), inputs(1), inputs(3), inputs(2)
+	tree := rrtMap{root: nil, a: root, b: a, c: root}
+	test.That(t, pathCost(tree, root, nodeConfigurationDistanceFunc), test.ShouldEqual, 0)
+	test.That(t, pathCost(tree, b, nodeConfigurationDistanceFunc), test.ShouldAlmostEqual, 3)
+
+	test.That(t, kNearestNeighbors(inputs(2.2), tree, 2), test.ShouldResemble, []*node{c, b})
+	test.That(t, kNearestNeighbors(inputs(2.2), tree, 10), test.ShouldHaveLength, 4)
+}
@@ -492,6 +492,18 @@ func myCost(start, end []float64) float64 {
 	return cost
 }
 
+// coversLimits returns whether the good limits of every input are its full limits.
+func coversLimits(limits []referenceframe.Limit) bool {
+	for _, l := range limits {
+		//nolint: revive
+		min, max, _ := l.GoodLimits()
+		if min != l.Min || max != l.Max {
+			return false
+		}
+	}
+	return true
+}
+
 func (ssc *smartSeedCache) findSeedsForFrame(
 	frameName string,
 	start []referenceframe.Input,
@@ -509,7 +521,9 @@ func (ssc *smartSeedCache) findSeedsForFrame(
 	logger.Infof("findSeedsForFrame: %s goalPose: %v start: %v norm: %0.2f maxNorm: %0.2f",
 		frameName, goalPose, logging.FloatArrayFormat{"", start}, n, ssc.rawCache[frameName].maxNorm)
 
-	if n > ssc.rawCache[frameName].maxNorm {
+	// The cache only spans the good limits of each input, so a frame with wider limits, like a base driving around a map, may
+	// reach further than anything in it.
+	if n > ssc.rawCache[frameName].maxNorm && coversLimits(frame.DoF()) {
 		return nil, nil, &tooFarError{ssc.rawCache[frameName].maxNorm, n}
 	}
 
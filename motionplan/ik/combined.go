@@ -14,13 +14,13 @@ import (
 
 // CombinedIK defines the fields necessary to run a combined solver.
 type CombinedIK struct {
-	solvers []*NloptIK
+	solvers []Solver
 	logger  logging.Logger
 }
 
-// CreateCombinedIKSolver creates a combined parallel IK solver that operates on a frame with a number of nlopt solvers equal to the
-// nCPU passed in. Each will be given a different random seed. When asked to solve, all solvers will be run in parallel
-// and the first valid found solution will be returned.
+// CreateCombinedIKSolver creates a combined parallel IK solver that operates on a frame with a number of solvers equal to the
+// nCPU passed in. These are nlopt solvers, or pure Go ones on builds without cgo. Each will be given a different random
+// seed. When asked to solve, all solvers will be run in parallel and the first valid found solution will be returned.
 func CreateCombinedIKSolver(
 	logger logging.Logger,
 	nCPU int,
@@ -32,11 +32,11 @@ func CreateCombinedIKSolver(
 	}
 
 	for i := 1; i <= nCPU; i++ {
-		nloptSolver, err := CreateNloptSolver(logger, -1, true, true, maxTime)
+		solver, err := CreateSolver(logger, -1, true, true, maxTime)
 		if err != nil {
 			return nil, err
 		}
-		ik.solvers = append(ik.solvers, nloptSolver)
+		ik.solvers = append(ik.solvers, solver)
 	}
 	return ik, nil
 }
@@ -0,0 +1,429 @@
+package ik
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+)
+
+var errEmptyBounds = errors.New("cannot solve with empty bounds. Are you trying to move a static frame?")
+
+const (
+	nativeStepsPerIter = 4001
+	nativeMaxIter      = 5000
+
+	// Step used to compute the numerical gradient of the cost function.
+	nativeJump = 1e-6
+
+	// Damping applied to the least-squares step so that it stays bounded when the gradient vanishes.
+	defaultDamping = 1e-3
+
+	// Armijo sufficient decrease constant and the maximum number of times a step is halved during a line search.
+	armijoConstant    = 1e-4
+	maxLineSearchIter = 40
+
+	// Size of the initial Nelder-Mead simplex as a fraction of each joint's range, capped at maxSimplexStep.
+	simplexStepFraction = 0.05
+	maxSimplexStep      = 0.25
+)
+
+// NativeIK can solve IK problems in pure Go, and so is available on builds without cgo. Each seed is descended using numerical
+// gradients: the first step is a damped least-squares step which drives the cost towards zero, after which BFGS updates refine
+// the search direction. Should the descent stall short of the goal, a gradient-free Nelder-Mead simplex search continues from
+// the best configuration found and the descent is restarted from wherever it ends up.
+type NativeIK struct {
+	maxIterations int
+	logger        logging.Logger
+
+	// If exact is false, then the solver will emit partial solutions where it was not able to meet the goal criteria but still
+	// was able to improve upon the seed.
+	exact bool
+
+	// useRelTol specifies whether to terminate a descent when an iteration changes the cost by less than some proportion of it.
+	useRelTol bool
+
+	maxTime time.Duration
+}
+
+// CreateNativeSolver creates a NativeIK object that can perform gradient descent on functions. Its parameters match those of
+// CreateNloptSolver; if the iteration count is less than 1, it will be set to the default of 5000.
+func CreateNativeSolver(
+	logger logging.Logger,
+	iter int,
+	exact, useRelTol bool,
+	maxTime time.Duration,
+) (*NativeIK, error) {
+	if iter < 1 {
+		iter = nativeMaxIter
+	}
+	return &NativeIK{
+		maxIterations: iter,
+		logger:        logger,
+		exact:         exact,
+		useRelTol:     useRelTol,
+		maxTime:       maxTime,
+	}, nil
+}
+
+type nativeSeedState struct {
+	seed                   []float64
+	lowerBound, upperBound []float64
+
+	meta string
+
+	minFunc    CostFunc
+	useRelTol  bool
+	iterations *int
+	evals      int
+	logger     logging.Logger
+}
+
+// Solve runs the actual solver and sends any solutions found to the given channel.
+func (ik *NativeIK) Solve(ctx context.Context,
+	solutionChan chan<- *Solution,
+	seeds [][]float64,
+	limits [][]referenceframe.Limit,
+	minFunc CostFunc,
+	rseed int,
+) (int, []SeedSolveMetaData, error) {
+	if len(seeds) == 0 {
+		return 0, nil, fmt.Errorf("no seeds")
+	}
+
+	if len(seeds) != len(limits) {
+		return 0, nil, fmt.Errorf("need matching limits (%d) and seeds (%d) arrays", len(limits), len(seeds))
+	}
+
+	randSeed := rand.New(rand.NewSource(int64(rseed))) //nolint: gosec
+
+	iterations := 0
+	seedStates := make([]*nativeSeedState, 0, len(seeds))
+	meta := make([]SeedSolveMetaData, 0, len(seeds))
+	for i, s := range seeds {
+		ss := &nativeSeedState{
+			seed:       s,
+			meta:       fmt.Sprintf("s:%d", i),
+			minFunc:    minFunc,
+			useRelTol:  ik.useRelTol,
+			iterations: &iterations,
+			logger:     ik.logger,
+		}
+		ss.lowerBound, ss.upperBound = limitsToArrays(limits[i])
+		if len(ss.lowerBound) == 0 || len(ss.upperBound) == 0 {
+			return 0, nil, errEmptyBounds
+		}
+		seedStates = append(seedStates, ss)
+		meta = append(meta, SeedSolveMetaData{})
+	}
+
+	solutionsFound := 0
+	seedNumber := rseed // start randomly in the list
+
+	itStart := time.Now()
+	for (iterations < ik.maxIterations || (ik.maxIterations >= 10 && time.Since(itStart) < ik.maxTime)) && ctx.Err() == nil {
+		iterations++
+
+		seedNumberRanged := seedNumber % len(seedStates)
+		ss := seedStates[seedNumberRanged]
+		meta[seedNumberRanged].Attempts++
+
+		solutionRaw, result := ss.optimize(ctx)
+		ik.logger.Debugf("seed (%d) %v\n\t result: %0.2f res: %v",
+			seedNumberRanged, logging.FloatArrayFormat{"", ss.seed},
+			result, logging.FloatArrayFormat{"", solutionRaw})
+
+		if math.IsInf(result, 1) || math.IsNaN(result) {
+			meta[seedNumberRanged].Errors++
+		} else if result < defaultGoalThreshold || !ik.exact {
+			meta[seedNumberRanged].Valid++
+			solution := &Solution{
+				Configuration: solutionRaw,
+				Score:         result,
+				Exact:         result < defaultGoalThreshold,
+				Meta:          ss.meta,
+			}
+			select {
+			case <-ctx.Done():
+			case solutionChan <- solution:
+				solutionsFound++
+			}
+		}
+		ss.seed = generateRandomPositions(randSeed, ss.lowerBound, ss.upperBound)
+
+		seedNumber++
+	}
+
+	return solutionsFound, meta, nil
+}
+
+// optimize minimizes the cost function starting from the current seed, returning the best configuration found and its cost.
+func (ss *nativeSeedState) optimize(ctx context.Context) ([]float64, float64) {
+	ss.evals = 0
+	x := ss.clamp(append([]float64{}, ss.seed...))
+	x, f := ss.descend(ctx, x, ss.eval(ctx, x))
+	for f >= defaultGoalThreshold && !ss.exhausted(ctx) {
+		simplexX, simplexF := ss.simplex(ctx, x, f)
+		if f-simplexF < defaultGoalThreshold {
+			break
+		}
+		x, f = ss.descend(ctx, simplexX, simplexF)
+	}
+	return x, f
+}
+
+func (ss *nativeSeedState) eval(ctx context.Context, x []float64) float64 {
+	*ss.iterations++
+	ss.evals++
+	return ss.minFunc(ctx, x)
+}
+
+func (ss *nativeSeedState) exhausted(ctx context.Context) bool {
+	return ss.evals >= nativeStepsPerIter || ctx.Err() != nil
+}
+
+func (ss *nativeSeedState) clamp(x []float64) []float64 {
+	for i := range x {
+		x[i] = math.Max(ss.lowerBound[i], math.Min(ss.upperBound[i], x[i]))
+	}
+	return x
+}
+
+// converged reports whether a change in cost from prev to next is small enough to stop descending.
+func (ss *nativeSeedState) converged(prev, next float64) bool {
+	change := math.Abs(prev - next)
+	return change < defaultGoalThreshold || (ss.useRelTol && change < defaultGoalThreshold*math.Abs(next))
+}
+
+// gradient computes the gradient of the cost function at x by central differences, falling back to one-sided differences at
+// the bounds. Components which would push x further outside of its bounds are zeroed, projecting the gradient onto the
+// feasible region.
+func (ss *nativeSeedState) gradient(ctx context.Context, x []float64) []float64 {
+	grad := make([]float64, len(x))
+	for i, xi := range x {
+		hi := math.Min(xi+nativeJump, ss.upperBound[i])
+		lo := math.Max(xi-nativeJump, ss.lowerBound[i])
+		if hi <= lo {
+			continue
+		}
+		x[i] = hi
+		fHi := ss.eval(ctx, x)
+		x[i] = lo
+		fLo := ss.eval(ctx, x)
+		x[i] = xi
+		grad[i] = (fHi - fLo) / (hi - lo)
+		if (xi <= ss.lowerBound[i] && grad[i] > 0) || (xi >= ss.upperBound[i] && grad[i] < 0) {
+			grad[i] = 0
+		}
+	}
+	return grad
+}
+
+// descend runs a projected quasi-Newton descent from x, whose cost is f. Whenever there is no curvature estimate to rely on,
+// the step taken is the damped least-squares step which would zero the cost were it linear along the gradient.
+func (ss *nativeSeedState) descend(ctx context.Context, x []float64, f float64) ([]float64, float64) {
+	if math.IsInf(f, 1) || math.IsNaN(f) {
+		return x, f
+	}
+	n := len(x)
+	grad := ss.gradient(ctx, x)
+	var invHessian [][]float64
+
+	for f >= defaultGoalThreshold && !ss.exhausted(ctx) {
+		dir := make([]float64, n)
+		step := 1.
+		if invHessian == nil {
+			gradNorm2 := dot(grad, grad)
+			if gradNorm2 == 0 {
+				break
+			}
+			for i := range dir {
+				dir[i] = -grad[i]
+			}
+			step = 2 * f / (gradNorm2 + 4*f*defaultDamping*defaultDamping)
+		} else {
+			for i := range dir {
+				for j := range grad {
+					dir[i] -= invHessian[i][j] * grad[j]
+				}
+			}
+		}
+		slope := dot(grad, dir)
+		if slope >= 0 {
+			// The curvature estimate no longer points downhill, so start again from the least-squares step.
+			if invHessian == nil {
+				break
+			}
+			invHessian = nil
+			continue
+		}
+
+		var next []float64
+		nextF := math.Inf(1)
+		for range maxLineSearchIter {
+			next = make([]float64, n)
+			for i := range next {
+				next[i] = x[i] + step*dir[i]
+			}
+			ss.clamp(next)
+			nextF = ss.eval(ctx, next)
+			if nextF <= f+armijoConstant*step*slope || ss.exhausted(ctx) {
+				break
+			}
+			step /= 2
+		}
+		if !(nextF < f) {
+			if invHessian == nil {
+				break
+			}
+			invHessian = nil
+			continue
+		}
+
+		s := make([]float64, n)
+		maxStep := 0.
+		for i := range s {
+			s[i] = next[i] - x[i]
+			maxStep = math.Max(maxStep, math.Abs(s[i]))
+		}
+		done := ss.converged(f, nextF) || maxStep < defaultGoalThreshold
+		x, f = next, nextF
+		if done || f < defaultGoalThreshold {
+			break
+		}
+
+		nextGrad := ss.gradient(ctx, x)
+		y := make([]float64, n)
+		for i := range y {
+			y[i] = nextGrad[i] - grad[i]
+		}
+		grad = nextGrad
+		invHessian = updateInverseHessian(invHessian, s, y)
+	}
+	return x, f
+}
+
+// updateInverseHessian applies the BFGS update to an inverse Hessian estimate given a step s and the change in gradient y over
+// that step. A nil estimate is initialized to a scaled identity. Updates which would not keep the estimate positive definite
+// are skipped.
+func updateInverseHessian(invHessian [][]float64, s, y []float64) [][]float64 {
+	sy := dot(s, y)
+	if sy <= 1e-12 {
+		return invHessian
+	}
+	n := len(s)
+	if invHessian == nil {
+		scale := sy / dot(y, y)
+		invHessian = make([][]float64, n)
+		for i := range invHessian {
+			invHessian[i] = make([]float64, n)
+			invHessian[i][i] = scale
+		}
+	}
+	rho := 1 / sy
+	hy := make([]float64, n)
+	for i := range hy {
+		hy[i] = dot(invHessian[i], y)
+	}
+	yhy := dot(y, hy)
+	for i := range invHessian {
+		for j := range invHessian[i] {
+			invHessian[i][j] += rho * ((1+rho*yhy)*s[i]*s[j] - hy[i]*s[j] - s[i]*hy[j])
+		}
+	}
+	return invHessian
+}
+
+// simplex runs a bounded Nelder-Mead search starting from x, whose cost is f, and returns the best vertex found.
+func (ss *nativeSeedState) simplex(ctx context.Context, x []float64, f float64) ([]float64, float64) {
+	n := len(x)
+	vertices := make([][]float64, n+1)
+	costs := make([]float64, n+1)
+	vertices[0], costs[0] = append([]float64{}, x...), f
+	for i := range n {
+		v := append([]float64{}, x...)
+		limit := referenceframe.Limit{Min: ss.lowerBound[i], Max: ss.upperBound[i]}
+		lo, hi, r := limit.GoodLimits()
+		step := math.Min(r*simplexStepFraction, maxSimplexStep)
+		if v[i]+step > hi && v[i]-step >= lo {
+			step = -step
+		}
+		v[i] += step
+		vertices[i+1] = ss.clamp(v)
+		costs[i+1] = ss.eval(ctx, vertices[i+1])
+	}
+
+	// point returns the clamped point along the line from the centroid through the worst vertex, scaled by coef.
+	point := func(centroid []float64, coef float64) ([]float64, float64) {
+		p := make([]float64, n)
+		for i := range p {
+			p[i] = centroid[i] + coef*(vertices[n][i]-centroid[i])
+		}
+		ss.clamp(p)
+		return p, ss.eval(ctx, p)
+	}
+
+	for !ss.exhausted(ctx) {
+		sortSimplex(vertices, costs)
+		if costs[0] < defaultGoalThreshold || costs[n]-costs[0] < defaultGoalThreshold {
+			break
+		}
+
+		centroid := make([]float64, n)
+		for _, v := range vertices[:n] {
+			for i := range centroid {
+				centroid[i] += v[i] / float64(n)
+			}
+		}
+
+		reflected, reflectedF := point(centroid, -1)
+		switch {
+		case reflectedF < costs[0]:
+			if expanded, expandedF := point(centroid, -2); expandedF < reflectedF {
+				vertices[n], costs[n] = expanded, expandedF
+			} else {
+				vertices[n], costs[n] = reflected, reflectedF
+			}
+		case reflectedF < costs[n-1]:
+			vertices[n], costs[n] = reflected, reflectedF
+		default:
+			if contracted, contractedF := point(centroid, 0.5); contractedF < costs[n] {
+				vertices[n], costs[n] = contracted, contractedF
+				continue
+			}
+			// Shrink every vertex towards the best one.
+			for i := 1; i <= n; i++ {
+				for j := range vertices[i] {
+					vertices[i][j] = vertices[0][j] + (vertices[i][j]-vertices[0][j])/2
+				}
+				costs[i] = ss.eval(ctx, vertices[i])
+			}
+		}
+	}
+	sortSimplex(vertices, costs)
+	return vertices[0], costs[0]
+}
+
+// sortSimplex orders the vertices of a simplex from lowest to highest cost.
+func sortSimplex(vertices [][]float64, costs []float64) {
+	for i := 1; i < len(costs); i++ {
+		for j := i; j > 0 && costs[j] < costs[j-1]; j-- {
+			costs[j], costs[j-1] = costs[j-1], costs[j]
+			vertices[j], vertices[j-1] = vertices[j-1], vertices[j]
+		}
+	}
+}
+
+func dot(a, b []float64) float64 {
+	total := 0.
+	for i := range a {
+		total += a[i] * b[i]
+	}
+	return total
+}
@@ -0,0 +1,66 @@
+package ik
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+func TestCreateNativeSolver(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m, err := referenceframe.ParseModelJSONFile(utils.ResolveFile("components/arm/fake/kinematics/xarm6.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	// matches xarm home end effector position
+	pos := spatialmath.NewPoseFromPoint(r3.Vector{X: 207, Z: 112})
+	seed := []float64{1, 1, -1, 1, 1, 0}
+	solveFunc := NewMetricMinFunc(motionplan.NewScaledSquaredNormMetric(pos, 10), m, logger)
+
+	t.Run("not exact", func(t *testing.T) {
+		ik, err := CreateNativeSolver(logger, -1, false, true, time.Second)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, _, err = DoSolve(context.Background(), ik, solveFunc, [][]float64{seed}, [][]referenceframe.Limit{m.DoF()})
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("exact", func(t *testing.T) {
+		ik, err := CreateNativeSolver(logger, 1, true, true, time.Second)
+		test.That(t, err, test.ShouldBeNil)
+
+		solutions, meta, err := DoSolve(context.Background(), ik, solveFunc, [][]float64{seed}, [][]referenceframe.Limit{m.DoF()})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, meta[0].Valid, test.ShouldEqual, 1)
+		test.That(t, solveFunc(context.Background(), solutions[0]), test.ShouldBeLessThan, defaultGoalThreshold)
+	})
+
+	t.Run("respects limits", func(t *testing.T) {
+		ik, err := CreateNativeSolver(logger, 1, false, true, time.Second)
+		test.That(t, err, test.ShouldBeNil)
+
+		limits := ComputeAdjustLimits(seed, m.DoF(), .05)
+		solutions, _, err := DoSolve(context.Background(), ik, solveFunc, [][]float64{seed}, [][]referenceframe.Limit{limits})
+		test.That(t, err, test.ShouldBeNil)
+		for i, v := range solutions[0] {
+			test.That(t, limits[i].IsValid(v), test.ShouldBeTrue)
+		}
+		test.That(t, solveFunc(context.Background(), solutions[0]), test.ShouldBeLessThan, solveFunc(context.Background(), seed))
+	})
+
+	t.Run("empty bounds", func(t *testing.T) {
+		ik, err := CreateNativeSolver(logger, 1, true, true, time.Second)
+		test.That(t, err, test.ShouldBeNil)
+
+		_, _, err = ik.Solve(context.Background(), make(chan *Solution, 1), [][]float64{{}}, [][]referenceframe.Limit{{}}, solveFunc, 1)
+		test.That(t, err, test.ShouldBeError, errEmptyBounds)
+	})
+}
@@ -74,6 +74,17 @@ func CreateNloptSolver(
 	return ik, nil
 }
 
+// CreateSolver creates the gradient descent solver best suited to this build, which is nlopt when built with cgo. Its
+// parameters are those of CreateNloptSolver.
+func CreateSolver(
+	logger logging.Logger,
+	iter int,
+	exact, useRelTol bool,
+	maxTime time.Duration,
+) (Solver, error) {
+	return CreateNloptSolver(logger, iter, exact, useRelTol, maxTime)
+}
+
 type nloptSeedState struct {
 	seed                   []float64
 	lowerBound, upperBound []float64
@@ -7,11 +7,22 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
-	"go.viam.com/rdk/logging"
 
+	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
 )
 
+// CreateSolver creates the gradient descent solver best suited to this build, which is the pure Go NativeIK when built
+// without cgo. Its parameters are those of CreateNloptSolver.
+func CreateSolver(
+	logger logging.Logger,
+	iter int,
+	exact, useRelTol bool,
+	maxTime time.Duration,
+) (Solver, error) {
+	return CreateNativeSolver(logger, iter, exact, useRelTol, maxTime)
+}
+
 // CreateNloptSolver is not supported on no_cgo builds.
 func CreateNloptSolver(
 	logger logging.Logger,
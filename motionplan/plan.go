@@ -38,10 +38,18 @@ type Plan interface {
 	Trajectory() Trajectory
 }
 
+// TimedPlan is a Plan which also knows when each waypoint of its Trajectory is reached. Its TimedTrajectory
+// is nil if the Trajectory couldn't be timed.
+type TimedPlan interface {
+	Plan
+	TimedTrajectory() TimedTrajectory
+}
+
 // SimplePlan is a simple implementation of Plan.
 type SimplePlan struct {
-	path Path
-	traj Trajectory
+	path  Path
+	traj  Trajectory
+	timed TimedTrajectory
 }
 
 // NewSimplePlan instantiates a new Plan from a Path and Trajectory.
@@ -111,6 +119,16 @@ func (plan *SimplePlan) Trajectory() Trajectory {
 	return plan.traj
 }
 
+// TimedTrajectory returns the TimedTrajectory associated with the Plan, if it has been timed.
+func (plan *SimplePlan) TimedTrajectory() TimedTrajectory {
+	return plan.timed
+}
+
+// SetTimedTrajectory sets the TimedTrajectory of the Plan, which must be a timing of its Trajectory.
+func (plan *SimplePlan) SetTimedTrajectory(timed TimedTrajectory) {
+	plan.timed = timed
+}
+
 // GetFramePoses returns a slice of poses a given frame should visit in the course of the Path.
 func (path Path) GetFramePoses(frameName string) ([]spatialmath.Pose, error) {
 	poses := []spatialmath.Pose{}
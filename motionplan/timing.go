@@ -0,0 +1,232 @@
+package motionplan
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// minSegmentDuration is the shortest time given to move between two waypoints, so waypoints which
+	// are the same still have distinct times.
+	minSegmentDuration = time.Millisecond
+	// maxParameterizationIterations bounds how many times the waypoints are checked against the limits.
+	maxParameterizationIterations = 1000
+)
+
+// errNoDynamicLimits is returned when none of the inputs which move in a trajectory have dynamic limits.
+var errNoDynamicLimits = errors.New("none of the moving inputs of the trajectory have dynamic limits")
+
+// TimedWaypoint is a waypoint of a Trajectory with the time it is reached at, measured from the start of
+// the Trajectory, and the velocity of each input when reaching it, in the units of the input per second.
+type TimedWaypoint struct {
+	Time       time.Duration
+	Inputs     referenceframe.FrameSystemInputs
+	Velocities map[string][]float64
+}
+
+// TimedTrajectory is a Trajectory with the time and velocities of each of its waypoints.
+type TimedTrajectory []TimedWaypoint
+
+// GetFrameTiming is a helper function which will extract the times and velocities of a single frame from a timed trajectory.
+func (traj TimedTrajectory) GetFrameTiming(frameName string) ([]time.Duration, [][]float64, error) {
+	times := make([]time.Duration, 0, len(traj))
+	velocities := make([][]float64, 0, len(traj))
+	for _, step := range traj {
+		frameVelocities, ok := step.Velocities[frameName]
+		if !ok {
+			return nil, nil, fmt.Errorf("frame named %s not found in trajectory", frameName)
+		}
+		times = append(times, step.Time)
+		velocities = append(velocities, frameVelocities)
+	}
+	return times, velocities, nil
+}
+
+// Duration returns how long it takes to follow the timed trajectory.
+func (traj TimedTrajectory) Duration() time.Duration {
+	if len(traj) == 0 {
+		return 0
+	}
+	return traj[len(traj)-1].Time
+}
+
+// ParameterizeTrajectory times a Trajectory such that it moves between its waypoints as fast as the dynamic
+// limits of the inputs allow. limits holds the dynamic limits of the inputs of each frame, in the order of the
+// inputs; inputs without limits aren't constrained. The trajectory starts and ends at rest.
+//
+// Every segment between two waypoints starts as short as the velocity limits allow. The velocity at each
+// waypoint is the average of the velocities of the segments around it, or zero where an input changes
+// direction, and the acceleration and jerk are estimated from the differences of those velocities. Segments
+// around a waypoint exceeding a limit are lengthened until none does, in the manner of iterative parabolic
+// time parameterization.
+func ParameterizeTrajectory(traj Trajectory, limits map[string][]referenceframe.DynamicLimit) (TimedTrajectory, error) {
+	if len(traj) == 0 {
+		return TimedTrajectory{}, nil
+	}
+
+	// flatten the inputs of the frames into one vector per waypoint
+	frames := make([]string, 0, len(traj[0]))
+	for frame := range traj[0] {
+		frames = append(frames, frame)
+	}
+	sort.Strings(frames)
+	var dimLimits []referenceframe.DynamicLimit
+	for _, frame := range frames {
+		frameLimits := limits[frame]
+		for i := range traj[0][frame] {
+			var limit referenceframe.DynamicLimit
+			if i < len(frameLimits) {
+				limit = frameLimits[i]
+			}
+			dimLimits = append(dimLimits, limit)
+		}
+	}
+	points := make([][]float64, 0, len(traj))
+	for idx, step := range traj {
+		point := make([]float64, 0, len(dimLimits))
+		for _, frame := range frames {
+			inputs, ok := step[frame]
+			if !ok || len(inputs) != len(traj[0][frame]) {
+				return nil, fmt.Errorf("inputs of frame %s in waypoint %d don't match the first waypoint", frame, idx)
+			}
+			point = append(point, inputs...)
+		}
+		points = append(points, point)
+	}
+
+	deltas := make([][]float64, len(points)-1)
+	durations := make([]float64, len(points)-1)
+	constrained := false
+	for i := range deltas {
+		deltas[i] = make([]float64, len(dimLimits))
+		durations[i] = minSegmentDuration.Seconds()
+		for j, limit := range dimLimits {
+			deltas[i][j] = points[i+1][j] - points[i][j]
+			if deltas[i][j] == 0 || limit == (referenceframe.DynamicLimit{}) {
+				continue
+			}
+			constrained = true
+			if limit.Velocity > 0 {
+				durations[i] = math.Max(durations[i], math.Abs(deltas[i][j])/limit.Velocity)
+			}
+		}
+	}
+	if len(deltas) > 0 && !constrained {
+		return nil, errNoDynamicLimits
+	}
+
+	converged := len(deltas) == 0
+	for iter := 0; iter < maxParameterizationIterations && !converged; iter++ {
+		converged = true
+		accelerations := waypointAccelerations(deltas, durations)
+		for k, acc := range accelerations {
+			scale := 1.0
+			for j, limit := range dimLimits {
+				if limit.Acceleration > 0 && math.Abs(acc[j]) > limit.Acceleration {
+					scale = math.Max(scale, math.Sqrt(math.Abs(acc[j])/limit.Acceleration))
+				}
+			}
+			if scale > 1 {
+				converged = false
+				// acceleration shrinks with the square of the duration of the segments around the waypoint
+				if k > 0 {
+					durations[k-1] *= scale
+				}
+				if k < len(durations) {
+					durations[k] *= scale
+				}
+			}
+		}
+		if !converged {
+			continue
+		}
+		for i := range durations {
+			scale := 1.0
+			for j, limit := range dimLimits {
+				jerk := (accelerations[i+1][j] - accelerations[i][j]) / durations[i]
+				if limit.Jerk > 0 && math.Abs(jerk) > limit.Jerk {
+					scale = math.Max(scale, math.Cbrt(math.Abs(jerk)/limit.Jerk))
+				}
+			}
+			if scale > 1 {
+				converged = false
+				// jerk shrinks with the cube of the duration of the segment
+				durations[i] *= scale
+			}
+		}
+	}
+	if !converged {
+		return nil, fmt.Errorf("trajectory timing did not converge in %d iterations", maxParameterizationIterations)
+	}
+
+	velocities := waypointVelocities(deltas, durations, len(dimLimits))
+	timed := make(TimedTrajectory, 0, len(traj))
+	var elapsed float64
+	for idx, step := range traj {
+		if idx > 0 {
+			elapsed += durations[idx-1]
+		}
+		stepVelocities := make(map[string][]float64, len(frames))
+		offset := 0
+		for _, frame := range frames {
+			dof := len(step[frame])
+			stepVelocities[frame] = velocities[idx][offset : offset+dof]
+			offset += dof
+		}
+		timed = append(timed, TimedWaypoint{
+			Time:       time.Duration(elapsed * float64(time.Second)),
+			Inputs:     step,
+			Velocities: stepVelocities,
+		})
+	}
+	return timed, nil
+}
+
+// waypointVelocities returns the velocity of each input at each waypoint. It is the average of the
+// velocities of the segments around the waypoint, or zero at the ends and where an input stops or
+// changes direction.
+func waypointVelocities(deltas [][]float64, durations []float64, dims int) [][]float64 {
+	velocities := make([][]float64, len(deltas)+1)
+	for k := range velocities {
+		velocities[k] = make([]float64, dims)
+		if k == 0 || k == len(deltas) {
+			continue
+		}
+		for j := range velocities[k] {
+			before := deltas[k-1][j] / durations[k-1]
+			after := deltas[k][j] / durations[k]
+			if before*after > 0 {
+				velocities[k][j] = (before + after) / 2
+			}
+		}
+	}
+	return velocities
+}
+
+// waypointAccelerations estimates the acceleration of each input at each waypoint from the change between
+// the velocities of the segments around it. The trajectory starts and ends at rest.
+func waypointAccelerations(deltas [][]float64, durations []float64) [][]float64 {
+	accelerations := make([][]float64, len(deltas)+1)
+	for k := range accelerations {
+		accelerations[k] = make([]float64, len(deltas[0]))
+		for j := range accelerations[k] {
+			switch k {
+			case 0:
+				accelerations[k][j] = 2 * deltas[0][j] / (durations[0] * durations[0])
+			case len(deltas):
+				last := len(deltas) - 1
+				accelerations[k][j] = -2 * deltas[last][j] / (durations[last] * durations[last])
+			default:
+				before := deltas[k-1][j] / durations[k-1]
+				after := deltas[k][j] / durations[k]
+				accelerations[k][j] = 2 * (after - before) / (durations[k-1] + durations[k])
+			}
+		}
+	}
+	return accelerations
+}
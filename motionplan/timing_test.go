@@ -0,0 +1,73 @@
+package motionplan
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+func TestParameterizeTrajectory(t *testing.T) {
+	traj := Trajectory{}
+	for i := 0; i <= 20; i++ {
+		x := float64(i) / 10
+		traj = append(traj, referenceframe.FrameSystemInputs{
+			"arm":     {x, -2 * x},
+			"gripper": {},
+		})
+	}
+	limits := map[string][]referenceframe.DynamicLimit{
+		"arm": {
+			{Velocity: 1, Acceleration: 2, Jerk: 20},
+			{Velocity: 1, Acceleration: 2},
+		},
+	}
+	timed, err := ParameterizeTrajectory(traj, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, timed, test.ShouldHaveLength, len(traj))
+	test.That(t, timed[0].Time, test.ShouldEqual, 0)
+
+	// the second joint moves twice as far and limits the velocity
+	test.That(t, timed.Duration(), test.ShouldBeGreaterThanOrEqualTo, 4*time.Second)
+	times, velocities, err := timed.GetFrameTiming("arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, velocities[0], test.ShouldResemble, []float64{0, 0})
+	test.That(t, velocities[len(velocities)-1], test.ShouldResemble, []float64{0, 0})
+	for i := 1; i < len(times); i++ {
+		test.That(t, times[i], test.ShouldBeGreaterThan, times[i-1])
+		for j := range velocities[i] {
+			test.That(t, math.Abs(velocities[i][j]), test.ShouldBeLessThanOrEqualTo, limits["arm"][j].Velocity+1e-9)
+		}
+		// the inputs keep their signs and the joints finish together
+		test.That(t, velocities[i][0], test.ShouldBeGreaterThanOrEqualTo, 0)
+		test.That(t, velocities[i][1], test.ShouldBeLessThanOrEqualTo, 0)
+	}
+	// starting from rest is limited by the acceleration of the second joint
+	firstSegment := (times[1] - times[0]).Seconds()
+	test.That(t, 2*0.2/(firstSegment*firstSegment), test.ShouldBeLessThanOrEqualTo, 2+1e-6)
+
+	// cruising in the middle of the trajectory is at the velocity limit
+	test.That(t, velocities[10][1], test.ShouldAlmostEqual, -1, 1e-6)
+
+	// a reversal stops the joint at the waypoint
+	back := append(Trajectory{}, traj...)
+	back = append(back, referenceframe.FrameSystemInputs{"arm": {1.9, -3.8}, "gripper": {}})
+	timed, err = ParameterizeTrajectory(back, limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, timed[20].Velocities["arm"], test.ShouldResemble, []float64{0, 0})
+
+	// a single waypoint is reached immediately
+	timed, err = ParameterizeTrajectory(traj[:1], limits)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, timed.Duration(), test.ShouldEqual, 0)
+
+	_, err = ParameterizeTrajectory(traj, nil)
+	test.That(t, err, test.ShouldBeError, errNoDynamicLimits)
+
+	traj[3] = referenceframe.FrameSystemInputs{"arm": {0.3}}
+	_, err = ParameterizeTrajectory(traj, limits)
+	test.That(t, err, test.ShouldNotBeNil)
+}
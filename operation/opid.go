@@ -3,11 +3,16 @@ package operation
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/session"
@@ -23,18 +28,50 @@ var methodPrefixesToFilter = [...]string{
 	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
 }
 
+// historyMethodPrefixesToSkip are the prefixes of the names of read only methods, which are not kept
+// in the operation history so frequent polling doesn't push out the operations which changed something.
+var historyMethodPrefixesToSkip = [...]string{"Get", "Is", "Stream"}
+
+// historyMethodsToSkip are methods clients call all the time to keep their connection going, which
+// are not kept in the operation history either. Log is among them so that audit logging doesn't
+// trigger more logging.
+var historyMethodsToSkip = [...]string{
+	"/viam.robot.v1.RobotService/SendSessionHeartbeat",
+	"/viam.robot.v1.RobotService/Log",
+	"/viam.robot.v1.RobotService/SendTraces",
+}
+
+const (
+	// defaultHistorySize is the number of completed operations a Manager keeps.
+	defaultHistorySize = 1000
+	// maxHistoryArgumentsSize is the largest encoded size of a request message kept as the
+	// arguments of an operation in the history.
+	maxHistoryArgumentsSize = 4 * 1024
+)
+
+// TruncatedArguments stands in for the arguments of an operation in the history when its request
+// message was too large to keep.
+type TruncatedArguments struct {
+	Type string `json:"type"`
+	Size int    `json:"size"`
+}
+
 // Operation is an operation happening on the server.
 type Operation struct {
 	ID        uuid.UUID
 	SessionID uuid.UUID
+	// Peer is the address of the client which started the operation, if it came over the network.
+	Peer      string
 	Method    string
 	Arguments interface{}
 	Started   time.Time
 
-	myManager  *Manager
-	cancel     context.CancelFunc
-	labelsLock sync.Mutex
-	labels     []string
+	myManager   *Manager
+	ctx         context.Context
+	cancel      context.CancelFunc
+	cancelCause context.CancelCauseFunc
+	labelsLock  sync.Mutex
+	labels      []string
 }
 
 // Cancel cancel the context associated with an operation.
@@ -42,6 +79,12 @@ func (o *Operation) Cancel() {
 	o.cancel()
 }
 
+// CancelWithReason cancels the context associated with an operation and records why in the
+// operation history.
+func (o *Operation) CancelWithReason(reason string) {
+	o.cancelCause(errors.New(reason))
+}
+
 // HasLabel returns true if this operation has a specific label.
 func (o *Operation) HasLabel(label string) bool {
 	o.labelsLock.Lock()
@@ -62,7 +105,7 @@ func (o *Operation) CancelOtherWithLabel(label string) {
 			continue
 		}
 		if op.HasLabel(label) {
-			op.Cancel()
+			op.CancelWithReason("superseded by operation " + o.ID.String() + " (" + o.Method + ")")
 		}
 	}
 
@@ -72,13 +115,114 @@ func (o *Operation) CancelOtherWithLabel(label string) {
 }
 
 func (o *Operation) cleanup() {
+	o.finish(nil)
+}
+
+// finish removes the operation from its manager and records it in the history with the error
+// it returned. It's a no-op on a nil operation.
+func (o *Operation) finish(err error) {
+	if o == nil {
+		return
+	}
+	completed := CompletedOperation{
+		ID:        o.ID,
+		SessionID: o.SessionID,
+		Peer:      o.Peer,
+		Method:    o.Method,
+		Arguments: historyArguments(o.Arguments),
+		Started:   o.Started,
+		Ended:     time.Now(),
+		Err:       err,
+	}
+	if o.ctx.Err() != nil {
+		completed.CancelReason = context.Cause(o.ctx).Error()
+	}
 	o.myManager.remove(o.ID)
+	o.myManager.record(completed)
+}
+
+// historyArguments returns the arguments to keep in the history for an operation, replacing
+// request messages larger than maxHistoryArgumentsSize with their type and size.
+func historyArguments(args interface{}) interface{} {
+	msg, ok := args.(proto.Message)
+	if !ok || msg == nil {
+		return args
+	}
+	if size := proto.Size(msg); size > maxHistoryArgumentsSize {
+		return TruncatedArguments{Type: string(msg.ProtoReflect().Descriptor().FullName()), Size: size}
+	}
+	return args
+}
+
+// CompletedOperation is an operation which has finished, as kept in the history of a Manager.
+type CompletedOperation struct {
+	ID        uuid.UUID
+	SessionID uuid.UUID
+	Peer      string
+	Method    string
+	Arguments interface{}
+	Started   time.Time
+	Ended     time.Time
+	// Err is the error the operation returned, nil if it succeeded.
+	Err error
+	// CancelReason is why the operation was cancelled, empty if it wasn't.
+	CancelReason string
+}
+
+// MarshalJSON encodes the operation with its error and cancel reason as strings and its
+// arguments in their protobuf JSON encoding if they are a request message.
+func (c CompletedOperation) MarshalJSON() ([]byte, error) {
+	var args json.RawMessage
+	var err error
+	switch a := c.Arguments.(type) {
+	case nil:
+	case proto.Message:
+		args, err = protojson.Marshal(a)
+	default:
+		args, err = json.Marshal(a)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var errStr string
+	if c.Err != nil {
+		errStr = c.Err.Error()
+	}
+	completed := struct {
+		ID           string          `json:"id"`
+		SessionID    string          `json:"session_id,omitempty"`
+		Peer         string          `json:"peer,omitempty"`
+		Method       string          `json:"method"`
+		Arguments    json.RawMessage `json:"arguments,omitempty"`
+		Started      time.Time       `json:"started"`
+		Ended        time.Time       `json:"ended"`
+		Error        string          `json:"error,omitempty"`
+		CancelReason string          `json:"cancel_reason,omitempty"`
+	}{
+		ID:           c.ID.String(),
+		Peer:         c.Peer,
+		Method:       c.Method,
+		Arguments:    args,
+		Started:      c.Started,
+		Ended:        c.Ended,
+		Error:        errStr,
+		CancelReason: c.CancelReason,
+	}
+	if c.SessionID != uuid.Nil {
+		completed.SessionID = c.SessionID.String()
+	}
+	return json.Marshal(completed)
 }
 
 // NewManager creates a new manager for holding Operations.
 func NewManager(logger logging.Logger) *Manager {
 	opLogger := logger.Sublogger("operation_manager")
-	return &Manager{ops: map[string]*Operation{}, logger: opLogger}
+	return &Manager{
+		ops:         map[string]*Operation{},
+		logger:      opLogger,
+		auditLogger: opLogger.Sublogger("audit"),
+		historySize: defaultHistorySize,
+	}
 }
 
 // Manager holds Operations.
@@ -86,6 +230,28 @@ type Manager struct {
 	ops    map[string]*Operation
 	lock   sync.Mutex
 	logger logging.Logger
+
+	// history holds the most recent completed operations, oldest first.
+	history     []CompletedOperation
+	historySize int
+	auditLog    bool
+	auditLogger logging.Logger
+}
+
+// SetAuditLog sets whether completed operations are logged, so they are written through the
+// logging appenders as well as kept in the history.
+func (m *Manager) SetAuditLog(enabled bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.auditLog = enabled
+}
+
+// History returns the most recently completed operations, oldest first. Read only methods, such
+// as Get* and Is*, and session heartbeats and logs are not kept.
+func (m *Manager) History() []CompletedOperation {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]CompletedOperation{}, m.history...)
 }
 
 func (m *Manager) remove(id uuid.UUID) {
@@ -94,6 +260,59 @@ func (m *Manager) remove(id uuid.UUID) {
 	delete(m.ops, id.String())
 }
 
+func (m *Manager) record(completed CompletedOperation) {
+	if skipInHistory(completed.Method) {
+		return
+	}
+	m.lock.Lock()
+	m.history = append(m.history, completed)
+	if len(m.history) > m.historySize {
+		m.history = m.history[len(m.history)-m.historySize:]
+	}
+	auditLog := m.auditLog
+	m.lock.Unlock()
+
+	if !auditLog {
+		return
+	}
+	fields := []interface{}{
+		"id", completed.ID.String(),
+		"method", completed.Method,
+		"started", completed.Started,
+		"duration", completed.Ended.Sub(completed.Started).String(),
+	}
+	if completed.SessionID != uuid.Nil {
+		fields = append(fields, "session_id", completed.SessionID.String())
+	}
+	if completed.Peer != "" {
+		fields = append(fields, "peer", completed.Peer)
+	}
+	if completed.Err != nil {
+		fields = append(fields, "error", completed.Err.Error())
+	}
+	if completed.CancelReason != "" {
+		fields = append(fields, "cancel_reason", completed.CancelReason)
+	}
+	m.auditLogger.Infow("Operation completed", fields...)
+}
+
+// skipInHistory returns whether the gRPC method, e.g. /viam.component.arm.v1.ArmService/GetEndPosition,
+// only reads or is one of historyMethodsToSkip.
+func skipInHistory(method string) bool {
+	for _, skip := range historyMethodsToSkip {
+		if method == skip {
+			return true
+		}
+	}
+	name := method[strings.LastIndex(method, "/")+1:]
+	for _, prefix := range historyMethodPrefixesToSkip {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (m *Manager) add(op *Operation) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -131,13 +350,20 @@ func (m *Manager) Create(ctx context.Context, method string, args interface{}) (
 }
 
 func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string, args interface{}) (context.Context, func()) {
+	ctx, op := m.create(ctx, id, method, args)
+	return ctx, func() { op.cleanup() }
+}
+
+// create puts an operation on this context and returns it, or nil if no new operation was created
+// because the method is filtered or the operation already exists.
+func (m *Manager) create(ctx context.Context, id uuid.UUID, method string, args interface{}) (context.Context, *Operation) {
 	if ctx.Value(opidKey) != nil {
 		panic("operations cannot be nested")
 	}
 
 	for _, val := range methodPrefixesToFilter {
 		if strings.HasPrefix(method, val) {
-			return ctx, func() {}
+			return ctx, nil
 		}
 	}
 
@@ -158,7 +384,7 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 			method,
 		)
 		ctx = context.WithValue(ctx, opidKey, o)
-		return ctx, func() {}
+		return ctx, nil
 	}
 
 	op := &Operation{
@@ -171,11 +397,16 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 	if sess, ok := session.FromContext(ctx); ok {
 		op.SessionID = sess.ID()
 	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		op.Peer = p.Addr.String()
+	}
 	ctx = context.WithValue(ctx, opidKey, op)
-	ctx, op.cancel = context.WithCancel(ctx)
+	ctx, op.cancelCause = context.WithCancelCause(ctx)
+	op.cancel = func() { op.cancelCause(nil) }
+	op.ctx = ctx
 	m.add(op)
 
-	return ctx, func() { op.cleanup() }
+	return ctx, op
 }
 
 // Get returns the current Operation. This can be nil.
@@ -115,3 +115,59 @@ func TestCreateDuplicate(t *testing.T) {
 	cleanup()
 	test.That(t, op3Ctx.Err(), test.ShouldBeError, context.Canceled)
 }
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+	logger, logs := logging.NewObservedTestLogger(t)
+	manager := NewManager(logger)
+
+	sess := session.New(ctx, "someone", 0, nil)
+	opCtx, cleanup := manager.Create(session.ToContext(ctx, sess), "/viam.component.arm.v1.ArmService/MoveToPosition", nil)
+	op := Get(opCtx)
+	cleanup()
+
+	// read only methods and high frequency connection upkeep aren't kept
+	_, cleanup = manager.Create(ctx, "/viam.component.arm.v1.ArmService/GetEndPosition", nil)
+	cleanup()
+	_, cleanup = manager.Create(ctx, "/viam.robot.v1.RobotService/SendSessionHeartbeat", nil)
+	cleanup()
+	_, cleanup = manager.Create(ctx, "/viam.robot.v1.RobotService/Log", nil)
+	cleanup()
+
+	opCtx2, cleanup2 := manager.Create(ctx, "a", nil)
+	opCtx3, cleanup3 := manager.Create(ctx, "b", nil)
+	CancelOtherWithLabel(opCtx2, "arm")
+	CancelOtherWithLabel(opCtx3, "arm")
+	test.That(t, opCtx2.Err(), test.ShouldNotBeNil)
+	cleanup2()
+	Get(opCtx3).CancelWithReason("operator pressed stop")
+	cleanup3()
+
+	history := manager.History()
+	test.That(t, history, test.ShouldHaveLength, 3)
+	test.That(t, history[0].ID, test.ShouldEqual, op.ID)
+	test.That(t, history[0].SessionID, test.ShouldEqual, sess.ID())
+	test.That(t, history[0].Method, test.ShouldEqual, "/viam.component.arm.v1.ArmService/MoveToPosition")
+	test.That(t, history[0].Ended, test.ShouldHappenOnOrAfter, history[0].Started)
+	test.That(t, history[0].CancelReason, test.ShouldBeEmpty)
+	test.That(t, history[1].Method, test.ShouldEqual, "a")
+	test.That(t, history[1].CancelReason, test.ShouldEqual, "superseded by operation "+Get(opCtx3).ID.String()+" (b)")
+	test.That(t, history[2].CancelReason, test.ShouldEqual, "operator pressed stop")
+
+	// the history is bounded
+	manager.historySize = 2
+	_, cleanup = manager.Create(ctx, "c", nil)
+	cleanup()
+	history = manager.History()
+	test.That(t, history, test.ShouldHaveLength, 2)
+	test.That(t, history[0].Method, test.ShouldEqual, "b")
+	test.That(t, history[1].Method, test.ShouldEqual, "c")
+
+	test.That(t, logs.FilterMessage("Operation completed").Len(), test.ShouldEqual, 0)
+	manager.SetAuditLog(true)
+	_, cleanup = manager.Create(ctx, "d", nil)
+	cleanup()
+	entries := logs.FilterMessage("Operation completed").All()
+	test.That(t, entries, test.ShouldHaveLength, 1)
+	test.That(t, entries[0].ContextMap()["method"], test.ShouldEqual, "d")
+}
@@ -52,9 +52,11 @@ func (m *Manager) UnaryServerInterceptor(
 	req interface{},
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
-) (interface{}, error) {
-	ctx, done := m.CreateFromIncomingContext(ctx, info.FullMethod)
-	defer done()
+) (resp interface{}, err error) {
+	ctx, created := m.createFromIncomingContext(ctx, info.FullMethod, req)
+	defer func() {
+		created.finish(err)
+	}()
 	if op := Get(ctx); op != nil && op.ID.String() != "" {
 		// SetHeader will occasionally error because of a data race if the request has been cancelled from client side.
 		// The cancel signal (RST_STREAM) is processed on a separate goroutine and will close the existing gRPC stream,
@@ -78,9 +80,11 @@ func (m *Manager) StreamServerInterceptor(
 	ss grpc.ServerStream,
 	info *grpc.StreamServerInfo,
 	handler grpc.StreamHandler,
-) error {
-	ctx, done := m.CreateFromIncomingContext(ss.Context(), info.FullMethod)
-	defer done()
+) (err error) {
+	ctx, created := m.createFromIncomingContext(ss.Context(), info.FullMethod, nil)
+	defer func() {
+		created.finish(err)
+	}()
 	if op := Get(ctx); op != nil && op.ID.String() != "" {
 		utils.UncheckedError(ss.SetHeader(metadata.MD{opidMetadataKey: []string{op.ID.String()}}))
 	}
@@ -89,17 +93,24 @@ func (m *Manager) StreamServerInterceptor(
 
 // CreateFromIncomingContext creates a new operation from an incoming context.
 func (m *Manager) CreateFromIncomingContext(ctx context.Context, method string) (context.Context, func()) {
+	ctx, op := m.createFromIncomingContext(ctx, method, nil)
+	return ctx, func() { op.cleanup() }
+}
+
+// createFromIncomingContext creates a new operation with the request message args from an
+// incoming context and returns it, or nil if no new operation was created.
+func (m *Manager) createFromIncomingContext(ctx context.Context, method string, args interface{}) (context.Context, *Operation) {
 	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		m.logger.CWarnw(ctx, "failed to pull metadata from context", "method", method)
-		return m.Create(ctx, method, nil)
+		return m.create(ctx, uuid.New(), method, args)
 	}
 	opid, err := GetOrCreateFromMetadata(meta)
 	if err != nil {
 		m.logger.CWarnw(ctx, "failed to create operation id from metadata", "error", err)
-		return m.Create(ctx, method, nil)
+		return m.create(ctx, uuid.New(), method, args)
 	}
-	return m.createWithID(ctx, opid, method, nil)
+	return m.create(ctx, opid, method, args)
 }
 
 // GetOrCreateFromMetadata returns an operation id from metadata, or generates a random
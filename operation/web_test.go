@@ -2,11 +2,19 @@ package operation
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
+	armpb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/test"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
 
 	"go.viam.com/rdk/logging"
 )
@@ -37,3 +45,46 @@ func TestCreateFromIncomingContextWithOpid(t *testing.T) {
 	test.That(t, ops, test.ShouldHaveLength, 1)
 	test.That(t, ops[0].ID.String(), test.ShouldEqual, opid.String())
 }
+
+func TestServerInterceptorRecordsOutcome(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m := NewManager(logger)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 5000}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.MD{})
+	errMove := errors.New("arm is in collision")
+	req := &armpb.MoveToPositionRequest{Name: "arm1"}
+	_, err := m.UnaryServerInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/MoveToPosition"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, errMove
+		})
+	test.That(t, err, test.ShouldEqual, errMove)
+
+	history := m.History()
+	test.That(t, history, test.ShouldHaveLength, 1)
+	test.That(t, history[0].Err, test.ShouldEqual, errMove)
+	test.That(t, history[0].Peer, test.ShouldEqual, "10.0.0.2:5000")
+	test.That(t, history[0].Arguments, test.ShouldEqual, req)
+	test.That(t, m.All(), test.ShouldHaveLength, 0)
+
+	md, err := json.Marshal(history[0])
+	test.That(t, err, test.ShouldBeNil)
+	var decoded map[string]interface{}
+	test.That(t, json.Unmarshal(md, &decoded), test.ShouldBeNil)
+	test.That(t, decoded["arguments"], test.ShouldResemble, map[string]interface{}{"name": "arm1"})
+	test.That(t, decoded["error"], test.ShouldEqual, errMove.Error())
+
+	// large request messages aren't kept whole
+	largeReq := &armpb.MoveToPositionRequest{Name: strings.Repeat("a", 5000)}
+	_, err = m.UnaryServerInterceptor(ctx, largeReq, &grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/MoveToPosition"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		})
+	test.That(t, err, test.ShouldBeNil)
+	history = m.History()
+	test.That(t, history, test.ShouldHaveLength, 2)
+	test.That(t, history[1].Arguments, test.ShouldResemble, TruncatedArguments{
+		Type: "viam.component.arm.v1.MoveToPositionRequest",
+		Size: proto.Size(largeReq),
+	})
+}
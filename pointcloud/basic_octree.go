@@ -348,9 +348,53 @@ func (octree *BasicOctree) DistanceFrom(geom spatialmath.Geometry) (float64, err
 	if collides {
 		return -1, nil
 	}
+	// The distance CollidesWith reports is only a lower bound, taken from the bounding boxes of the octree's nodes. Geometries
+	// which cannot measure their distance from a box keep that bound.
+	if exact, err := octree.distanceFrom(geom, math.Inf(1)); err == nil {
+		return exact, nil
+	}
 	return dist, nil
 }
 
+// distanceFrom returns the distance from the closest point of the octree at or above the confidence threshold to the given
+// geometry, or best if no point is closer. Nodes whose bounding box is no closer than best are skipped.
+func (octree *BasicOctree) distanceFrom(geom spatialmath.Geometry, best float64) (float64, error) {
+	if octree.MaxVal() < octree.confidenceThreshold {
+		return best, nil
+	}
+	switch octree.node.nodeType {
+	case internalNode:
+		box, err := spatialmath.NewBox(
+			spatialmath.NewPoseFromPoint(octree.center),
+			r3.Vector{X: octree.sideLength, Y: octree.sideLength, Z: octree.sideLength},
+			"",
+		)
+		if err != nil {
+			return best, err
+		}
+		bound, err := geom.DistanceFrom(box)
+		if err != nil {
+			return best, err
+		}
+		if bound >= best {
+			return best, nil
+		}
+		for _, child := range octree.node.children {
+			if best, err = child.distanceFrom(geom, best); err != nil {
+				return best, err
+			}
+		}
+	case leafNodeFilled:
+		dist, err := geom.DistanceFrom(spatialmath.NewPoint(octree.node.point.P, ""))
+		if err != nil {
+			return best, err
+		}
+		best = math.Min(best, dist)
+	case leafNodeEmpty:
+	}
+	return best, nil
+}
+
 // EncompassedBy returns true if the given octree is within the given geometry.
 // TODO (RSDK-3743): Implement BasicOctree Geometry functions.
 func (octree *BasicOctree) EncompassedBy(geom spatialmath.Geometry) (bool, error) {
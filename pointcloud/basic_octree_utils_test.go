@@ -368,3 +368,35 @@ func TestBasicOctreeCollision(t *testing.T) {
 		test.That(t, collides, test.ShouldBeFalse)
 	})
 }
+
+func TestBasicOctreeDistanceFrom(t *testing.T) {
+	basicOct := newBasicOctree(r3.Vector{}, 2000, defaultConfidenceThreshold)
+	for x := -1000.; x <= 1000; x += 50 {
+		test.That(t, basicOct.Set(r3.Vector{X: x, Y: 1000}, NewValueData(100)), test.ShouldBeNil)
+		test.That(t, basicOct.Set(r3.Vector{X: x, Y: -1000}, NewValueData(0)), test.ShouldBeNil)
+	}
+
+	t.Run("distance to the closest point", func(t *testing.T) {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 500}), r3.Vector{X: 100, Y: 100, Z: 100}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		dist, err := basicOct.DistanceFrom(box)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, 450)
+	})
+
+	t.Run("low-probability points are ignored", func(t *testing.T) {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: -900}), r3.Vector{X: 100, Y: 100, Z: 100}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		dist, err := basicOct.DistanceFrom(box)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldAlmostEqual, 1850)
+	})
+
+	t.Run("colliding geometries are at a negative distance", func(t *testing.T) {
+		box, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 1000}), r3.Vector{X: 100, Y: 100, Z: 100}, "box")
+		test.That(t, err, test.ShouldBeNil)
+		dist, err := basicOct.DistanceFrom(box)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dist, test.ShouldBeLessThan, 0)
+	})
+}
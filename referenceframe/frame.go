@@ -35,6 +35,14 @@ func (l *Limit) Range() float64 {
 	return l.Max - l.Min
 }
 
+// DynamicLimit represents how fast a degree of freedom may move, in the units of its input per second,
+// per second squared and per second cubed. A limit of zero is no limit.
+type DynamicLimit struct {
+	Velocity     float64
+	Acceleration float64
+	Jerk         float64
+}
+
 // Hash returns a hash value for this limit.
 func (l *Limit) Hash() int {
 	hash := 0
@@ -37,6 +37,9 @@ type JointConfig struct {
 	Max      float64                 `json:"max"`                // in mm or degs
 	Min      float64                 `json:"min"`                // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"` // only valid for prismatic/translational joints
+	MaxVel   float64                 `json:"max_vel,omitempty"`  // in mm or degs per second
+	MaxAcc   float64                 `json:"max_acc,omitempty"`  // in mm or degs per second squared
+	MaxJerk  float64                 `json:"max_jerk,omitempty"` // in mm or degs per second cubed
 }
 
 // DHParamConfig is a revolute and static frame combined in a set of Denavit Hartenberg parameters.
@@ -49,6 +52,9 @@ type DHParamConfig struct {
 	Max      float64                 `json:"max"` // in mm or degs
 	Min      float64                 `json:"min"` // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"`
+	MaxVel   float64                 `json:"max_vel,omitempty"`  // in degs per second
+	MaxAcc   float64                 `json:"max_acc,omitempty"`  // in degs per second squared
+	MaxJerk  float64                 `json:"max_jerk,omitempty"` // in degs per second cubed
 }
 
 // NewLinkConfig constructs a config from a Frame.
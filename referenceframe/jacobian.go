@@ -0,0 +1,203 @@
+package referenceframe
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+
+	"go.viam.com/rdk/spatialmath"
+)
+
+// singularValueTolerance is the size below which a singular value of a jacobian is treated as zero when inverting it.
+const singularValueTolerance = 1e-9
+
+// Jacobian computes the geometric jacobian of the model's end effector at the given inputs. The jacobian has six rows, the
+// first three mapping joint velocities to the linear velocity of the end effector in mm and the last three to its angular
+// velocity in radians, both expressed in the model's base frame. It has a column for each degree of freedom of the model.
+// Only models built from revolute and prismatic joints are supported.
+func (m *SimpleModel) Jacobian(inputs []Input) (*mat.Dense, error) {
+	if len(m.DoF()) != len(inputs) {
+		return nil, NewIncorrectDoFError(len(inputs), len(m.DoF()))
+	}
+	// Out of bounds inputs still have a well defined jacobian.
+	endEffector, err := m.Transform(inputs)
+	if endEffector == nil {
+		return nil, err
+	}
+	jacobian := mat.NewDense(6, len(inputs), nil)
+	if _, err := jacobianColumns(jacobian, 0, m, spatialmath.NewZeroPose(), inputs, endEffector.Point()); err != nil {
+		return nil, err
+	}
+	return jacobian, nil
+}
+
+// Jacobian computes the geometric jacobian of the named frame in the world frame at the given inputs. It has six rows, as
+// described for SimpleModel.Jacobian, and a column for each of the linearized inputs, in order. Columns belonging to
+// frames which do not move the named frame are zero.
+func (sfs *FrameSystem) Jacobian(inputs *LinearInputs, frameName string) (*mat.Dense, error) {
+	frame := sfs.Frame(frameName)
+	if frame == nil {
+		return nil, NewFrameMissingError(frameName)
+	}
+	chain, err := sfs.TracebackFrame(frame)
+	if err != nil {
+		return nil, err
+	}
+	endEffector, err := sfs.GetFrameToWorldTransform(inputs, frame)
+	if err != nil {
+		return nil, err
+	}
+	endEffectorPoint := (&spatialmath.DualQuaternion{Number: endEffector}).Point()
+
+	jacobian := mat.NewDense(6, len(inputs.GetLinearizedInputs()), nil)
+	// Walk from the world outwards so that the pose of each frame's parent is known when it is reached.
+	for i := len(chain) - 1; i >= 0; i-- {
+		f := chain[i]
+		if len(f.DoF()) == 0 {
+			continue
+		}
+		meta, ok := inputs.meta(f.Name())
+		if !ok || meta.dof != len(f.DoF()) {
+			return nil, NewIncorrectDoFError(meta.dof, len(f.DoF()))
+		}
+		parent, err := sfs.Parent(f)
+		if err != nil {
+			return nil, err
+		}
+		parentToWorld, err := sfs.GetFrameToWorldTransform(inputs, parent)
+		if err != nil {
+			return nil, err
+		}
+		frameInputs := inputs.inputs[meta.offset : meta.offset+meta.dof]
+		if _, err := jacobianColumns(
+			jacobian, meta.offset, f, &spatialmath.DualQuaternion{Number: parentToWorld}, frameInputs, endEffectorPoint,
+		); err != nil {
+			return nil, err
+		}
+	}
+	return jacobian, nil
+}
+
+// jacobianColumns fills in the columns of the jacobian belonging to frame f, starting at column col, given the pose of its
+// parent in the jacobian's reference frame and the position of the end effector in that frame. It returns the pose of f.
+func jacobianColumns(
+	jacobian *mat.Dense, col int, f Frame, parent spatialmath.Pose, inputs []Input, endEffector r3.Vector,
+) (spatialmath.Pose, error) {
+	if named, ok := f.(*namedFrame); ok {
+		f = named.Frame
+	}
+	setColumn := func(linear, angular r3.Vector) {
+		jacobian.SetCol(col, []float64{linear.X, linear.Y, linear.Z, angular.X, angular.Y, angular.Z})
+	}
+
+	switch frame := f.(type) {
+	case *rotationalFrame:
+		axis := rotateVector(parent.Orientation(), frame.rotAxis)
+		setColumn(axis.Cross(endEffector.Sub(parent.Point())), axis)
+	case *translationalFrame:
+		setColumn(rotateVector(parent.Orientation(), frame.transAxis), r3.Vector{})
+	case *SimpleModel:
+		posIdx := 0
+		for _, transform := range frame.ordTransforms {
+			dof := len(transform.DoF())
+			var err error
+			parent, err = jacobianColumns(jacobian, col+posIdx, transform, parent, inputs[posIdx:posIdx+dof], endEffector)
+			if err != nil {
+				return nil, err
+			}
+			posIdx += dof
+		}
+		return parent, nil
+	default:
+		if len(f.DoF()) != 0 {
+			return nil, fmt.Errorf("cannot compute the jacobian of frame %q of type %T, only revolute and prismatic joints are supported",
+				f.Name(), f)
+		}
+	}
+
+	pose, err := f.Transform(inputs)
+	if pose == nil {
+		return nil, err
+	}
+	return spatialmath.Compose(parent, pose), nil
+}
+
+// rotateVector rotates v by the given orientation.
+func rotateVector(o spatialmath.Orientation, v r3.Vector) r3.Vector {
+	q := o.Quaternion()
+	rotated := quat.Mul(quat.Mul(q, quat.Number{Imag: v.X, Jmag: v.Y, Kmag: v.Z}), quat.Conj(q))
+	return r3.Vector{X: rotated.Imag, Y: rotated.Jmag, Z: rotated.Kmag}
+}
+
+// Manipulability returns the Yoshikawa manipulability measure of a jacobian, the product of its singular values. This is
+// proportional to the volume of the ellipsoid of end effector velocities reachable with unit joint velocity, and goes to zero
+// as the jacobian approaches a singularity.
+func Manipulability(jacobian *mat.Dense) float64 {
+	values, ok := singularValues(jacobian)
+	if !ok {
+		return 0
+	}
+	manipulability := 1.
+	for _, v := range values {
+		manipulability *= v
+	}
+	return manipulability
+}
+
+// IsNearSingularity returns whether the smallest singular value of a jacobian is below the given threshold, meaning that
+// there is a direction in which the end effector can barely move however fast the joints do.
+func IsNearSingularity(jacobian *mat.Dense, threshold float64) bool {
+	values, ok := singularValues(jacobian)
+	if !ok || len(values) == 0 {
+		return true
+	}
+	return values[len(values)-1] < threshold
+}
+
+// JointVelocities maps a desired linear (mm/s) and angular (rad/s) velocity of the end effector to joint velocities using the
+// damped least-squares inverse of the jacobian. A damping of zero gives the pseudo-inverse, which is exact away from
+// singularities; larger dampings trade tracking accuracy for bounded joint velocities near them.
+func JointVelocities(jacobian *mat.Dense, linear, angular r3.Vector, damping float64) ([]float64, error) {
+	rows, cols := jacobian.Dims()
+	if rows != 6 {
+		return nil, fmt.Errorf("jacobian must have 6 rows, not %d", rows)
+	}
+	var svd mat.SVD
+	if !svd.Factorize(jacobian, mat.SVDThin) {
+		return nil, errors.New("failed to factorize jacobian")
+	}
+	var u, v mat.Dense
+	svd.UTo(&u)
+	svd.VTo(&v)
+	values := svd.Values(nil)
+
+	velocity := mat.NewVecDense(6, []float64{linear.X, linear.Y, linear.Z, angular.X, angular.Y, angular.Z})
+	jointVelocities := mat.NewVecDense(cols, nil)
+	for i, sigma := range values {
+		if sigma < singularValueTolerance {
+			continue
+		}
+		scale := sigma / (sigma*sigma + damping*damping) * mat.Dot(u.ColView(i), velocity)
+		jointVelocities.AddScaledVec(jointVelocities, scale, v.ColView(i))
+	}
+	return jointVelocities.RawVector().Data, nil
+}
+
+// singularValues returns the singular values of a jacobian in descending order.
+func singularValues(jacobian *mat.Dense) ([]float64, bool) {
+	var svd mat.SVD
+	if !svd.Factorize(jacobian, mat.SVDNone) {
+		return nil, false
+	}
+	values := svd.Values(nil)
+	for _, v := range values {
+		if math.IsNaN(v) {
+			return nil, false
+		}
+	}
+	return values, true
+}
@@ -0,0 +1,147 @@
+package referenceframe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// numericJacobian approximates the jacobian of the frame by central differences of its transform.
+func numericJacobian(t *testing.T, f Frame, inputs []Input) *mat.Dense {
+	t.Helper()
+	const h = 1e-6
+	jacobian := mat.NewDense(6, len(inputs), nil)
+	for i := range inputs {
+		jogged := append([]Input{}, inputs...)
+		jogged[i] = inputs[i] + h
+		hi, err := f.Transform(jogged)
+		test.That(t, err, test.ShouldBeNil)
+		jogged[i] = inputs[i] - h
+		lo, err := f.Transform(jogged)
+		test.That(t, err, test.ShouldBeNil)
+
+		linear := hi.Point().Sub(lo.Point()).Mul(1 / (2 * h))
+		// For small rotations the imaginary part of the quaternion is half the rotation vector.
+		between := spatial.OrientationBetween(lo.Orientation(), hi.Orientation()).Quaternion()
+		angular := r3.Vector{X: between.Imag, Y: between.Jmag, Z: between.Kmag}.Mul(1 / h)
+		jacobian.SetCol(i, []float64{linear.X, linear.Y, linear.Z, angular.X, angular.Y, angular.Z})
+	}
+	return jacobian
+}
+
+func TestModelJacobian(t *testing.T) {
+	for _, file := range []string{"ur5e.json", "xarm6.json", "xarm7.json"} {
+		t.Run(file, func(t *testing.T) {
+			m, err := ParseModelJSONFile(utils.ResolveFile("components/arm/fake/kinematics/"+file), "")
+			test.That(t, err, test.ShouldBeNil)
+			inputs := make([]Input, len(m.DoF()))
+			for i, limit := range m.DoF() {
+				inputs[i] = limit.Min + 0.1*float64(i+1)*limit.Range()
+			}
+
+			jacobian, err := m.(*SimpleModel).Jacobian(inputs)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, mat.EqualApprox(jacobian, numericJacobian(t, m, inputs), 1e-3), test.ShouldBeTrue)
+		})
+	}
+
+	m, err := ParseModelJSONFile(utils.ResolveFile("components/arm/fake/kinematics/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = m.(*SimpleModel).Jacobian([]Input{0})
+	test.That(t, err, test.ShouldBeError, NewIncorrectDoFError(1, 6))
+}
+
+func TestFrameSystemJacobian(t *testing.T) {
+	fs := NewEmptyFrameSystem("test")
+	gantry, err := NewTranslationalFrame("gantry", r3.Vector{X: 1}, Limit{Min: -1000, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(gantry, fs.World()), test.ShouldBeNil)
+	mount, err := NewStaticFrame("mount", spatial.NewPose(r3.Vector{Z: 100}, &spatial.OrientationVectorDegrees{OY: 1, Theta: 30}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(mount, gantry), test.ShouldBeNil)
+	arm, err := ParseModelJSONFile(utils.ResolveFile("components/arm/fake/kinematics/ur5e.json"), "arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(arm, mount), test.ShouldBeNil)
+	other, err := NewRotationalFrame("other", spatial.R4AA{RZ: 1}, Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(other, fs.World()), test.ShouldBeNil)
+
+	armInputs := []Input{0.3, 0.6, 0.9, 1.2, 1.5, 1.8}
+	inputs := NewLinearInputs()
+	inputs.Put("other", []Input{0.5})
+	inputs.Put("gantry", []Input{50})
+	inputs.Put("arm", armInputs)
+
+	jacobian, err := fs.Jacobian(inputs, "arm")
+	test.That(t, err, test.ShouldBeNil)
+	rows, cols := jacobian.Dims()
+	test.That(t, rows, test.ShouldEqual, 6)
+	test.That(t, cols, test.ShouldEqual, 8)
+
+	// The unrelated frame doesn't move the arm.
+	test.That(t, mat.Norm(jacobian.ColView(0), 2), test.ShouldEqual, 0)
+	// The gantry translates the arm along the world X axis.
+	test.That(t, mat.Col(nil, 1, jacobian), test.ShouldResemble, []float64{1, 0, 0, 0, 0, 0})
+
+	// The arm's columns are those of its own jacobian rotated into the world frame.
+	armJacobian, err := arm.(*SimpleModel).Jacobian(armInputs)
+	test.That(t, err, test.ShouldBeNil)
+	rotation := mount.(*staticFrame).transform.Orientation()
+	for i := range armInputs {
+		col := mat.Col(nil, i+2, jacobian)
+		armCol := mat.Col(nil, i, armJacobian)
+		linear := rotateVector(rotation, r3.Vector{X: armCol[0], Y: armCol[1], Z: armCol[2]})
+		angular := rotateVector(rotation, r3.Vector{X: armCol[3], Y: armCol[4], Z: armCol[5]})
+		test.That(t, spatial.R3VectorAlmostEqual(r3.Vector{X: col[0], Y: col[1], Z: col[2]}, linear, 1e-6), test.ShouldBeTrue)
+		test.That(t, spatial.R3VectorAlmostEqual(r3.Vector{X: col[3], Y: col[4], Z: col[5]}, angular, 1e-6), test.ShouldBeTrue)
+	}
+
+	_, err = fs.Jacobian(inputs, "missing")
+	test.That(t, err, test.ShouldBeError, NewFrameMissingError("missing"))
+}
+
+func TestJacobianSingularities(t *testing.T) {
+	m, err := ParseModelJSONFile(utils.ResolveFile("components/arm/fake/kinematics/ur5e.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	model := m.(*SimpleModel)
+
+	// Fully stretched out, the elbow is singular.
+	stretched, err := model.Jacobian([]Input{0, 0, 0, 0, 0, 0})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, Manipulability(stretched), test.ShouldBeLessThan, 1e-6)
+	test.That(t, IsNearSingularity(stretched, 1e-3), test.ShouldBeTrue)
+
+	inputs := []Input{0.3, -1, 1.5, -0.5, 1, 0}
+	bent, err := model.Jacobian(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, Manipulability(bent), test.ShouldBeGreaterThan, 1)
+	test.That(t, IsNearSingularity(bent, 1e-3), test.ShouldBeFalse)
+
+	t.Run("joint velocities", func(t *testing.T) {
+		linear := r3.Vector{X: 10, Y: -20, Z: 5}
+		angular := r3.Vector{Z: 0.1}
+		velocities, err := JointVelocities(bent, linear, angular, 0)
+		test.That(t, err, test.ShouldBeNil)
+
+		var achieved mat.VecDense
+		achieved.MulVec(bent, mat.NewVecDense(len(velocities), velocities))
+		test.That(t, mat.EqualApprox(&achieved, mat.NewVecDense(6, []float64{10, -20, 5, 0, 0, 0.1}), 1e-6), test.ShouldBeTrue)
+
+		// Damping keeps joint velocities bounded at singularities.
+		undamped, err := JointVelocities(stretched, r3.Vector{X: 10}, r3.Vector{}, 0)
+		test.That(t, err, test.ShouldBeNil)
+		damped, err := JointVelocities(stretched, r3.Vector{X: 10}, r3.Vector{}, 1)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, floats.Norm(damped, 2), test.ShouldBeLessThan, floats.Norm(undamped, 2))
+
+		_, err = JointVelocities(mat.NewDense(3, 6, nil), linear, angular, 0)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
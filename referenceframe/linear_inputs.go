@@ -212,6 +212,16 @@ func (li *LinearInputs) Get(frameName string) []Input {
 	return nil
 }
 
+// meta returns the schema entry for the given frame name.
+func (li *LinearInputs) meta(frameName string) (linearInputMeta, bool) {
+	for _, meta := range li.schema.metas {
+		if meta.frameName == frameName {
+			return meta, true
+		}
+	}
+	return linearInputMeta{}, false
+}
+
 // Keys returns an iterator over the keys. This is analogous to ranging over the keys of a map.
 func (li *LinearInputs) Keys() iter.Seq[string] {
 	return func(yield func(string) bool) {
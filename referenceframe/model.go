@@ -18,6 +18,7 @@ import (
 	"gonum.org/v1/gonum/num/quat"
 
 	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
 )
 
 // A Model represents a frame that can change its name, and can return itself as a ModelConfig struct.
@@ -27,6 +28,12 @@ type Model interface {
 	ModelPieceFrames([]Input) (map[string]Frame, error)
 }
 
+// A DynamicLimiter is a Model which knows how fast each of its degrees of freedom may move.
+type DynamicLimiter interface {
+	// DynamicLimits returns the dynamic limit of each degree of freedom, in the same order as DoF.
+	DynamicLimits() []DynamicLimit
+}
+
 // KinematicModelFromProtobuf returns a model from a protobuf message representing it.
 func KinematicModelFromProtobuf(name string, resp *commonpb.GetKinematicsResponse) (Model, error) {
 	if resp == nil {
@@ -157,6 +164,39 @@ func (m *SimpleModel) ModelConfig() *ModelConfigJSON {
 	return m.modelConfig
 }
 
+// DynamicLimits returns the dynamic limits of the joints in the config of the model. Models without
+// a config have no dynamic limits.
+func (m *SimpleModel) DynamicLimits() []DynamicLimit {
+	byJoint := map[string]DynamicLimit{}
+	if m.modelConfig != nil {
+		for _, joint := range m.modelConfig.Joints {
+			limit := DynamicLimit{Velocity: joint.MaxVel, Acceleration: joint.MaxAcc, Jerk: joint.MaxJerk}
+			if joint.Type != PrismaticJoint {
+				limit = DynamicLimit{
+					Velocity:     utils.DegToRad(limit.Velocity),
+					Acceleration: utils.DegToRad(limit.Acceleration),
+					Jerk:         utils.DegToRad(limit.Jerk),
+				}
+			}
+			byJoint[joint.ID] = limit
+		}
+		for _, dh := range m.modelConfig.DHParams {
+			byJoint[dh.ID+"_j"] = DynamicLimit{
+				Velocity:     utils.DegToRad(dh.MaxVel),
+				Acceleration: utils.DegToRad(dh.MaxAcc),
+				Jerk:         utils.DegToRad(dh.MaxJerk),
+			}
+		}
+	}
+	limits := make([]DynamicLimit, 0, len(m.DoF()))
+	for _, transform := range m.ordTransforms {
+		for range transform.DoF() {
+			limits = append(limits, byJoint[transform.Name()])
+		}
+	}
+	return limits
+}
+
 // Hash returns a hash value for this simple model.
 func (m *SimpleModel) Hash() int {
 	h := m.hash()
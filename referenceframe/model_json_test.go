@@ -2,6 +2,7 @@ package referenceframe
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
 
 	"go.viam.com/test"
@@ -95,3 +96,45 @@ func TestParseJSONFile(t *testing.T) {
 		})
 	}
 }
+
+func TestDynamicLimits(t *testing.T) {
+	jsonData := []byte(`{
+		"name": "slider",
+		"links": [
+			{"id": "base_link", "parent": "world"},
+			{"id": "tip", "parent": "spin", "translation": {"x": 100}}
+		],
+		"joints": [
+			{"id": "slide", "type": "prismatic", "parent": "base_link", "axis": {"x": 1}, "min": 0, "max": 500,
+				"max_vel": 200, "max_acc": 400},
+			{"id": "spin", "type": "revolute", "parent": "slide", "axis": {"z": 1}, "min": -180, "max": 180,
+				"max_vel": 180, "max_acc": 360, "max_jerk": 720}
+		]
+	}`)
+	model, err := UnmarshalModelJSON(jsonData, "")
+	test.That(t, err, test.ShouldBeNil)
+	limiter, ok := model.(DynamicLimiter)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, limiter.DynamicLimits(), test.ShouldResemble, []DynamicLimit{
+		{Velocity: 200, Acceleration: 400},
+		{Velocity: math.Pi, Acceleration: 2 * math.Pi, Jerk: 4 * math.Pi},
+	})
+
+	// the limits survive serialization of the model
+	data, err := json.Marshal(model)
+	test.That(t, err, test.ShouldBeNil)
+	deserialized := new(SimpleModel)
+	test.That(t, deserialized.UnmarshalJSON(data), test.ShouldBeNil)
+	test.That(t, deserialized.DynamicLimits(), test.ShouldResemble, limiter.DynamicLimits())
+
+	// urdf joint limits have a velocity
+	model, err = ParseModelXMLFile(utils.ResolveFile("referenceframe/testfiles/ur5e.urdf"), "")
+	test.That(t, err, test.ShouldBeNil)
+	limits := model.(DynamicLimiter).DynamicLimits()
+	test.That(t, limits, test.ShouldHaveLength, 6)
+	test.That(t, limits[0].Velocity, test.ShouldAlmostEqual, 3.141592)
+	test.That(t, limits[0].Acceleration, test.ShouldEqual, 0)
+
+	// models which aren't made from a config have none
+	test.That(t, NewSimpleModel("empty").DynamicLimits(), test.ShouldBeEmpty)
+}
@@ -319,6 +319,8 @@ type limit struct {
 	XMLName xml.Name `xml:"limit"`
 	Lower   float64  `xml:"lower,attr"` // translation limits are in meters, revolute limits are in radians
 	Upper   float64  `xml:"upper,attr"` // translation limits are in meters, revolute limits are in radians
+	// Velocity is in meters per second for translations and radians per second for revolutions
+	Velocity float64 `xml:"velocity,attr,omitempty"`
 }
 
 type axis struct {
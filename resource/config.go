@@ -330,10 +330,22 @@ func (conf *Config) validate(path, defaultAPIType string) ([]string, []string, e
 		if err != nil {
 			return nil, nil, err
 		}
+		if validator, ok := conf.ConvertedAttributes.(AssociatedConfigValidator); ok {
+			if err := validator.ValidateAssociated(path, conf.AssociatedAttributes); err != nil {
+				return nil, nil, err
+			}
+		}
 	}
 	return requiredDeps, optionalDeps, nil
 }
 
+// An AssociatedConfigValidator is a ConfigValidator that also validates the configs other
+// resources associated with it (e.g. the data capture configs of components associated with a
+// data manager), such as references from those configs back into its own config.
+type AssociatedConfigValidator interface {
+	ValidateAssociated(path string, associated map[Name]AssociatedConfig) error
+}
+
 // A ConfigValidator validates a configuration and also returns both required and optional
 // dependencies that were implicitly discovered.
 type ConfigValidator interface {
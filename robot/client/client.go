@@ -117,6 +117,8 @@ type RobotClient struct {
 	sessionsSupported        *bool // when nil, we have not yet checked
 	currentSessionID         string
 	sessionHeartbeatInterval time.Duration
+	leases                   map[resource.Name]struct{}
+	holdsLeases              atomic.Bool
 
 	heartbeatWorkers   sync.WaitGroup
 	heartbeatCtx       context.Context
@@ -295,6 +297,7 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 		resourceClients:     make(map[resource.Name]resource.Resource),
 		remoteNameMap:       make(map[resource.Name]resource.Name),
 		sessionsDisabled:    rOpts.disableSessions,
+		leases:              map[resource.Name]struct{}{},
 		heartbeatCtx:        heartbeatCtx,
 		heartbeatCtxCancel:  heartbeatCtxCancel,
 	}
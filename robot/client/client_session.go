@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -54,7 +55,8 @@ func (rc *RobotClient) heartbeatLoop() {
 			sendReq := &pb.SendSessionHeartbeatRequest{
 				Id: sessID,
 			}
-			if _, err := rc.client.SendSessionHeartbeat(rc.heartbeatCtx, sendReq); err != nil {
+			heartbeatCtx := context.WithValue(rc.heartbeatCtx, ctxKeyInSessionMDReq, true)
+			if _, err := rc.client.SendSessionHeartbeat(heartbeatCtx, sendReq); err != nil {
 				if s, ok := status.FromError(err); ok && s.Code() == codes.Unavailable {
 					rc.sessionReset()
 					return
@@ -84,10 +86,24 @@ func (rc *RobotClient) sessionMetadata(ctx context.Context, method string) (cont
 		return ctx, nil
 	}
 	ctx = context.WithValue(ctx, ctxKeyInSessionMDReq, true)
+	if err := rc.ensureSession(ctx); err != nil {
+		return nil, err
+	}
+	rc.sessionMu.RLock()
+	defer rc.sessionMu.RUnlock()
+	if rc.sessionsSupported == nil {
+		return ctx, nil
+	}
+	return rc.sessionMetadataInner(ctx), nil
+}
+
+// ensureSession starts a session with the robot, leasing the resources the client holds leases on,
+// if it has not checked yet whether the robot supports sessions.
+func (rc *RobotClient) ensureSession(ctx context.Context) error {
 	rc.sessionMu.RLock()
 	if rc.sessionsSupported != nil {
-		defer rc.sessionMu.RUnlock()
-		return rc.sessionMetadataInner(ctx), nil
+		rc.sessionMu.RUnlock()
+		return nil
 	}
 	rc.sessionMu.RUnlock()
 	// upgrade lock
@@ -96,11 +112,14 @@ func (rc *RobotClient) sessionMetadata(ctx context.Context, method string) (cont
 
 	// check one more time
 	if rc.sessionsSupported != nil {
-		return rc.sessionMetadataInner(ctx), nil
+		return nil
 	}
 
 	reqCtx, cancel := utils.MergeContext(ctx, rc.backgroundCtx)
 	defer cancel()
+	for resName := range rc.leases {
+		reqCtx = metadata.AppendToOutgoingContext(reqCtx, session.LeaseMetadataKey, resName.String())
+	}
 
 	var startReq pb.StartSessionRequest
 	if rc.currentSessionID != "" {
@@ -118,16 +137,16 @@ func (rc *RobotClient) sessionMetadata(ctx context.Context, method string) (cont
 			falseVal := false
 			rc.sessionsSupported = &falseVal
 			rc.logger.CInfow(ctx, "sessions unsupported; will not try again")
-			return ctx, nil
+			return nil
 		}
-		return nil, err
+		return err
 	}
 
 	heartbeatWindow := startResp.HeartbeatWindow.AsDuration()
 	sessionHeartbeatInterval := heartbeatWindow / 5
 	if heartbeatWindow <= 0 || sessionHeartbeatInterval <= 0 {
 		rc.logger.CInfow(ctx, "session heartbeat window invalid; will not try again", "heartbeat_window", heartbeatWindow)
-		return ctx, nil
+		return nil
 	}
 
 	trueVal := true
@@ -136,7 +155,60 @@ func (rc *RobotClient) sessionMetadata(ctx context.Context, method string) (cont
 	rc.sessionHeartbeatInterval = sessionHeartbeatInterval
 	rc.heartbeatLoop()
 
-	return rc.sessionMetadataInner(ctx), nil
+	return nil
+}
+
+// AcquireLeases leases the resources to the session of the client, giving it exclusive control of
+// them until the leases are released, the session ends or an administrator revokes them. Calls
+// from the client which actuate the resources carry the session from then on. If the session is
+// restarted, the leases are requested again.
+func (rc *RobotClient) AcquireLeases(ctx context.Context, resourceNames ...resource.Name) error {
+	if rc.sessionsDisabled {
+		return errors.New("sessions are disabled on this client")
+	}
+	// the session requests below must not go through session metadata themselves
+	ctx = context.WithValue(ctx, ctxKeyInSessionMDReq, true)
+	if err := rc.ensureSession(ctx); err != nil {
+		return err
+	}
+	rc.sessionMu.Lock()
+	defer rc.sessionMu.Unlock()
+	if rc.sessionsSupported == nil || !*rc.sessionsSupported {
+		return errors.New("the robot does not support sessions")
+	}
+	leaseCtx := ctx
+	for _, resName := range resourceNames {
+		leaseCtx = metadata.AppendToOutgoingContext(leaseCtx, session.LeaseMetadataKey, resName.String())
+	}
+	if _, err := rc.client.SendSessionHeartbeat(leaseCtx, &pb.SendSessionHeartbeatRequest{Id: rc.currentSessionID}); err != nil {
+		return err
+	}
+	for _, resName := range resourceNames {
+		rc.leases[resName] = struct{}{}
+	}
+	rc.holdsLeases.Store(len(rc.leases) > 0)
+	return nil
+}
+
+// ReleaseLeases releases the leases of the session of the client on the resources.
+func (rc *RobotClient) ReleaseLeases(ctx context.Context, resourceNames ...resource.Name) error {
+	ctx = context.WithValue(ctx, ctxKeyInSessionMDReq, true)
+	rc.sessionMu.Lock()
+	defer rc.sessionMu.Unlock()
+	for _, resName := range resourceNames {
+		delete(rc.leases, resName)
+	}
+	rc.holdsLeases.Store(len(rc.leases) > 0)
+	if rc.sessionsSupported == nil || !*rc.sessionsSupported {
+		// leases end with the session
+		return nil
+	}
+	releaseCtx := ctx
+	for _, resName := range resourceNames {
+		releaseCtx = metadata.AppendToOutgoingContext(releaseCtx, session.ReleaseLeaseMetadataKey, resName.String())
+	}
+	_, err := rc.client.SendSessionHeartbeat(releaseCtx, &pb.SendSessionHeartbeatRequest{Id: rc.currentSessionID})
+	return err
 }
 
 func (rc *RobotClient) safetyMonitorFromHeaders(ctx context.Context, hdr metadata.MD) {
@@ -154,7 +226,8 @@ func (rc *RobotClient) safetyMonitorFromHeaders(ctx context.Context, hdr metadat
 }
 
 func (rc *RobotClient) useSessionInRequest(ctx context.Context, method string) bool {
-	return !rc.sessionsDisabled && ctx.Value(ctxKeyInSessionMDReq) == nil && robot.IsSafetyHeartbeatMonitored(method)
+	return !rc.sessionsDisabled && ctx.Value(ctxKeyInSessionMDReq) == nil &&
+		(robot.IsSafetyHeartbeatMonitored(method) || rc.holdsLeases.Load())
 }
 
 func (rc *RobotClient) sessionUnaryClientInterceptor(
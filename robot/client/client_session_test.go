@@ -21,6 +21,7 @@ import (
 	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
@@ -827,3 +828,62 @@ func (srv *echoServer) EchoResourceMultiple(
 	session.SafetyMonitorResourceName(server.Context(), someTargetName2)
 	return nil
 }
+
+func TestClientSessionLeases(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	baseName := base.Named("base1")
+	injectRobot := &inject.Robot{
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI {
+			return []resource.RPCAPI{{API: base.API, Desc: resource.RegisteredAPIs()[base.API].ReflectRPCServiceDesc}}
+		},
+		MachineStatusFunc: func(_ context.Context) (robot.MachineStatus, error) {
+			return robot.MachineStatus{State: robot.StateRunning}, nil
+		},
+		LoggerFunc: func() logging.Logger { return logger },
+	}
+	injectBase := &inject.Base{
+		SetPowerFunc: func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+			return nil
+		},
+		StopFunc: func(ctx context.Context, extra map[string]interface{}) error {
+			return nil
+		},
+	}
+	injectRobot.MockResourcesFromMap(map[resource.Name]resource.Resource{baseName: injectBase})
+	sessMgr := robot.NewSessionManager(injectRobot, config.DefaultSessionHeartbeatWindow)
+	defer sessMgr.Close()
+	injectRobot.SessMgr = sessMgr
+
+	svc := web.New(injectRobot, logger)
+	defer svc.Stop()
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	newBaseClient := func() (*client.RobotClient, base.Base) {
+		roboClient, err := client.New(ctx, addr, logger)
+		test.That(t, err, test.ShouldBeNil)
+		res, err := roboClient.ResourceByName(baseName)
+		test.That(t, err, test.ShouldBeNil)
+		return roboClient, res.(base.Base)
+	}
+	holder, holderBase := newBaseClient()
+	defer holder.Close(ctx)
+	other, otherBase := newBaseClient()
+	defer other.Close(ctx)
+
+	test.That(t, holder.AcquireLeases(ctx, baseName), test.ShouldBeNil)
+	test.That(t, sessMgr.Leases(), test.ShouldHaveLength, 1)
+	test.That(t, holderBase.SetPower(ctx, r3.Vector{Y: 1}, r3.Vector{}, nil), test.ShouldBeNil)
+
+	err := otherBase.SetPower(ctx, r3.Vector{Y: 1}, r3.Vector{}, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+	test.That(t, other.AcquireLeases(ctx, baseName), test.ShouldNotBeNil)
+	// anyone can stop a leased resource
+	test.That(t, otherBase.Stop(ctx, nil), test.ShouldBeNil)
+
+	test.That(t, holder.ReleaseLeases(ctx, baseName), test.ShouldBeNil)
+	test.That(t, sessMgr.Leases(), test.ShouldBeEmpty)
+	test.That(t, otherBase.SetPower(ctx, r3.Vector{Y: 1}, r3.Vector{}, nil), test.ShouldBeNil)
+}
@@ -6,11 +6,13 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
 	"go.uber.org/zap/zapcore"
+	datasyncpb "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils"
 	"go.viam.com/utils/testutils"
@@ -35,6 +37,7 @@ import (
 	"go.viam.com/rdk/components/servo"
 	sw "go.viam.com/rdk/components/switch"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/ml"
 	"go.viam.com/rdk/referenceframe"
@@ -319,6 +322,343 @@ func TestJobManagerHistory(t *testing.T) {
 	})
 }
 
+func TestJobManagerRetriesDependenciesAndResults(t *testing.T) {
+	t.Parallel()
+	logger := logging.NewTestLogger(t)
+
+	model := resource.DefaultModelFamily.WithModel(utils.RandomAlphaString(8))
+	var calibrateCalls, afterCalls atomic.Int32
+	injectSensor := inject.NewSensor("sensor")
+	injectSensor.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		switch cmd["command"] {
+		case "calibrate":
+			// fails twice before succeeding
+			if calibrateCalls.Add(1) <= 2 {
+				return nil, errors.New("not ready")
+			}
+			return map[string]interface{}{"offset": 1.5}, nil
+		case "after":
+			afterCalls.Add(1)
+			return map[string]interface{}{}, nil
+		default:
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+	}
+	resource.RegisterComponent(
+		sensor.API,
+		model,
+		resource.Registration[sensor.Sensor, resource.NoNativeConfig]{Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (sensor.Sensor, error) {
+			return injectSensor, nil
+		}})
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Model: model,
+				Name:  "sensor",
+				API:   sensor.API,
+			},
+		},
+		Jobs: []config.JobConfig{
+			{
+				config.JobConfigData{
+					Name:         "calibrate",
+					Schedule:     "1s",
+					Resource:     "sensor",
+					Method:       "DoCommand",
+					Command:      map[string]any{"command": "calibrate"},
+					Retries:      2,
+					RetryBackoff: "10ms",
+				},
+			},
+			{
+				config.JobConfigData{
+					Name:      "after calibrate",
+					Resource:  "sensor",
+					Method:    "DoCommand",
+					Command:   map[string]any{"command": "after"},
+					DependsOn: []string{"calibrate"},
+				},
+			},
+			{
+				config.JobConfigData{
+					Name:     "hang",
+					Schedule: "1s",
+					Resource: "sensor",
+					Method:   "DoCommand",
+					Command:  map[string]any{"command": "hang"},
+					Timeout:  "50ms",
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	lr := setupLocalRobot(t, ctx, cfg, logger)
+
+	testutils.WaitForAssertionWithSleep(t, time.Second, 5, func(tb testing.TB) {
+		tb.Helper()
+		ms, err := lr.MachineStatus(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+
+		calibrate := ms.JobStatuses["calibrate"]
+		hang := ms.JobStatuses["hang"]
+		test.That(tb, len(calibrate.RecentResults), test.ShouldBeGreaterThan, 0)
+		test.That(tb, len(hang.RecentResults), test.ShouldBeGreaterThan, 0)
+		if tb.Failed() {
+			return
+		}
+		// the failed attempts are retried rather than recorded as failed runs
+		test.That(tb, len(calibrate.RecentFailedRuns), test.ShouldEqual, 0)
+		test.That(tb, calibrate.RecentResults[0].Attempts, test.ShouldEqual, 3)
+		test.That(tb, calibrate.RecentResults[0].Error, test.ShouldBeEmpty)
+		test.That(tb, calibrate.RecentResults[0].Response, test.ShouldResemble, map[string]any{"offset": 1.5})
+
+		after := ms.JobStatuses["after calibrate"]
+		test.That(tb, len(after.RecentSuccessfulRuns), test.ShouldBeGreaterThan, 0)
+		test.That(tb, afterCalls.Load(), test.ShouldBeGreaterThan, 0)
+
+		test.That(tb, len(hang.RecentFailedRuns), test.ShouldBeGreaterThan, 0)
+		test.That(tb, hang.RecentResults[0].Attempts, test.ShouldEqual, 1)
+		test.That(tb, hang.RecentResults[0].Error, test.ShouldContainSubstring, "deadline exceeded")
+	})
+}
+
+func TestJobManagerTriggers(t *testing.T) {
+	t.Parallel()
+	logger := logging.NewTestLogger(t)
+
+	model := resource.DefaultModelFamily.WithModel(utils.RandomAlphaString(8))
+	otherModel := resource.DefaultModelFamily.WithModel(utils.RandomAlphaString(8))
+	var temperature atomic.Int64
+	temperature.Store(20)
+	var readingsCalls atomic.Int64
+	var startupCalls, hotCalls, sealedCalls, markerCalls, reconfiguredCalls atomic.Int32
+	injectSensor := inject.NewSensor("sensor")
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		readingsCalls.Add(1)
+		return map[string]interface{}{"temp": map[string]interface{}{"celsius": temperature.Load()}}, nil
+	}
+	injectSensor.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		switch cmd["command"] {
+		case "startup":
+			startupCalls.Add(1)
+		case "hot":
+			hotCalls.Add(1)
+		case "sealed":
+			sealedCalls.Add(1)
+		case "marker":
+			markerCalls.Add(1)
+		case "reconfigured":
+			reconfiguredCalls.Add(1)
+		}
+		return map[string]interface{}{}, nil
+	}
+	for _, m := range []resource.Model{model, otherModel} {
+		resource.RegisterComponent(
+			sensor.API,
+			m,
+			resource.Registration[sensor.Sensor, resource.NoNativeConfig]{Constructor: func(
+				ctx context.Context,
+				deps resource.Dependencies,
+				conf resource.Config,
+				logger logging.Logger,
+			) (sensor.Sensor, error) {
+				return injectSensor, nil
+			}})
+	}
+
+	// capture files are only sealed by data managers, so a fake one seals them for the test
+	dataManager := &sealingDataManager{DataManagerService: inject.NewDataManagerService("sealing")}
+	resource.RegisterService(
+		datamanager.API,
+		model,
+		resource.Registration[datamanager.Service, resource.NoNativeConfig]{Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (datamanager.Service, error) {
+			return dataManager, nil
+		}})
+
+	sensorName := "triggers_" + utils.RandomAlphaString(8)
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Model: model,
+				Name:  sensorName,
+				API:   sensor.API,
+			},
+		},
+		Services: []resource.Config{
+			{
+				Model: model,
+				Name:  "sealing",
+				API:   datamanager.API,
+			},
+		},
+		Jobs: []config.JobConfig{
+			{
+				config.JobConfigData{
+					Name:     "on startup",
+					Resource: sensorName,
+					Method:   "DoCommand",
+					Command:  map[string]any{"command": "startup"},
+					Trigger:  &config.JobTriggerConfig{Type: config.JobTriggerStartup},
+				},
+			},
+			{
+				config.JobConfigData{
+					Name:     "on hot",
+					Resource: sensorName,
+					Method:   "DoCommand",
+					Command:  map[string]any{"command": "hot"},
+					Trigger: &config.JobTriggerConfig{
+						Type:         config.JobTriggerSensorReading,
+						Path:         "temp.celsius",
+						Operator:     ">",
+						Value:        50.0,
+						PollInterval: "50ms",
+					},
+				},
+			},
+			{
+				config.JobConfigData{
+					Name:     "on sealed",
+					Resource: sensorName,
+					Method:   "DoCommand",
+					Command:  map[string]any{"command": "sealed"},
+					Trigger:  &config.JobTriggerConfig{Type: config.JobTriggerCaptureFileSealed, Debounce: "1h"},
+				},
+			},
+			{
+				config.JobConfigData{
+					Name:     "on marker sealed",
+					Resource: sensorName,
+					Method:   "DoCommand",
+					Command:  map[string]any{"command": "marker"},
+					Trigger:  &config.JobTriggerConfig{Type: config.JobTriggerCaptureFileSealed, Resource: "marker"},
+				},
+			},
+			{
+				config.JobConfigData{
+					Name:     "on reconfigured",
+					Resource: sensorName,
+					Method:   "DoCommand",
+					Command:  map[string]any{"command": "reconfigured"},
+					Trigger:  &config.JobTriggerConfig{Type: config.JobTriggerResourceReconfigured},
+				},
+			},
+		},
+	}
+	ctx := context.Background()
+	lr := setupLocalRobot(t, ctx, cfg, logger)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, startupCalls.Load(), test.ShouldEqual, 1)
+	})
+
+	// waitForReadings waits until the trigger has polled the sensor a few more times
+	waitForReadings := func() {
+		t.Helper()
+		polled := readingsCalls.Load()
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, readingsCalls.Load(), test.ShouldBeGreaterThanOrEqualTo, polled+3)
+		})
+	}
+
+	// the job runs when the reading starts matching, not for as long as it matches
+	waitForReadings()
+	test.That(t, hotCalls.Load(), test.ShouldEqual, 0)
+	temperature.Store(60)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, hotCalls.Load(), test.ShouldEqual, 1)
+	})
+	waitForReadings()
+	test.That(t, hotCalls.Load(), test.ShouldEqual, 1)
+
+	// files captured from other components don't run the job, and the debounce drops the second
+	// file. Sealed files are handled in order, so once the marker file ran its job the others have
+	// been handled too.
+	sealedCh := dataManager.subscriber(t)
+	for _, component := range []string{"other", sensorName, sensorName, "marker"} {
+		sealedCh <- data.SealedCaptureFile{Metadata: &datasyncpb.DataCaptureMetadata{ComponentName: component}}
+	}
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, markerCalls.Load(), test.ShouldEqual, 1)
+		test.That(tb, sealedCalls.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+	})
+	test.That(t, sealedCalls.Load(), test.ShouldEqual, 1)
+
+	// the sensor has to be seen by the status watcher before it is reconfigured, otherwise it is
+	// treated as a new resource, so keep reconfiguring it until the trigger fires. Changing the
+	// startup job reschedules it, which doesn't run it again.
+	test.That(t, reconfiguredCalls.Load(), test.ShouldEqual, 0)
+	models := []resource.Model{otherModel, model}
+	var reconfigures int
+	testutils.WaitForAssertionWithSleep(t, 100*time.Millisecond, 50, func(tb testing.TB) {
+		tb.Helper()
+		if reconfiguredCalls.Load() == 0 && reconfigures%5 == 0 {
+			newCfg := *cfg
+			newCfg.Components = []resource.Config{
+				{
+					Model: models[(reconfigures/5)%2],
+					Name:  sensorName,
+					API:   sensor.API,
+				},
+			}
+			newCfg.Jobs = append([]config.JobConfig{}, cfg.Jobs...)
+			newCfg.Jobs[0].Command = map[string]any{"command": "startup", "attempt": reconfigures}
+			lr.Reconfigure(ctx, &newCfg)
+		}
+		reconfigures++
+		test.That(tb, reconfiguredCalls.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+	})
+	test.That(t, startupCalls.Load(), test.ShouldEqual, 1)
+}
+
+// sealingDataManager is a data manager which lets a test seal capture files for its subscribers.
+type sealingDataManager struct {
+	*inject.DataManagerService
+	mu       sync.Mutex
+	sealedCh chan<- data.SealedCaptureFile
+}
+
+func (dm *sealingDataManager) SubscribeCaptureFileSealed(ch chan<- data.SealedCaptureFile) func() {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.sealedCh = ch
+	return func() {
+		dm.mu.Lock()
+		defer dm.mu.Unlock()
+		dm.sealedCh = nil
+	}
+}
+
+// subscriber waits for the data manager to be subscribed to and returns the subscribed channel.
+func (dm *sealingDataManager) subscriber(t *testing.T) chan<- data.SealedCaptureFile {
+	t.Helper()
+	var sealedCh chan<- data.SealedCaptureFile
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		dm.mu.Lock()
+		defer dm.mu.Unlock()
+		sealedCh = dm.sealedCh
+		test.That(tb, sealedCh, test.ShouldNotBeNil)
+	})
+	return sealedCh
+}
+
 // Test continuous mode, include switching to and from.
 func TestJobContinuousSchedule(t *testing.T) {
 	t.Parallel()
@@ -504,6 +504,7 @@ func newWithResources(
 	}
 
 	r.mostRecentCfg.Store(config.Config{})
+	r.operations.SetAuditLog(rOpts.enableOperationAuditLog)
 
 	var heartbeatWindow time.Duration
 	if cfg.Network.Sessions.HeartbeatWindow == 0 {
@@ -546,7 +547,11 @@ func newWithResources(
 
 	// we assume these never appear in our configs and as such will not be removed from the
 	// resource graph
-	r.webSvc = web.New(r, logger, rOpts.webOptions...)
+	webOptions := rOpts.webOptions
+	if r.ftdc != nil {
+		webOptions = append(slices.Clone(webOptions), web.WithFTDC(r.ftdc))
+	}
+	r.webSvc = web.New(r, logger, webOptions...)
 	if r.ftdc != nil {
 		r.ftdc.Add("web", r.webSvc.RequestCounter())
 	}
@@ -635,7 +640,7 @@ func newWithResources(
 		return r.ResourceByName(match)
 	}
 
-	jobManager, err := jobmanager.New(ctx, logger, getResource, r.webSvc.ModuleAddresses())
+	jobManager, err := jobmanager.New(ctx, logger, getResource, r.manager.resources.Status, r.webSvc.ModuleAddresses())
 	if err != nil {
 		r.logger.CErrorw(ctx, "Job manager failed to start", "error", err)
 	}
@@ -1616,6 +1621,9 @@ func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config,
 		logNoun = "reconfiguration"
 	}
 	r.logger.CInfof(ctx, "%ving robot", logVerb)
+	if len(diff.Overlaid) > 0 {
+		r.logger.CInfow(ctx, "Config overlay changes resources", "resources", diff.Overlaid)
+	}
 
 	if r.revealSensitiveConfigDiffs {
 		r.logger.CDebugf(ctx, "%ving with %+v", logVerb, diff)
@@ -1957,9 +1965,14 @@ func (r *localRobot) MachineStatus(ctx context.Context) (robot.MachineStatus, er
 				result.JobStatuses = make(map[string]robot.JobStatus)
 			}
 			for jobName, jobHistory := range r.jobManager.JobHistories.Range {
+				var recentResults []robot.JobResult
+				for _, jr := range jobHistory.Results() {
+					recentResults = append(recentResults, robot.JobResult(jr))
+				}
 				result.JobStatuses[jobName] = robot.JobStatus{
 					RecentSuccessfulRuns: jobHistory.Successes(),
 					RecentFailedRuns:     jobHistory.Failures(),
+					RecentResults:        recentResults,
 				}
 			}
 		}
@@ -22,6 +22,9 @@ type options struct {
 	// whether or not to run FTDC
 	enableFTDC bool
 
+	// whether or not completed operations are logged
+	enableOperationAuditLog bool
+
 	// disableCompleteConfigWorker starts the robot without the complete config worker - should only be used for tests.
 	disableCompleteConfigWorker bool
 }
@@ -55,6 +58,14 @@ func WithFTDC() Option {
 	})
 }
 
+// WithOperationAuditLog returns an Option which causes completed operations to be
+// logged, so they are written through the logging appenders.
+func WithOperationAuditLog() Option {
+	return newFuncOption(func(o *options) {
+		o.enableOperationAuditLog = true
+	})
+}
+
 // WithWebOptions returns a Option which sets the streamConfig
 // used to enable audio/video streaming over WebRTC.
 func WithWebOptions(opts ...web.Option) Option {
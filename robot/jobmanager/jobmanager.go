@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -39,6 +40,10 @@ const (
 	// the job manager will be looking for.
 	componentServiceIndex int = 2
 	historyLength         int = 10
+	// maxResponseBytes bounds the size of a job response kept in its history, larger responses
+	// are dropped and only their truncation is recorded.
+	maxResponseBytes    int           = 64 * 1024
+	defaultRetryBackoff time.Duration = time.Second
 )
 
 // JobManager keeps track of the currently scheduled jobs and updates the schedule with
@@ -53,16 +58,57 @@ type JobManager struct {
 	isClosed      bool
 	closeMutex    sync.Mutex
 
+	jobsMu sync.Mutex
+	jobs   map[string]*managedJob
+	// startupFired holds the jobs whose startup trigger fired, so it fires once per robot start.
+	startupFired map[string]bool
+
+	// triggeredMu protects triggeredClosed, which stops new triggered runs once the job manager
+	// is closing so triggeredJobs can be waited on.
+	triggeredMu     sync.Mutex
+	triggeredClosed bool
+	triggeredJobs   sync.WaitGroup
+	triggeredCtx    context.Context
+	cancelTriggered context.CancelFunc
+
+	watchers sync.WaitGroup
+
 	NumJobHistories atomic.Int32
 	JobHistories    ssync.Map[string, *JobHistory]
 }
 
+// managedJob is a job the job manager knows about, whether it is on the scheduler or run after
+// other jobs.
+type managedJob struct {
+	cfg config.JobConfig
+	run func(ctx context.Context) error
+	// succeededDeps holds the dependencies which succeeded since this job last ran.
+	succeededDeps map[string]bool
+	// debounce and lastTriggered drop trigger events too close to the last one which ran the job.
+	debounce      time.Duration
+	lastTriggered time.Time
+}
+
+// JobResult is the outcome of a single run of a job.
+type JobResult struct {
+	StartTime time.Time
+	Duration  time.Duration
+	// Attempts is the number of times the job was tried, more than 1 if it was retried.
+	Attempts int
+	Response map[string]any
+	// ResponseTruncated is set when the response was too large to keep.
+	ResponseTruncated bool
+	Error             string
+}
+
 // JobHistory records historical metadata about a job.
 type JobHistory struct {
 	successTimesMu sync.Mutex
 	successTimes   *ring.Ring
 	failureTimesMu sync.Mutex
 	failureTimes   *ring.Ring
+	resultsMu      sync.Mutex
+	results        *ring.Ring
 }
 
 // Successes returns timestamps of the last historyLength number successfully completed jobs.
@@ -109,6 +155,28 @@ func (jh *JobHistory) AddFailure(time time.Time) {
 	jh.failureTimes = jh.failureTimes.Next()
 }
 
+// Results returns the last historyLength number of job results, oldest first.
+func (jh *JobHistory) Results() []JobResult {
+	results := make([]JobResult, 0, historyLength)
+	jh.resultsMu.Lock()
+	defer jh.resultsMu.Unlock()
+	for i := 0; i < historyLength; i++ {
+		if jh.results.Value != nil {
+			results = append(results, jh.results.Value.(JobResult))
+		}
+		jh.results = jh.results.Next()
+	}
+	return results
+}
+
+// AddResult adds a result to results, overwriting the earliest entry if it is full.
+func (jh *JobHistory) AddResult(result JobResult) {
+	jh.resultsMu.Lock()
+	defer jh.resultsMu.Unlock()
+	jh.results.Value = result
+	jh.results = jh.results.Next()
+}
+
 // New sets up the context and grpcConn that is used in scheduled jobs. The actual
 // scheduler is initialized and automatically started. Any jobs added to the config will
 // then immediately get scheduled according to their "Schedule" field.
@@ -116,6 +184,7 @@ func New(
 	robotContext context.Context,
 	logger logging.Logger,
 	getResource func(string) (resource.Resource, error),
+	getStatuses func() []resource.NodeStatus,
 	parentAddr config.ParentSockAddrs,
 ) (*JobManager, error) {
 	jobLogger := logger.Sublogger("job_manager")
@@ -150,7 +219,11 @@ func New(
 		namesToJobIDs: make(map[string]uuid.UUID),
 		ctx:           robotContext,
 		conn:          conn,
+		jobs:          make(map[string]*managedJob),
+		startupFired:  make(map[string]bool),
 	}
+	jm.triggeredCtx, jm.cancelTriggered = context.WithCancel(robotContext)
+	jm.startTriggers(getStatuses)
 
 	jm.scheduler.Start()
 	return jm, nil
@@ -168,7 +241,14 @@ func (jm *JobManager) Close() error {
 	jm.isClosed = true
 	jm.logger.CInfo(jm.ctx, "JobManager is shutting down.")
 	utils.UncheckedError(jm.conn.Close())
-	return jm.scheduler.Shutdown()
+	err := jm.scheduler.Shutdown()
+	jm.triggeredMu.Lock()
+	jm.triggeredClosed = true
+	jm.triggeredMu.Unlock()
+	jm.cancelTriggered()
+	jm.watchers.Wait()
+	jm.triggeredJobs.Wait()
+	return err
 }
 
 // createDescriptorSourceAndgRPCMethod sets up a DescriptorSource for grpc translations
@@ -216,29 +296,29 @@ func (jm *JobManager) createJobFunction(jc config.JobConfig, continuous bool) fu
 	// deduplication for job loggers.
 	jobLogger.NeverDeduplicate()
 
-	// using jm.ctx so we interrupt only if JM is shutting down. When changing schedule, let existing jobs complete instead of interrupting.
-	jobFunc := func(_ context.Context) error {
+	// jobFunc makes a single attempt of the job, interrupted only if ctx is done.
+	jobFunc := func(ctx context.Context) (map[string]any, error) {
 		res, err := jm.getResource(jc.Resource)
 		if err != nil {
 			jobLogger.CWarnw(jm.ctx, "Could not get resource", "error", err.Error())
-			return err
+			return nil, err
 		}
 		if jc.Method == "DoCommand" {
 			jobLogger.CDebugw(jm.ctx, "Job triggered", "name", jc.Name)
 			// unlike below InvokeRPC, if DoCommand panics there is no recover
-			response, err := res.DoCommand(jm.ctx, jc.Command)
+			response, err := res.DoCommand(ctx, jc.Command)
 			if err != nil {
 				jobLogger.CWarnw(jm.ctx, "Job failed", "error", err.Error())
-				return err
+				return nil, err
 			}
 			jobLogger.CDebugw(jm.ctx, "Job succeeded", "name", jc.Name, "response", response)
-			return nil
+			return response, nil
 		}
 
 		descSource, grpcService, grpcMethod, err := jm.createDescriptorSourceAndgRPCMethod(res, jc.Method)
 		if err != nil {
 			jobLogger.CWarnw(jm.ctx, "grpc setup failed", "error", err)
-			return err
+			return nil, err
 		}
 
 		gRPCArgument := resource.GetResourceNameOverride(grpcService, grpcMethod)
@@ -248,7 +328,7 @@ func (jm *JobManager) createJobFunction(jc config.JobConfig, continuous bool) fu
 		argumentBytes, err := json.Marshal(argumentMap)
 		if err != nil {
 			jobLogger.CWarnw(jm.ctx, "could not serialize gRPC method arguments", "error", err.Error())
-			return err
+			return nil, err
 		}
 		options := grpcurl.FormatOptions{
 			EmitJSONDefaultFields: true,
@@ -262,7 +342,7 @@ func (jm *JobManager) createJobFunction(jc config.JobConfig, continuous bool) fu
 			options)
 		if err != nil {
 			jobLogger.CWarnw(jm.ctx, "could not create parser and formatter for grpc requests", "error", err.Error())
-			return err
+			return nil, err
 		}
 
 		buffer := bytes.NewBuffer(make([]byte, 0))
@@ -273,27 +353,94 @@ func (jm *JobManager) createJobFunction(jc config.JobConfig, continuous bool) fu
 		}
 		jobLogger.CDebugw(jm.ctx, "Job triggered", "name", jc.Name)
 		grpcMethodCombined := grpcService + "." + grpcMethod
-		err = grpcurl.InvokeRPC(jm.ctx, descSource, jm.conn, grpcMethodCombined, nil, h, rf.Next)
+		err = grpcurl.InvokeRPC(ctx, descSource, jm.conn, grpcMethodCombined, nil, h, rf.Next)
 		if err != nil {
 			jobLogger.CWarnw(jm.ctx, "Job failed", "name", jc.Name, "error", err.Error())
-			return err
+			return nil, err
 		} else if h.Status != nil && h.Status.Err() != nil {
 			// if job panics, it seems to be captured here.
 			jobLogger.CWarnw(jm.ctx, "Job failed", "name", jc.Name, "error", h.Status.Err())
-			return h.Status.Err()
+			return nil, h.Status.Err()
 		}
 		response := map[string]any{}
 		err = json.Unmarshal(buffer.Bytes(), &response)
 		if err != nil {
 			jobLogger.CWarnw(jm.ctx, "Unmarshalling grpc response failed with error", "name", jc.Name,
 				"error", err.Error())
-			return err
+			return nil, err
 		}
 		jobLogger.CDebugw(jm.ctx, "Job succeeded", "name", jc.Name, "response", response)
-		return nil
+		return response, nil
 	}
 
+	// validated by scheduleJob, so parse errors can't happen here.
+	var timeout time.Duration
+	if jc.Timeout != "" {
+		timeout, _ = time.ParseDuration(jc.Timeout)
+	}
+	retryBackoff := defaultRetryBackoff
+	if jc.RetryBackoff != "" {
+		retryBackoff, _ = time.ParseDuration(jc.RetryBackoff)
+	}
+
+	// runWithRetries runs the job until it succeeds or is out of retries, returning the result of
+	// the last attempt.
+	runWithRetries := func(ctx context.Context) (JobResult, error) {
+		result := JobResult{StartTime: time.Now()}
+		backoff := retryBackoff
+		var response map[string]any
+		var err error
+		for {
+			result.Attempts++
+			// using jm.ctx so we interrupt only if JM is shutting down. When changing schedule, let existing jobs complete instead of interrupting.
+			attemptCtx, cancel := context.WithCancel(jm.ctx)
+			if timeout > 0 {
+				attemptCtx, cancel = context.WithTimeout(jm.ctx, timeout)
+			}
+			response, err = jobFunc(attemptCtx)
+			cancel()
+			if err == nil || result.Attempts > jc.Retries {
+				break
+			}
+			jobLogger.CInfow(jm.ctx, "Retrying job", "name", jc.Name, "attempt", result.Attempts+1, "backoff", backoff)
+			select {
+			case <-ctx.Done():
+			case <-jm.ctx.Done():
+			case <-time.After(backoff):
+				backoff *= 2
+				continue
+			}
+			break
+		}
+		result.Duration = time.Since(result.StartTime)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if response != nil {
+			if b, mErr := json.Marshal(response); mErr != nil || len(b) > maxResponseBytes {
+				result.ResponseTruncated = true
+			} else {
+				result.Response = response
+			}
+		}
+		return result, err
+	}
+
+	maxConcurrent := jc.MaxConcurrent
+	if maxConcurrent == 0 {
+		maxConcurrent = 1
+	}
+	running := make(chan struct{}, maxConcurrent)
+
 	return func(ctx context.Context) error {
+		select {
+		case running <- struct{}{}:
+			defer func() { <-running }()
+		default:
+			jobLogger.CWarnw(jm.ctx, "Skipping job run, max_concurrent runs are already in progress",
+				"name", jc.Name, "max_concurrent", maxConcurrent)
+			return nil
+		}
 		var err error
 		for {
 			select {
@@ -305,7 +452,8 @@ func (jm *JobManager) createJobFunction(jc config.JobConfig, continuous bool) fu
 				return err
 			default:
 			}
-			err = jobFunc(ctx)
+			var result JobResult
+			result, err = runWithRetries(ctx)
 			now := time.Now()
 			if jh, ok := jm.JobHistories.Load(jc.Name); ok {
 				if err != nil {
@@ -314,6 +462,10 @@ func (jm *JobManager) createJobFunction(jc config.JobConfig, continuous bool) fu
 				} else {
 					jh.AddSuccess(now)
 				}
+				jh.AddResult(result)
+			}
+			if err == nil {
+				jm.jobSucceeded(jc.Name)
 			}
 			if !continuous {
 				return err
@@ -322,12 +474,61 @@ func (jm *JobManager) createJobFunction(jc config.JobConfig, continuous bool) fu
 	}
 }
 
+// jobSucceeded records that the named job succeeded and runs every job whose dependencies have
+// now all succeeded since it last ran.
+func (jm *JobManager) jobSucceeded(name string) {
+	jm.jobsMu.Lock()
+	var ready []*managedJob
+	for _, j := range jm.jobs {
+		if !slices.Contains(j.cfg.DependsOn, name) {
+			continue
+		}
+		j.succeededDeps[name] = true
+		allSucceeded := true
+		for _, dep := range j.cfg.DependsOn {
+			allSucceeded = allSucceeded && j.succeededDeps[dep]
+		}
+		if allSucceeded {
+			j.succeededDeps = make(map[string]bool)
+			ready = append(ready, j)
+		}
+	}
+	jm.jobsMu.Unlock()
+
+	for _, j := range ready {
+		jm.runTriggered(j)
+	}
+}
+
+// runTriggered runs a job which is not on the scheduler in the background, unless the job manager
+// is closing.
+func (jm *JobManager) runTriggered(j *managedJob) {
+	jm.triggeredMu.Lock()
+	defer jm.triggeredMu.Unlock()
+	if jm.triggeredClosed {
+		return
+	}
+	jm.logger.CDebugw(jm.ctx, "Running job after its dependencies succeeded", "name", j.cfg.Name)
+	jm.triggeredJobs.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer jm.triggeredJobs.Done()
+		utils.UncheckedError(j.run(jm.triggeredCtx))
+	})
+}
+
 // removeJob removes the job from the scheduler and clears the internal map entry.
 func (jm *JobManager) removeJob(name string, verbose bool) {
-	jobID := jm.namesToJobIDs[name]
 	if verbose {
 		jm.logger.CInfow(jm.ctx, "Removing job", "name", name)
 	}
+	jm.jobsMu.Lock()
+	delete(jm.jobs, name)
+	jm.jobsMu.Unlock()
+	jobID, ok := jm.namesToJobIDs[name]
+	if !ok {
+		// jobs which run after their dependencies are not on the scheduler
+		return
+	}
 	err := jm.scheduler.RemoveJob(jobID)
 	if err != nil {
 		jm.logger.CWarnw(jm.ctx, "Removing the job failed", "error", err.Error())
@@ -386,15 +587,23 @@ func (jm *JobManager) scheduleJob(jc config.JobConfig, verbose bool) {
 
 		// It is also important to note that DURATION jobs start relative to when they were
 		// queued on the job scheduler, while CRON jobs are tied to the physical clock.
+
+		// Jobs with a max_concurrent above 1 are not singletons, runs past the limit are skipped
+		// by the job function instead.
+		singleton := jc.MaxConcurrent <= 1
 		t, err := time.ParseDuration(jc.Schedule)
 		if err != nil {
 			// TODO(RSDK-12757): exit if cron job is also invalid. Currently it's stored as an invalid string and validated at NewJob call.
 			withSeconds := len(strings.Split(jc.Schedule, " ")) >= 6
 			jobDefinition = gocron.CronJob(jc.Schedule, withSeconds)
-			jobOptions = append(jobOptions, gocron.WithSingletonMode(gocron.LimitModeReschedule))
+			if singleton {
+				jobOptions = append(jobOptions, gocron.WithSingletonMode(gocron.LimitModeReschedule))
+			}
 		} else {
 			jobDefinition = gocron.DurationJob(t)
-			jobOptions = append(jobOptions, gocron.WithSingletonMode(gocron.LimitModeWait))
+			if singleton {
+				jobOptions = append(jobOptions, gocron.WithSingletonMode(gocron.LimitModeWait))
+			}
 		}
 	}
 
@@ -408,11 +617,31 @@ func (jm *JobManager) scheduleJob(jc config.JobConfig, verbose bool) {
 		jm.JobHistories.Store(jc.Name, &JobHistory{
 			successTimes: ring.New(historyLength),
 			failureTimes: ring.New(historyLength),
+			results:      ring.New(historyLength),
 		})
 		jm.NumJobHistories.Add(1)
 	}
 
 	jobFunc := jm.createJobFunction(jc, continuous)
+	mj := &managedJob{cfg: jc, run: jobFunc, succeededDeps: make(map[string]bool)}
+	if jc.Trigger != nil && jc.Trigger.Debounce != "" {
+		// validated above
+		mj.debounce, _ = time.ParseDuration(jc.Trigger.Debounce)
+	}
+	jm.jobsMu.Lock()
+	jm.jobs[jc.Name] = mj
+	jm.jobsMu.Unlock()
+	if len(jc.DependsOn) != 0 {
+		if verbose {
+			jobLogger.CInfow(jm.ctx, "Job created", "name", jc.Name, "depends_on", jc.DependsOn)
+		}
+		return
+	}
+	if jc.Trigger != nil {
+		jm.scheduleTriggeredJob(mj, verbose)
+		return
+	}
+
 	j, err := jm.scheduler.NewJob(
 		jobDefinition,
 		gocron.NewTask(jobFunc),
@@ -0,0 +1,321 @@
+package jobmanager
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+)
+
+const (
+	// resourceStatusPollInterval is how often resource statuses are checked for
+	// resource_reconfigured and resource_unhealthy triggers.
+	resourceStatusPollInterval = time.Second
+	defaultTriggerPollInterval = time.Second
+	// sealedBufferSize is how many sealed capture files can wait for the job manager before
+	// further ones are dropped.
+	sealedBufferSize = 64
+)
+
+// triggerResource returns the name of the resource whose events run the job.
+func triggerResource(jc config.JobConfig) string {
+	if jc.Trigger.Resource != "" {
+		return jc.Trigger.Resource
+	}
+	if jc.Trigger.Type == config.JobTriggerCaptureFileSealed {
+		return ""
+	}
+	return jc.Resource
+}
+
+// fireTrigger runs a triggered job unless it already ran within its debounce. jobsMu must be held.
+func (jm *JobManager) fireTrigger(j *managedJob) {
+	now := time.Now()
+	if !j.lastTriggered.IsZero() && now.Sub(j.lastTriggered) < j.debounce {
+		jm.logger.CDebugw(jm.ctx, "Dropping trigger within debounce", "name", j.cfg.Name)
+		return
+	}
+	j.lastTriggered = now
+	jm.logger.CDebugw(jm.ctx, "Job trigger fired", "name", j.cfg.Name, "trigger", j.cfg.Trigger.Type)
+	jm.runTriggered(j)
+}
+
+// triggerEvent fires the trigger of every job waiting on triggerType events of the named resource.
+// An empty resourceName matches only triggers for any resource.
+func (jm *JobManager) triggerEvent(triggerType, resourceName string) {
+	jm.jobsMu.Lock()
+	defer jm.jobsMu.Unlock()
+	for _, j := range jm.jobs {
+		if j.cfg.Trigger == nil || j.cfg.Trigger.Type != triggerType {
+			continue
+		}
+		if watched := triggerResource(j.cfg); watched != "" && watched != resourceName {
+			continue
+		}
+		jm.fireTrigger(j)
+	}
+}
+
+// watchResourceStatuses fires resource_reconfigured and resource_unhealthy triggers from changes
+// in resource statuses until the job manager closes. The statuses when it starts are the
+// baseline, so resources being configured for the first time are not reconfigurations.
+func (jm *JobManager) watchResourceStatuses(getStatuses func() []resource.NodeStatus) {
+	previous := make(map[resource.Name]resource.NodeStatus)
+	for _, status := range getStatuses() {
+		previous[status.Name] = status
+	}
+	ticker := time.NewTicker(resourceStatusPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-jm.triggeredCtx.Done():
+			return
+		case <-ticker.C:
+		}
+		current := make(map[resource.Name]resource.NodeStatus)
+		for _, status := range getStatuses() {
+			current[status.Name] = status
+			prev, ok := previous[status.Name]
+			if !ok {
+				continue
+			}
+			if status.State == resource.NodeStateUnhealthy && prev.State != resource.NodeStateUnhealthy {
+				jm.triggerEvent(config.JobTriggerResourceUnhealthy, status.Name.Name)
+			}
+			if status.State == resource.NodeStateReady && status.LastUpdated.After(prev.LastUpdated) {
+				jm.triggerEvent(config.JobTriggerResourceReconfigured, status.Name.Name)
+			}
+		}
+		previous = current
+	}
+}
+
+// sealedSubscription is a subscription of the job manager to the capture files sealed by a data
+// manager.
+type sealedSubscription struct {
+	dataManager datamanager.CaptureFileSealedSubscriber
+	unsubscribe func()
+}
+
+// watchSealedCaptureFiles fires capture_file_sealed triggers for the capture files completed by
+// the data managers of the robot until the job manager closes. Data managers are found with the
+// resource statuses, so ones which are added or rebuilt are subscribed to on the next poll.
+func (jm *JobManager) watchSealedCaptureFiles(getStatuses func() []resource.NodeStatus) {
+	sealedCh := make(chan data.SealedCaptureFile, sealedBufferSize)
+	subscriptions := make(map[resource.Name]sealedSubscription)
+	defer func() {
+		for _, sub := range subscriptions {
+			sub.unsubscribe()
+		}
+	}()
+	jm.subscribeDataManagers(getStatuses(), subscriptions, sealedCh)
+	ticker := time.NewTicker(resourceStatusPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-jm.triggeredCtx.Done():
+			return
+		case <-ticker.C:
+			jm.subscribeDataManagers(getStatuses(), subscriptions, sealedCh)
+		case sealed := <-sealedCh:
+			jm.triggerEvent(config.JobTriggerCaptureFileSealed, sealed.Metadata.GetComponentName())
+		}
+	}
+}
+
+// subscribeDataManagers updates subscriptions to send the capture files sealed by the local data
+// managers among statuses to sealedCh.
+func (jm *JobManager) subscribeDataManagers(
+	statuses []resource.NodeStatus,
+	subscriptions map[resource.Name]sealedSubscription,
+	sealedCh chan<- data.SealedCaptureFile,
+) {
+	current := make(map[resource.Name]bool)
+	for _, status := range statuses {
+		name := status.Name
+		if name.API != datamanager.API || name.ContainsRemoteNames() || status.State != resource.NodeStateReady {
+			continue
+		}
+		res, err := jm.getResource(name.Name)
+		if err != nil {
+			continue
+		}
+		dataManager, ok := res.(datamanager.CaptureFileSealedSubscriber)
+		if !ok {
+			continue
+		}
+		current[name] = true
+		if sub, ok := subscriptions[name]; ok {
+			if sub.dataManager == dataManager {
+				continue
+			}
+			sub.unsubscribe()
+		}
+		subscriptions[name] = sealedSubscription{
+			dataManager: dataManager,
+			unsubscribe: dataManager.SubscribeCaptureFileSealed(sealedCh),
+		}
+	}
+	for name, sub := range subscriptions {
+		if !current[name] {
+			sub.unsubscribe()
+			delete(subscriptions, name)
+		}
+	}
+}
+
+// scheduleSensorTrigger puts a job on the scheduler which reads the sensor of a sensor_reading
+// trigger and fires it when its predicate becomes true.
+func (jm *JobManager) scheduleSensorTrigger(jc config.JobConfig) (gocron.Job, error) {
+	interval := defaultTriggerPollInterval
+	if jc.Trigger.PollInterval != "" {
+		// validated by scheduleJob
+		interval, _ = time.ParseDuration(jc.Trigger.PollInterval)
+	}
+	sensorName := triggerResource(jc)
+	jobLogger := jm.logger.Sublogger(jc.Name)
+	var matched bool
+	poll := func(ctx context.Context) {
+		res, err := jm.getResource(sensorName)
+		if err != nil {
+			jobLogger.CWarnw(jm.ctx, "Could not get trigger resource", "error", err.Error())
+			return
+		}
+		sensor, ok := res.(resource.Sensor)
+		if !ok {
+			jobLogger.CWarnw(jm.ctx, "Trigger resource is not a sensor", "resource", sensorName)
+			return
+		}
+		readings, err := sensor.Readings(ctx, nil)
+		if err != nil {
+			jobLogger.CWarnw(jm.ctx, "Could not get trigger readings", "error", err.Error())
+			return
+		}
+		matches, err := readingMatches(readings, jc.Trigger.Path, jc.Trigger.Operator, jc.Trigger.Value)
+		if err != nil {
+			jobLogger.CWarnw(jm.ctx, "Could not evaluate trigger", "error", err.Error())
+			return
+		}
+		if matches && !matched {
+			jm.jobsMu.Lock()
+			if j, ok := jm.jobs[jc.Name]; ok {
+				jm.fireTrigger(j)
+			}
+			jm.jobsMu.Unlock()
+		}
+		matched = matches
+	}
+	return jm.scheduler.NewJob(
+		gocron.DurationJob(interval),
+		gocron.NewTask(poll),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+		gocron.WithName(jc.Name),
+		gocron.WithContext(jm.ctx),
+	)
+}
+
+// readingMatches reports whether the reading at the dot separated path compares to value with
+// operator. Numbers are compared as numbers, other values only for (in)equality.
+func readingMatches(readings map[string]interface{}, path, operator string, value any) (bool, error) {
+	var reading any = readings
+	for _, key := range strings.Split(path, ".") {
+		m, ok := reading.(map[string]interface{})
+		if !ok {
+			return false, errors.Errorf("reading path %q not found", path)
+		}
+		if reading, ok = m[key]; !ok {
+			return false, errors.Errorf("reading path %q not found", path)
+		}
+	}
+
+	readingNum, readingIsNum := toFloat64(reading)
+	valueNum, valueIsNum := toFloat64(value)
+	if readingIsNum && valueIsNum {
+		switch operator {
+		case "==":
+			return readingNum == valueNum, nil
+		case "!=":
+			return readingNum != valueNum, nil
+		case ">":
+			return readingNum > valueNum, nil
+		case ">=":
+			return readingNum >= valueNum, nil
+		case "<":
+			return readingNum < valueNum, nil
+		case "<=":
+			return readingNum <= valueNum, nil
+		}
+	}
+	switch operator {
+	case "==":
+		return reflect.DeepEqual(reading, value), nil
+	case "!=":
+		return !reflect.DeepEqual(reading, value), nil
+	default:
+		return false, errors.Errorf("cannot compare %v %s %v, only numbers can be ordered", reading, operator, value)
+	}
+}
+
+func toFloat64(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// startTriggers starts the background watchers which fire job triggers.
+func (jm *JobManager) startTriggers(getStatuses func() []resource.NodeStatus) {
+	if getStatuses == nil {
+		return
+	}
+	jm.watchers.Add(1)
+	utils.ManagedGo(func() {
+		jm.watchSealedCaptureFiles(getStatuses)
+	}, jm.watchers.Done)
+	jm.watchers.Add(1)
+	utils.ManagedGo(func() {
+		jm.watchResourceStatuses(getStatuses)
+	}, jm.watchers.Done)
+}
+
+// scheduleTriggeredJob sets up the trigger of a job which runs on events.
+func (jm *JobManager) scheduleTriggeredJob(j *managedJob, verbose bool) {
+	jc := j.cfg
+	jobLogger := jm.logger.Sublogger(jc.Name)
+	switch jc.Trigger.Type {
+	case config.JobTriggerStartup:
+		// jobs are scheduled again when their config changes, which is not a startup
+		jm.jobsMu.Lock()
+		if !jm.startupFired[jc.Name] {
+			jm.startupFired[jc.Name] = true
+			jm.fireTrigger(j)
+		}
+		jm.jobsMu.Unlock()
+	case config.JobTriggerSensorReading:
+		poller, err := jm.scheduleSensorTrigger(jc)
+		if err != nil {
+			jobLogger.CErrorw(jm.ctx, "Failed to create a new job", "name", jc.Name, "error", err.Error())
+			return
+		}
+		jm.namesToJobIDs[jc.Name] = poller.ID()
+	}
+	if verbose {
+		jobLogger.CInfow(jm.ctx, "Job created", "name", jc.Name, "trigger", jc.Trigger.Type)
+	}
+}
@@ -358,6 +358,22 @@ type MachineStatus struct {
 type JobStatus struct {
 	RecentSuccessfulRuns []time.Time
 	RecentFailedRuns     []time.Time
+	// RecentResults holds the outcome of the most recent runs, oldest first. It is only available
+	// from a local robot, the machine status API does not carry it. The web server serves it over
+	// HTTP at /jobs/results instead when its job results option is on.
+	RecentResults []JobResult
+}
+
+// JobResult is the outcome of a single run of a JobManager job.
+type JobResult struct {
+	StartTime time.Time     `json:"start_time"`
+	Duration  time.Duration `json:"duration"`
+	// Attempts is the number of times the job was tried, more than 1 if it was retried.
+	Attempts int            `json:"attempts"`
+	Response map[string]any `json:"response,omitempty"`
+	// ResponseTruncated is set when the response was too large to keep.
+	ResponseTruncated bool   `json:"response_truncated,omitempty"`
+	Error             string `json:"error,omitempty"`
 }
 
 // VersionResponse encapsulates the version info of the robot.
@@ -21,6 +21,11 @@ import (
 	"go.viam.com/utils"
 	vprotoutils "go.viam.com/utils/protoutils"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -198,6 +203,18 @@ func convertInterfaceToStruct(i interface{}) (*structpb.Struct, error) {
 	if i == nil {
 		return &structpb.Struct{}, nil
 	}
+	// operations created for requests have the request message as their arguments
+	if msg, ok := i.(proto.Message); ok {
+		md, err := protojson.Marshal(msg)
+		if err != nil {
+			return nil, err
+		}
+		s := &structpb.Struct{}
+		if err := protojson.Unmarshal(md, s); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
 	return vprotoutils.StructToStructPb(i)
 }
 
@@ -205,7 +222,11 @@ func convertInterfaceToStruct(i interface{}) (*structpb.Struct, error) {
 func (s *Server) CancelOperation(ctx context.Context, req *pb.CancelOperationRequest) (*pb.CancelOperationResponse, error) {
 	op := s.robot.OperationManager().FindString(req.Id)
 	if op != nil {
-		op.Cancel()
+		reason := "cancelled by CancelOperation request"
+		if me := operation.Get(ctx); me != nil && me.Peer != "" {
+			reason += " from " + me.Peer
+		}
+		op.CancelWithReason(reason)
 	}
 	return &pb.CancelOperationResponse{}, nil
 }
@@ -388,16 +409,26 @@ func (s *Server) StartSession(ctx context.Context, req *pb.StartSessionRequest)
 				return nil, err
 			}
 		} else {
+			if err := s.acquireLeases(ctx, sess.ID()); err != nil {
+				return nil, err
+			}
 			return &pb.StartSessionResponse{
 				Id:              req.Resume,
 				HeartbeatWindow: durationpb.New(sess.HeartbeatWindow()),
 			}, nil
 		}
 	}
-	sess, err := s.robot.SessionManager().Start(
-		ctx,
-		authUID,
-	)
+	leaseMgr, toLease, _, err := s.requestedLeases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var sess *session.Session
+	if leaseMgr != nil {
+		// a session is only started if it can hold the leases requested with it
+		sess, err = leaseMgr.StartWithLeases(ctx, authUID, toLease)
+	} else {
+		sess, err = s.robot.SessionManager().Start(ctx, authUID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -420,9 +451,76 @@ func (s *Server) SendSessionHeartbeat(ctx context.Context, req *pb.SendSessionHe
 	if _, err := s.robot.SessionManager().FindByID(ctx, sessID, authUID); err != nil {
 		return nil, err
 	}
+	if err := s.acquireLeases(ctx, sessID); err != nil {
+		return nil, err
+	}
 	return &pb.SendSessionHeartbeatResponse{}, nil
 }
 
+// acquireLeases leases the resources named in the lease metadata of a request to the session and
+// releases the ones named in its release metadata. Either all of the resources are leased or, if
+// any of them can't be, none of the ones the session did not already hold are.
+func (s *Server) acquireLeases(ctx context.Context, sessID uuid.UUID) error {
+	leaseMgr, toLease, toRelease, err := s.requestedLeases(ctx)
+	if err != nil || leaseMgr == nil {
+		return err
+	}
+	for _, resName := range toRelease {
+		leaseMgr.ReleaseLease(sessID, resName)
+	}
+	held := leaseMgr.Leases()
+	acquired := make([]resource.Name, 0, len(toLease))
+	for _, resName := range toLease {
+		if err := leaseMgr.AcquireLease(sessID, resName); err != nil {
+			for _, acquiredName := range acquired {
+				leaseMgr.ReleaseLease(sessID, acquiredName)
+			}
+			return err
+		}
+		if held[resName] != sessID {
+			acquired = append(acquired, resName)
+		}
+	}
+	return nil
+}
+
+// requestedLeases returns the resources named in the lease and release metadata of a request along
+// with the lease manager of the robot, which is nil if the request names none.
+func (s *Server) requestedLeases(ctx context.Context) (session.LeaseManager, []resource.Name, []resource.Name, error) {
+	meta, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil, nil, nil
+	}
+	toLease, toRelease := meta.Get(session.LeaseMetadataKey), meta.Get(session.ReleaseLeaseMetadataKey)
+	if len(toLease) == 0 && len(toRelease) == 0 {
+		return nil, nil, nil, nil
+	}
+	leaseMgr, ok := s.robot.SessionManager().(session.LeaseManager)
+	if !ok {
+		return nil, nil, nil, status.Error(codes.Unimplemented, "this machine does not support resource leases")
+	}
+	parse := func(names []string) ([]resource.Name, error) {
+		resNames := make([]resource.Name, 0, len(names))
+		for _, name := range names {
+			resName, err := resource.NewFromString(name)
+			if err != nil {
+				return nil, err
+			}
+			resNames = append(resNames, resName)
+		}
+		return resNames, nil
+	}
+	leaseNames, err := parse(toLease)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	releaseNames, err := parse(toRelease)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return leaseMgr, leaseNames, releaseNames, nil
+}
+
 // Log receives logs to be logged by this robot.
 func (s *Server) Log(ctx context.Context, req *pb.LogRequest) (*pb.LogResponse, error) {
 	if req.Logs == nil {
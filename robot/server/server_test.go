@@ -18,7 +18,10 @@ import (
 	armpb "go.viam.com/api/component/arm/v1"
 	pb "go.viam.com/api/robot/v1"
 	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/cloud"
 	"go.viam.com/rdk/components/arm"
@@ -609,6 +612,69 @@ func TestServer(t *testing.T) {
 		})
 	})
 
+	t.Run("StartSession with leases", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		injectRobot := &inject.Robot{}
+		injectRobot.LoggerFunc = func() logging.Logger { return logger }
+		sessMgr := robot.NewSessionManager(injectRobot, config.DefaultSessionHeartbeatWindow)
+		defer sessMgr.Close()
+		injectRobot.SessMgr = sessMgr
+		server := server.New(injectRobot)
+
+		armName := arm.Named("arm1")
+		leaseCtx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(session.LeaseMetadataKey, armName.String()))
+		startResp, err := server.StartSession(leaseCtx, &pb.StartSessionRequest{})
+		test.That(t, err, test.ShouldBeNil)
+		sessID, err := uuid.Parse(startResp.Id)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sessMgr.Leases(), test.ShouldResemble, map[resource.Name]uuid.UUID{armName: sessID})
+
+		// another session can't lease the same resource, and no session is started for it
+		_, err = server.StartSession(leaseCtx, &pb.StartSessionRequest{})
+		test.That(t, err, test.ShouldBeError, session.NewResourceLeasedError(armName, sessID))
+		test.That(t, sessMgr.All(), test.ShouldHaveLength, 1)
+
+		otherResp, err := server.StartSession(context.Background(), &pb.StartSessionRequest{})
+		test.That(t, err, test.ShouldBeNil)
+		_, err = server.SendSessionHeartbeat(leaseCtx, &pb.SendSessionHeartbeatRequest{Id: otherResp.Id})
+		test.That(t, err, test.ShouldBeError, session.NewResourceLeasedError(armName, sessID))
+
+		// none of the resources are leased if any of them can't be
+		bothCtx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(session.LeaseMetadataKey, arm.Named("arm2").String(), session.LeaseMetadataKey, armName.String()))
+		_, err = server.SendSessionHeartbeat(bothCtx, &pb.SendSessionHeartbeatRequest{Id: otherResp.Id})
+		test.That(t, err, test.ShouldBeError, session.NewResourceLeasedError(armName, sessID))
+		test.That(t, sessMgr.Leases(), test.ShouldResemble, map[resource.Name]uuid.UUID{armName: sessID})
+
+		test.That(t, sessMgr.RevokeLease(armName), test.ShouldBeTrue)
+		_, err = server.SendSessionHeartbeat(leaseCtx, &pb.SendSessionHeartbeatRequest{Id: otherResp.Id})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sessMgr.Leases()[armName].String(), test.ShouldEqual, otherResp.Id)
+
+		releaseCtx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(session.ReleaseLeaseMetadataKey, armName.String()))
+		_, err = server.SendSessionHeartbeat(releaseCtx, &pb.SendSessionHeartbeatRequest{Id: otherResp.Id})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sessMgr.Leases(), test.ShouldBeEmpty)
+	})
+
+	t.Run("StartSession with leases unsupported", func(t *testing.T) {
+		logger := logging.NewTestLogger(t)
+		injectRobot := &inject.Robot{}
+		injectRobot.LoggerFunc = func() logging.Logger { return logger }
+		sessMgr := robot.NewSessionManager(injectRobot, config.DefaultSessionHeartbeatWindow)
+		defer sessMgr.Close()
+		// hide the lease methods of the session manager
+		injectRobot.SessMgr = struct{ session.Manager }{sessMgr}
+		server := server.New(injectRobot)
+
+		leaseCtx := metadata.NewIncomingContext(context.Background(),
+			metadata.Pairs(session.LeaseMetadataKey, arm.Named("arm1").String()))
+		_, err := server.StartSession(leaseCtx, &pb.StartSessionRequest{})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+	})
+
 	t.Run("Shutdown", func(t *testing.T) {
 		injectRobot := &inject.Robot{}
 		injectRobot.ResourceRPCAPIsFunc = func() []resource.RPCAPI { return nil }
@@ -22,6 +22,8 @@ func NewSessionManager(robot Robot, heartbeatWindow time.Duration) *SessionManag
 		logger:            robot.Logger().Sublogger("networking.session_manager"),
 		sessions:          map[uuid.UUID]*session.Session{},
 		resourceToSession: map[resource.Name]uuid.UUID{},
+		leases:            map[resource.Name]uuid.UUID{},
+		revokedLeases:     map[uuid.UUID]map[resource.Name]struct{}{},
 	}
 	m.workers = utils.NewBackgroundStoppableWorkers(m.expireLoop)
 	return m
@@ -39,6 +41,9 @@ type SessionManager struct {
 
 	resourceToSession map[resource.Name]uuid.UUID
 
+	leases        map[resource.Name]uuid.UUID
+	revokedLeases map[uuid.UUID]map[resource.Name]struct{}
+
 	workers *utils.StoppableWorkers
 }
 
@@ -87,6 +92,12 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 			defer m.sessionResourceMu.Unlock()
 			for id := range toDelete {
 				delete(m.sessions, id)
+				delete(m.revokedLeases, id)
+			}
+			for resName, id := range m.leases {
+				if _, ok := toDelete[id]; ok {
+					delete(m.leases, resName)
+				}
 			}
 
 			if len(toStop) == 0 {
@@ -152,13 +163,31 @@ const (
 
 // Start creates a new session that expects at least one heartbeat within the configured window.
 func (m *SessionManager) Start(ctx context.Context, ownerID string) (*session.Session, error) {
+	return m.StartWithLeases(ctx, ownerID, nil)
+}
+
+// StartWithLeases creates a new session like Start which holds leases on the given resources. If any
+// of them is leased by another session, no session is started.
+func (m *SessionManager) StartWithLeases(
+	ctx context.Context,
+	ownerID string,
+	resourceNames []resource.Name,
+) (*session.Session, error) {
 	sess := session.New(ctx, ownerID, m.heartbeatWindow, m.AssociateResource)
 	m.sessionResourceMu.Lock()
 	defer m.sessionResourceMu.Unlock()
 	if len(m.sessions) > maxSessions {
 		return nil, errors.New("too many concurrent sessions")
 	}
+	for _, resName := range resourceNames {
+		if holder, ok := m.leases[resName]; ok {
+			return nil, session.NewResourceLeasedError(resName, holder)
+		}
+	}
 	m.sessions[sess.ID()] = sess
+	for _, resName := range resourceNames {
+		m.leases[resName] = sess.ID()
+	}
 	return sess, nil
 }
 
@@ -189,6 +218,80 @@ func (m *SessionManager) AssociateResource(id uuid.UUID, resourceName resource.N
 	m.sessionResourceMu.Unlock()
 }
 
+// AcquireLease gives the session exclusive control of the resource until the session expires,
+// the lease is released or it is revoked. Acquiring a lease the session already holds has no effect.
+func (m *SessionManager) AcquireLease(id uuid.UUID, resourceName resource.Name) error {
+	m.sessionResourceMu.Lock()
+	defer m.sessionResourceMu.Unlock()
+	sess, ok := m.sessions[id]
+	if !ok || !sess.Active(time.Now()) {
+		return session.ErrNoSession
+	}
+	if _, revoked := m.revokedLeases[id][resourceName]; revoked {
+		return errors.Errorf("lease on %q was revoked from session %s", resourceName, id)
+	}
+	if holder, ok := m.leases[resourceName]; ok && holder != id {
+		return session.NewResourceLeasedError(resourceName, holder)
+	}
+	m.leases[resourceName] = id
+	return nil
+}
+
+// ReleaseLease releases the lease of the session on the resource, if it has one.
+func (m *SessionManager) ReleaseLease(id uuid.UUID, resourceName resource.Name) {
+	m.sessionResourceMu.Lock()
+	defer m.sessionResourceMu.Unlock()
+	if holder, ok := m.leases[resourceName]; ok && holder == id {
+		delete(m.leases, resourceName)
+	}
+}
+
+// RevokeLease takes the lease on the resource away from the session holding it. The session
+// can't acquire the lease again for the rest of its lifetime.
+func (m *SessionManager) RevokeLease(resourceName resource.Name) bool {
+	m.sessionResourceMu.Lock()
+	defer m.sessionResourceMu.Unlock()
+	holder, ok := m.leases[resourceName]
+	if !ok {
+		return false
+	}
+	delete(m.leases, resourceName)
+	if m.revokedLeases[holder] == nil {
+		m.revokedLeases[holder] = map[resource.Name]struct{}{}
+	}
+	m.revokedLeases[holder][resourceName] = struct{}{}
+	m.logger.Infow("lease revoked", "resource", resourceName, "session_id", holder)
+	return true
+}
+
+// Leases returns the session holding each leased resource.
+func (m *SessionManager) Leases() map[resource.Name]uuid.UUID {
+	m.sessionResourceMu.RLock()
+	defer m.sessionResourceMu.RUnlock()
+	leases := make(map[resource.Name]uuid.UUID, len(m.leases))
+	for resName, id := range m.leases {
+		leases[resName] = id
+	}
+	return leases
+}
+
+// hasLeases reports whether any resource is leased.
+func (m *SessionManager) hasLeases() bool {
+	m.sessionResourceMu.RLock()
+	defer m.sessionResourceMu.RUnlock()
+	return len(m.leases) > 0
+}
+
+// checkLease returns an error if the resource is leased by a session other than the given one.
+func (m *SessionManager) checkLease(id uuid.UUID, resourceName resource.Name) error {
+	m.sessionResourceMu.RLock()
+	defer m.sessionResourceMu.RUnlock()
+	if holder, ok := m.leases[resourceName]; ok && holder != id {
+		return session.NewResourceLeasedError(resourceName, holder)
+	}
+	return nil
+}
+
 // Close stops the session manager but will not explicitly expire any sessions.
 func (m *SessionManager) Close() {
 	m.workers.Stop()
@@ -2,15 +2,27 @@ package robot_test
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	commonpb "go.viam.com/api/common/v1"
+	basepb "go.viam.com/api/component/base/v1"
+	motionpb "go.viam.com/api/service/motion/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
 
+	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/testutils/inject"
 )
@@ -87,3 +99,178 @@ func TestSessionManagerExpiredSessions(t *testing.T) {
 			test.ShouldEqual, 1)
 	})
 }
+
+func TestSessionManagerLeases(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+	baseName := base.Named("base1")
+	r.ResourceRPCAPIsFunc = func() []resource.RPCAPI {
+		return []resource.RPCAPI{
+			{API: base.API, Desc: resource.RegisteredAPIs()[base.API].ReflectRPCServiceDesc},
+			{API: motion.API, Desc: resource.RegisteredAPIs()[motion.API].ReflectRPCServiceDesc},
+		}
+	}
+	r.ResourceNamesFunc = func() []resource.Name {
+		return []resource.Name{baseName, motion.Named("builtin")}
+	}
+	r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		return &inject.Base{}, nil
+	}
+
+	sm := robot.NewSessionManager(r, config.DefaultSessionHeartbeatWindow)
+	defer sm.Close()
+
+	// no owners since the calls below are not authenticated
+	fooSess, err := sm.Start(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+	barSess, err := sm.Start(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, sm.AcquireLease(uuid.New(), baseName), test.ShouldBeError, session.ErrNoSession)
+	test.That(t, sm.AcquireLease(fooSess.ID(), baseName), test.ShouldBeNil)
+	test.That(t, sm.AcquireLease(fooSess.ID(), baseName), test.ShouldBeNil)
+	test.That(t, sm.AcquireLease(barSess.ID(), baseName), test.ShouldBeError,
+		session.NewResourceLeasedError(baseName, fooSess.ID()))
+	test.That(t, sm.Leases(), test.ShouldResemble, map[resource.Name]uuid.UUID{baseName: fooSess.ID()})
+
+	call := func(sess *session.Session, method string, req interface{}) error {
+		md := metadata.MD{}
+		if sess != nil {
+			md.Set(session.IDMetadataKey, sess.ID().String())
+		}
+		_, err := sm.UnaryServerInterceptor(
+			metadata.NewIncomingContext(ctx, md),
+			req,
+			&grpc.UnaryServerInfo{FullMethod: "/viam.component.base.v1.BaseService/" + method},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+		)
+		return err
+	}
+	move := func(sess *session.Session) error {
+		return call(sess, "MoveStraight", &basepb.MoveStraightRequest{Name: baseName.ShortName()})
+	}
+	test.That(t, move(fooSess), test.ShouldBeNil)
+	test.That(t, move(barSess), test.ShouldBeError, session.NewResourceLeasedError(baseName, fooSess.ID()))
+	test.That(t, move(nil), test.ShouldBeError, session.NewResourceLeasedError(baseName, fooSess.ID()))
+
+	// calls which actuate the resource are rejected even if they are not safety monitored, calls
+	// which stop it or only read its state are not
+	stop := &basepb.StopRequest{Name: baseName.ShortName()}
+	test.That(t, call(fooSess, "Stop", stop), test.ShouldBeNil)
+	test.That(t, call(barSess, "Stop", stop), test.ShouldBeNil)
+	test.That(t, call(nil, "Stop", stop), test.ShouldBeNil)
+	test.That(t, call(barSess, "SetPower", &basepb.SetPowerRequest{Name: baseName.ShortName()}), test.ShouldBeError,
+		session.NewResourceLeasedError(baseName, fooSess.ID()))
+	test.That(t, call(barSess, "IsMoving", &basepb.IsMovingRequest{Name: baseName.ShortName()}), test.ShouldBeNil)
+
+	// calls to services are checked against the lease of the component they actuate
+	_, err = sm.UnaryServerInterceptor(
+		metadata.NewIncomingContext(ctx, metadata.Pairs(session.IDMetadataKey, barSess.ID().String())),
+		&motionpb.MoveRequest{Name: "builtin", ComponentName: baseName.ShortName()},
+		&grpc.UnaryServerInfo{FullMethod: "/viam.service.motion.v1.MotionService/Move"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	)
+	test.That(t, err, test.ShouldBeError, session.NewResourceLeasedError(baseName, fooSess.ID()))
+
+	// commands are checked against the lease of the resource they are sent to and of any component named in them
+	doCommand := func(sess *session.Session, service, name string, command map[string]interface{}) error {
+		cmd, err := structpb.NewStruct(command)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = sm.UnaryServerInterceptor(
+			metadata.NewIncomingContext(ctx, metadata.Pairs(session.IDMetadataKey, sess.ID().String())),
+			&commonpb.DoCommandRequest{Name: name, Command: cmd},
+			&grpc.UnaryServerInfo{FullMethod: "/" + service + "/DoCommand"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, nil
+			},
+		)
+		return err
+	}
+	const baseService, motionService = "viam.component.base.v1.BaseService", "viam.service.motion.v1.MotionService"
+	test.That(t, doCommand(fooSess, baseService, baseName.ShortName(), map[string]interface{}{"drive": true}), test.ShouldBeNil)
+	test.That(t, doCommand(barSess, baseService, baseName.ShortName(), map[string]interface{}{"drive": true}), test.ShouldBeError,
+		session.NewResourceLeasedError(baseName, fooSess.ID()))
+	servo := map[string]interface{}{"servo": map[string]interface{}{"component_name": baseName.ShortName()}}
+	test.That(t, doCommand(fooSess, motionService, "builtin", servo), test.ShouldBeNil)
+	test.That(t, doCommand(barSess, motionService, "builtin", servo), test.ShouldBeError,
+		session.NewResourceLeasedError(baseName, fooSess.ID()))
+	test.That(t, doCommand(barSess, motionService, "builtin", map[string]interface{}{"plan_cache": "clear"}), test.ShouldBeNil)
+
+	// only the holder can release the lease
+	sm.ReleaseLease(barSess.ID(), baseName)
+	test.That(t, move(barSess), test.ShouldNotBeNil)
+	sm.ReleaseLease(fooSess.ID(), baseName)
+	test.That(t, move(barSess), test.ShouldBeNil)
+
+	// a revoked lease can't be acquired again by the same session
+	test.That(t, sm.AcquireLease(barSess.ID(), baseName), test.ShouldBeNil)
+	test.That(t, sm.RevokeLease(baseName), test.ShouldBeTrue)
+	test.That(t, sm.RevokeLease(baseName), test.ShouldBeFalse)
+	test.That(t, sm.Leases(), test.ShouldBeEmpty)
+	test.That(t, move(fooSess), test.ShouldBeNil)
+	test.That(t, sm.AcquireLease(barSess.ID(), baseName), test.ShouldNotBeNil)
+	test.That(t, sm.AcquireLease(fooSess.ID(), baseName), test.ShouldBeNil)
+}
+
+func TestSessionManagerLeaseExpires(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+
+	sm := robot.NewSessionManager(r, 100*time.Millisecond)
+	defer sm.Close()
+
+	sess, err := sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sm.AcquireLease(sess.ID(), base.Named("base1")), test.ShouldBeNil)
+	test.That(t, sm.Leases(), test.ShouldHaveLength, 1)
+
+	// the lease ends with the session
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, sm.Leases(), test.ShouldBeEmpty)
+	})
+}
+
+func TestSessionManagerStartWithLeases(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+
+	sm := robot.NewSessionManager(r, config.DefaultSessionHeartbeatWindow)
+	defer sm.Close()
+
+	// of the sessions started concurrently with the same lease, only one is started
+	baseName := base.Named("base1")
+	const numStarts = 10
+	var wg sync.WaitGroup
+	var started atomic.Int32
+	for range numStarts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := sm.StartWithLeases(ctx, "", []resource.Name{baseName}); err == nil {
+				started.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	test.That(t, started.Load(), test.ShouldEqual, 1)
+	sessions := sm.All()
+	test.That(t, sessions, test.ShouldHaveLength, 1)
+	test.That(t, sm.Leases(), test.ShouldResemble, map[resource.Name]uuid.UUID{baseName: sessions[0].ID()})
+}
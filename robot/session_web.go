@@ -2,6 +2,7 @@ package robot
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -62,20 +63,136 @@ func IsSafetyHeartbeatMonitored(method string) bool {
 	return false
 }
 
-func (m *SessionManager) safetyMonitoredResourceFromUnary(req interface{}, method string) resource.Name {
-	subType, _, ok := m.safetyMonitoredTypeAndMethod(method)
-	if !ok {
-		return resource.Name{}
+// actuatingMethods are the names of the resource methods which actuate a resource and so are
+// rejected from anywhere but the session holding a lease on it. Stop is deliberately absent so
+// that any client can always stop a leased resource.
+var actuatingMethods = map[string]bool{
+	// commands may do anything, including actuating the component named in them
+	"DoCommand": true,
+	// arm, gantry, servo and motion
+	"Move":                      true,
+	"MoveToPosition":            true,
+	"MoveToJointPositions":      true,
+	"MoveThroughJointPositions": true,
+	"MoveOnMap":                 true,
+	"MoveOnGlobe":               true,
+	"Home":                      true,
+	// base and motor
+	"MoveStraight":      true,
+	"Spin":              true,
+	"SetPower":          true,
+	"SetVelocity":       true,
+	"GoFor":             true,
+	"GoTo":              true,
+	"SetRPM":            true,
+	"ResetZeroPosition": true,
+	// gripper
+	"Open": true,
+	"Grab": true,
+	// board
+	"SetGPIO":         true,
+	"SetPWM":          true,
+	"SetPWMFrequency": true,
+	"WriteAnalog":     true,
+	"SetPowerMode":    true,
+	// input controller, button, switch and navigation
+	"TriggerEvent": true,
+	"Push":         true,
+	"SetPosition":  true,
+	"SetMode":      true,
+}
+
+// isActuatingMethod reports whether a resource method actuates the resource.
+func isActuatingMethod(methodDesc *desc.MethodDescriptor) bool {
+	if methodDesc == nil {
+		return false
+	}
+	return actuatingMethods[methodDesc.GetName()]
+}
+
+// leaseTargets returns the resources actuated by a request: the resource named in the request
+// and, for service requests such as the motion ones, the component the service moves. The
+// components named by a component_name anywhere in the command of a DoCommand are included.
+func (m *SessionManager) leaseTargets(msg *dynamic.Message, subType *resource.RPCAPI) []resource.Name {
+	var targets []resource.Name
+	if _, resName, err := ResourceFromProtoMessage(m.robot, msg, subType.API); err == nil {
+		targets = append(targets, resName)
+	}
+	if msg.GetMessageDescriptor().GetFullyQualifiedName() == doCommandRequestName {
+		var req commonpb.DoCommandRequest
+		if err := msg.ConvertTo(&req); err == nil && req.GetCommand() != nil {
+			for _, name := range commandComponentNames(req.GetCommand().AsMap()) {
+				targets = append(targets, m.resourceNamesByShortName(name)...)
+			}
+		}
+	}
+	if msg.HasFieldName("component_name") {
+		if name, ok := msg.GetFieldByName("component_name").(string); ok && name != "" {
+			targets = append(targets, m.resourceNamesByShortName(name)...)
+		}
+	}
+	if msg.HasFieldName("component_name_deprecated") {
+		if nameMsg, ok := msg.GetFieldByName("component_name_deprecated").(*dynamic.Message); ok && nameMsg != nil {
+			api := resource.APINamespace(fmt.Sprint(nameMsg.GetFieldByName("namespace"))).
+				WithType(fmt.Sprint(nameMsg.GetFieldByName("type"))).
+				WithSubtype(fmt.Sprint(nameMsg.GetFieldByName("subtype")))
+			if name := fmt.Sprint(nameMsg.GetFieldByName("name")); name != "" {
+				targets = append(targets, resource.NewName(api, name))
+			}
+		}
+	}
+	return targets
+}
+
+var doCommandRequestName = string((&commonpb.DoCommandRequest{}).ProtoReflect().Descriptor().FullName())
+
+// commandComponentNames returns the values of the component_name keys found anywhere in a command.
+func commandComponentNames(command interface{}) []string {
+	var names []string
+	switch command := command.(type) {
+	case map[string]interface{}:
+		for key, value := range command {
+			if name, ok := value.(string); ok && key == "component_name" && name != "" {
+				names = append(names, name)
+				continue
+			}
+			names = append(names, commandComponentNames(value)...)
+		}
+	case []interface{}:
+		for _, value := range command {
+			names = append(names, commandComponentNames(value)...)
+		}
+	}
+	return names
+}
+
+// resourceNamesByShortName returns the names of the resources of the robot with the given short name.
+func (m *SessionManager) resourceNamesByShortName(shortName string) []resource.Name {
+	var names []resource.Name
+	for _, name := range m.robot.ResourceNames() {
+		if name.ShortName() == shortName {
+			names = append(names, name)
+		}
 	}
+	return names
+}
 
+func (m *SessionManager) dynamicMessageFromUnary(req interface{}, method string) *dynamic.Message {
 	reqMsg := protoutils.MessageToProtoV1(req)
 	if reqMsg == nil {
-		return resource.Name{}
+		return nil
 	}
-
 	msg, err := dynamic.AsDynamicMessage(reqMsg)
 	if err != nil {
 		m.logger.Errorw("error converting message to dynamic", "error", err, "method", method)
+		return nil
+	}
+	return msg
+}
+
+func (m *SessionManager) resourceFromUnary(req interface{}, method string, subType *resource.RPCAPI) resource.Name {
+	msg := m.dynamicMessageFromUnary(req, method)
+	if msg == nil {
 		return resource.Name{}
 	}
 
@@ -108,22 +225,16 @@ func (w *firstMessageServerStreamWrapper) RecvMsg(m interface{}) error {
 	return w.ServerStream.RecvMsg(m)
 }
 
-func (m *SessionManager) safetyMonitoredResourceFromStream(
+func (m *SessionManager) resourceFromStream(
 	stream grpc.ServerStream,
-	method string,
-) (resource.Name, grpc.ServerStream, error) {
-	subType, methodDesc, ok := m.safetyMonitoredTypeAndMethod(method)
-	if !ok {
-		// Note(erd): could maybe cache this in the future but may be subject to a DOS attack
-		// since method space is unbounded.
-		return resource.Name{}, nil, nil
-	}
-
+	subType *resource.RPCAPI,
+	methodDesc *desc.MethodDescriptor,
+) (resource.Name, *dynamic.Message, grpc.ServerStream, error) {
 	firstMsg := dynamic.NewMessage(methodDesc.GetInputType())
 
 	if err := stream.RecvMsg(firstMsg); err != nil {
 		// this error counts
-		return resource.Name{}, nil, err
+		return resource.Name{}, nil, nil, err
 	}
 
 	newStream := &firstMessageServerStreamWrapper{ServerStream: stream, firstMsg: firstMsg}
@@ -131,10 +242,10 @@ func (m *SessionManager) safetyMonitoredResourceFromStream(
 	_, resName, err := ResourceFromProtoMessage(m.robot, firstMsg, subType.API)
 	if err != nil {
 		m.logger.Errorw("unable to find resource", "error", err)
-		return resource.Name{}, newStream, nil
+		return resource.Name{}, firstMsg, newStream, nil
 	}
 
-	return resName, newStream, nil
+	return resName, firstMsg, newStream, nil
 }
 
 // ServerInterceptors returns gRPC interceptors to work with sessions.
@@ -146,45 +257,84 @@ func (m *SessionManager) ServerInterceptors() session.ServerInterceptors {
 }
 
 // UnaryServerInterceptor associates the current session (if present) in the current context before
-// passing it to the unary response handler.
+// passing it to the unary response handler. Calls which actuate a resource leased by another
+// session are rejected.
 func (m *SessionManager) UnaryServerInterceptor(
 	ctx context.Context,
 	req interface{},
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	if _, _, isMonitored := m.safetyMonitoredTypeAndMethod(info.FullMethod); !isMonitored {
-		return handler(ctx, req)
+	subType, methodDesc, isMonitored := m.safetyMonitoredTypeAndMethod(info.FullMethod)
+	if subType != nil && isActuatingMethod(methodDesc) {
+		var targets []resource.Name
+		if m.hasLeases() {
+			if msg := m.dynamicMessageFromUnary(req, info.FullMethod); msg != nil {
+				targets = m.leaseTargets(msg, subType)
+			}
+		}
+		var err error
+		if ctx, err = actuatingContext(ctx, m, targets, info.FullMethod); err != nil {
+			return nil, err
+		}
 	}
-	safetyMonitoredResourceName := m.safetyMonitoredResourceFromUnary(req, info.FullMethod)
-	ctx, err := associateSession(ctx, m, safetyMonitoredResourceName, info.FullMethod)
-	if err != nil {
-		return nil, err
+	if isMonitored {
+		safetyMonitoredResourceName := m.resourceFromUnary(req, info.FullMethod, subType)
+		ctx, err := associateSession(ctx, m, safetyMonitoredResourceName, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
 	}
 	return handler(ctx, req)
 }
 
 // StreamServerInterceptor associates the current session (if present) in the current context before
-// passing it to the stream response handler.
+// passing it to the stream response handler. Calls which actuate a resource leased by another
+// session are rejected.
 func (m *SessionManager) StreamServerInterceptor(
 	srv interface{},
 	ss grpc.ServerStream,
 	info *grpc.StreamServerInfo,
 	handler grpc.StreamHandler,
 ) error {
-	if _, _, isMonitored := m.safetyMonitoredTypeAndMethod(info.FullMethod); !isMonitored {
+	subType, methodDesc, isMonitored := m.safetyMonitoredTypeAndMethod(info.FullMethod)
+	actuating := subType != nil && isActuatingMethod(methodDesc)
+	if !isMonitored && !actuating {
 		return handler(srv, ss)
 	}
-	safetyMonitoredResource, wrappedStream, err := m.safetyMonitoredResourceFromStream(ss, info.FullMethod)
-	if err != nil {
-		return err
+	checkLeases := actuating && m.hasLeases()
+	var resourceName resource.Name
+	var firstMsg *dynamic.Message
+	if isMonitored || checkLeases {
+		// Note(erd): could maybe cache this in the future but may be subject to a DOS attack
+		// since method space is unbounded.
+		var wrappedStream grpc.ServerStream
+		var err error
+		resourceName, firstMsg, wrappedStream, err = m.resourceFromStream(ss, subType, methodDesc)
+		if err != nil {
+			return err
+		}
+		if wrappedStream != nil {
+			ss = wrappedStream
+		}
 	}
-	if wrappedStream != nil {
-		ss = wrappedStream
+	ctx := ss.Context()
+	if actuating {
+		var targets []resource.Name
+		if checkLeases && firstMsg != nil {
+			targets = m.leaseTargets(firstMsg, subType)
+		}
+		var err error
+		if ctx, err = actuatingContext(ctx, m, targets, info.FullMethod); err != nil {
+			return err
+		}
 	}
-	ctx, err := associateSession(ss.Context(), m, safetyMonitoredResource, info.FullMethod)
-	if err != nil {
-		return err
+	if isMonitored {
+		var err error
+		if ctx, err = associateSession(ctx, m, resourceName, info.FullMethod); err != nil {
+			return err
+		}
 	}
 	return handler(srv, &ssStreamContextWrapper{ss, ctx})
 }
@@ -201,9 +351,11 @@ func associateSession(
 		// defer this because no matter what we want to know that someone was using
 		// a resource with a monitored method as long as no error happened.
 		defer func() {
-			if err == nil {
-				m.AssociateResource(sessID, safetyMonitoredResourceName)
+			if err != nil {
+				nextCtx = nil
+				return
 			}
+			m.AssociateResource(sessID, safetyMonitoredResourceName)
 		}()
 	}
 	meta, ok := metadata.FromIncomingContext(ctx)
@@ -219,6 +371,10 @@ func associateSession(
 	if sessID == uuid.Nil {
 		return ctx, nil
 	}
+	// reuse the session the lease check already found for the call
+	if sess, ok := session.FromContext(ctx); ok && sess.ID() == sessID {
+		return ctx, nil
+	}
 	authEntity, _ := rpc.ContextAuthEntity(ctx)
 	sess, err := m.FindByID(ctx, sessID, authEntity.Entity)
 	if err != nil {
@@ -227,6 +383,47 @@ func associateSession(
 	return session.ToContext(ctx, sess), nil
 }
 
+// actuatingContext returns an error if any of the resources actuated by a call is leased by a
+// session other than the one of the incoming context, if any. Otherwise it returns the context
+// with that session and a check of the leases of the resources the call goes on to actuate, see
+// session.CheckLease. The context is returned unchanged while no resource is leased.
+func actuatingContext(
+	ctx context.Context,
+	m *SessionManager,
+	resourceNames []resource.Name,
+	method string,
+) (context.Context, error) {
+	if !m.hasLeases() {
+		return ctx, nil
+	}
+	var sessID uuid.UUID
+	if meta, ok := metadata.FromIncomingContext(ctx); ok {
+		var err error
+		sessID, err = sessionFromMetadata(meta)
+		if err != nil {
+			m.logger.CWarnw(ctx, "failed to get session id from metadata", "error", err, "method", method)
+			return nil, err
+		}
+	}
+	if sessID != uuid.Nil {
+		// the session must belong to the caller for its leases to count
+		authEntity, _ := rpc.ContextAuthEntity(ctx)
+		sess, err := m.FindByID(ctx, sessID, authEntity.Entity)
+		if err != nil {
+			return nil, err
+		}
+		ctx = session.ToContext(ctx, sess)
+	}
+	for _, resourceName := range resourceNames {
+		if err := m.checkLease(sessID, resourceName); err != nil {
+			return nil, err
+		}
+	}
+	return session.WithLeaseCheck(ctx, func(resourceName resource.Name) error {
+		return m.checkLease(sessID, resourceName)
+	}), nil
+}
+
 // sessionFromMetadata returns a session id from metadata.
 func sessionFromMetadata(meta metadata.MD) (uuid.UUID, error) {
 	values := meta.Get(session.IDMetadataKey)
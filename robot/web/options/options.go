@@ -19,6 +19,23 @@ type Options struct {
 	// Pprof turns on the pprof profiler accessible at /debug
 	Pprof bool
 
+	// FTDCStream turns on live streaming of FTDC diagnostics data accessible at
+	// /debug/ftdc/stream
+	FTDCStream bool
+
+	// OperationHistory turns on serving the recently completed operations, including their
+	// request arguments, accessible at /debug/operations/history
+	OperationHistory bool
+
+	// JobResults turns on serving the recent results of each job, including the responses of
+	// their DoCommand calls, accessible at /jobs/results
+	JobResults bool
+
+	// LeaseAdminEntities turns on resource lease administration accessible at /admin/leases
+	// for requests authenticated as one of these entities, such as API key ids. Administration
+	// is unavailable when authentication is not configured.
+	LeaseAdminEntities []string
+
 	// SharedDir is the location of static web assets.
 	SharedDir string
 
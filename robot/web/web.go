@@ -12,6 +12,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,6 +37,7 @@ import (
 	googlegrpc "google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/config"
@@ -48,6 +50,7 @@ import (
 	grpcserver "go.viam.com/rdk/robot/server"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	webstream "go.viam.com/rdk/robot/web/stream"
+	"go.viam.com/rdk/session"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -806,6 +809,42 @@ func (svc *webService) initMux(options weboptions.Options) *goji.Mux {
 		mux.HandleFunc(pat.New("/debug/pprof/trace"), pprof.Trace)
 	}
 
+	// endpoints which are turned off are not found rather than handled by the gRPC handler below
+	notFound := func(patterns ...string) {
+		for _, p := range patterns {
+			mux.HandleFunc(pat.New(p), http.NotFound)
+		}
+	}
+
+	if options.FTDCStream {
+		mux.HandleFunc(pat.New("/debug/ftdc/stream"), svc.requireAuth(options, svc.handleFTDCStream))
+	} else {
+		notFound("/debug/ftdc/stream")
+	}
+
+	if options.OperationHistory {
+		mux.HandleFunc(pat.New("/debug/operations/history"), svc.requireAuth(options, svc.handleOperationHistory))
+	} else {
+		notFound("/debug/operations/history")
+	}
+
+	switch {
+	case len(options.LeaseAdminEntities) == 0:
+		notFound("/admin/leases", "/admin/leases/revoke")
+	case len(options.Auth.Handlers) == 0:
+		svc.logger.Warn("lease administration requires authentication to be configured; not serving it")
+		notFound("/admin/leases", "/admin/leases/revoke")
+	default:
+		mux.HandleFunc(pat.Get("/admin/leases"), svc.requireAdmin(options, svc.handleLeases))
+		mux.HandleFunc(pat.Post("/admin/leases/revoke"), svc.requireAdmin(options, svc.handleRevokeLease))
+	}
+
+	if options.JobResults {
+		mux.HandleFunc(pat.Get("/jobs/results"), svc.requireAuth(options, svc.handleJobResults))
+	} else {
+		notFound("/jobs/results")
+	}
+
 	// serve resource graph visualization
 	// TODO: hide behind option
 	// TODO: accept params to display different formats
@@ -1055,6 +1094,144 @@ type RestartStatusResponse struct {
 	ModuleServerTCPAddr string `json:"module_server_tcp_addr,omitempty"`
 }
 
+// handleFTDCStream streams FTDC data to the client as it's recorded. The response body is in the
+// FTDC file format and can be read with `ftdc.ParseStream`. The stream ends when the client
+// disconnects or FTDC stops.
+func (svc *webService) handleFTDCStream(w http.ResponseWriter, r *http.Request) {
+	if svc.opts.ftdc == nil {
+		http.Error(w, "FTDC is not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	dataCh, unsubscribe := svc.opts.ftdc.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-dataCh:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(data); err != nil {
+				svc.logger.Debugw("error writing ftdc stream", "error", err)
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// requireAuth wraps an HTTP handler such that requests must carry an access token (in the
+// `Authorization` header) that the RPC server would accept, when the web service is configured
+// with authentication.
+func (svc *webService) requireAuth(options weboptions.Options, handler http.HandlerFunc) http.HandlerFunc {
+	if len(options.Auth.Handlers) == 0 {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := metadata.NewIncomingContext(r.Context(),
+			metadata.Pairs(rpc.MetadataFieldAuthorization, r.Header.Get("Authorization")))
+		authedCtx, err := svc.rpcServer.EnsureAuthed(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		handler(w, r.WithContext(authedCtx))
+	}
+}
+
+// handleJobResults serves the recent results of each job, oldest first, as a JSON object keyed by
+// job name.
+func (svc *webService) handleJobResults(w http.ResponseWriter, r *http.Request) {
+	mStatus, err := svc.r.MachineStatus(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	results := make(map[string][]robot.JobResult, len(mStatus.JobStatuses))
+	for jobName, jobStatus := range mStatus.JobStatuses {
+		results[jobName] = jobStatus.RecentResults
+	}
+	w.Header().Set("Content-Type", "application/json")
+	utils.UncheckedError(json.NewEncoder(w).Encode(results))
+}
+
+// requireAdmin wraps an HTTP handler such that requests must carry an access token (in the
+// `Authorization` header) that the RPC server would accept for one of the lease admin entities.
+func (svc *webService) requireAdmin(options weboptions.Options, handler http.HandlerFunc) http.HandlerFunc {
+	return svc.requireAuth(options, func(w http.ResponseWriter, r *http.Request) {
+		authEntity, ok := rpc.ContextAuthEntity(r.Context())
+		if !ok || !slices.Contains(options.LeaseAdminEntities, authEntity.Entity) {
+			http.Error(w, "not a lease administrator", http.StatusForbidden)
+			return
+		}
+		handler(w, r)
+	})
+}
+
+// handleOperationHistory serves the operations the robot recently completed as a JSON array,
+// oldest first.
+func (svc *webService) handleOperationHistory(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	utils.UncheckedError(json.NewEncoder(w).Encode(svc.r.OperationManager().History()))
+}
+
+// leaseManager returns the session manager of the robot if it supports resource leases, or writes
+// an error response and returns false if it doesn't.
+func (svc *webService) leaseManager(w http.ResponseWriter) (session.LeaseManager, bool) {
+	leaseMgr, ok := svc.r.SessionManager().(session.LeaseManager)
+	if !ok {
+		http.Error(w, "this machine does not support resource leases", http.StatusNotImplemented)
+		return nil, false
+	}
+	return leaseMgr, true
+}
+
+// handleLeases serves the session id holding each leased resource as a JSON object keyed by
+// resource name.
+func (svc *webService) handleLeases(w http.ResponseWriter, r *http.Request) {
+	leaseMgr, ok := svc.leaseManager(w)
+	if !ok {
+		return
+	}
+	leases := map[string]string{}
+	for resName, sessID := range leaseMgr.Leases() {
+		leases[resName.String()] = sessID.String()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	utils.UncheckedError(json.NewEncoder(w).Encode(leases))
+}
+
+// handleRevokeLease revokes the lease on the resource named by the `resource` query parameter.
+func (svc *webService) handleRevokeLease(w http.ResponseWriter, r *http.Request) {
+	leaseMgr, ok := svc.leaseManager(w)
+	if !ok {
+		return
+	}
+	resName, err := resource.NewFromString(r.URL.Query().Get("resource"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !leaseMgr.RevokeLease(resName) {
+		http.Error(w, fmt.Sprintf("%q is not leased", resName), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // Handles the `/restart_status` endpoint.
 func (svc *webService) handleRestartStatus(w http.ResponseWriter, r *http.Request) {
 	modAddrs := svc.ModuleAddresses()
@@ -5,6 +5,7 @@ package web
 import (
 	"context"
 
+	"go.viam.com/rdk/ftdc"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/utils/rpc"
 )
@@ -24,4 +25,7 @@ func (svc *webService) initStreamServer(_ context.Context, _ rpc.Server) error {
 }
 
 // stub for missing gostream
-type options struct{}
+type options struct {
+	// ftdc is the source of live diagnostics data for the FTDC stream endpoint.
+	ftdc *ftdc.FTDC
+}
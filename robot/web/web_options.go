@@ -1,5 +1,7 @@
 package web
 
+import "go.viam.com/rdk/ftdc"
+
 // Option configures how we set up the web service.
 // Cribbed from https://github.com/grpc/grpc-go/blob/aff571cc86e6e7e740130dbbb32a9741558db805/dialoptions.go#L41
 type Option interface {
@@ -21,3 +23,11 @@ func newFuncOption(f func(*options)) *funcOption {
 		f: f,
 	}
 }
+
+// WithFTDC returns an Option which sets the FTDC instance whose data is served live at
+// `/debug/ftdc/stream` when the FTDC stream web option is enabled.
+func WithFTDC(ftdc *ftdc.FTDC) Option {
+	return newFuncOption(func(o *options) {
+		o.ftdc = ftdc
+	})
+}
@@ -2,12 +2,18 @@
 
 package web
 
-import "go.viam.com/rdk/gostream"
+import (
+	"go.viam.com/rdk/ftdc"
+	"go.viam.com/rdk/gostream"
+)
 
 // options configures a web service.
 type options struct {
 	// streamConfig is used to enable audio/video streaming over WebRTC.
 	streamConfig *gostream.StreamConfig
+
+	// ftdc is the source of live diagnostics data for the FTDC stream endpoint.
+	ftdc *ftdc.FTDC
 }
 
 // WithStreamConfig returns an Option which sets the streamConfig
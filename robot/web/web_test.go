@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"sync"
@@ -25,11 +27,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
+	armpb "go.viam.com/api/component/arm/v1"
 	echopb "go.viam.com/api/component/testecho/v1"
 	robotpb "go.viam.com/api/robot/v1"
 	streampb "go.viam.com/api/stream/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils"
+	rpcpb "go.viam.com/utils/proto/rpc/v1"
 	"go.viam.com/utils/rpc"
 	"go.viam.com/utils/testutils"
 	"google.golang.org/grpc"
@@ -41,6 +45,7 @@ import (
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/config"
 	gizmopb "go.viam.com/rdk/examples/customresources/apis/proto/api/component/gizmo/v1"
+	"go.viam.com/rdk/ftdc"
 	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/gostream/codec/x264"
 	rgrpc "go.viam.com/rdk/grpc"
@@ -1864,3 +1869,308 @@ func TestPerResourceLimitsAndFTDC(t *testing.T) {
 		clientCallsWg.Wait()
 	})
 }
+
+type ftdcStreamStats struct {
+	Count int
+}
+
+type ftdcStreamStatser struct {
+	mu    sync.Mutex
+	count int
+}
+
+func (statser *ftdcStreamStatser) Stats() any {
+	statser.mu.Lock()
+	defer statser.mu.Unlock()
+	statser.count++
+	return ftdcStreamStats{Count: statser.count}
+}
+
+func TestFTDCStream(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	defer injectRobot.Close(ctx)
+
+	ftdcWorker := ftdc.New(t.TempDir(), logger.Sublogger("ftdc"))
+	ftdcWorker.Add("counter", &ftdcStreamStatser{})
+	ftdcWorker.Start()
+	defer ftdcWorker.StopAndJoin(ctx)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		svc := web.New(injectRobot, logger, web.WithFTDC(ftdcWorker))
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		resp, err := http.Get(fmt.Sprintf("http://%s/debug/ftdc/stream", addr))
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusNotFound)
+	})
+
+	t.Run("without ftdc", func(t *testing.T) {
+		svc := web.New(injectRobot, logger)
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		options.FTDCStream = true
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		resp, err := http.Get(fmt.Sprintf("http://%s/debug/ftdc/stream", addr))
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusServiceUnavailable)
+	})
+
+	t.Run("streams datums", func(t *testing.T) {
+		svc := web.New(injectRobot, logger, web.WithFTDC(ftdcWorker))
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		options.FTDCStream = true
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		resp, err := http.Get(fmt.Sprintf("http://%s/debug/ftdc/stream", addr))
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+
+		// FTDC records a datum every second. Read two datums off of the stream and stop.
+		errEnoughDatums := errors.New("read enough datums")
+		var datums []ftdc.FlatDatum
+		err = ftdc.ParseStream(resp.Body, logger, func(flatDatum ftdc.FlatDatum) error {
+			datums = append(datums, flatDatum)
+			if len(datums) == 2 {
+				return errEnoughDatums
+			}
+			return nil
+		})
+		test.That(t, err, test.ShouldEqual, errEnoughDatums)
+		test.That(t, datums[0].Readings[0].MetricName, test.ShouldEqual, "counter.Count")
+		test.That(t, datums[1].Readings[0].RawValue, test.ShouldEqual, datums[0].Readings[0].RawValue.(int64)+1)
+	})
+
+	t.Run("requires auth", func(t *testing.T) {
+		svc := web.New(injectRobot, logger, web.WithFTDC(ftdcWorker))
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		options.FTDCStream = true
+		apiKeyID := uuid.New().String()
+		apiKey := utils.RandomAlphaString(32)
+		options.Auth.Handlers = []config.AuthHandlerConfig{
+			{
+				Type: rpc.CredentialsTypeAPIKey,
+				Config: rutils.AttributeMap{
+					apiKeyID: apiKey,
+					"keys":   []string{apiKeyID},
+				},
+			},
+		}
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		resp, err := http.Get(fmt.Sprintf("http://%s/debug/ftdc/stream", addr))
+		test.That(t, err, test.ShouldBeNil)
+		utils.UncheckedError(resp.Body.Close())
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusUnauthorized)
+
+		conn, err := rpc.DialDirectGRPC(ctx, addr, logger, rpc.WithInsecure())
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(conn.Close)
+		authResp, err := rpcpb.NewAuthServiceClient(conn).Authenticate(ctx, &rpcpb.AuthenticateRequest{
+			Entity:      apiKeyID,
+			Credentials: &rpcpb.Credentials{Type: string(rpc.CredentialsTypeAPIKey), Payload: apiKey},
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/debug/ftdc/stream", addr), nil)
+		test.That(t, err, test.ShouldBeNil)
+		req.Header.Set("Authorization", rpc.AuthorizationValuePrefixBearer+authResp.GetAccessToken())
+		resp, err = http.DefaultClient.Do(req)
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	})
+}
+
+func TestJobResults(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	defer injectRobot.Close(ctx)
+	start := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC)
+	injectRobot.(*inject.Robot).MachineStatusFunc = func(ctx context.Context) (robot.MachineStatus, error) {
+		return robot.MachineStatus{
+			State: robot.StateRunning,
+			JobStatuses: map[string]robot.JobStatus{
+				"measure": {
+					RecentSuccessfulRuns: []time.Time{start},
+					RecentResults: []robot.JobResult{
+						{StartTime: start, Duration: time.Second, Attempts: 2, Response: map[string]any{"temp": 21.5}},
+					},
+				},
+			},
+		}, nil
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		svc := web.New(injectRobot, logger)
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		resp, err := http.Get(fmt.Sprintf("http://%s/jobs/results", addr))
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusNotFound)
+	})
+
+	svc := web.New(injectRobot, logger)
+	defer svc.Stop()
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.JobResults = true
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/jobs/results", addr))
+	test.That(t, err, test.ShouldBeNil)
+	defer utils.UncheckedErrorFunc(resp.Body.Close)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	var results map[string][]robot.JobResult
+	test.That(t, json.NewDecoder(resp.Body).Decode(&results), test.ShouldBeNil)
+	test.That(t, results, test.ShouldResemble, map[string][]robot.JobResult{
+		"measure": {{StartTime: start, Duration: time.Second, Attempts: 2, Response: map[string]any{"temp": 21.5}}},
+	})
+}
+
+func TestOperationHistory(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	defer injectRobot.Close(ctx)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		svc := web.New(injectRobot, logger)
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		resp, err := http.Get(fmt.Sprintf("http://%s/debug/operations/history", addr))
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusNotFound)
+	})
+
+	svc := web.New(injectRobot, logger)
+	defer svc.Stop()
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.OperationHistory = true
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	_, err := injectRobot.OperationManager().UnaryServerInterceptor(ctx, &armpb.StopRequest{Name: "arm1"},
+		&grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/Stop"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &armpb.StopResponse{}, nil
+		})
+	test.That(t, err, test.ShouldBeNil)
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/debug/operations/history", addr))
+	test.That(t, err, test.ShouldBeNil)
+	defer utils.UncheckedErrorFunc(resp.Body.Close)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	var history []map[string]interface{}
+	test.That(t, json.NewDecoder(resp.Body).Decode(&history), test.ShouldBeNil)
+	test.That(t, history, test.ShouldHaveLength, 1)
+	test.That(t, history[0]["method"], test.ShouldEqual, "/viam.component.arm.v1.ArmService/Stop")
+	test.That(t, history[0]["arguments"], test.ShouldResemble, map[string]interface{}{"name": "arm1"})
+}
+
+func TestLeaseAdmin(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+	defer injectRobot.Close(ctx)
+	sessMgr := robot.NewSessionManager(injectRobot, config.DefaultSessionHeartbeatWindow)
+	defer sessMgr.Close()
+	injectRobot.(*inject.Robot).SessMgr = sessMgr
+
+	sess, err := sessMgr.Start(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+	armName := arm.Named("arm1")
+	test.That(t, sessMgr.AcquireLease(sess.ID(), armName), test.ShouldBeNil)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		svc := web.New(injectRobot, logger)
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		resp, err := http.Post(fmt.Sprintf("http://%s/admin/leases/revoke?resource=%s", addr, url.QueryEscape(armName.String())), "", nil)
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusNotFound)
+		test.That(t, sessMgr.Leases(), test.ShouldHaveLength, 1)
+	})
+
+	t.Run("requires an admin", func(t *testing.T) {
+		svc := web.New(injectRobot, logger)
+		defer svc.Stop()
+		options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+		adminKeyID, adminKey := uuid.New().String(), utils.RandomAlphaString(32)
+		userKeyID, userKey := uuid.New().String(), utils.RandomAlphaString(32)
+		options.Auth.Handlers = []config.AuthHandlerConfig{
+			{
+				Type: rpc.CredentialsTypeAPIKey,
+				Config: rutils.AttributeMap{
+					adminKeyID: adminKey,
+					userKeyID:  userKey,
+					"keys":     []string{adminKeyID, userKeyID},
+				},
+			},
+		}
+		options.LeaseAdminEntities = []string{adminKeyID}
+		test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+		conn, err := rpc.DialDirectGRPC(ctx, addr, logger, rpc.WithInsecure())
+		test.That(t, err, test.ShouldBeNil)
+		defer utils.UncheckedErrorFunc(conn.Close)
+		accessToken := func(keyID, key string) string {
+			authResp, err := rpcpb.NewAuthServiceClient(conn).Authenticate(ctx, &rpcpb.AuthenticateRequest{
+				Entity:      keyID,
+				Credentials: &rpcpb.Credentials{Type: string(rpc.CredentialsTypeAPIKey), Payload: key},
+			})
+			test.That(t, err, test.ShouldBeNil)
+			return authResp.GetAccessToken()
+		}
+		do := func(method, path, token string) *http.Response {
+			req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("http://%s%s", addr, path), nil)
+			test.That(t, err, test.ShouldBeNil)
+			if token != "" {
+				req.Header.Set("Authorization", rpc.AuthorizationValuePrefixBearer+token)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			test.That(t, err, test.ShouldBeNil)
+			return resp
+		}
+		adminToken := accessToken(adminKeyID, adminKey)
+		revokePath := "/admin/leases/revoke?resource=" + url.QueryEscape(armName.String())
+
+		resp := do(http.MethodPost, revokePath, "")
+		utils.UncheckedError(resp.Body.Close())
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusUnauthorized)
+
+		resp = do(http.MethodPost, revokePath, accessToken(userKeyID, userKey))
+		utils.UncheckedError(resp.Body.Close())
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusForbidden)
+		test.That(t, sessMgr.Leases(), test.ShouldHaveLength, 1)
+
+		resp = do(http.MethodGet, "/admin/leases", adminToken)
+		defer utils.UncheckedErrorFunc(resp.Body.Close)
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+		var leases map[string]string
+		test.That(t, json.NewDecoder(resp.Body).Decode(&leases), test.ShouldBeNil)
+		test.That(t, leases, test.ShouldResemble, map[string]string{armName.String(): sess.ID().String()})
+
+		resp = do(http.MethodPost, revokePath, adminToken)
+		utils.UncheckedError(resp.Body.Close())
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusNoContent)
+		test.That(t, sessMgr.Leases(), test.ShouldBeEmpty)
+
+		resp = do(http.MethodPost, revokePath, adminToken)
+		utils.UncheckedError(resp.Body.Close())
+		test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusNotFound)
+	})
+}
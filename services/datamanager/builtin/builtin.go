@@ -12,6 +12,7 @@ package builtin
 import (
 	"context"
 	"errors"
+	"fmt"
 	"image"
 	"os"
 	"sync"
@@ -22,6 +23,7 @@ import (
 	"google.golang.org/grpc"
 
 	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -82,6 +84,7 @@ type builtIn struct {
 	logger logging.Logger
 
 	mu                sync.Mutex
+	captureDir        string
 	capture           *capture.Capture
 	sync              *datasync.Sync
 	diskSummaryLogger *diskSummaryLogger
@@ -188,6 +191,7 @@ func (b *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies, c
 	}
 
 	captureConfig := c.captureConfig(b.logger)
+	captureConfig.TriggerDependencies = deps
 	collectorConfigsByResource, err := lookupCollectorConfigsByResource(deps, conf, captureConfig.CaptureDir, b.logger)
 	if err != nil {
 		// If this error occurs it's a resource graph error
@@ -199,6 +203,8 @@ func (b *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies, c
 
 	syncSensor, syncSensorEnabled := syncSensorFromDeps(c.SelectiveSyncerName, deps, b.logger)
 	syncConfig := c.syncConfig(syncSensor, syncSensorEnabled, b.logger)
+	syncConfig.RetentionPolicies = retentionPolicies(collectorConfigsByResource, captureConfig.CaptureDir)
+	syncConfig.CollectorSinks = collectorSinks(collectorConfigsByResource, captureConfig.CaptureDir)
 
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -206,6 +212,7 @@ func (b *builtIn) Reconfigure(ctx context.Context, deps resource.Dependencies, c
 	// It is important that no errors happen for a given Reconfigure call after we being callin Reconfigure on capture & sync
 	// or we could leak goroutines, wasting resources and cauing bugs due to duplicate work.
 	b.diskSummaryLogger.reconfigure(syncConfig.SyncPaths(), diskSummaryLogInterval)
+	b.captureDir = captureConfig.CaptureDir
 	b.capture.Reconfigure(ctx, collectorConfigsByResource, captureConfig)
 	b.sync.Reconfigure(ctx, syncConfig, cloudConnSvc)
 
@@ -257,6 +264,40 @@ func lookupCollectorConfigsByResource(
 	return collectorConfigsByResource, nil
 }
 
+// DoCommand handles the datamanager.TriggerCaptureCommand, firing the triggers of the collectors
+// configured with the event, and the datamanager.QueryLocalDataCommand.
+func (b *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	query, _, isQuery, err := datamanager.LocalDataQueryFromCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if isQuery {
+		sensorData, err := b.queryLocalData(query)
+		if err != nil {
+			return nil, err
+		}
+		return datamanager.LocalDataQueryResponse(sensorData)
+	}
+
+	rawEvent, ok := cmd[datamanager.TriggerCaptureCommand]
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	event, ok := rawEvent.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected %s to be a string, got %T", datamanager.TriggerCaptureCommand, rawEvent)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return map[string]interface{}{"triggered_collectors": b.capture.TriggerEvent(event)}, nil
+}
+
+// SubscribeCaptureFileSealed sends every capture file this data manager completes to ch, until the
+// returned function is called.
+func (b *builtIn) SubscribeCaptureFileSealed(ch chan<- data.SealedCaptureFile) (unsubscribe func()) {
+	return b.capture.SubscribeCaptureFileSealed(ch)
+}
+
 // TODO (DATA-4528): Don't ignore the extra field in the UploadBinaryDataToDatasets request.
 func (b *builtIn) UploadBinaryDataToDatasets(ctx context.Context,
 	binaryData []byte,
@@ -289,3 +330,23 @@ func (b *builtIn) UploadImageToDatasets(ctx context.Context,
 	defer b.mu.Unlock()
 	return b.sync.UploadBinaryDataToDatasets(ctx, imgBytes, datasetIDs, tags, mimeType)
 }
+
+// queryLocalData reads the capture files of the collector selected by the query which have not yet
+// been synced and deleted.
+func (b *builtIn) queryLocalData(query datamanager.LocalDataQuery) ([]*v1.SensorData, error) {
+	b.logger.Debug("QueryLocalData START")
+	defer b.logger.Debug("QueryLocalData END")
+	// only hold the lock to read the capture directory, scanning it can take a while
+	b.mu.Lock()
+	captureDir := b.captureDir
+	b.mu.Unlock()
+	targetDir := capture.TargetDir(captureDir, datamanager.DataCaptureConfig{
+		Name:   query.Resource,
+		Method: query.Method,
+	})
+	return data.QueryCaptureDir(targetDir, data.CaptureQuery{
+		Start: query.Start,
+		End:   query.End,
+		Limit: query.Limit,
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
 	datasync "go.viam.com/rdk/services/datamanager/builtin/sync"
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/testutils/inject"
@@ -348,3 +349,55 @@ func waitForCaptureFilesToExceedNFiles(captureDir string, n int, logger logging.
 		}
 	}
 }
+
+func TestSubscribeCaptureFileSealed(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	r := setupRobot(nil, map[resource.Name]resource.Resource{
+		arm.Named("arm1"): &inject.Arm{
+			EndPositionFunc: func(
+				ctx context.Context,
+				extra map[string]interface{},
+			) (spatialmath.Pose, error) {
+				return spatialmath.NewZeroPose(), nil
+			},
+		},
+	})
+
+	// each data manager only sends the files it captured itself to its subscribers
+	var captureDirs []string
+	var sealedChs []chan data.SealedCaptureFile
+	for i := 0; i < 2; i++ {
+		config, deps := setupConfig(t, r, enabledTabularCollectorConfigPath)
+		c := config.ConvertedAttributes.(*Config)
+		c.CaptureDisabled = false
+		c.ScheduledSyncDisabled = true
+		c.CaptureDir = t.TempDir()
+		// every reading is its own capture file, so files are sealed as they are captured
+		c.MaximumCaptureFileSizeBytes = 1
+
+		b, err := New(context.Background(), deps, config, datasync.NoOpCloudClientConstructor, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, b.Close(context.Background()), test.ShouldBeNil)
+		}()
+		subscriber, ok := b.(datamanager.CaptureFileSealedSubscriber)
+		test.That(t, ok, test.ShouldBeTrue)
+		sealedCh := make(chan data.SealedCaptureFile, 10)
+		defer subscriber.SubscribeCaptureFileSealed(sealedCh)()
+		captureDirs = append(captureDirs, c.CaptureDir)
+		sealedChs = append(sealedChs, sealedCh)
+	}
+
+	for i, sealedCh := range sealedChs {
+		for j := 0; j < 3; j++ {
+			select {
+			case sealed := <-sealedCh:
+				test.That(t, sealed.Path, test.ShouldStartWith, captureDirs[i])
+				test.That(t, filepath.Ext(sealed.Path), test.ShouldEqual, data.CompletedCaptureFileExt)
+				test.That(t, sealed.Metadata.GetComponentName(), test.ShouldEqual, "arm1")
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for a sealed capture file")
+			}
+		}
+	}
+}
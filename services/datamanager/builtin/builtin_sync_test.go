@@ -544,13 +544,12 @@ func TestDataCaptureUploadIntegration(t *testing.T) {
 
 func TestArbitraryFileUpload(t *testing.T) {
 	logger := logging.NewTestLogger(t)
-	fileName := "some_file_name.txt"
-	fileExt := ".txt"
 	emptyFileTestName := "error due to empty file, local files should not be deleted"
 	// Disable the check to see if the file was modified recently,
 	// since we are testing instanteous arbitrary file uploads.
 	tests := []struct {
 		name                 string
+		fileName             string
 		manualSync           bool
 		scheduleSyncDisabled bool
 		uploadToDataset      bool
@@ -604,10 +603,22 @@ func TestArbitraryFileUpload(t *testing.T) {
 			uploadToDataset:      true,
 			connStateConstructor: NoOpClientConnReady,
 		},
+		{
+			name:                 "index files in additional sync paths should be uploaded, not removed as capture indexes",
+			fileName:             "some_file_name.idx",
+			manualSync:           true,
+			scheduleSyncDisabled: true,
+			connStateConstructor: NoOpClientConnReady,
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
+			fileName := "some_file_name.txt"
+			if tc.fileName != "" {
+				fileName = tc.fileName
+			}
+			fileExt := filepath.Ext(fileName)
 			additionalPathsDir := t.TempDir()
 			captureDir := t.TempDir()
 
@@ -207,6 +207,24 @@ func TestNew(t *testing.T) {
 	})
 }
 
+func TestQueryLocalDataCommand(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	b, closeFunc := builtinWithEmptyConfig(t, logger)
+	defer closeFunc()
+
+	// no data has been captured for the arm yet
+	query := datamanager.LocalDataQuery{Resource: arm.Named("arm1"), Method: "EndPosition"}
+	sensorData, err := datamanager.QueryLocalData(context.Background(), b, query, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sensorData, test.ShouldBeEmpty)
+
+	_, err = b.DoCommand(context.Background(), map[string]interface{}{datamanager.QueryLocalDataCommand: "arm1"})
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = b.DoCommand(context.Background(), map[string]interface{}{"unknown": true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
+
 func TestReconfigure(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	b, closeFunc := builtinWithEmptyConfig(t, logger)
@@ -909,6 +927,10 @@ func getAllFiles(dir string) ([]os.FileInfo, []string) {
 			//nolint:nilerr
 			return nil
 		}
+		if data.IsCaptureIndexFile(path) {
+			// capture file indexes are not data
+			return nil
+		}
 		fileInfos = append(fileInfos, info)
 		filePaths = append(filePaths, path)
 		return nil
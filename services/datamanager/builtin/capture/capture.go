@@ -64,6 +64,10 @@ type Capture struct {
 	maxCaptureFileSize int64
 	mongoMU            sync.Mutex
 	mongo              captureMongo
+
+	sealedMu          sync.Mutex
+	sealedSubscribers map[int]chan<- data.SealedCaptureFile
+	nextSealedID      int
 }
 
 type captureMongo struct {
@@ -86,6 +90,8 @@ type collectorAndConfig struct {
 	Resource  resource.Resource
 	Collector data.Collector
 	Config    datamanager.DataCaptureConfig
+	// TriggerSource holds the resources the collector's trigger depends on, if it has one.
+	TriggerSource triggerSource
 }
 
 // Identifier for a particular collector: component name, component model, component type,
@@ -96,9 +102,10 @@ func New(
 	logger logging.Logger,
 ) *Capture {
 	return &Capture{
-		clk:        clock,
-		logger:     logger,
-		collectors: collectors{},
+		clk:               clock,
+		logger:            logger,
+		collectors:        collectors{},
+		sealedSubscribers: map[int]chan<- data.SealedCaptureFile{},
 	}
 }
 
@@ -255,6 +262,33 @@ func (c *Capture) Close(ctx context.Context) {
 	}
 }
 
+// SubscribeCaptureFileSealed sends every capture file the collectors complete from now on to ch,
+// until the returned function is called. Sends don't block the capture, so files are dropped when
+// ch is full and the subscriber should drain it promptly.
+func (c *Capture) SubscribeCaptureFileSealed(ch chan<- data.SealedCaptureFile) (unsubscribe func()) {
+	c.sealedMu.Lock()
+	defer c.sealedMu.Unlock()
+	id := c.nextSealedID
+	c.nextSealedID++
+	c.sealedSubscribers[id] = ch
+	return func() {
+		c.sealedMu.Lock()
+		defer c.sealedMu.Unlock()
+		delete(c.sealedSubscribers, id)
+	}
+}
+
+func (c *Capture) notifyCaptureFileSealed(sealed data.SealedCaptureFile) {
+	c.sealedMu.Lock()
+	defer c.sealedMu.Unlock()
+	for _, ch := range c.sealedSubscribers {
+		select {
+		case ch <- sealed:
+		default:
+		}
+	}
+}
+
 // closeNoMongoMutex exists for cases when we need to perform close actions in a function
 // which is already holding the mongoMu.
 func (c *Capture) closeNoMongoMutex(ctx context.Context) {
@@ -282,10 +316,21 @@ func (c *Capture) initializeOrUpdateCollector(
 		return nil, err
 	}
 
+	var source triggerSource
+	if collectorConfig.Trigger != nil {
+		if err := collectorConfig.Trigger.Validate(); err != nil {
+			return nil, errors.Wrap(err, "invalid trigger")
+		}
+		if source, err = newTriggerSource(collectorConfig.Trigger, config.TriggerDependencies); err != nil {
+			return nil, err
+		}
+	}
+
 	maxFileSizeChanged := c.maxCaptureFileSize != config.MaximumCaptureFileSizeBytes
 	if storedCollectorAndConfig, ok := c.collectors[md]; ok {
 		if storedCollectorAndConfig.Config.Equals(&collectorConfig) &&
 			res == storedCollectorAndConfig.Resource &&
+			source == storedCollectorAndConfig.TriggerSource &&
 			!maxFileSizeChanged {
 			// If the attributes have not changed, do nothing and leave the existing collector.
 			return c.collectors[md], nil
@@ -318,7 +363,7 @@ func (c *Capture) initializeOrUpdateCollector(
 		}
 	}
 
-	targetDir := targetDir(config.CaptureDir, collectorConfig)
+	targetDir := TargetDir(config.CaptureDir, collectorConfig)
 	// Create a collector for this resource and method.
 	if err := os.MkdirAll(targetDir, 0o700); err != nil {
 		return nil, errors.Wrapf(err, "failed to create target directory %s with 700 file permissions", targetDir)
@@ -335,6 +380,12 @@ func (c *Capture) initializeOrUpdateCollector(
 	// Parameters to initialize collector.
 	queueSize := defaultIfZeroVal(collectorConfig.CaptureQueueSize, defaultCaptureQueueSize)
 	bufferSize := defaultIfZeroVal(collectorConfig.CaptureBufferSize, defaultCaptureBufferSize)
+	var gate *data.CaptureGate
+	if collectorConfig.Trigger != nil {
+		gate = newTriggerGate(collectorConfig.Trigger, c.clk)
+	}
+	target := data.NewCaptureBuffer(targetDir, captureMetadata, config.MaximumCaptureFileSizeBytes)
+	target.NotifySealed = c.notifyCaptureFileSealed
 	collector, err := collectorConstructor(res, data.CollectorParams{
 		MongoCollection: collection,
 		DataType:        dataType,
@@ -343,23 +394,35 @@ func (c *Capture) initializeOrUpdateCollector(
 		MethodName:      collectorConfig.Method,
 		Interval:        data.GetDurationFromHz(collectorConfig.CaptureFrequencyHz),
 		MethodParams:    methodParams,
-		Target:          data.NewCaptureBuffer(targetDir, captureMetadata, config.MaximumCaptureFileSizeBytes),
+		Target:          target,
 		// Set queue size to defaultCaptureQueueSize if it was not set in the config.
 		QueueSize:  queueSize,
 		BufferSize: bufferSize,
 		Logger:     c.logger,
 		Clock:      c.clk,
+		Gate:       gate,
 	})
 	if err != nil {
 		return nil, errors.Wrapf(err, "constructor for collector %s failed with config: %s",
 			md, collectorConfigDescription(collectorConfig, targetDir, config.MaximumCaptureFileSizeBytes, queueSize, bufferSize))
 	}
 
+	if collectorConfig.Trigger != nil {
+		collector = &triggeredCollector{
+			Collector: collector,
+			trigger:   *collectorConfig.Trigger,
+			source:    source,
+			gate:      gate,
+			clk:       c.clk,
+			logger:    c.logger,
+		}
+	}
+
 	c.logger.Infof("collector initialized; collector: %s, config: %s",
 		md, collectorConfigDescription(collectorConfig, targetDir, config.MaximumCaptureFileSizeBytes, queueSize, bufferSize))
 	collector.Collect()
 
-	return &collectorAndConfig{res, collector, collectorConfig}, nil
+	return &collectorAndConfig{res, collector, collectorConfig, source}, nil
 }
 
 func collectorConfigDescription(
@@ -369,14 +432,19 @@ func collectorConfigDescription(
 	queueSize,
 	bufferSize int,
 ) string {
+	var trigger datamanager.CaptureTriggerType
+	if collectorConfig.Trigger != nil {
+		trigger = collectorConfig.Trigger.Type
+	}
 	return fmt.Sprintf("[CaptureFrequencyHz: %f, Tags: %v, MaximumCaptureFileSize: %s, "+
-		"CaptureBufferQueueSize: %d, CaptureBufferSize: %d, TargetDir: %s]",
+		"CaptureBufferQueueSize: %d, CaptureBufferSize: %d, TargetDir: %s, Trigger: %s]",
 		collectorConfig.CaptureFrequencyHz, collectorConfig.Tags, data.FormatBytesI64(maximumCaptureFileSizeBytes),
-		queueSize, bufferSize, targetDir,
+		queueSize, bufferSize, targetDir, trigger,
 	)
 }
 
-func targetDir(captureDir string, collectorConfig datamanager.DataCaptureConfig) string {
+// TargetDir returns the directory the collector described by collectorConfig writes its capture files to.
+func TargetDir(captureDir string, collectorConfig datamanager.DataCaptureConfig) string {
 	return data.CaptureFilePathWithReplacedReservedChars(
 		filepath.Join(captureDir, collectorConfig.Name.API.String(),
 			collectorConfig.Name.ShortName(), collectorConfig.Method))
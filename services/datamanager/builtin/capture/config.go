@@ -1,5 +1,7 @@
 package capture
 
+import "go.viam.com/rdk/resource"
+
 // MongoConfig is the optional data capture mongo config.
 type MongoConfig struct {
 	URI        string `json:"uri"`
@@ -26,4 +28,6 @@ type Config struct {
 	MaximumCaptureFileSizeBytes int64
 
 	MongoConfig *MongoConfig
+	// TriggerDependencies are used to look up the resources that capture triggers depend on.
+	TriggerDependencies resource.Dependencies
 }
@@ -0,0 +1,219 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/vision"
+)
+
+// defaultTriggerCheckFrequencyHz is how often trigger conditions are checked when
+// check_frequency_hz is not set.
+const defaultTriggerCheckFrequencyHz = 1.0
+
+// triggerSource holds the resources the condition of a capture trigger depends on.
+type triggerSource struct {
+	sensor sensor.Sensor
+	vision vision.Service
+}
+
+func newTriggerSource(trigger *datamanager.CaptureTrigger, deps resource.Dependencies) (triggerSource, error) {
+	switch trigger.Type {
+	case datamanager.CaptureTriggerSensorThreshold:
+		s, err := sensor.FromProvider(deps, trigger.Sensor)
+		if err != nil {
+			return triggerSource{}, errors.Wrapf(err, "failed to find trigger sensor %s", trigger.Sensor)
+		}
+		return triggerSource{sensor: s}, nil
+	case datamanager.CaptureTriggerDetection:
+		v, err := vision.FromProvider(deps, trigger.VisionService)
+		if err != nil {
+			return triggerSource{}, errors.Wrapf(err, "failed to find trigger vision service %s", trigger.VisionService)
+		}
+		return triggerSource{vision: v}, nil
+	case datamanager.CaptureTriggerEvent:
+	}
+	return triggerSource{}, nil
+}
+
+// conditionMet returns true if the condition of a sensor_threshold or detection trigger is met.
+func (s triggerSource) conditionMet(ctx context.Context, trigger *datamanager.CaptureTrigger) (bool, error) {
+	switch trigger.Type {
+	case datamanager.CaptureTriggerSensorThreshold:
+		readings, err := s.sensor.Readings(ctx, data.FromDMExtraMap)
+		if err != nil {
+			return false, err
+		}
+		value, err := readingValue(readings, trigger.Key)
+		if err != nil {
+			return false, err
+		}
+		return (trigger.Above != nil && value > *trigger.Above) || (trigger.Below != nil && value < *trigger.Below), nil
+	case datamanager.CaptureTriggerDetection:
+		detections, err := s.vision.DetectionsFromCamera(ctx, trigger.Camera, data.FromDMExtraMap)
+		if err != nil {
+			return false, err
+		}
+		for _, detection := range detections {
+			if detection.Label() == trigger.Label && detection.Score() >= trigger.MinConfidence {
+				return true, nil
+			}
+		}
+		return false, nil
+	case datamanager.CaptureTriggerEvent:
+	}
+	return false, nil
+}
+
+// readingValue returns the numeric reading at key, where dots separate the keys of nested readings.
+func readingValue(readings map[string]interface{}, key string) (float64, error) {
+	var value interface{} = readings
+	for _, part := range strings.Split(key, ".") {
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return 0, fmt.Errorf("reading %q not found", key)
+		}
+		if value, ok = nested[part]; !ok {
+			return 0, fmt.Errorf("reading %q not found", key)
+		}
+	}
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	case int:
+		return float64(v), nil
+	case int32:
+		return float64(v), nil
+	case int64:
+		return float64(v), nil
+	case uint32:
+		return float64(v), nil
+	case uint64:
+		return float64(v), nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("reading %q is a %T, not a number", key, value)
+	}
+}
+
+// triggeredCollector is a collector which only writes the data captured around the moments its
+// trigger fires.
+type triggeredCollector struct {
+	data.Collector
+	trigger datamanager.CaptureTrigger
+	source  triggerSource
+	gate    *data.CaptureGate
+	clk     clock.Clock
+	logger  logging.Logger
+	workers *goutils.StoppableWorkers
+}
+
+// newTriggerGate returns the gate limiting the writes of a collector with trigger.
+func newTriggerGate(trigger *datamanager.CaptureTrigger, clk clock.Clock) *data.CaptureGate {
+	preWindow := time.Duration(trigger.PreSeconds * float64(time.Second))
+	postWindow := time.Duration(trigger.PostSeconds * float64(time.Second))
+	if trigger.Type != datamanager.CaptureTriggerEvent {
+		// The gate is reopened on every check which finds the condition met. Keep it open until the
+		// next check so that data is captured for as long as the condition is met.
+		postWindow += triggerCheckInterval(trigger)
+	}
+	return data.NewCaptureGate(preWindow, postWindow, clk)
+}
+
+func triggerCheckInterval(trigger *datamanager.CaptureTrigger) time.Duration {
+	checkFrequencyHz := trigger.CheckFrequencyHz
+	if checkFrequencyHz == 0 {
+		checkFrequencyHz = defaultTriggerCheckFrequencyHz
+	}
+	return time.Duration(float64(time.Second) / checkFrequencyHz)
+}
+
+// Collect starts the collector and, for triggers with a condition, checking the condition.
+func (tc *triggeredCollector) Collect() {
+	tc.Collector.Collect()
+	if tc.trigger.Type == datamanager.CaptureTriggerEvent {
+		return
+	}
+	// The ticker must be created before Collect returns, so that tests advancing a mock clock
+	// afterwards are guaranteed to trigger a check.
+	ticker := tc.clk.Ticker(triggerCheckInterval(&tc.trigger))
+	tc.workers = goutils.NewBackgroundStoppableWorkers(func(ctx context.Context) {
+		defer ticker.Stop()
+		tc.checkCondition(ctx, ticker)
+	})
+}
+
+func (tc *triggeredCollector) checkCondition(ctx context.Context, ticker *clock.Ticker) {
+	var lastErr string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		met, err := tc.source.conditionMet(ctx, &tc.trigger)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			// Only log when the error changes to not log on every check.
+			if err.Error() != lastErr {
+				tc.logger.Warnw("failed to check capture trigger condition", "trigger", tc.trigger.Type, "error", err)
+				lastErr = err.Error()
+			}
+			continue
+		}
+		lastErr = ""
+		if met {
+			tc.gate.Open()
+		}
+	}
+}
+
+// Close stops checking the trigger condition and closes the collector.
+func (tc *triggeredCollector) Close() {
+	if tc.workers != nil {
+		tc.workers.Stop()
+	}
+	tc.Collector.Close()
+}
+
+// TriggerEvent fires the event triggers of the collectors configured with event. It returns the
+// number of collectors triggered.
+func (c *Capture) TriggerEvent(event string) int {
+	c.collectorsMu.Lock()
+	defer c.collectorsMu.Unlock()
+	var triggered int
+	for md, collectorAndConfig := range c.collectors {
+		trigger := collectorAndConfig.Config.Trigger
+		if trigger == nil || trigger.Type != datamanager.CaptureTriggerEvent || trigger.Event != event {
+			continue
+		}
+		tc, ok := collectorAndConfig.Collector.(*triggeredCollector)
+		if !ok {
+			continue
+		}
+		c.logger.Debugf("%s capture triggered by event %s", md, event)
+		tc.gate.Open()
+		triggered++
+	}
+	return triggered
+}
@@ -0,0 +1,222 @@
+package capture
+
+import (
+	"context"
+	"image"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestReadingValue(t *testing.T) {
+	readings := map[string]interface{}{
+		"temp":   21.5,
+		"count":  int64(3),
+		"open":   true,
+		"status": "ok",
+		"imu":    map[string]interface{}{"accel": map[string]interface{}{"x": float32(9.5)}},
+	}
+
+	value, err := readingValue(readings, "temp")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, value, test.ShouldEqual, 21.5)
+	value, err = readingValue(readings, "count")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, value, test.ShouldEqual, 3)
+	value, err = readingValue(readings, "open")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, value, test.ShouldEqual, 1)
+	value, err = readingValue(readings, "imu.accel.x")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, value, test.ShouldEqual, 9.5)
+
+	_, err = readingValue(readings, "status")
+	test.That(t, err, test.ShouldBeError, `reading "status" is a string, not a number`)
+	_, err = readingValue(readings, "imu.gyro.x")
+	test.That(t, err, test.ShouldBeError, `reading "imu.gyro.x" not found`)
+	_, err = readingValue(readings, "temp.celsius")
+	test.That(t, err, test.ShouldBeError, `reading "temp.celsius" not found`)
+}
+
+func TestTriggerConditionMet(t *testing.T) {
+	ctx := context.Background()
+	threshold := 30.0
+	var temp atomic.Value
+	temp.Store(20.0)
+	injectSensor := inject.NewSensor("thermometer")
+	injectSensor.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": temp.Load()}, nil
+	}
+	injectVision := inject.NewVisionService("detector")
+	injectVision.DetectionsFromCameraFunc = func(
+		ctx context.Context, cameraName string, extra map[string]interface{},
+	) ([]objectdetection.Detection, error) {
+		return []objectdetection.Detection{
+			objectdetection.NewDetectionWithoutImgBounds(image.Rect(0, 0, 10, 10), 0.9, "cat"),
+			objectdetection.NewDetectionWithoutImgBounds(image.Rect(0, 0, 10, 10), 0.4, "person"),
+		}, nil
+	}
+	deps := resource.Dependencies{
+		sensor.Named("thermometer"): injectSensor,
+		vision.Named("detector"):    injectVision,
+	}
+
+	sensorTrigger := &datamanager.CaptureTrigger{
+		Type:   datamanager.CaptureTriggerSensorThreshold,
+		Sensor: "thermometer",
+		Key:    "temp",
+		Above:  &threshold,
+	}
+	source, err := newTriggerSource(sensorTrigger, deps)
+	test.That(t, err, test.ShouldBeNil)
+	met, err := source.conditionMet(ctx, sensorTrigger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, met, test.ShouldBeFalse)
+	temp.Store(35.0)
+	met, err = source.conditionMet(ctx, sensorTrigger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, met, test.ShouldBeTrue)
+
+	detectionTrigger := &datamanager.CaptureTrigger{
+		Type:          datamanager.CaptureTriggerDetection,
+		VisionService: "detector",
+		Camera:        "cam",
+		Label:         "person",
+		MinConfidence: 0.5,
+	}
+	source, err = newTriggerSource(detectionTrigger, deps)
+	test.That(t, err, test.ShouldBeNil)
+	met, err = source.conditionMet(ctx, detectionTrigger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, met, test.ShouldBeFalse)
+	detectionTrigger.Label = "cat"
+	met, err = source.conditionMet(ctx, detectionTrigger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, met, test.ShouldBeTrue)
+
+	_, err = newTriggerSource(&datamanager.CaptureTrigger{
+		Type: datamanager.CaptureTriggerDetection, VisionService: "missing", Camera: "cam", Label: "cat",
+	}, deps)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestTriggeredCapture(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	mockClock := clock.NewMock()
+	captureDir := t.TempDir()
+
+	var temp atomic.Value
+	temp.Store(20.0)
+	thermometer := inject.NewSensor("thermometer")
+	thermometer.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"temp": temp.Load()}, nil
+	}
+	doorCam := inject.NewSensor("door")
+	doorCam.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"open": true}, nil
+	}
+
+	threshold := 30.0
+	thresholdConfig := datamanager.DataCaptureConfig{
+		Name:               thermometer.Name(),
+		Method:             "Readings",
+		CaptureFrequencyHz: 10,
+		Trigger: &datamanager.CaptureTrigger{
+			Type:             datamanager.CaptureTriggerSensorThreshold,
+			Sensor:           "thermometer",
+			Key:              "temp",
+			Above:            &threshold,
+			CheckFrequencyHz: 10,
+		},
+	}
+	eventConfig := datamanager.DataCaptureConfig{
+		Name:               doorCam.Name(),
+		Method:             "Readings",
+		CaptureFrequencyHz: 10,
+		Trigger: &datamanager.CaptureTrigger{
+			Type:        datamanager.CaptureTriggerEvent,
+			Event:       "door_opened",
+			PostSeconds: 1,
+		},
+	}
+
+	c := New(mockClock, logger)
+	defer c.Close(ctx)
+	c.Reconfigure(ctx, CollectorConfigsByResource{
+		thermometer: {thresholdConfig},
+		doorCam:     {eventConfig},
+	}, Config{
+		CaptureDir:                  captureDir,
+		MaximumCaptureFileSizeBytes: 1024 * 1024,
+		TriggerDependencies:         resource.Dependencies{thermometer.Name(): thermometer},
+	})
+
+	capturedReadings := func(collectorConfig datamanager.DataCaptureConfig) int {
+		c.FlushCollectors()
+		sensorData, err := data.QueryCaptureDir(TargetDir(captureDir, collectorConfig), data.CaptureQuery{})
+		test.That(t, err, test.ShouldBeNil)
+		return len(sensorData)
+	}
+	waitForReadings := func(collectorConfig datamanager.DataCaptureConfig) {
+		for attempt := 0; capturedReadings(collectorConfig) == 0; attempt++ {
+			test.That(t, attempt, test.ShouldBeLessThan, 1000)
+			mockClock.Add(100 * time.Millisecond)
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// Nothing is captured until the triggers fire.
+	for i := 0; i < 10; i++ {
+		mockClock.Add(100 * time.Millisecond)
+		time.Sleep(time.Millisecond)
+	}
+	test.That(t, capturedReadings(thresholdConfig), test.ShouldEqual, 0)
+	test.That(t, capturedReadings(eventConfig), test.ShouldEqual, 0)
+
+	temp.Store(35.0)
+	waitForReadings(thresholdConfig)
+	test.That(t, capturedReadings(eventConfig), test.ShouldEqual, 0)
+
+	test.That(t, c.TriggerEvent("door_closed"), test.ShouldEqual, 0)
+	test.That(t, c.TriggerEvent("door_opened"), test.ShouldEqual, 1)
+	waitForReadings(eventConfig)
+}
+
+func TestTriggerValidate(t *testing.T) {
+	above := 1.0
+	for _, tc := range []struct {
+		trigger datamanager.CaptureTrigger
+		err     string
+	}{
+		{datamanager.CaptureTrigger{Type: "timer"}, `trigger type "timer" is not one of`},
+		{datamanager.CaptureTrigger{Type: datamanager.CaptureTriggerSensorThreshold, Sensor: "s", Key: "k"}, "requires above, below or both"},
+		{datamanager.CaptureTrigger{Type: datamanager.CaptureTriggerDetection, VisionService: "v", Label: "cat"}, "requires a vision_service"},
+		{datamanager.CaptureTrigger{Type: datamanager.CaptureTriggerEvent, Event: "e"}, "requires pre_seconds, post_seconds or both"},
+		{datamanager.CaptureTrigger{
+			Type: datamanager.CaptureTriggerSensorThreshold, Sensor: "s", Key: "k", Above: &above, PreSeconds: -1,
+		}, "can't be negative"},
+		{datamanager.CaptureTrigger{Type: datamanager.CaptureTriggerSensorThreshold, Sensor: "s", Key: "k", Above: &above}, ""},
+		{datamanager.CaptureTrigger{Type: datamanager.CaptureTriggerDetection, VisionService: "v", Camera: "c", Label: "cat"}, ""},
+	} {
+		err := tc.trigger.Validate()
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+}
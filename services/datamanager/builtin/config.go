@@ -2,6 +2,7 @@ package builtin
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -10,6 +11,8 @@ import (
 	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/services/datamanager/builtin/capture"
 	"go.viam.com/rdk/services/datamanager/builtin/shared"
 	datasync "go.viam.com/rdk/services/datamanager/builtin/sync"
@@ -63,6 +66,8 @@ type Config struct {
 	ScheduledSyncDisabled  bool     `json:"sync_disabled"`
 	SelectiveSyncerName    string   `json:"selective_syncer_name"`
 	SyncIntervalMins       float64  `json:"sync_interval_mins"`
+	// SyncSinks are the destinations other than the Viam cloud that collectors can sync to.
+	SyncSinks []datasync.SinkConfig `json:"sync_sinks"`
 }
 
 // Validate returns components which will be depended upon weakly due to the above matcher.
@@ -88,9 +93,51 @@ func (c *Config) Validate(path string) ([]string, []string, error) {
 	if c.CaptureDirDeletionThreshold < 0 {
 		return nil, nil, errors.New("capture_dir_deletion_threshold can't be negative")
 	}
+	sinkNames := map[string]bool{}
+	for _, sink := range c.SyncSinks {
+		if err := sink.Validate(); err != nil {
+			return nil, nil, err
+		}
+		if sinkNames[sink.Name] {
+			return nil, nil, fmt.Errorf("sync sink name %q is used more than once", sink.Name)
+		}
+		sinkNames[sink.Name] = true
+	}
 	return []string{cloud.InternalServiceName.String()}, nil, nil
 }
 
+// ValidateAssociated returns an error if a collector associated with the data manager has an
+// invalid retention policy or trigger, or syncs to a sink that isn't defined in sync_sinks.
+func (c *Config) ValidateAssociated(path string, associated map[resource.Name]resource.AssociatedConfig) error {
+	sinkNames := map[string]bool{}
+	for _, sink := range c.SyncSinks {
+		sinkNames[sink.Name] = true
+	}
+	for _, assocConfig := range associated {
+		captureConfig, ok := assocConfig.(*datamanager.AssociatedConfig)
+		if !ok {
+			continue
+		}
+		for _, collectorConfig := range captureConfig.CaptureMethods {
+			if collectorConfig.Retention != nil {
+				if err := collectorConfig.Retention.Validate(); err != nil {
+					return fmt.Errorf("%s: collector %s %s: %w", path, collectorConfig.Name, collectorConfig.Method, err)
+				}
+			}
+			if collectorConfig.Trigger != nil {
+				if err := collectorConfig.Trigger.Validate(); err != nil {
+					return fmt.Errorf("%s: collector %s %s: %w", path, collectorConfig.Name, collectorConfig.Method, err)
+				}
+			}
+			if collectorConfig.SyncSink != "" && !sinkNames[collectorConfig.SyncSink] {
+				return fmt.Errorf("%s: collector %s %s: sync sink %q is not defined in sync_sinks",
+					path, collectorConfig.Name, collectorConfig.Method, collectorConfig.SyncSink)
+			}
+		}
+	}
+	return nil
+}
+
 func (c *Config) getCaptureDir(logger logging.Logger) string {
 	captureDir := shared.ViamCaptureDotDir
 	if c.CaptureDir != "" {
@@ -159,6 +206,14 @@ func (c *Config) syncConfig(syncSensor sensor.Sensor, syncSensorEnabled bool, lo
 			c.SyncIntervalMins, syncIntervalMinsEpsilon, defaultSyncIntervalMins)
 	}
 
+	var sinks map[string]datasync.SinkConfig
+	for _, sink := range c.SyncSinks {
+		if sinks == nil {
+			sinks = map[string]datasync.SinkConfig{}
+		}
+		sinks[sink.Name] = sink
+	}
+
 	return datasync.Config{
 		AdditionalSyncPaths:         c.AdditionalSyncPaths,
 		Tags:                        c.Tags,
@@ -174,5 +229,46 @@ func (c *Config) syncConfig(syncSensor sensor.Sensor, syncSensorEnabled bool, lo
 		SyncIntervalMins:            syncIntervalMins,
 		SelectiveSyncSensor:         syncSensor,
 		SelectiveSyncSensorEnabled:  syncSensorEnabled,
+		Sinks:                       sinks,
+	}
+}
+
+// retentionPolicies returns the retention policies of the configured collectors keyed by
+// the directory each collector writes its capture files to. Policies have already been validated
+// by ValidateAssociated.
+func retentionPolicies(
+	collectorConfigsByResource capture.CollectorConfigsByResource,
+	captureDir string,
+) map[string]datamanager.RetentionPolicy {
+	var policies map[string]datamanager.RetentionPolicy
+	for _, collectorConfigs := range collectorConfigsByResource {
+		for _, collectorConfig := range collectorConfigs {
+			if collectorConfig.Retention == nil || collectorConfig.Disabled {
+				continue
+			}
+			if policies == nil {
+				policies = map[string]datamanager.RetentionPolicy{}
+			}
+			policies[capture.TargetDir(captureDir, collectorConfig)] = *collectorConfig.Retention
+		}
+	}
+	return policies
+}
+
+// collectorSinks returns the name of the sync sink of the configured collectors that have one,
+// keyed by the directory each collector writes its capture files to.
+func collectorSinks(collectorConfigsByResource capture.CollectorConfigsByResource, captureDir string) map[string]string {
+	var sinks map[string]string
+	for _, collectorConfigs := range collectorConfigsByResource {
+		for _, collectorConfig := range collectorConfigs {
+			if collectorConfig.SyncSink == "" {
+				continue
+			}
+			if sinks == nil {
+				sinks = map[string]string{}
+			}
+			sinks[capture.TargetDir(captureDir, collectorConfig)] = collectorConfig.SyncSink
+		}
 	}
+	return sinks
 }
@@ -9,8 +9,11 @@ import (
 
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/services/datamanager/builtin/capture"
 	"go.viam.com/rdk/services/datamanager/builtin/shared"
 	"go.viam.com/rdk/services/datamanager/builtin/sync"
@@ -84,6 +87,19 @@ func TestConfig(t *testing.T) {
 				config: Config{CaptureDirDeletionThreshold: -1},
 				err:    errors.New("capture_dir_deletion_threshold can't be negative"),
 			},
+			{
+				name:   "returns an error if a sync sink is invalid",
+				config: Config{SyncSinks: []sync.SinkConfig{{Name: "mirror", Type: sync.SinkTypeLocalDir}}},
+				err:    errors.New(`sync sink "mirror": path is required for type "local_dir"`),
+			},
+			{
+				name: "returns an error if sync sink names are not unique",
+				config: Config{SyncSinks: []sync.SinkConfig{
+					{Name: "mirror", Type: sync.SinkTypeLocalDir, Path: "/mnt/a"},
+					{Name: "mirror", Type: sync.SinkTypeLocalDir, Path: "/mnt/b"},
+				}},
+				err: errors.New(`sync sink name "mirror" is used more than once`),
+			},
 		}
 
 		for _, tc := range tcs {
@@ -99,6 +115,45 @@ func TestConfig(t *testing.T) {
 		}
 	})
 
+	t.Run("ValidateAssociated", func(t *testing.T) {
+		armName := resource.NewName(arm.API, "arm1")
+		associated := func(captureConfig datamanager.DataCaptureConfig) map[resource.Name]resource.AssociatedConfig {
+			captureConfig.Name = armName
+			captureConfig.Method = "EndPosition"
+			return map[resource.Name]resource.AssociatedConfig{
+				armName: &datamanager.AssociatedConfig{CaptureMethods: []datamanager.DataCaptureConfig{captureConfig}},
+			}
+		}
+
+		c := &Config{}
+		test.That(t, c.ValidateAssociated("", nil), test.ShouldBeNil)
+		test.That(t, c.ValidateAssociated("", associated(datamanager.DataCaptureConfig{
+			Retention: &datamanager.RetentionPolicy{Priority: datamanager.RetentionPriorityHigh, MaxAgeMins: 10},
+		})), test.ShouldBeNil)
+
+		err := c.ValidateAssociated("services.0", associated(datamanager.DataCaptureConfig{
+			Retention: &datamanager.RetentionPolicy{MaxBytes: -1},
+		}))
+		test.That(t, err, test.ShouldBeError,
+			errors.New("services.0: collector rdk:component:arm/arm1 EndPosition: retention max_bytes can't be negative"))
+
+		err = c.ValidateAssociated("services.0", associated(datamanager.DataCaptureConfig{
+			Trigger: &datamanager.CaptureTrigger{Type: datamanager.CaptureTriggerEvent, Event: "grasp"},
+		}))
+		test.That(t, err, test.ShouldBeError,
+			errors.New("services.0: collector rdk:component:arm/arm1 EndPosition: event trigger requires pre_seconds, post_seconds or both"))
+		test.That(t, c.ValidateAssociated("", associated(datamanager.DataCaptureConfig{
+			Trigger: &datamanager.CaptureTrigger{Type: datamanager.CaptureTriggerEvent, Event: "grasp", PostSeconds: 5},
+		})), test.ShouldBeNil)
+
+		err = c.ValidateAssociated("services.0", associated(datamanager.DataCaptureConfig{SyncSink: "mirror"}))
+		test.That(t, err, test.ShouldBeError,
+			errors.New(`services.0: collector rdk:component:arm/arm1 EndPosition: sync sink "mirror" is not defined in sync_sinks`))
+
+		c.SyncSinks = []sync.SinkConfig{{Name: "mirror", Type: sync.SinkTypeLocalDir, Path: "/mnt/a"}}
+		test.That(t, c.ValidateAssociated("", associated(datamanager.DataCaptureConfig{SyncSink: "mirror"})), test.ShouldBeNil)
+	})
+
 	t.Run("getCaptureDir", func(t *testing.T) {
 		t.Run("returns the default capture directory by default", func(t *testing.T) {
 			c := &Config{}
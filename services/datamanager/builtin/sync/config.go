@@ -1,11 +1,15 @@
 package sync
 
 import (
+	"maps"
+	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 
 	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/services/datamanager/builtin/shared"
 )
 
@@ -68,6 +72,19 @@ type Config struct {
 	DiskUsageDeletionThreshold float64
 	// Defaults to 0.50
 	CaptureDirDeletionThreshold float64
+	// RetentionPolicies maps the capture directory of a collector to its retention policy.
+	// When non empty, disk pressure deletion deletes files of lower priority collectors first
+	// instead of deleting every Nth file of the CaptureDir, and the MaxAgeMins & MaxBytes limits
+	// of each policy are enforced regardless of disk usage.
+	// See datamanager.RetentionPolicy for more info.
+	RetentionPolicies map[string]datamanager.RetentionPolicy
+	// Sinks defines the destinations other than the Viam cloud that files can be synced to,
+	// keyed by name.
+	Sinks map[string]SinkConfig
+	// CollectorSinks maps the capture directory of a collector to the name of the sink its files
+	// are synced to. Files of all other collectors, as well as arbitrary files, are synced to the
+	// Viam cloud.
+	CollectorSinks map[string]string
 	// FileLastModifiedMillis defines the number of milliseconds that
 	// we should wait for an arbitrary file (aka a file that doesn't end in
 	// either the .prog nor the .capture file extension) before we consider
@@ -97,6 +114,12 @@ type Config struct {
 	SelectiveSyncSensor sensor.Sensor
 }
 
+// sinkName returns the name of the sink the file at path should be synced to, or the empty
+// string if it should be synced to the Viam cloud.
+func (c Config) sinkName(path string) string {
+	return c.CollectorSinks[filepath.Dir(path)]
+}
+
 func (c Config) schedulerEnabled() bool {
 	configDisabled := c.ScheduledSyncDisabled
 	selectiveSyncerInvalid := c.SelectiveSyncSensorEnabled && c.SelectiveSyncSensor == nil
@@ -111,6 +134,9 @@ func (c Config) Equal(o Config) bool {
 		c.DeleteEveryNthWhenDiskFull == o.DeleteEveryNthWhenDiskFull &&
 		c.DiskUsageDeletionThreshold == o.DiskUsageDeletionThreshold &&
 		c.CaptureDirDeletionThreshold == o.CaptureDirDeletionThreshold &&
+		reflect.DeepEqual(c.RetentionPolicies, o.RetentionPolicies) &&
+		reflect.DeepEqual(c.Sinks, o.Sinks) &&
+		reflect.DeepEqual(c.CollectorSinks, o.CollectorSinks) &&
 		c.FileLastModifiedMillis == o.FileLastModifiedMillis &&
 		c.MaximumNumSyncThreads == o.MaximumNumSyncThreads &&
 		c.ScheduledSyncDisabled == o.ScheduledSyncDisabled &&
@@ -145,6 +171,19 @@ func (c *Config) logDiff(o Config, logger logging.Logger) {
 			c.DeleteEveryNthWhenDiskFull, o.DeleteEveryNthWhenDiskFull)
 	}
 
+	if !reflect.DeepEqual(c.RetentionPolicies, o.RetentionPolicies) {
+		logger.Infof("retention policies: old: %v, new: %v", c.RetentionPolicies, o.RetentionPolicies)
+	}
+
+	if !reflect.DeepEqual(c.Sinks, o.Sinks) {
+		// sink configs may contain credentials, only log their names
+		logger.Infof("sync sinks: old: %v, new: %v", slices.Sorted(maps.Keys(c.Sinks)), slices.Sorted(maps.Keys(o.Sinks)))
+	}
+
+	if !reflect.DeepEqual(c.CollectorSinks, o.CollectorSinks) {
+		logger.Infof("collector sync sinks: old: %v, new: %v", c.CollectorSinks, o.CollectorSinks)
+	}
+
 	if c.FileLastModifiedMillis != o.FileLastModifiedMillis {
 		logger.Infof("file_last_modified_millis: old: %d, new: %d", c.FileLastModifiedMillis, o.FileLastModifiedMillis)
 	}
@@ -7,13 +7,14 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
-	"strings"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/utils/diskusage"
 )
 
@@ -26,6 +27,7 @@ func deleteExcessFilesOnSchedule(
 	deleteEveryNth int,
 	diskUsageThreshold float64,
 	captureDirThreshold float64,
+	retentionPolicies map[string]datamanager.RetentionPolicy,
 	clock clock.Clock,
 	logger logging.Logger,
 ) {
@@ -44,7 +46,8 @@ func deleteExcessFilesOnSchedule(
 		case <-ctx.Done():
 			return
 		case <-t.C:
-			maybeDeleteExcessFiles(ctx, fileTracker, captureDir, deleteEveryNth, diskUsageThreshold, captureDirThreshold, clock, logger)
+			maybeDeleteExcessFiles(ctx, fileTracker, captureDir, deleteEveryNth, diskUsageThreshold, captureDirThreshold,
+				retentionPolicies, clock, logger)
 		}
 	}
 }
@@ -56,10 +59,21 @@ func maybeDeleteExcessFiles(
 	deleteEveryNth int,
 	diskUsageThreshold float64,
 	captureDirThreshold float64,
+	retentionPolicies map[string]datamanager.RetentionPolicy,
 	clock clock.Clock,
 	logger logging.Logger,
 ) {
 	start := clock.Now()
+	if len(retentionPolicies) > 0 {
+		deletedFileCount, err := deleteFilesExceedingRetentionLimits(ctx, fileTracker, captureDir, retentionPolicies, start, logger)
+		switch {
+		case err != nil:
+			logger.Errorw("error enforcing data capture retention policies", "error", err)
+		case deletedFileCount > 0:
+			logger.Infof("%d files have been deleted due to data capture retention policies", deletedFileCount)
+		}
+	}
+
 	logger.Debug("checking disk usage")
 	usage, err := diskusage.Statfs(captureDir)
 	logger.Debugf("disk usage: %s", usage)
@@ -80,6 +94,7 @@ func maybeDeleteExcessFiles(
 		deleteEveryNth,
 		diskUsageThreshold,
 		captureDirThreshold,
+		retentionPolicies,
 		logger)
 
 	duration := clock.Since(start)
@@ -102,6 +117,7 @@ func deleteExcessFiles(
 	deleteEveryNth int,
 	diskUsageThreshold float64,
 	captureDirToFSThreshold float64,
+	retentionPolicies map[string]datamanager.RetentionPolicy,
 	logger logging.Logger,
 ) (int, error) {
 	shouldDelete, err := shouldDeleteBasedOnDiskUsage(
@@ -120,7 +136,39 @@ func deleteExcessFiles(
 	}
 
 	logger.Warnf("current disk usage of the data capture directory exceeds threshold (%f)", captureDirToFSThreshold)
-	return deleteFiles(ctx, fileTracker, deleteEveryNth, captureDir, logger)
+	if len(retentionPolicies) == 0 {
+		return deleteFiles(ctx, fileTracker, deleteEveryNth, captureDir, logger)
+	}
+
+	groups, err := groupCaptureFilesByCollector(ctx, captureDir, retentionPolicies)
+	if err != nil {
+		return 0, err
+	}
+	overThreshold := func() (bool, error) {
+		usage, err := diskusage.Statfs(captureDir)
+		if err != nil {
+			return false, errors.Wrap(err, "error checking file system stats")
+		}
+		return shouldDeleteBasedOnDiskUsage(ctx, usage, captureDir, diskUsageThreshold, captureDirToFSThreshold, logger)
+	}
+	return deleteFilesByPriority(ctx, fileTracker, groups, retentionPolicies, deleteEveryNth, overThreshold, logger)
+}
+
+// deleteFilesExceedingRetentionLimits deletes the capture files which exceed the MaxAgeMins or MaxBytes
+// of their collector's retention policy.
+func deleteFilesExceedingRetentionLimits(
+	ctx context.Context,
+	fileTracker *fileTracker,
+	captureDir string,
+	retentionPolicies map[string]datamanager.RetentionPolicy,
+	now time.Time,
+	logger logging.Logger,
+) (int, error) {
+	groups, err := groupCaptureFilesByCollector(ctx, captureDir, retentionPolicies)
+	if err != nil {
+		return 0, err
+	}
+	return enforceRetentionLimits(ctx, fileTracker, groups, retentionPolicies, now, logger)
 }
 
 func shouldDeleteBasedOnDiskUsage(
@@ -237,7 +285,7 @@ func deleteFiles(
 			}
 			return err
 		}
-		isCompletedDataCaptureFile := strings.Contains(fileInfo.Name(), data.CompletedCaptureFileExt)
+		isCompletedDataCaptureFile := filepath.Ext(fileInfo.Name()) == data.CompletedCaptureFileExt
 		// if at nth file and the file is not currently being written, mark as in progress if possible
 		if isCompletedDataCaptureFile && index%deleteEveryNth == 0 {
 			if !fileTracker.markInProgress(path) {
@@ -249,6 +297,9 @@ func deleteFiles(
 				fileTracker.unmarkInProgress(path)
 				return err
 			}
+			if err := data.RemoveCaptureIndex(path); err != nil {
+				logger.Warnw("error deleting capture file index", "error", err)
+			}
 			logger.Infof("successfully deleted %s", d.Name())
 			deletedFileCount++
 		}
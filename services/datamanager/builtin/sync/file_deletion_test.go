@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/utils/diskusage"
 )
 
@@ -134,6 +137,119 @@ func TestFileDeletionUsageCheck(t *testing.T) {
 	}
 }
 
+func TestFileDeletionByPriority(t *testing.T) {
+	tests := []struct {
+		name                      string
+		overThresholdAfterClasses int
+		expectedRemaining         map[string][]string
+	}{
+		{
+			name:                      "low priority files are downsampled first and higher priority files survive",
+			overThresholdAfterClasses: 0,
+			expectedRemaining: map[string][]string{
+				"low":      {"1.capture", "2.capture", "3.capture"},
+				"normal":   {"0.capture", "1.capture", "2.capture", "3.capture"},
+				"unset":    {"0.capture", "1.capture", "2.capture", "3.capture"},
+				"high":     {"0.capture", "1.capture", "2.capture", "3.capture"},
+				"critical": {"0.capture", "1.capture", "2.capture", "3.capture"},
+			},
+		},
+		{
+			name:                      "collectors without a retention policy are treated as normal priority",
+			overThresholdAfterClasses: 1,
+			expectedRemaining: map[string][]string{
+				"low":      {"1.capture", "2.capture", "3.capture"},
+				"normal":   {"1.capture", "3.capture"},
+				"unset":    {"1.capture", "2.capture", "3.capture"},
+				"high":     {"0.capture", "1.capture", "2.capture", "3.capture"},
+				"critical": {"0.capture", "1.capture", "2.capture", "3.capture"},
+			},
+		},
+		{
+			name:                      "critical priority files are never deleted due to disk pressure",
+			overThresholdAfterClasses: 3,
+			expectedRemaining: map[string][]string{
+				"low":      {"1.capture", "2.capture", "3.capture"},
+				"normal":   {"1.capture", "3.capture"},
+				"unset":    {"1.capture", "2.capture", "3.capture"},
+				"high":     {"1.capture", "2.capture", "3.capture"},
+				"critical": {"0.capture", "1.capture", "2.capture", "3.capture"},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tempCaptureDir := t.TempDir()
+			logger := logging.NewTestLogger(t)
+			ft := newFileTracker()
+			fileList := []string{"0.capture", "1.capture", "2.capture", "3.capture"}
+			policies := map[string]datamanager.RetentionPolicy{}
+			for _, dir := range []string{"low", "normal", "unset", "high", "critical"} {
+				collectorDir := filepath.Join(tempCaptureDir, dir)
+				test.That(t, os.MkdirAll(collectorDir, 0o700), test.ShouldBeNil)
+				writeFiles(t, collectorDir, fileList)
+			}
+			// the unset collector has no policy at all, while the normal priority collector leaves its priority unset but is
+			// downsampled more aggressively than the default
+			policies[filepath.Join(tempCaptureDir, "low")] = datamanager.RetentionPolicy{Priority: datamanager.RetentionPriorityLow}
+			policies[filepath.Join(tempCaptureDir, "normal")] = datamanager.RetentionPolicy{DownsampleEveryNth: 2}
+			policies[filepath.Join(tempCaptureDir, "high")] = datamanager.RetentionPolicy{Priority: datamanager.RetentionPriorityHigh}
+			policies[filepath.Join(tempCaptureDir, "critical")] = datamanager.RetentionPolicy{
+				Priority: datamanager.RetentionPriorityCritical,
+			}
+
+			groups, err := groupCaptureFilesByCollector(context.Background(), tempCaptureDir, policies)
+			test.That(t, err, test.ShouldBeNil)
+			checks := 0
+			overThreshold := func() (bool, error) {
+				checks++
+				return checks <= tc.overThresholdAfterClasses, nil
+			}
+			_, err = deleteFilesByPriority(context.Background(), ft, groups, policies, 5, overThreshold, logger)
+			test.That(t, err, test.ShouldBeNil)
+			for dir, expected := range tc.expectedRemaining {
+				test.That(t, getFileNames(t, filepath.Join(tempCaptureDir, dir)), test.ShouldResemble, expected)
+			}
+		})
+	}
+}
+
+func TestRetentionLimits(t *testing.T) {
+	tempCaptureDir := t.TempDir()
+	logger := logging.NewTestLogger(t)
+	ft := newFileTracker()
+	now := time.Now()
+
+	ageDir := filepath.Join(tempCaptureDir, "age")
+	bytesDir := filepath.Join(tempCaptureDir, "bytes")
+	unlimitedDir := filepath.Join(tempCaptureDir, "unlimited")
+	for _, dir := range []string{ageDir, bytesDir, unlimitedDir} {
+		test.That(t, os.MkdirAll(dir, 0o700), test.ShouldBeNil)
+		filepaths := writeFiles(t, dir, []string{"0.capture", "0.capture.idx", "1.capture", "2.capture", "2.capture.idx", "3.prog"})
+		// 0.capture is two hours old, 1.capture is one hour old and 2.capture is new
+		test.That(t, os.Chtimes(filepaths["0.capture"], now, now.Add(-2*time.Hour)), test.ShouldBeNil)
+		test.That(t, os.Chtimes(filepaths["1.capture"], now, now.Add(-time.Hour)), test.ShouldBeNil)
+		test.That(t, os.Chtimes(filepaths["3.prog"], now, now.Add(-2*time.Hour)), test.ShouldBeNil)
+		// indexes neither count towards the limits nor are deleted apart from their capture files
+		test.That(t, os.Chtimes(filepaths["2.capture.idx"], now, now.Add(-2*time.Hour)), test.ShouldBeNil)
+	}
+	fileSize := int64(len("never gonna let you down"))
+	policies := map[string]datamanager.RetentionPolicy{
+		ageDir:       {MaxAgeMins: 90},
+		bytesDir:     {MaxBytes: fileSize},
+		unlimitedDir: {Priority: datamanager.RetentionPriorityLow},
+	}
+
+	deletedFileCount, err := deleteFilesExceedingRetentionLimits(context.Background(), ft, tempCaptureDir, policies, now, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, deletedFileCount, test.ShouldEqual, 3)
+	test.That(t, getFileNames(t, ageDir), test.ShouldResemble, []string{"1.capture", "2.capture", "2.capture.idx", "3.prog"})
+	test.That(t, getFileNames(t, bytesDir), test.ShouldResemble, []string{"2.capture", "2.capture.idx", "3.prog"})
+	test.That(t, getFileNames(t, unlimitedDir), test.ShouldResemble,
+		[]string{"0.capture", "0.capture.idx", "1.capture", "2.capture", "2.capture.idx", "3.prog"})
+}
+
 func writeFiles(t *testing.T, dir string, filenames []string) map[string]string {
 	t.Helper()
 	fileContents := []byte("never gonna let you down")
@@ -0,0 +1,208 @@
+package sync
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/services/datamanager"
+)
+
+// completedCaptureFile is a completed (.capture) file which is a candidate for deletion.
+type completedCaptureFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// groupCaptureFilesByCollector walks the capture directory and returns the completed capture files
+// grouped by the collector directory of the retention policy they fall under. Files which don't
+// belong to a collector with a retention policy are grouped under the empty string.
+// Within a group files are ordered oldest first, as capture file names are timestamps.
+func groupCaptureFilesByCollector(
+	ctx context.Context,
+	captureDirPath string,
+	retentionPolicies map[string]datamanager.RetentionPolicy,
+) (map[string][]completedCaptureFile, error) {
+	groups := map[string][]completedCaptureFile{}
+	walk := func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			// a .prog file may be renamed to .capture while we are walking the dir
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(d.Name()) != data.CompletedCaptureFileExt {
+			return nil
+		}
+		fileInfo, err := d.Info()
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		key := filepath.Dir(path)
+		if _, ok := retentionPolicies[key]; !ok {
+			key = ""
+		}
+		groups[key] = append(groups[key], completedCaptureFile{
+			path:    path,
+			size:    fileInfo.Size(),
+			modTime: fileInfo.ModTime(),
+		})
+		return nil
+	}
+	if err := filepath.WalkDir(captureDirPath, walk); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// deleteCaptureFile deletes the file at path unless it is currently being synced.
+// It returns true if the file was deleted.
+func deleteCaptureFile(fileTracker *fileTracker, path string, logger logging.Logger) (bool, error) {
+	if !fileTracker.markInProgress(path) {
+		logger.Debugw("Tried to mark file as in progress but lock already held", "file", filepath.Base(path))
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil {
+		fileTracker.unmarkInProgress(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		logger.Warnw("error deleting file", "error", err)
+		return false, err
+	}
+	if err := data.RemoveCaptureIndex(path); err != nil {
+		logger.Warnw("error deleting capture file index", "error", err)
+	}
+	logger.Infof("successfully deleted %s", filepath.Base(path))
+	return true, nil
+}
+
+// enforceRetentionLimits deletes the capture files of collectors which are older than their retention
+// policy's MaxAgeMins, followed by the oldest files of collectors whose files exceed the policy's MaxBytes.
+// Retention limits are enforced regardless of disk usage.
+func enforceRetentionLimits(
+	ctx context.Context,
+	fileTracker *fileTracker,
+	groups map[string][]completedCaptureFile,
+	retentionPolicies map[string]datamanager.RetentionPolicy,
+	now time.Time,
+	logger logging.Logger,
+) (int, error) {
+	deletedFileCount := 0
+	for collectorDir, policy := range retentionPolicies {
+		if policy.MaxAgeMins == 0 && policy.MaxBytes == 0 {
+			continue
+		}
+		var kept []completedCaptureFile
+		var keptBytes int64
+		for _, f := range groups[collectorDir] {
+			if ctx.Err() != nil {
+				return deletedFileCount, ctx.Err()
+			}
+			maxAge := time.Duration(policy.MaxAgeMins * float64(time.Minute))
+			if policy.MaxAgeMins != 0 && now.Sub(f.modTime) > maxAge {
+				deleted, err := deleteCaptureFile(fileTracker, f.path, logger)
+				if err != nil {
+					return deletedFileCount, err
+				}
+				if deleted {
+					deletedFileCount++
+					continue
+				}
+			}
+			kept = append(kept, f)
+			keptBytes += f.size
+		}
+
+		for i := 0; policy.MaxBytes != 0 && keptBytes > policy.MaxBytes && i < len(kept); i++ {
+			if ctx.Err() != nil {
+				return deletedFileCount, ctx.Err()
+			}
+			deleted, err := deleteCaptureFile(fileTracker, kept[i].path, logger)
+			if err != nil {
+				return deletedFileCount, err
+			}
+			if deleted {
+				deletedFileCount++
+				keptBytes -= kept[i].size
+			}
+		}
+	}
+	return deletedFileCount, nil
+}
+
+// deleteFilesByPriority frees disk space by downsampling the capture files of the lowest priority collectors
+// first. Every Nth completed file of each collector of a given priority is deleted, where N is the collector's
+// DownsampleEveryNth if set and deleteEveryNth otherwise. Files which don't belong to a collector with
+// a retention policy are treated as normal priority. After each priority class, overThreshold is called and
+// deletion stops once it returns false. Files of critical priority collectors are never deleted.
+func deleteFilesByPriority(
+	ctx context.Context,
+	fileTracker *fileTracker,
+	groups map[string][]completedCaptureFile,
+	retentionPolicies map[string]datamanager.RetentionPolicy,
+	deleteEveryNth int,
+	overThreshold func() (bool, error),
+	logger logging.Logger,
+) (int, error) {
+	deletedFileCount := 0
+	for rank := datamanager.RetentionPriorityLow.Rank(); rank < datamanager.RetentionPriorityCritical.Rank(); rank++ {
+		deletedInRank := 0
+		for collectorDir, files := range groups {
+			policy := retentionPolicies[collectorDir]
+			if policy.Priority.Rank() != rank {
+				continue
+			}
+			n := deleteEveryNth
+			if policy.DownsampleEveryNth != 0 {
+				n = policy.DownsampleEveryNth
+			}
+			if collectorDir == "" {
+				logger.Infof("Deleting every %dth file without a retention policy", n)
+			} else {
+				logger.Infof("Deleting every %dth file in %s", n, collectorDir)
+			}
+			for i, f := range files {
+				if ctx.Err() != nil {
+					return deletedFileCount, ctx.Err()
+				}
+				if i%n != 0 {
+					continue
+				}
+				deleted, err := deleteCaptureFile(fileTracker, f.path, logger)
+				if err != nil {
+					return deletedFileCount, err
+				}
+				if deleted {
+					deletedFileCount++
+					deletedInRank++
+				}
+			}
+		}
+		if deletedInRank == 0 {
+			continue
+		}
+		stillOver, err := overThreshold()
+		if err != nil {
+			return deletedFileCount, err
+		}
+		if !stillOver {
+			return deletedFileCount, nil
+		}
+	}
+	return deletedFileCount, nil
+}
@@ -0,0 +1,417 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/benbjohnson/clock"
+	"github.com/pkg/errors"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/logging"
+)
+
+// SinkType is the kind of destination a Sink uploads files to.
+type SinkType string
+
+// The supported sink types.
+const (
+	// SinkTypeLocalDir mirrors files into a directory on the machine, e.g. a mounted network share.
+	SinkTypeLocalDir SinkType = "local_dir"
+	// SinkTypeS3 uploads files to a bucket of an S3 compatible object store.
+	SinkTypeS3 SinkType = "s3"
+	// SinkTypeGRPC uploads files to a gRPC endpoint implementing the DataSyncService API.
+	SinkTypeGRPC SinkType = "grpc"
+	// SinkTypeHTTP POSTs each file to an HTTP endpoint.
+	SinkTypeHTTP SinkType = "http"
+)
+
+// SinkConfig describes a destination, other than the Viam cloud, that data sync can upload files to.
+type SinkConfig struct {
+	// Name is referenced by the sync_sink of a collector to sync its files to this sink.
+	Name string   `json:"name"`
+	Type SinkType `json:"type"`
+	// Path is the directory a local_dir sink copies files to.
+	Path string `json:"path,omitempty"`
+	// Endpoint is the address of a grpc sink, the URL of an http sink or, optionally, the URL of an
+	// S3 compatible store such as MinIO. An s3 sink uses AWS when Endpoint is empty.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Bucket, Prefix, Region, AccessKeyID and SecretAccessKey configure an s3 sink. Objects are
+	// written to Bucket at Prefix followed by the path of the file relative to the capture directory.
+	Bucket          string `json:"bucket,omitempty"`
+	Prefix          string `json:"prefix,omitempty"`
+	Region          string `json:"region,omitempty"`
+	AccessKeyID     string `json:"access_key_id,omitempty"`
+	SecretAccessKey string `json:"secret_access_key,omitempty"`
+	// Headers are added to every request of an http sink, e.g. for authorization.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Insecure, when true, dials a grpc sink without TLS.
+	Insecure bool `json:"insecure,omitempty"`
+	// PartID is sent as the part id of the uploads to a grpc sink.
+	PartID string `json:"part_id,omitempty"`
+}
+
+// Validate returns an error if the sink config is invalid.
+func (c *SinkConfig) Validate() error {
+	if c.Name == "" {
+		return errors.New("sync sink name can't be empty")
+	}
+	switch c.Type {
+	case SinkTypeLocalDir:
+		if c.Path == "" {
+			return fmt.Errorf("sync sink %q: path is required for type %q", c.Name, c.Type)
+		}
+	case SinkTypeS3:
+		if c.Bucket == "" {
+			return fmt.Errorf("sync sink %q: bucket is required for type %q", c.Name, c.Type)
+		}
+		if (c.AccessKeyID == "") != (c.SecretAccessKey == "") {
+			return fmt.Errorf("sync sink %q: access_key_id and secret_access_key must be set together", c.Name)
+		}
+	case SinkTypeGRPC, SinkTypeHTTP:
+		if c.Endpoint == "" {
+			return fmt.Errorf("sync sink %q: endpoint is required for type %q", c.Name, c.Type)
+		}
+	default:
+		return fmt.Errorf("sync sink %q: type %q is not one of %q, %q, %q or %q",
+			c.Name, c.Type, SinkTypeLocalDir, SinkTypeS3, SinkTypeGRPC, SinkTypeHTTP)
+	}
+	return nil
+}
+
+// Sink uploads the files data sync finds to a destination. Uploads are retried by data sync with
+// exponential backoff, so implementations make a single attempt and return the number of bytes
+// uploaded.
+type Sink interface {
+	// UploadDataCaptureFile uploads a completed data capture file.
+	UploadDataCaptureFile(ctx context.Context, f *data.CaptureFile) (uint64, error)
+	// UploadArbitraryFile uploads a file which was not written by data capture.
+	UploadArbitraryFile(ctx context.Context, f *os.File, tags, datasetIDs []string, fileLastModifiedMillis int) (uint64, error)
+	// Close releases the resources of the sink.
+	Close() error
+}
+
+// cloudSink uploads files to the Viam cloud over the cloud connection.
+type cloudSink struct {
+	conn   cloudConn
+	clock  clock.Clock
+	logger logging.Logger
+}
+
+func (s cloudSink) UploadDataCaptureFile(ctx context.Context, f *data.CaptureFile) (uint64, error) {
+	return uploadDataCaptureFile(ctx, f, s.conn, s.logger)
+}
+
+func (s cloudSink) UploadArbitraryFile(
+	ctx context.Context, f *os.File, tags, datasetIDs []string, fileLastModifiedMillis int,
+) (uint64, error) {
+	return uploadArbitraryFile(ctx, f, s.conn, tags, datasetIDs, fileLastModifiedMillis, s.clock, s.logger)
+}
+
+func (s cloudSink) Close() error {
+	return nil
+}
+
+// newSink creates the sink described by config. Files are identified at the sink by their path
+// relative to captureDir.
+func newSink(config SinkConfig, captureDir string, clock clock.Clock, logger logging.Logger) (Sink, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	logger = logger.Sublogger(config.Name)
+	var store blobStore
+	switch config.Type {
+	case SinkTypeLocalDir:
+		store = localDirStore{dir: config.Path}
+	case SinkTypeS3:
+		s3Store, err := newS3Store(config)
+		if err != nil {
+			return nil, err
+		}
+		store = s3Store
+	case SinkTypeHTTP:
+		store = httpStore{url: config.Endpoint, headers: config.Headers, client: &http.Client{}, timeout: httpUploadTimeout}
+	case SinkTypeGRPC:
+		return &grpcSink{config: config, clock: clock, logger: logger}, nil
+	}
+	return &blobSink{store: store, captureDir: captureDir, clock: clock, logger: logger}, nil
+}
+
+// newSinks creates the sinks described by configs keyed by name. Sinks which fail to be created
+// are logged and omitted, so the files routed to them are left on disk.
+func newSinks(configs map[string]SinkConfig, captureDir string, clock clock.Clock, logger logging.Logger) map[string]Sink {
+	sinks := make(map[string]Sink, len(configs))
+	for name, config := range configs {
+		sink, err := newSink(config, captureDir, clock, logger)
+		if err != nil {
+			logger.Errorw("failed to create sync sink, files of the collectors using it will not be synced",
+				"sink", name, "error", err)
+			continue
+		}
+		sinks[name] = sink
+	}
+	return sinks
+}
+
+func closeSinks(sinks map[string]Sink, logger logging.Logger) {
+	for name, sink := range sinks {
+		if err := sink.Close(); err != nil {
+			logger.Warnw("error closing sync sink", "sink", name, "error", err)
+		}
+	}
+}
+
+// blobStore writes whole files to a destination under a key.
+type blobStore interface {
+	put(ctx context.Context, key string, f *os.File, size int64) error
+}
+
+// blobSink uploads the raw bytes of every file to a blobStore, keyed by the file's path relative to
+// the capture directory.
+type blobSink struct {
+	store      blobStore
+	captureDir string
+	clock      clock.Clock
+	logger     logging.Logger
+}
+
+func (s *blobSink) UploadDataCaptureFile(ctx context.Context, f *data.CaptureFile) (uint64, error) {
+	//nolint:gosec
+	raw, err := os.Open(f.GetPath())
+	if err != nil {
+		return 0, err
+	}
+	//nolint:errcheck
+	defer raw.Close()
+	return s.upload(ctx, raw, f.Size())
+}
+
+func (s *blobSink) UploadArbitraryFile(
+	ctx context.Context, f *os.File, _, _ []string, fileLastModifiedMillis int,
+) (uint64, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return 0, errors.Wrap(err, "stat failed")
+	}
+	if info.Size() == 0 {
+		return 0, errFileEmpty
+	}
+	if s.clock.Since(info.ModTime()) < time.Duration(fileLastModifiedMillis)*time.Millisecond {
+		return 0, errFileModifiedTooRecently
+	}
+	return s.upload(ctx, f, info.Size())
+}
+
+func (s *blobSink) upload(ctx context.Context, f *os.File, size int64) (uint64, error) {
+	relPath, err := filepath.Rel(s.captureDir, f.Name())
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to get path relative to the capture directory")
+	}
+	// A previous attempt may have moved the read offset.
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, errors.Wrap(err, "error trying to Seek to beginning of file")
+	}
+	key := filepath.ToSlash(relPath)
+	s.logger.Debugf("uploading %s as %s", f.Name(), key)
+	if err := s.store.put(ctx, key, f, size); err != nil {
+		return 0, err
+	}
+	return uint64(size), nil
+}
+
+func (s *blobSink) Close() error {
+	return nil
+}
+
+// localDirStore copies files into dir.
+type localDirStore struct {
+	dir string
+}
+
+func (l localDirStore) put(_ context.Context, key string, f *os.File, _ int64) error {
+	target := filepath.Join(l.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return err
+	}
+	// Write to a temporary file first so readers of the mirror never see partial files.
+	tmp, err := os.CreateTemp(filepath.Dir(target), filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, f); err != nil {
+		return discardTmpFile(tmp, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return discardTmpFile(tmp, err)
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+func discardTmpFile(tmp *os.File, cause error) error {
+	//nolint:errcheck
+	tmp.Close()
+	if err := os.Remove(tmp.Name()); err != nil {
+		return errors.Wrapf(cause, "also failed to remove %s: %v", tmp.Name(), err)
+	}
+	return cause
+}
+
+// s3Store puts files as objects of an S3 compatible object store.
+type s3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func newS3Store(config SinkConfig) (*s3Store, error) {
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	opts := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(region)}
+	if config.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(config.AccessKeyID, config.SecretAccessKey, "")))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load s3 config")
+	}
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			// S3 compatible stores generally don't support virtual hosted buckets.
+			o.BaseEndpoint = aws.String(config.Endpoint)
+			o.UsePathStyle = true
+		}
+		// Not every S3 compatible store supports the checksums AWS adds to uploads by default.
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+	})
+	return &s3Store{client: client, bucket: config.Bucket, prefix: config.Prefix}, nil
+}
+
+func (s *s3Store) put(ctx context.Context, key string, f *os.File, size int64) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucket),
+		Key:           aws.String(path.Join(s.prefix, key)),
+		Body:          f,
+		ContentLength: aws.Int64(size),
+		ContentType:   aws.String("application/octet-stream"),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to put object %s in bucket %s", key, s.bucket)
+	}
+	return nil
+}
+
+// SinkPathHeader is the header of the requests of an http sink holding the path of the uploaded
+// file relative to the capture directory.
+const SinkPathHeader = "X-Viam-Sync-Path"
+
+// httpStore POSTs files to url, giving up on each upload after timeout.
+type httpStore struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+	timeout time.Duration
+}
+
+func (h httpStore) put(ctx context.Context, key string, f *os.File, size int64) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	for name, value := range h.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set(SinkPathHeader, key)
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("http sink responded to upload of %s with %s: %s", key, resp.Status, body)
+	}
+	return nil
+}
+
+// grpcSink uploads files to a gRPC endpoint implementing the DataSyncService API, in the same way
+// as they are uploaded to the Viam cloud. The endpoint is dialed on the first upload so that an
+// unavailable endpoint is retried like an offline cloud connection.
+type grpcSink struct {
+	config SinkConfig
+	clock  clock.Clock
+	logger logging.Logger
+
+	mu   sync.Mutex
+	conn rpc.ClientConn
+}
+
+func (s *grpcSink) cloudSink(ctx context.Context) (cloudSink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		var opts []rpc.DialOption
+		if s.config.Insecure {
+			opts = append(opts, rpc.WithInsecure())
+		}
+		dialCtx, cancel := context.WithTimeout(ctx, grpcConnectionTimeout)
+		defer cancel()
+		conn, err := rpc.DialDirectGRPC(dialCtx, s.config.Endpoint, s.logger, opts...)
+		if err != nil {
+			return cloudSink{}, errors.Wrapf(err, "failed to dial %s", s.config.Endpoint)
+		}
+		s.conn = conn
+	}
+	return cloudSink{
+		conn:   cloudConn{partID: s.config.PartID, client: v1.NewDataSyncServiceClient(s.conn)},
+		clock:  s.clock,
+		logger: s.logger,
+	}, nil
+}
+
+func (s *grpcSink) UploadDataCaptureFile(ctx context.Context, f *data.CaptureFile) (uint64, error) {
+	sink, err := s.cloudSink(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return sink.UploadDataCaptureFile(ctx, f)
+}
+
+func (s *grpcSink) UploadArbitraryFile(
+	ctx context.Context, f *os.File, tags, datasetIDs []string, fileLastModifiedMillis int,
+) (uint64, error) {
+	sink, err := s.cloudSink(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return sink.UploadArbitraryFile(ctx, f, tags, datasetIDs, fileLastModifiedMillis)
+}
+
+func (s *grpcSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,295 @@
+package sync
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	v1 "go.viam.com/api/app/datasync/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/internal/cloud"
+	"go.viam.com/rdk/logging"
+)
+
+// writeSinkTestCaptureFile writes a completed capture file with a single tabular reading to dir
+// and returns its path.
+func writeSinkTestCaptureFile(t *testing.T, dir string) string {
+	t.Helper()
+	md, _ := data.BuildCaptureMetadata(sensor.API, "sensor", "Readings", nil, nil, nil)
+	w, err := data.NewCaptureFile(dir, md)
+	test.That(t, err, test.ShouldBeNil)
+	reading, err := structpb.NewStruct(map[string]interface{}{"readings": map[string]interface{}{"a": 1}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, w.WriteNext(&v1.SensorData{
+		Metadata: &v1.SensorMetadata{},
+		Data:     &v1.SensorData_Struct{Struct: reading},
+	}), test.ShouldBeNil)
+	test.That(t, w.Flush(), test.ShouldBeNil)
+	test.That(t, w.Close(), test.ShouldBeNil)
+	return strings.TrimSuffix(w.GetPath(), data.InProgressCaptureFileExt) + data.CompletedCaptureFileExt
+}
+
+func uploadToSink(t *testing.T, sink Sink, path string) uint64 {
+	t.Helper()
+	//nolint:gosec
+	f, err := os.Open(path)
+	test.That(t, err, test.ShouldBeNil)
+	defer f.Close()
+	captureFile, err := data.ReadCaptureFile(f)
+	test.That(t, err, test.ShouldBeNil)
+	bytesUploaded, err := sink.UploadDataCaptureFile(context.Background(), captureFile)
+	test.That(t, err, test.ShouldBeNil)
+	return bytesUploaded
+}
+
+type fakeDataSyncServer struct {
+	v1.UnimplementedDataSyncServiceServer
+	mu       sync.Mutex
+	requests []*v1.DataCaptureUploadRequest
+}
+
+func (s *fakeDataSyncServer) DataCaptureUpload(
+	_ context.Context, req *v1.DataCaptureUploadRequest,
+) (*v1.DataCaptureUploadResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, req)
+	return &v1.DataCaptureUploadResponse{}, nil
+}
+
+func TestSinks(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	captureDir := t.TempDir()
+	collectorDir := filepath.Join(captureDir, "rdk_component_sensor", "sensor", "Readings")
+	test.That(t, os.MkdirAll(collectorDir, 0o700), test.ShouldBeNil)
+	path := writeSinkTestCaptureFile(t, collectorDir)
+	contents, err := os.ReadFile(path)
+	test.That(t, err, test.ShouldBeNil)
+	key := "rdk_component_sensor/sensor/Readings/" + filepath.Base(path)
+
+	t.Run("local_dir", func(t *testing.T) {
+		mirrorDir := t.TempDir()
+		sink, err := newSink(SinkConfig{Name: "mirror", Type: SinkTypeLocalDir, Path: mirrorDir}, captureDir, clock.New(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer sink.Close()
+
+		test.That(t, uploadToSink(t, sink, path), test.ShouldEqual, len(contents))
+		mirrored, err := os.ReadFile(filepath.Join(mirrorDir, filepath.FromSlash(key)))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, mirrored, test.ShouldResemble, contents)
+	})
+
+	t.Run("s3", func(t *testing.T) {
+		// A stand in for an S3 compatible store such as MinIO.
+		var mu sync.Mutex
+		objects := map[string][]byte{}
+		store := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPut || !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			mu.Lock()
+			objects[r.URL.Path] = body
+			mu.Unlock()
+		}))
+		defer store.Close()
+
+		sink, err := newSink(SinkConfig{
+			Name:            "minio",
+			Type:            SinkTypeS3,
+			Endpoint:        store.URL,
+			Bucket:          "robot-data",
+			Prefix:          "machine-1",
+			AccessKeyID:     "minioadmin",
+			SecretAccessKey: "minioadmin",
+		}, captureDir, clock.New(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer sink.Close()
+
+		test.That(t, uploadToSink(t, sink, path), test.ShouldEqual, len(contents))
+		mu.Lock()
+		defer mu.Unlock()
+		test.That(t, objects["/robot-data/machine-1/"+key], test.ShouldResemble, contents)
+	})
+
+	t.Run("http", func(t *testing.T) {
+		received := make(chan *http.Request, 1)
+		var body []byte
+		endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var err error
+			body, err = io.ReadAll(r.Body)
+			if err != nil || r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			received <- r
+		}))
+		defer endpoint.Close()
+
+		sink, err := newSink(SinkConfig{
+			Name:     "ingest",
+			Type:     SinkTypeHTTP,
+			Endpoint: endpoint.URL + "/upload",
+			Headers:  map[string]string{"Authorization": "Bearer token"},
+		}, captureDir, clock.New(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer sink.Close()
+
+		test.That(t, uploadToSink(t, sink, path), test.ShouldEqual, len(contents))
+		req := <-received
+		test.That(t, req.URL.Path, test.ShouldEqual, "/upload")
+		test.That(t, req.Header.Get(SinkPathHeader), test.ShouldEqual, key)
+		test.That(t, body, test.ShouldResemble, contents)
+
+		// Errors are returned so that the upload is retried.
+		failingEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer failingEndpoint.Close()
+		failingSink, err := newSink(SinkConfig{Name: "down", Type: SinkTypeHTTP, Endpoint: failingEndpoint.URL},
+			captureDir, clock.New(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer failingSink.Close()
+		//nolint:gosec
+		f, err := os.Open(path)
+		test.That(t, err, test.ShouldBeNil)
+		defer f.Close()
+		captureFile, err := data.ReadCaptureFile(f)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = failingSink.UploadDataCaptureFile(context.Background(), captureFile)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "503")
+
+		// An endpoint which never responds doesn't hold up sync forever.
+		stalledEndpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the request is only cancelled once its body has been read
+			//nolint:errcheck
+			io.ReadAll(r.Body)
+			<-r.Context().Done()
+		}))
+		defer stalledEndpoint.Close()
+		stalledStore := httpStore{url: stalledEndpoint.URL, client: &http.Client{}, timeout: 100 * time.Millisecond}
+		_, err = f.Seek(0, io.SeekStart)
+		test.That(t, err, test.ShouldBeNil)
+		err = stalledStore.put(context.Background(), key, f, int64(len(contents)))
+		test.That(t, err, test.ShouldWrap, context.DeadlineExceeded)
+	})
+
+	t.Run("grpc", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "localhost:0")
+		test.That(t, err, test.ShouldBeNil)
+		server := grpc.NewServer()
+		dataSyncServer := &fakeDataSyncServer{}
+		v1.RegisterDataSyncServiceServer(server, dataSyncServer)
+		go server.Serve(listener)
+		defer server.Stop()
+
+		sink, err := newSink(SinkConfig{
+			Name:     "self-hosted",
+			Type:     SinkTypeGRPC,
+			Endpoint: listener.Addr().String(),
+			Insecure: true,
+			PartID:   "part",
+		}, captureDir, clock.New(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer sink.Close()
+
+		test.That(t, uploadToSink(t, sink, path), test.ShouldEqual, len(contents))
+		dataSyncServer.mu.Lock()
+		defer dataSyncServer.mu.Unlock()
+		test.That(t, len(dataSyncServer.requests), test.ShouldEqual, 1)
+		test.That(t, dataSyncServer.requests[0].GetMetadata().GetPartId(), test.ShouldEqual, "part")
+		test.That(t, dataSyncServer.requests[0].GetMetadata().GetComponentName(), test.ShouldEqual, "sensor")
+		test.That(t, len(dataSyncServer.requests[0].GetSensorContents()), test.ShouldEqual, 1)
+	})
+}
+
+func TestSinkConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		config SinkConfig
+		err    string
+	}{
+		{SinkConfig{Type: SinkTypeLocalDir, Path: "/mnt"}, "name can't be empty"},
+		{SinkConfig{Name: "a", Type: "ftp"}, `type "ftp" is not one of`},
+		{SinkConfig{Name: "a", Type: SinkTypeLocalDir}, "path is required"},
+		{SinkConfig{Name: "a", Type: SinkTypeS3}, "bucket is required"},
+		{SinkConfig{Name: "a", Type: SinkTypeS3, Bucket: "b", AccessKeyID: "id"}, "must be set together"},
+		{SinkConfig{Name: "a", Type: SinkTypeGRPC}, "endpoint is required"},
+		{SinkConfig{Name: "a", Type: SinkTypeHTTP}, "endpoint is required"},
+		{SinkConfig{Name: "a", Type: SinkTypeS3, Bucket: "b"}, ""},
+	} {
+		err := tc.config.Validate()
+		if tc.err == "" {
+			test.That(t, err, test.ShouldBeNil)
+		} else {
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+		}
+	}
+}
+
+func TestSyncToSinkWithoutCloud(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	captureDir := t.TempDir()
+	mirrorDir := t.TempDir()
+	sinkDir := filepath.Join(captureDir, "rdk_component_sensor", "to-mirror", "Readings")
+	cloudDir := filepath.Join(captureDir, "rdk_component_sensor", "to-cloud", "Readings")
+	for _, dir := range []string{sinkDir, cloudDir} {
+		test.That(t, os.MkdirAll(dir, 0o700), test.ShouldBeNil)
+	}
+	sinkFile := writeSinkTestCaptureFile(t, sinkDir)
+	cloudFile := writeSinkTestCaptureFile(t, cloudDir)
+
+	s := New(v1.NewDataSyncServiceClient, func() {}, clock.New(), logger)
+	defer s.Close()
+	s.Reconfigure(context.Background(), Config{
+		CaptureDir:            captureDir,
+		CaptureDisabled:       true,
+		MaximumNumSyncThreads: 1,
+		ScheduledSyncDisabled: true,
+		Sinks:                 map[string]SinkConfig{"mirror": {Name: "mirror", Type: SinkTypeLocalDir, Path: mirrorDir}},
+		CollectorSinks:        map[string]string{sinkDir: "mirror"},
+	}, cloud.NewCloudConnectionService(nil, nil, logger))
+
+	// The machine is not cloud managed, yet the files of the collector syncing to a sink are synced.
+	test.That(t, s.Sync(context.Background(), nil), test.ShouldBeNil)
+	mirrored := filepath.Join(mirrorDir, "rdk_component_sensor", "to-mirror", "Readings", filepath.Base(sinkFile))
+	testutils.WaitForAssertionWithSleep(t, 10*time.Millisecond, 500, func(tb testing.TB) {
+		tb.Helper()
+		_, err := os.Stat(mirrored)
+		test.That(tb, err, test.ShouldBeNil)
+		_, err = os.Stat(sinkFile)
+		test.That(tb, os.IsNotExist(err), test.ShouldBeTrue)
+	})
+	_, err := os.Stat(cloudFile)
+	test.That(t, err, test.ShouldBeNil)
+
+	// Without any sinks, Sync requires the cloud connection.
+	s.Reconfigure(context.Background(), Config{
+		CaptureDir:            captureDir,
+		CaptureDisabled:       true,
+		MaximumNumSyncThreads: 1,
+		ScheduledSyncDisabled: true,
+	}, nil)
+	test.That(t, s.Sync(context.Background(), nil), test.ShouldBeError, "not connected to the cloud")
+}
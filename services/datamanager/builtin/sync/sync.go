@@ -40,6 +40,8 @@ const (
 	DatasetDir = "datasetUpload"
 	// grpcConnectionTimeout defines the timeout for getting a connection with app.viam.com.
 	grpcConnectionTimeout = 10 * time.Second
+	// httpUploadTimeout defines the timeout for uploading a single file to an http sink.
+	httpUploadTimeout = time.Minute
 	// durationBetweenAcquireConnection defines how long to wait after a call to cloud.AcquireConnection fails
 	// with a transient error.
 	durationBetweenAcquireConnection = time.Second
@@ -75,6 +77,8 @@ type Sync struct {
 	configCancelFunc func()
 
 	cloudConn cloudConn
+	// sinks are replaced on Reconfigure while the workers are stopped
+	sinks map[string]Sink
 
 	Scheduler        *goutils.StoppableWorkers
 	cloudConnManager *goutils.StoppableWorkers
@@ -148,6 +152,9 @@ func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.
 	// wait for workers to stop
 	s.workersWg.Wait()
 
+	closeSinks(s.sinks, s.logger)
+	s.sinks = newSinks(config.Sinks, config.CaptureDir, s.clock, s.logger)
+
 	// update config
 	s.configMu.Lock()
 	s.config = config
@@ -184,6 +191,7 @@ func (s *Sync) Reconfigure(_ context.Context, config Config, cloudConnSvc cloud.
 				config.DeleteEveryNthWhenDiskFull,
 				config.DiskUsageDeletionThreshold,
 				config.CaptureDirDeletionThreshold,
+				config.RetentionPolicies,
 				s.clock,
 				s.logger,
 			)
@@ -198,6 +206,7 @@ func (s *Sync) Close() {
 	s.FileDeletingWorkers.Stop()
 	s.Scheduler.Stop()
 	s.workersWg.Wait()
+	closeSinks(s.sinks, s.logger)
 	if s.cloudConnManager != nil {
 		s.cloudConnManager.Stop()
 	}
@@ -214,16 +223,25 @@ func (s *Sync) CloudConnReady() chan struct{} {
 // Sync performs a non-scheduled sync of the data in the capture directory.
 // If automated sync is also enabled, calling Sync will upload the files,
 // regardless of whether or not is the scheduled time.
+// When not connected to the cloud only the files of collectors syncing to a sink are uploaded.
 func (s *Sync) Sync(ctx context.Context, _ map[string]interface{}) error {
+	s.configMu.Lock()
+	config := s.config
+	s.configMu.Unlock()
+	cloudReady := s.cloudConnReady()
+	if !cloudReady && len(config.CollectorSinks) == 0 {
+		return errors.New("not connected to the cloud")
+	}
+	return s.walkDirsAndSendFilesToSync(ctx, config, cloudReady)
+}
+
+func (s *Sync) cloudConnReady() bool {
 	select {
 	case <-s.cloudConn.ready:
+		return true
 	default:
-		return errors.New("not connected to the cloud")
+		return false
 	}
-	s.configMu.Lock()
-	config := s.config
-	s.configMu.Unlock()
-	return s.walkDirsAndSendFilesToSync(ctx, config)
 }
 
 type cloudConn struct {
@@ -353,6 +371,12 @@ func (s *Sync) syncFile(config Config, filePath string) {
 		return
 	}
 
+	sink, err := s.sinkFor(config, filePath)
+	if err != nil {
+		s.logger.Warnf("ignoring request to sync file %s: %v", filePath, err)
+		return
+	}
+
 	// If the file is already being synced, do not kick off a new goroutine.
 	// The goroutine will again check and return early if sync is already in progress.
 	if !s.fileTracker.markInProgress(filePath) {
@@ -372,13 +396,31 @@ func (s *Sync) syncFile(config Config, filePath string) {
 	}
 
 	if data.IsDataCaptureFile(f) {
-		s.syncDataCaptureFile(f, config.CaptureDir, s.logger)
+		s.syncDataCaptureFile(f, sink, config.CaptureDir, s.logger)
 	} else {
-		s.syncArbitraryFile(f, config.Tags, []string{}, config.FileLastModifiedMillis, s.logger)
+		s.syncArbitraryFile(f, sink, config.Tags, []string{}, config.FileLastModifiedMillis, s.logger)
+	}
+}
+
+// sinkFor returns the sink the file at path should be synced to.
+func (s *Sync) sinkFor(config Config, path string) (Sink, error) {
+	name := config.sinkName(path)
+	if name == "" {
+		return s.cloudSink(), nil
+	}
+	sink, ok := s.sinks[name]
+	if !ok {
+		return nil, fmt.Errorf("sync sink %q is not available", name)
 	}
+	return sink, nil
+}
+
+// cloudSink must only be called once the cloud connection is ready.
+func (s *Sync) cloudSink() cloudSink {
+	return cloudSink{conn: s.cloudConn, clock: s.clock, logger: s.logger}
 }
 
-func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging.Logger) {
+func (s *Sync) syncDataCaptureFile(f *os.File, sink Sink, captureDir string, logger logging.Logger) {
 	captureFile, err := data.ReadCaptureFile(f)
 	// if you can't read the capture file's metadata field, close & move it to the failed directory
 	if err != nil {
@@ -400,7 +442,7 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 	retry := newExponentialRetry(s.configCtx, s.clock, s.logger, f.Name(), func(ctx context.Context) (uint64, error) {
 		msg := "error uploading data capture file %s, size: %s, md: %s"
 		errMetadata := fmt.Sprintf(msg, captureFile.GetPath(), data.FormatBytesI64(captureFile.Size()), captureFile.ReadMetadata())
-		bytesUploaded, err := uploadDataCaptureFile(ctx, captureFile, s.cloudConn, logger)
+		bytesUploaded, err := sink.UploadDataCaptureFile(ctx, captureFile)
 		if err != nil {
 			return 0, errors.Wrap(err, errMetadata)
 		}
@@ -446,10 +488,16 @@ func (s *Sync) syncDataCaptureFile(f *os.File, captureDir string, logger logging
 	}
 }
 
-func (s *Sync) syncArbitraryFile(f *os.File, tags, datasetIDs []string, fileLastModifiedMillis int, logger logging.Logger) {
+func (s *Sync) syncArbitraryFile(
+	f *os.File,
+	sink Sink,
+	tags, datasetIDs []string,
+	fileLastModifiedMillis int,
+	logger logging.Logger,
+) {
 	retry := newExponentialRetry(s.configCtx, s.clock, s.logger, f.Name(), func(ctx context.Context) (uint64, error) {
 		errMetadata := fmt.Sprintf("error uploading arbitrary file %s", f.Name())
-		bytesUploaded, err := uploadArbitraryFile(ctx, f, s.cloudConn, tags, datasetIDs, fileLastModifiedMillis, s.clock, logger)
+		bytesUploaded, err := sink.UploadArbitraryFile(ctx, f, tags, datasetIDs, fileLastModifiedMillis)
 		if err != nil {
 			return 0, errors.Wrap(err, errMetadata)
 		}
@@ -519,7 +567,7 @@ func (s *Sync) UploadBinaryDataToDatasets(ctx context.Context, binaryData []byte
 		}
 		// Since we wrote to the file, the file last modified time should be 0, indicating we should wait no time
 		// before deciding this file is ready for upload and is not still being written to.
-		s.syncArbitraryFile(f, tags, datasetIDs, 0, s.logger)
+		s.syncArbitraryFile(f, s.cloudSink(), tags, datasetIDs, 0, s.logger)
 	}()
 
 	return <-errChan
@@ -560,13 +608,16 @@ func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Confi
 		}
 
 		// wait for the cloud connection to be ready
-		// or the scheduler to be cancelled
-		select {
-		case <-ctx.Done():
-			return
-		case <-s.cloudConn.ready:
-			if !readyLogged {
-				readyLogged = true
+		// or the scheduler to be cancelled.
+		// Collectors syncing to a sink don't need the cloud connection.
+		if len(config.CollectorSinks) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-s.cloudConn.ready:
+				if !readyLogged {
+					readyLogged = true
+				}
 			}
 		}
 
@@ -575,11 +626,16 @@ func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Confi
 			return
 		case <-tkr.C:
 			shouldSync := readyToSyncDirectories(ctx, config, s.logger)
-			state := s.cloudConn.conn.GetState()
-			online := state == connectivity.Ready
-			if !online {
-				s.logger.Infof("data manager: NOT syncing data to the cloud as it's cloud connection is in state: %s"+
-					"; waiting for it to be in state: %s", state, connectivity.Ready)
+			online := false
+			if s.cloudConnReady() {
+				state := s.cloudConn.conn.GetState()
+				online = state == connectivity.Ready
+				if !online {
+					s.logger.Infof("data manager: NOT syncing data to the cloud as it's cloud connection is in state: %s"+
+						"; waiting for it to be in state: %s", state, connectivity.Ready)
+				}
+			}
+			if !online && len(config.CollectorSinks) == 0 {
 				continue
 			}
 
@@ -588,7 +644,7 @@ func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Confi
 				continue
 			}
 
-			if err := s.walkDirsAndSendFilesToSync(ctx, config); err != nil && !errors.Is(err, context.Canceled) {
+			if err := s.walkDirsAndSendFilesToSync(ctx, config, online); err != nil && !errors.Is(err, context.Canceled) {
 				goutils.UncheckedError(err)
 			}
 		}
@@ -597,7 +653,8 @@ func (s *Sync) runScheduler(ctx context.Context, tkr *clock.Ticker, config Confi
 
 // returns early with an error if either ctx is cancelled or if the reconfigure is called
 // while walkDirsAndSendFilesToSync.
-func (s *Sync) walkDirsAndSendFilesToSync(ctx context.Context, config Config) error {
+// When cloudReady is false only files which are synced to a sink are sent to the workers.
+func (s *Sync) walkDirsAndSendFilesToSync(ctx context.Context, config Config, cloudReady bool) error {
 	s.flushCollectors()
 	var errs []error
 	for _, dir := range config.SyncPaths() {
@@ -629,6 +686,17 @@ func (s *Sync) walkDirsAndSendFilesToSync(ctx context.Context, config Config) er
 				return nil
 			}
 
+			if data.IsCaptureIndexFile(path) {
+				if err := data.RemoveOrphanCaptureIndex(path); err != nil {
+					s.logger.Debugw("failed to remove orphan capture index", "path", path, "error", err)
+				}
+				return nil
+			}
+
+			if !cloudReady && config.sinkName(path) == "" {
+				return nil
+			}
+
 			// If a non data capture owned file was modified within the past lastModifiedMillis, do not sync it (data
 			// may still be being written).
 			// When using a mock clock in tests, s.clock.Since(info.ModTime()) can be negative since the file system will still use the system clock.
@@ -670,6 +738,7 @@ func isCompletedCaptureFile(path string) bool {
 func isNonCaptureFileThatIsNotBeingWrittenTo(timeSinceMod time.Duration, path string, info fs.FileInfo, fileLastModifiedMillis int) bool {
 	return filepath.Ext(path) != data.InProgressCaptureFileExt &&
 		filepath.Ext(path) != data.CompletedCaptureFileExt &&
+		!data.IsCaptureIndexFile(path) &&
 		timeSinceMod >= time.Duration(fileLastModifiedMillis)*time.Millisecond &&
 		// if the file size is 0 then there is nothing to sync from this arbitrary file
 		info.Size() > 0
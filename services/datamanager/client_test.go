@@ -4,11 +4,15 @@ import (
 	"context"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
+	datasyncpb "go.viam.com/api/app/datasync/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go.viam.com/rdk/components/camera"
 	viamgrpc "go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -76,6 +80,37 @@ func TestClient(t *testing.T) {
 		test.That(t, resp["command"], test.ShouldEqual, testutils.TestCommand["command"])
 		test.That(t, resp["data"], test.ShouldEqual, testutils.TestCommand["data"])
 
+		// QueryLocalData
+		start := time.Date(2025, 1, 1, 10, 2, 0, 0, time.UTC)
+		end := start.Add(3 * time.Minute)
+		var receivedQuery datamanager.LocalDataQuery
+		injectDS.DoCommandFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			query, extra, isQuery, err := datamanager.LocalDataQueryFromCommand(cmd)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, isQuery, test.ShouldBeTrue)
+			receivedQuery = query
+			extraOptions = extra
+			return datamanager.LocalDataQueryResponse([]*datasyncpb.SensorData{{
+				Metadata: &datasyncpb.SensorMetadata{TimeRequested: timestamppb.New(start)},
+				Data:     &datasyncpb.SensorData_Binary{Binary: []byte("frame")},
+			}})
+		}
+		query := datamanager.LocalDataQuery{
+			Resource: camera.Named("cam"),
+			Method:   "ReadImage",
+			Start:    start,
+			End:      end,
+			Limit:    10,
+		}
+		extra = map[string]interface{}{"foo": "QueryLocalData"}
+		sensorData, err := datamanager.QueryLocalData(context.Background(), client, query, extra)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, receivedQuery, test.ShouldResemble, query)
+		test.That(t, extraOptions, test.ShouldResemble, extra)
+		test.That(t, len(sensorData), test.ShouldEqual, 1)
+		test.That(t, sensorData[0].GetBinary(), test.ShouldResemble, []byte("frame"))
+		test.That(t, sensorData[0].GetMetadata().GetTimeRequested().AsTime(), test.ShouldEqual, start)
+
 		test.That(t, client.Close(context.Background()), test.ShouldBeNil)
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
@@ -7,6 +7,8 @@ package datamanager
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"image"
 	"reflect"
 	"slices"
@@ -14,6 +16,7 @@ import (
 	datasyncpb "go.viam.com/api/app/datasync/v1"
 	servicepb "go.viam.com/api/service/datamanager/v1"
 
+	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/utils"
@@ -58,6 +61,14 @@ type Service interface {
 		mimeType datasyncpb.MimeType, extra map[string]interface{}) error
 }
 
+// CaptureFileSealedSubscriber is implemented by data managers capturing on this machine, so that
+// the rest of the machine can act on the capture files they complete.
+type CaptureFileSealedSubscriber interface {
+	// SubscribeCaptureFileSealed sends every capture file the data manager completes from now on
+	// to ch, until the returned function is called. Files are dropped when ch is full.
+	SubscribeCaptureFileSealed(ch chan<- data.SealedCaptureFile) (unsubscribe func())
+}
+
 // SubtypeName is the name of the type of service.
 const SubtypeName = "data_manager"
 
@@ -169,6 +180,11 @@ type DataCaptureConfig struct {
 	Disabled           bool                   `json:"disabled"`
 	Tags               []string               `json:"tags,omitempty"`
 	CaptureDirectory   string                 `json:"capture_directory"`
+	Retention          *RetentionPolicy       `json:"retention,omitempty"`
+	Trigger            *CaptureTrigger        `json:"trigger,omitempty"`
+	// SyncSink, when set, names the data manager sync sink the collector's capture files are
+	// synced to instead of the Viam cloud.
+	SyncSink string `json:"sync_sink,omitempty"`
 }
 
 // Equals checks if one capture config is equal to another.
@@ -181,7 +197,159 @@ func (c *DataCaptureConfig) Equals(other *DataCaptureConfig) bool {
 		c.Disabled == other.Disabled &&
 		slices.Compare(c.Tags, other.Tags) == 0 &&
 		reflect.DeepEqual(c.AdditionalParams, other.AdditionalParams) &&
-		c.CaptureDirectory == other.CaptureDirectory
+		c.CaptureDirectory == other.CaptureDirectory &&
+		reflect.DeepEqual(c.Retention, other.Retention) &&
+		reflect.DeepEqual(c.Trigger, other.Trigger) &&
+		c.SyncSink == other.SyncSink
+}
+
+// RetentionPriority orders collectors by how important it is to keep their capture files
+// when the disk fills up. Files of lower priority collectors are deleted first.
+type RetentionPriority string
+
+// The supported retention priorities, from least to most important.
+const (
+	RetentionPriorityLow    RetentionPriority = "low"
+	RetentionPriorityNormal RetentionPriority = "normal"
+	RetentionPriorityHigh   RetentionPriority = "high"
+	// RetentionPriorityCritical files are never deleted due to disk pressure, only due to
+	// an explicit max_age_mins or max_bytes limit.
+	RetentionPriorityCritical RetentionPriority = "critical"
+)
+
+// Rank returns the relative importance of the priority. The zero value is treated as
+// RetentionPriorityNormal.
+func (p RetentionPriority) Rank() int {
+	switch p {
+	case RetentionPriorityLow:
+		return 0
+	case RetentionPriorityHigh:
+		return 2
+	case RetentionPriorityCritical:
+		return 3
+	case RetentionPriorityNormal:
+	}
+	return 1
+}
+
+// RetentionPolicy describes how long the capture files of a single collector are kept on disk
+// and in what order they are deleted when the disk fills up before they can be synced.
+type RetentionPolicy struct {
+	// Priority defaults to RetentionPriorityNormal.
+	Priority RetentionPriority `json:"priority,omitempty"`
+	// MaxAgeMins, when non zero, causes completed capture files older than this to be deleted
+	// regardless of disk usage.
+	MaxAgeMins float64 `json:"max_age_mins,omitempty"`
+	// MaxBytes, when non zero, caps the total size of the collector's completed capture files.
+	// The oldest files are deleted first when the cap is exceeded, regardless of disk usage.
+	MaxBytes int64 `json:"max_bytes,omitempty"`
+	// DownsampleEveryNth, when non zero, overrides the data manager's delete_every_nth_when_disk_full
+	// for this collector, so that under disk pressure every Nth file is deleted, thinning the
+	// collector's data rather than dropping whole time ranges.
+	DownsampleEveryNth int `json:"downsample_every_nth,omitempty"`
+}
+
+// Validate returns an error if the retention policy is invalid.
+func (p *RetentionPolicy) Validate() error {
+	switch p.Priority {
+	case "", RetentionPriorityLow, RetentionPriorityNormal, RetentionPriorityHigh, RetentionPriorityCritical:
+	default:
+		return fmt.Errorf("retention priority %q is not one of %q, %q, %q or %q", p.Priority,
+			RetentionPriorityLow, RetentionPriorityNormal, RetentionPriorityHigh, RetentionPriorityCritical)
+	}
+	if p.MaxAgeMins < 0 {
+		return errors.New("retention max_age_mins can't be negative")
+	}
+	if p.MaxBytes < 0 {
+		return errors.New("retention max_bytes can't be negative")
+	}
+	if p.DownsampleEveryNth < 0 {
+		return errors.New("retention downsample_every_nth can't be negative")
+	}
+	return nil
+}
+
+// CaptureTriggerType is the kind of condition that causes a triggered collector to capture.
+type CaptureTriggerType string
+
+// The supported capture trigger types.
+const (
+	// CaptureTriggerSensorThreshold fires while a sensor reading is above or below a threshold.
+	CaptureTriggerSensorThreshold CaptureTriggerType = "sensor_threshold"
+	// CaptureTriggerDetection fires while a vision service detects an object with a label in the
+	// images of a camera.
+	CaptureTriggerDetection CaptureTriggerType = "detection"
+	// CaptureTriggerEvent fires when the event is sent to the data manager with a TriggerCaptureCommand
+	// DoCommand.
+	CaptureTriggerEvent CaptureTriggerType = "event"
+)
+
+// TriggerCaptureCommand is the DoCommand key used to send an event to the data manager, firing the
+// triggers of type CaptureTriggerEvent with that event, e.g. {"trigger_capture": "door_opened"}.
+const TriggerCaptureCommand = "trigger_capture"
+
+// CaptureTrigger limits a collector to capturing around the moments a condition is met, rather than
+// continuously. The collector still captures at its capture_frequency_hz, but only writes the
+// readings from PreSeconds before the trigger fires until PostSeconds after it last fired.
+type CaptureTrigger struct {
+	Type CaptureTriggerType `json:"type"`
+	// Sensor, Key, Above and Below configure a sensor_threshold trigger. Key is the name of the
+	// reading to compare, with dots separating the keys of nested readings. The trigger fires while
+	// the reading is greater than Above or less than Below.
+	Sensor string   `json:"sensor,omitempty"`
+	Key    string   `json:"key,omitempty"`
+	Above  *float64 `json:"above,omitempty"`
+	Below  *float64 `json:"below,omitempty"`
+	// VisionService, Camera, Label and MinConfidence configure a detection trigger. The trigger fires
+	// while a detection with the label and at least MinConfidence is found in the camera's images.
+	VisionService string  `json:"vision_service,omitempty"`
+	Camera        string  `json:"camera,omitempty"`
+	Label         string  `json:"label,omitempty"`
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+	// Event configures an event trigger.
+	Event string `json:"event,omitempty"`
+	// CheckFrequencyHz is how often the condition of a sensor_threshold or detection trigger is
+	// checked. Defaults to 1.
+	CheckFrequencyHz float64 `json:"check_frequency_hz,omitempty"`
+	// PreSeconds is how much of the data captured before the trigger fires is kept in memory, to be
+	// written once it fires.
+	PreSeconds float64 `json:"pre_seconds,omitempty"`
+	// PostSeconds is how long data is written after the trigger last fired.
+	PostSeconds float64 `json:"post_seconds,omitempty"`
+}
+
+// Validate returns an error if the capture trigger is invalid.
+func (t *CaptureTrigger) Validate() error {
+	switch t.Type {
+	case CaptureTriggerSensorThreshold:
+		if t.Sensor == "" || t.Key == "" {
+			return errors.New("sensor_threshold trigger requires a sensor and a key")
+		}
+		if t.Above == nil && t.Below == nil {
+			return errors.New("sensor_threshold trigger requires above, below or both")
+		}
+	case CaptureTriggerDetection:
+		if t.VisionService == "" || t.Camera == "" || t.Label == "" {
+			return errors.New("detection trigger requires a vision_service, a camera and a label")
+		}
+	case CaptureTriggerEvent:
+		if t.Event == "" {
+			return errors.New("event trigger requires an event")
+		}
+		if t.PreSeconds == 0 && t.PostSeconds == 0 {
+			return errors.New("event trigger requires pre_seconds, post_seconds or both")
+		}
+	default:
+		return fmt.Errorf("trigger type %q is not one of %q, %q or %q", t.Type,
+			CaptureTriggerSensorThreshold, CaptureTriggerDetection, CaptureTriggerEvent)
+	}
+	if t.CheckFrequencyHz < 0 {
+		return errors.New("trigger check_frequency_hz can't be negative")
+	}
+	if t.PreSeconds < 0 || t.PostSeconds < 0 {
+		return errors.New("trigger pre_seconds and post_seconds can't be negative")
+	}
+	return nil
 }
 
 // ShouldSyncKey is a special key we use within a modular sensor to pass a boolean
@@ -165,6 +165,16 @@ func TestDataCaptureConfig(t *testing.T) {
 			},
 			equal: false,
 		},
+		{
+			name: "different Retention are not equal",
+			a: &DataCaptureConfig{
+				Retention: &RetentionPolicy{Priority: RetentionPriorityHigh},
+			},
+			b: &DataCaptureConfig{
+				Retention: &RetentionPolicy{Priority: RetentionPriorityLow},
+			},
+			equal: false,
+		},
 	}
 
 	for _, tc := range tcs {
@@ -174,3 +184,21 @@ func TestDataCaptureConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestRetentionPolicyValidate(t *testing.T) {
+	test.That(t, (&RetentionPolicy{}).Validate(), test.ShouldBeNil)
+	test.That(t, (&RetentionPolicy{
+		Priority:           RetentionPriorityCritical,
+		MaxAgeMins:         60,
+		MaxBytes:           1024,
+		DownsampleEveryNth: 2,
+	}).Validate(), test.ShouldBeNil)
+	test.That(t, (&RetentionPolicy{Priority: "urgent"}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&RetentionPolicy{MaxAgeMins: -1}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&RetentionPolicy{MaxBytes: -1}).Validate(), test.ShouldNotBeNil)
+	test.That(t, (&RetentionPolicy{DownsampleEveryNth: -1}).Validate(), test.ShouldNotBeNil)
+
+	test.That(t, RetentionPriority("").Rank(), test.ShouldEqual, RetentionPriorityNormal.Rank())
+	test.That(t, RetentionPriorityLow.Rank(), test.ShouldBeLessThan, RetentionPriorityNormal.Rank())
+	test.That(t, RetentionPriorityHigh.Rank(), test.ShouldBeLessThan, RetentionPriorityCritical.Rank())
+}
@@ -0,0 +1,140 @@
+package datamanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	datasyncpb "go.viam.com/api/app/datasync/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/resource"
+)
+
+// LocalDataQuery selects the data captured by one collector that is still stored on the machine.
+type LocalDataQuery struct {
+	// Resource and Method identify the collector whose data is read.
+	Resource resource.Name
+	Method   string
+	// Start and End bound the time the data was requested (inclusive). A zero value leaves that
+	// end of the time window unbounded.
+	Start time.Time
+	End   time.Time
+	// Limit caps the number of results when positive.
+	Limit int
+}
+
+// QueryLocalDataCommand is the DoCommand key used to query the data that is still stored on the
+// machine, see QueryLocalData.
+const QueryLocalDataCommand = "query_local_data"
+
+const queryLocalDataResult = "sensor_data"
+
+// QueryLocalData returns the data matching the query that is still stored on the machine of the data
+// manager svc, ordered by time. Data that has already been synced and deleted is not returned. The
+// query is sent as a QueryLocalDataCommand DoCommand, so svc may be local or remote.
+func QueryLocalData(
+	ctx context.Context,
+	svc Service,
+	query LocalDataQuery,
+	extra map[string]interface{},
+) ([]*datasyncpb.SensorData, error) {
+	resp, err := svc.DoCommand(ctx, localDataQueryToCommand(query, extra))
+	if err != nil {
+		return nil, err
+	}
+	return sensorDataFromResponse(resp)
+}
+
+func localDataQueryToCommand(query LocalDataQuery, extra map[string]interface{}) map[string]interface{} {
+	args := map[string]interface{}{
+		"resource": query.Resource.String(),
+		"method":   query.Method,
+		"limit":    query.Limit,
+	}
+	if !query.Start.IsZero() {
+		args["start"] = query.Start.Format(time.RFC3339Nano)
+	}
+	if !query.End.IsZero() {
+		args["end"] = query.End.Format(time.RFC3339Nano)
+	}
+	if extra != nil {
+		args["extra"] = extra
+	}
+	return map[string]interface{}{QueryLocalDataCommand: args}
+}
+
+// LocalDataQueryFromCommand returns the query and extra encoded in cmd. The boolean return value
+// is false when cmd is not a QueryLocalDataCommand.
+func LocalDataQueryFromCommand(cmd map[string]interface{}) (LocalDataQuery, map[string]interface{}, bool, error) {
+	rawArgs, ok := cmd[QueryLocalDataCommand]
+	if !ok {
+		return LocalDataQuery{}, nil, false, nil
+	}
+	args, ok := rawArgs.(map[string]interface{})
+	if !ok {
+		return LocalDataQuery{}, nil, true, fmt.Errorf("expected %s arguments to be a map, got %T", QueryLocalDataCommand, rawArgs)
+	}
+
+	var query LocalDataQuery
+	resourceName, _ := args["resource"].(string)
+	name, err := resource.NewFromString(resourceName)
+	if err != nil {
+		return LocalDataQuery{}, nil, true, err
+	}
+	query.Resource = name
+	query.Method, _ = args["method"].(string)
+	if limit, ok := args["limit"].(float64); ok {
+		query.Limit = int(limit)
+	}
+	for key, bound := range map[string]*time.Time{"start": &query.Start, "end": &query.End} {
+		formatted, ok := args[key].(string)
+		if !ok {
+			continue
+		}
+		if *bound, err = time.Parse(time.RFC3339Nano, formatted); err != nil {
+			return LocalDataQuery{}, nil, true, fmt.Errorf("invalid %s time: %w", key, err)
+		}
+	}
+
+	extra, _ := args["extra"].(map[string]interface{})
+	return query, extra, true, nil
+}
+
+// LocalDataQueryResponse returns the DoCommand response to a QueryLocalDataCommand which found sensorData.
+func LocalDataQueryResponse(sensorData []*datasyncpb.SensorData) (map[string]interface{}, error) {
+	encoded := make([]interface{}, 0, len(sensorData))
+	for _, datum := range sensorData {
+		asJSON, err := protojson.Marshal(datum)
+		if err != nil {
+			return nil, err
+		}
+		var asMap map[string]interface{}
+		if err := json.Unmarshal(asJSON, &asMap); err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, asMap)
+	}
+	return map[string]interface{}{queryLocalDataResult: encoded}, nil
+}
+
+func sensorDataFromResponse(resp map[string]interface{}) ([]*datasyncpb.SensorData, error) {
+	encoded, ok := resp[queryLocalDataResult].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected %s in %s response", queryLocalDataResult, QueryLocalDataCommand)
+	}
+	sensorData := make([]*datasyncpb.SensorData, 0, len(encoded))
+	for _, asMap := range encoded {
+		asJSON, err := json.Marshal(asMap)
+		if err != nil {
+			return nil, err
+		}
+		datum := &datasyncpb.SensorData{}
+		if err := protojson.Unmarshal(asJSON, datum); err != nil {
+			return nil, err
+		}
+		sensorData = append(sensorData, datum)
+	}
+	return sensorData, nil
+}
@@ -23,6 +23,7 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/structpb"
 
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/motionplan"
@@ -57,6 +58,7 @@ const (
 	DoPlan              = "plan"
 	DoExecute           = "execute"
 	DoExecuteCheckStart = "executeCheckStart"
+	DoServo             = "servo"
 )
 
 const (
@@ -90,6 +92,11 @@ type Config struct {
 	LogPlannerErrors            bool   `json:"log_planner_errors"`
 	LogSlowPlanThresholdMS      int    `json:"log_slow_plan_threshold_ms"`
 
+	// PlanCacheSize is how many plans to keep for reuse by repeated requests, zero disables reusing plans.
+	// Plans are kept across restarts if PlanCacheDir is set.
+	PlanCacheSize int    `json:"plan_cache_size"`
+	PlanCacheDir  string `json:"plan_cache_dir"`
+
 	// example { "arm" : { "3" : { "min" : 0, "max" : 2 } } }
 	InputRangeOverride map[string]map[string]referenceframe.Limit `json:"input_range_override"`
 }
@@ -121,6 +128,14 @@ func (c *Config) Validate(path string) ([]string, []string, error) {
 		return nil, nil, fmt.Errorf("need a plan_file_path if you sent LogSlowPlanThresholdMS to %v", c.LogSlowPlanThresholdMS)
 	}
 
+	if c.PlanCacheSize < 0 {
+		return nil, nil, fmt.Errorf("cannot configure a plan_cache_size of %d, number must be positive", c.PlanCacheSize)
+	}
+
+	if c.PlanCacheDir != "" && c.PlanCacheSize == 0 {
+		return nil, nil, fmt.Errorf("need a plan_cache_size if you sent plan_cache_dir to %v", c.PlanCacheDir)
+	}
+
 	return []string{framesystem.InternalServiceName.String()}, nil, nil
 }
 
@@ -135,6 +150,10 @@ type builtIn struct {
 	components              map[string]resource.Resource
 	logger                  logging.Logger
 	configuredDefaultExtras map[string]any
+	planCache               *armplanning.PlanCache
+	executions              *executionState
+	servosMu                sync.Mutex
+	servos                  map[string]*servoStream
 }
 
 // NewBuiltIn returns a new move and grab service for the given robot.
@@ -145,6 +164,8 @@ func NewBuiltIn(
 		Named:                   conf.ResourceName().AsNamed(),
 		logger:                  logger,
 		configuredDefaultExtras: make(map[string]any),
+		executions:              newExecutionState(),
+		servos:                  make(map[string]*servoStream),
 	}
 
 	if err := ms.Reconfigure(ctx, deps, conf); err != nil {
@@ -166,6 +187,18 @@ func (ms *builtIn) Reconfigure(
 	if err != nil {
 		return err
 	}
+	// executions keep driving with the dependencies they started with, which may be closed or replaced now
+	ms.executions.stopAll()
+	// keep the cached plans unless where they are kept changed
+	if ms.conf == nil || ms.conf.PlanCacheSize != config.PlanCacheSize || ms.conf.PlanCacheDir != config.PlanCacheDir {
+		ms.planCache = nil
+		if config.PlanCacheSize > 0 {
+			ms.planCache, err = armplanning.NewPlanCache(config.PlanCacheSize, config.PlanCacheDir, ms.logger.Sublogger("plan_cache"))
+			if err != nil {
+				return err
+			}
+		}
+	}
 	ms.conf = config
 
 	if config.LogFilePath != "" {
@@ -203,6 +236,8 @@ func (ms *builtIn) Reconfigure(
 }
 
 func (ms *builtIn) Close(ctx context.Context) error {
+	ms.executions.stopAll()
+	ms.stopServos()
 	return nil
 }
 
@@ -216,16 +251,62 @@ func (ms *builtIn) Move(ctx context.Context, req motion.MoveReq) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	err = ms.execute(ctx, plan.Trajectory(), math.MaxFloat64)
+	var timed motionplan.TimedTrajectory
+	if timedPlan, ok := plan.(motionplan.TimedPlan); ok {
+		timed = timedPlan.TimedTrajectory()
+	}
+	err = ms.execute(ctx, plan.Trajectory(), timed, math.MaxFloat64)
 	return err == nil, err
 }
 
 func (ms *builtIn) MoveOnMap(ctx context.Context, req motion.MoveOnMapReq) (motion.ExecutionID, error) {
-	return uuid.Nil, fmt.Errorf("MoveOnMap not supported by builtin")
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if req.Extra == nil {
+		req.Extra = make(map[string]any)
+	}
+	ms.applyDefaultExtras(req.Extra)
+	// the new plan starts from where the base is, so it must not still be driving a previous one
+	if err := ms.executions.stopActive(req.ComponentName); err != nil {
+		return uuid.Nil, err
+	}
+	mr, err := ms.newMoveOnMapRequest(ctx, req)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	plan, err := mr.plan(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return ms.executions.start(ctx, req.ComponentName, plan, nil, func(ctx context.Context, ex *execution) error {
+		return mr.run(ctx, ex, plan)
+	})
 }
 
 func (ms *builtIn) MoveOnGlobe(ctx context.Context, req motion.MoveOnGlobeReq) (motion.ExecutionID, error) {
-	return uuid.Nil, fmt.Errorf("MoveOnGlobeReqe not supported by builtin")
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if req.Extra == nil {
+		req.Extra = make(map[string]any)
+	}
+	ms.applyDefaultExtras(req.Extra)
+	// the new plan starts from where the base is, so it must not still be driving a previous one
+	if err := ms.executions.stopActive(req.ComponentName); err != nil {
+		return uuid.Nil, err
+	}
+	mr, anchor, err := ms.newMoveOnGlobeRequest(ctx, req)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	plan, err := mr.plan(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return ms.executions.start(ctx, req.ComponentName, plan, anchor, func(ctx context.Context, ex *execution) error {
+		return mr.run(ctx, ex, plan)
+	})
 }
 
 // GetPose is deprecated.
@@ -246,24 +327,24 @@ func (ms *builtIn) StopPlan(
 	ctx context.Context,
 	req motion.StopPlanReq,
 ) error {
-	return fmt.Errorf("StopPlan not supported by builtin")
+	return ms.executions.stop(req.ComponentName)
 }
 
 func (ms *builtIn) ListPlanStatuses(
 	ctx context.Context,
 	req motion.ListPlanStatusesReq,
 ) ([]motion.PlanStatusWithID, error) {
-	return nil, fmt.Errorf("ListPlanStatuses not supported by builtin")
+	return ms.executions.listPlanStatuses(req.OnlyActivePlans), nil
 }
 
 func (ms *builtIn) PlanHistory(
 	ctx context.Context,
 	req motion.PlanHistoryReq,
 ) ([]motion.PlanWithStatus, error) {
-	return nil, fmt.Errorf("PlanHistory not supported by builtin")
+	return ms.executions.planHistory(req)
 }
 
-// DoCommand supports two commands which are specified through the command map
+// DoCommand supports three commands which are specified through the command map
 //   - DoPlan generates and returns a Trajectory for a given motionpb.MoveRequest without executing it
 //     required key: DoPlan
 //     input value: a motionpb.MoveRequest which will be used to create a Trajectory
@@ -272,7 +353,19 @@ func (ms *builtIn) PlanHistory(
 //     required key: DoExecute
 //     input value: a motionplan.Trajectory
 //     output value: a bool
+//   - DoServo sends one target to the servo of a component, see motion.Servoer, starting it if it isn't running. The servo
+//     stops when no target arrives within its timeout or a command with stop set arrives. It is handled on its own, other
+//     commands in the same map are ignored.
+//     required key: DoServo
+//     input value: a map with the component_name and either a twist, given by linear (mm/s) and angular (rad/s) vectors with
+//     x, y and z keys, or a pose with x, y, z, o_x, o_y, o_z and theta (degrees) keys in frame (the world frame if unset).
+//     The frequency_hz, timeout_ms, max_linear_mm_per_sec and max_angular_degs_per_sec keys configure a servo when it starts.
+//     output value: a bool
 func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if req, ok := cmd[DoServo]; ok {
+		// the servo runs after the command returns, so the service must not stay locked
+		return ms.doServo(ctx, req)
+	}
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
 	resp := make(map[string]interface{}, 0)
@@ -349,7 +442,7 @@ func (ms *builtIn) DoCommand(ctx context.Context, cmd map[string]interface{}) (m
 
 			resp[DoExecuteCheckStart] = "resource at starting location"
 		}
-		if err := ms.execute(ctx, trajectory, epsilon); err != nil {
+		if err := ms.execute(ctx, trajectory, nil, epsilon); err != nil {
 			return nil, err
 		}
 		resp[DoExecute] = true
@@ -490,7 +583,12 @@ func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq, logger logging.
 	}
 
 	start := time.Now()
-	plan, _, err := armplanning.PlanMotion(ctx, logger, planRequest)
+	var plan motionplan.Plan
+	if ms.planCache != nil {
+		plan, _, err = ms.planCache.PlanMotion(ctx, logger, planRequest)
+	} else {
+		plan, _, err = armplanning.PlanMotion(ctx, logger, planRequest)
+	}
 	if ms.conf.shouldWritePlan(start, err) {
 		var traceID string
 		if span := trace.FromContext(ctx); span != nil {
@@ -505,10 +603,15 @@ func (ms *builtIn) plan(ctx context.Context, req motion.MoveReq, logger logging.
 	return plan, err
 }
 
-func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory, epsilon float64) error {
+func (ms *builtIn) execute(
+	ctx context.Context, trajectory motionplan.Trajectory, timed motionplan.TimedTrajectory, epsilon float64,
+) error {
 	// Batch GoToInputs calls if possible; components may want to blend between inputs
 	combinedSteps := []map[string][][]referenceframe.Input{}
 	currStep := map[string][][]referenceframe.Input{}
+	// the indices in the trajectory of the inputs of each batch, to find their timing
+	combinedIndices := []map[string][]int{}
+	currIndices := map[string][]int{}
 	for i, step := range trajectory {
 		if i == 0 {
 			for name, inputs := range step {
@@ -533,6 +636,7 @@ func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory
 						name, epsilon, inputs, curr)
 				}
 				currStep[name] = append(currStep[name], inputs)
+				currIndices[name] = append(currIndices[name], i)
 			}
 			continue
 		}
@@ -569,18 +673,22 @@ func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory
 			if reset {
 				combinedSteps = append(combinedSteps, currStep)
 				currStep = map[string][][]referenceframe.Input{}
+				combinedIndices = append(combinedIndices, currIndices)
+				currIndices = map[string][]int{}
 			}
 			for name, inputs := range step {
 				if len(inputs) == 0 {
 					continue
 				}
 				currStep[name] = append(currStep[name], inputs)
+				currIndices[name] = append(currIndices[name], i)
 			}
 		}
 	}
 	combinedSteps = append(combinedSteps, currStep)
+	combinedIndices = append(combinedIndices, currIndices)
 
-	for _, step := range combinedSteps {
+	for stepIndex, step := range combinedSteps {
 		for name, inputs := range step {
 			if len(inputs) == 0 {
 				continue
@@ -593,20 +701,55 @@ func (ms *builtIn) execute(ctx context.Context, trajectory motionplan.Trajectory
 			if err != nil {
 				return err
 			}
-			if err := ie.GoToInputs(ctx, inputs...); err != nil {
-				// If there is an error on GoToInputs, stop the component if possible before returning the error
+			var moveErr error
+			if a, ok := r.(arm.Arm); ok && len(timed) > 0 {
+				// arms which can follow a timed trajectory are given the timing of the plan
+				indices := combinedIndices[stepIndex][name]
+				if indices[0] > 0 {
+					// a later batch starts where the previous one left the arm, so it is timed from there
+					indices = append([]int{indices[0] - 1}, indices...)
+					inputs = append([][]referenceframe.Input{trajectory[indices[0]][name]}, inputs...)
+				}
+				timing, err := frameTiming(timed, name, indices)
+				if err != nil {
+					return err
+				}
+				moveErr = a.MoveThroughJointPositions(ctx, inputs, nil, timing.ToExtra(nil))
+			} else {
+				moveErr = ie.GoToInputs(ctx, inputs...)
+			}
+			if moveErr != nil {
+				// If there is an error moving the component, stop it if possible before returning the error
 				if actuator, ok := r.(inputEnabledActuator); ok {
 					if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
-						return errors.Wrap(err, stopErr.Error())
+						return errors.Wrap(moveErr, stopErr.Error())
 					}
 				}
-				return err
+				return moveErr
 			}
 		}
 	}
 	return nil
 }
 
+// frameTiming returns the timing of the inputs of a frame at the given indices of a timed trajectory, measured from
+// the first of them.
+func frameTiming(timed motionplan.TimedTrajectory, frameName string, indices []int) (*arm.TrajectoryTiming, error) {
+	timing := &arm.TrajectoryTiming{}
+	for _, i := range indices {
+		if i >= len(timed) {
+			return nil, fmt.Errorf("timed trajectory has %d waypoints but the plan has more", len(timed))
+		}
+		velocities, ok := timed[i].Velocities[frameName]
+		if !ok {
+			return nil, fmt.Errorf("frame named %s not found in timed trajectory", frameName)
+		}
+		timing.Times = append(timing.Times, timed[i].Time-timed[indices[0]].Time)
+		timing.Velocities = append(timing.Velocities, velocities)
+	}
+	return timing, nil
+}
+
 // applyDefaultExtras iterates through the list of default extras configured on the builtIn motion service and adds them to the
 // given map of extras if the key does not already exist.
 func (ms *builtIn) applyDefaultExtras(extras map[string]any) {
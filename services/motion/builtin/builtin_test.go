@@ -17,6 +17,7 @@ import (
 	"go.viam.com/utils/protoutils"
 	"google.golang.org/protobuf/encoding/protojson"
 
+	"go.viam.com/rdk/components/arm"
 	_ "go.viam.com/rdk/components/register"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
@@ -28,6 +29,7 @@ import (
 	robotimpl "go.viam.com/rdk/robot/impl"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
 )
 
 func setupMotionServiceFromConfig(t *testing.T, configFilename string) (motion.Service, func()) {
@@ -591,6 +593,34 @@ func TestConfiguredDefaultExtras(t *testing.T) {
 		_, _, err := cfg.Validate("")
 		test.That(t, err, test.ShouldNotBeNil)
 	})
+
+	t.Run("configure plan cache", func(t *testing.T) {
+		cfg := &Config{PlanCacheSize: -1}
+		_, _, err := cfg.Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		cfg = &Config{PlanCacheDir: t.TempDir()}
+		_, _, err = cfg.Validate("")
+		test.That(t, err, test.ShouldNotBeNil)
+
+		cfg = &Config{PlanCacheSize: 10, PlanCacheDir: t.TempDir()}
+		_, _, err = cfg.Validate("")
+		test.That(t, err, test.ShouldBeNil)
+		ms, err := NewBuiltIn(ctx, nil, resource.Config{ConvertedAttributes: cfg}, logger)
+		test.That(t, err, test.ShouldBeNil)
+		defer test.That(t, ms.Close(ctx), test.ShouldBeNil)
+		planCache := ms.(*builtIn).planCache
+		test.That(t, planCache, test.ShouldNotBeNil)
+
+		// reconfiguring keeps the cached plans unless the cache changes
+		test.That(t, ms.Reconfigure(ctx, nil, resource.Config{ConvertedAttributes: &Config{
+			PlanCacheSize: 10, PlanCacheDir: cfg.PlanCacheDir, NumThreads: 2,
+		}}), test.ShouldBeNil)
+		test.That(t, ms.(*builtIn).planCache, test.ShouldEqual, planCache)
+
+		test.That(t, ms.Reconfigure(ctx, nil, resource.Config{ConvertedAttributes: &Config{}}), test.ShouldBeNil)
+		test.That(t, ms.(*builtIn).planCache, test.ShouldBeNil)
+	})
 }
 
 func TestConfigureJointLimits(t *testing.T) {
@@ -723,3 +753,95 @@ func TestWritePlanRequest(t *testing.T) {
 	test.That(t, planFile.IsDir(), test.ShouldBeFalse)
 	test.That(t, filepath.Ext(planFile.Name()), test.ShouldEqual, ".json")
 }
+
+func TestExecuteTimedTrajectory(t *testing.T) {
+	ctx := context.Background()
+	var gotPositions [][]referenceframe.Input
+	var gotExtra map[string]interface{}
+	a := inject.NewArm("arm")
+	a.CurrentInputsFunc = func(ctx context.Context) ([]referenceframe.Input, error) {
+		return []referenceframe.Input{0, 0}, nil
+	}
+	// the injected arm only calls MoveThroughJointPositionsFunc when MoveToJointPositionsFunc is set
+	a.MoveToJointPositionsFunc = func(ctx context.Context, positions []referenceframe.Input, extra map[string]interface{}) error {
+		return nil
+	}
+	a.MoveThroughJointPositionsFunc = func(
+		ctx context.Context, positions [][]referenceframe.Input, options *arm.MoveOptions, extra map[string]interface{},
+	) error {
+		gotPositions, gotExtra = positions, extra
+		return nil
+	}
+	a.GoToInputsFunc = func(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+		return a.MoveThroughJointPositions(ctx, inputSteps, nil, nil)
+	}
+	ms := &builtIn{components: map[string]resource.Resource{"arm": a}}
+
+	trajectory := motionplan.Trajectory{
+		{"arm": {0, 0}},
+		{"arm": {0.5, 0}},
+		{"arm": {1, 0.5}},
+	}
+	timed := motionplan.TimedTrajectory{
+		{Time: 0, Inputs: trajectory[0], Velocities: map[string][]float64{"arm": {0, 0}}},
+		{Time: time.Second, Inputs: trajectory[1], Velocities: map[string][]float64{"arm": {0.5, 0.25}}},
+		{Time: 2 * time.Second, Inputs: trajectory[2], Velocities: map[string][]float64{"arm": {0, 0}}},
+	}
+	test.That(t, ms.execute(ctx, trajectory, timed, math.MaxFloat64), test.ShouldBeNil)
+	test.That(t, gotPositions, test.ShouldResemble, [][]referenceframe.Input{{0, 0}, {0.5, 0}, {1, 0.5}})
+	timing, err := arm.TrajectoryTimingFromExtra(gotExtra, len(gotPositions))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, timing.Times, test.ShouldResemble, []time.Duration{0, time.Second, 2 * time.Second})
+	test.That(t, timing.Velocities, test.ShouldResemble, [][]float64{{0, 0}, {0.5, 0.25}, {0, 0}})
+
+	// arms are given no timing when the plan was not timed
+	test.That(t, ms.execute(ctx, trajectory, nil, math.MaxFloat64), test.ShouldBeNil)
+	test.That(t, gotExtra, test.ShouldBeNil)
+}
+
+func TestExecuteTimedTrajectoryBatches(t *testing.T) {
+	ctx := context.Background()
+	gotPositions := map[string][][]referenceframe.Input{}
+	gotExtras := map[string]map[string]interface{}{}
+	newArm := func(name string) *inject.Arm {
+		a := inject.NewArm(name)
+		a.CurrentInputsFunc = func(ctx context.Context) ([]referenceframe.Input, error) {
+			return []referenceframe.Input{0, 0}, nil
+		}
+		a.MoveToJointPositionsFunc = func(ctx context.Context, positions []referenceframe.Input, extra map[string]interface{}) error {
+			return nil
+		}
+		a.MoveThroughJointPositionsFunc = func(
+			ctx context.Context, positions [][]referenceframe.Input, options *arm.MoveOptions, extra map[string]interface{},
+		) error {
+			gotPositions[name], gotExtras[name] = positions, extra
+			return nil
+		}
+		return a
+	}
+	ms := &builtIn{components: map[string]resource.Resource{"arm": newArm("arm"), "arm2": newArm("arm2")}}
+
+	// both arms move in the last step, which starts a second batch
+	trajectory := motionplan.Trajectory{
+		{"arm": {0, 0}, "arm2": {0, 0}},
+		{"arm": {0.5, 0}, "arm2": {0, 0}},
+		{"arm": {1, 0.5}, "arm2": {0.5, 0}},
+	}
+	timed := motionplan.TimedTrajectory{
+		{Time: 0, Inputs: trajectory[0], Velocities: map[string][]float64{"arm": {0, 0}, "arm2": {0, 0}}},
+		{Time: time.Second, Inputs: trajectory[1], Velocities: map[string][]float64{"arm": {0.5, 0.25}, "arm2": {0, 0}}},
+		{Time: 3 * time.Second, Inputs: trajectory[2], Velocities: map[string][]float64{"arm": {0, 0}, "arm2": {0, 0}}},
+	}
+	test.That(t, ms.execute(ctx, trajectory, timed, math.MaxFloat64), test.ShouldBeNil)
+
+	// the second batch starts from where the first left each arm and takes the time between them to get going
+	test.That(t, gotPositions["arm"], test.ShouldResemble, [][]referenceframe.Input{{0.5, 0}, {1, 0.5}})
+	test.That(t, gotPositions["arm2"], test.ShouldResemble, [][]referenceframe.Input{{0, 0}, {0.5, 0}})
+	for name, positions := range gotPositions {
+		timing, err := arm.TrajectoryTimingFromExtra(gotExtras[name], len(positions))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, timing.Times, test.ShouldResemble, []time.Duration{0, 2 * time.Second})
+		test.That(t, timing.Times[1]-timing.Times[0], test.ShouldBeGreaterThan, 0)
+	}
+	test.That(t, gotExtras, test.ShouldHaveLength, 2)
+}
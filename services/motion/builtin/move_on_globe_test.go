@@ -0,0 +1,219 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	fakebase "go.viam.com/rdk/components/base/fake"
+	"go.viam.com/rdk/components/movementsensor"
+	fakemovementsensor "go.viam.com/rdk/components/movementsensor/fake"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+	viz "go.viam.com/rdk/vision"
+)
+
+// injectMovementSensor returns a movement sensor reporting the GPS position and compass heading of the simulated base, which
+// starts at origin with +X pointing east and +Y north.
+func (sb *simulatedBase) injectMovementSensor(origin *geo.Point) *inject.MovementSensor {
+	ms := inject.NewMovementSensor("test-gps")
+	ms.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		p := sb.pose().Point()
+		return origin.PointAtDistanceAndBearing(math.Hypot(p.X, p.Y)*1e-6, utils.RadToDeg(math.Atan2(p.X, p.Y))), 0, nil
+	}
+	ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		sb.mu.Lock()
+		defer sb.mu.Unlock()
+		// compass headings turn clockwise
+		return math.Mod(360-math.Mod(utils.RadToDeg(sb.theta), 360), 360), nil
+	}
+	ms.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{PositionSupported: true, CompassHeadingSupported: true}, nil
+	}
+	return ms
+}
+
+func newMoveOnGlobeService(t *testing.T, deps resource.Dependencies) motion.Service {
+	t.Helper()
+	ms, err := NewBuiltIn(context.Background(), deps, resource.Config{ConvertedAttributes: &Config{}}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() { test.That(t, ms.Close(context.Background()), test.ShouldBeNil) })
+	return ms
+}
+
+func TestMoveOnGlobe(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	origin := geo.NewPoint(40.7, -73.98)
+	// 10m north of the origin
+	destination := origin.PointAtDistanceAndBearing(0.01, 0)
+
+	t.Run("drives around an obstacle to the destination", func(t *testing.T) {
+		sb := &simulatedBase{}
+		ms := newMoveOnGlobeService(t, resource.Dependencies{
+			base.Named("test-base"):          sb.injectBase(),
+			movementsensor.Named("test-gps"): sb.injectMovementSensor(origin),
+		})
+		box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 2000, Y: 1000, Z: 100}, "wall")
+		test.That(t, err, test.ShouldBeNil)
+		wall := spatialmath.NewGeoGeometry(origin.PointAtDistanceAndBearing(0.005, 0), []spatialmath.Geometry{box})
+
+		executionID, err := ms.MoveOnGlobe(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      "test-base",
+			Destination:        destination,
+			MovementSensorName: "test-gps",
+			Obstacles:          []*spatialmath.GeoGeometry{wall},
+			MotionCfg:          &motion.MotionConfiguration{PlanDeviationMM: 200},
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		err = motion.PollHistoryUntilSuccessOrError(timeoutCtx, ms, time.Millisecond, motion.PlanHistoryReq{ComponentName: "test-base"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sb.moves, test.ShouldBeGreaterThan, 1)
+		test.That(t, distance2D(sb.pose().Point(), r3.Vector{Y: 10000}), test.ShouldBeLessThanOrEqualTo, 200)
+
+		history, err := ms.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: "test-base", ExecutionID: executionID})
+		test.That(t, err, test.ShouldBeNil)
+		anchor := history[0].Plan.AnchorGeoPose
+		test.That(t, anchor, test.ShouldNotBeNil)
+		test.That(t, anchor.Location().Lat(), test.ShouldAlmostEqual, origin.Lat())
+		test.That(t, anchor.Location().Lng(), test.ShouldAlmostEqual, origin.Lng())
+	})
+
+	t.Run("fails when the destination is outside the bounding regions", func(t *testing.T) {
+		sb := &simulatedBase{}
+		ms := newMoveOnGlobeService(t, resource.Dependencies{
+			base.Named("test-base"):          sb.injectBase(),
+			movementsensor.Named("test-gps"): sb.injectMovementSensor(origin),
+		})
+		box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 5000, Y: 5000, Z: 100}, "region")
+		test.That(t, err, test.ShouldBeNil)
+
+		_, err = ms.MoveOnGlobe(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      "test-base",
+			Destination:        destination,
+			MovementSensorName: "test-gps",
+			BoundingRegions:    []*spatialmath.GeoGeometry{spatialmath.NewGeoGeometry(origin, []spatialmath.Geometry{box})},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "bounding regions")
+	})
+
+	t.Run("fails when the cameras of obstacle detectors cannot be located", func(t *testing.T) {
+		sb := &simulatedBase{}
+		ms := newMoveOnGlobeService(t, resource.Dependencies{
+			base.Named("test-base"):          sb.injectBase(),
+			movementsensor.Named("test-gps"): sb.injectMovementSensor(origin),
+		})
+
+		_, err := ms.MoveOnGlobe(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      "test-base",
+			Destination:        destination,
+			MovementSensorName: "test-gps",
+			MotionCfg: &motion.MotionConfiguration{ObstacleDetectors: []motion.ObstacleDetectorName{
+				{VisionServiceName: "detector", CameraName: "camera"},
+			}},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "frame system")
+	})
+
+	t.Run("with the fake base and movement sensor", func(t *testing.T) {
+		fakeBase, err := fakebase.NewBase(
+			ctx, nil, resource.NewEmptyConfig(base.Named("test-base"), resource.DefaultModelFamily.WithModel("fake")), logger,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		fakeGPS, err := fakemovementsensor.NewMovementSensor(
+			ctx, nil, resource.NewEmptyConfig(movementsensor.Named("test-gps"), resource.DefaultModelFamily.WithModel("fake")), logger,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		ms := newMoveOnGlobeService(t, resource.Dependencies{
+			base.Named("test-base"):          fakeBase,
+			movementsensor.Named("test-gps"): fakeGPS,
+		})
+
+		_, err = ms.MoveOnGlobe(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      "test-base",
+			Destination:        origin,
+			MovementSensorName: "test-gps",
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "already within")
+
+		_, err = ms.MoveOnGlobe(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      "test-base",
+			Destination:        destination,
+			MovementSensorName: "other-gps",
+		})
+		test.That(t, err, test.ShouldBeError, resource.DependencyNotFoundError(movementsensor.Named("other-gps")))
+
+		// the fake base never moves, so it never gets any closer to the destination
+		_, err = ms.MoveOnGlobe(ctx, motion.MoveOnGlobeReq{
+			ComponentName:      "test-base",
+			Destination:        destination,
+			MovementSensorName: "test-gps",
+			Extra:              map[string]interface{}{maxReplansExtraKey: 0},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		err = motion.PollHistoryUntilSuccessOrError(timeoutCtx, ms, time.Millisecond, motion.PlanHistoryReq{ComponentName: "test-base"})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "from the goal")
+	})
+}
+
+func TestCheckObstacles(t *testing.T) {
+	ctx := context.Background()
+	geometry, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 200, Y: 200, Z: 100}, "base")
+	test.That(t, err, test.ShouldBeNil)
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 1000}), r3.Vector{X: 500, Y: 100, Z: 100}, "box")
+	test.That(t, err, test.ShouldBeNil)
+	mr := &baseMoveRequest{geometry: geometry, obstacles: []spatialmath.Geometry{obstacle}}
+
+	err = mr.checkObstacles(ctx, []referenceframe.Input{0, 0, 0}, []referenceframe.Input{0, 2000, 0})
+	test.That(t, err, test.ShouldBeError, &obstacleError{obstacle: "box"})
+
+	// passing beside the obstacle
+	test.That(t, mr.checkObstacles(ctx, []referenceframe.Input{1000, 0, 0}, []referenceframe.Input{1000, 2000, 0}), test.ShouldBeNil)
+
+	// stopping short of the obstacle
+	test.That(t, mr.checkObstacles(ctx, []referenceframe.Input{0, 0, 0}, []referenceframe.Input{0, 700, 0}), test.ShouldBeNil)
+
+	// leaving the obstacle the base is already touching
+	test.That(t, mr.checkObstacles(ctx, []referenceframe.Input{0, 950, 0}, []referenceframe.Input{0, 2000, 0}), test.ShouldBeNil)
+
+	// obstacles seen by an obstacle detector are placed in the world from where the base is, the camera here being mounted
+	// 100mm in front of the base and seeing a box 900mm further ahead
+	seen, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Y: 900}), r3.Vector{X: 500, Y: 100, Z: 100}, "seen")
+	test.That(t, err, test.ShouldBeNil)
+	detector := inject.NewVisionService("detector")
+	detector.GetObjectPointCloudsFunc = func(ctx context.Context, cameraName string, extra map[string]interface{}) ([]*viz.Object, error) {
+		test.That(t, cameraName, test.ShouldEqual, "camera")
+		return []*viz.Object{{Geometry: seen}}, nil
+	}
+	mr = &baseMoveRequest{geometry: geometry, detectors: []obstacleDetector{{
+		name:       motion.ObstacleDetectorName{VisionServiceName: "detector", CameraName: "camera"},
+		vision:     detector,
+		cameraPose: spatialmath.NewPoseFromPoint(r3.Vector{Y: 100}),
+	}}}
+	err = mr.checkObstacles(ctx, []referenceframe.Input{0, 0, 0}, []referenceframe.Input{0, 2000, 0})
+	test.That(t, err, test.ShouldBeError, &obstacleError{obstacle: "seen"})
+	// turned around, the camera sees the box behind where the base started
+	err = mr.checkObstacles(ctx, []referenceframe.Input{0, 0, math.Pi}, []referenceframe.Input{0, -2000, math.Pi})
+	test.That(t, err, test.ShouldBeError, &obstacleError{obstacle: "seen"})
+	test.That(t, mr.checkObstacles(ctx, []referenceframe.Input{0, 0, math.Pi}, []referenceframe.Input{0, 2000, 0}), test.ShouldBeNil)
+}
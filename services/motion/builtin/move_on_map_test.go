@@ -0,0 +1,337 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+// simulatedBase is a base which moves instantly, with the SLAM service reporting where it is.
+type simulatedBase struct {
+	mu       sync.Mutex
+	x, y     float64
+	theta    float64 // radians, zero facing +Y
+	moves    int
+	onMove   func(b *simulatedBase) // called with mu held after each MoveStraight
+	blockCtx bool                   // block in MoveStraight until its context is done
+}
+
+func (sb *simulatedBase) pose() spatialmath.Pose {
+	sb.mu.Lock()
+	defer sb.mu.Unlock()
+	return spatialmath.NewPose(r3.Vector{X: sb.x, Y: sb.y}, &spatialmath.OrientationVector{OZ: 1, Theta: sb.theta})
+}
+
+func (sb *simulatedBase) injectBase() *inject.Base {
+	b := inject.NewBase("test-base")
+	b.GeometriesFunc = func(ctx context.Context) ([]spatialmath.Geometry, error) {
+		box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 200, Y: 200, Z: 100}, "test-base")
+		return []spatialmath.Geometry{box}, err
+	}
+	b.SpinFunc = func(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+		sb.mu.Lock()
+		defer sb.mu.Unlock()
+		sb.theta += utils.DegToRad(angleDeg)
+		return nil
+	}
+	b.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		if sb.blockCtx {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		sb.mu.Lock()
+		defer sb.mu.Unlock()
+		sb.x -= math.Sin(sb.theta) * float64(distanceMm)
+		sb.y += math.Cos(sb.theta) * float64(distanceMm)
+		sb.moves++
+		if sb.onMove != nil {
+			sb.onMove(sb)
+		}
+		return nil
+	}
+	b.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		return nil
+	}
+	return b
+}
+
+// injectSLAM returns a SLAM service reporting the position of the simulated base on a map of the given points.
+func (sb *simulatedBase) injectSLAM(t *testing.T, points []r3.Vector) *inject.SLAMService {
+	t.Helper()
+	pc := pointcloud.NewBasicEmpty()
+	for _, p := range points {
+		test.That(t, pc.Set(p, pointcloud.NewBasicData()), test.ShouldBeNil)
+	}
+	var buf bytes.Buffer
+	test.That(t, pointcloud.ToPCD(pc, &buf, pointcloud.PCDBinary), test.ShouldBeNil)
+
+	slamSvc := inject.NewSLAMService("test-slam")
+	slamSvc.PositionFunc = func(ctx context.Context) (spatialmath.Pose, error) {
+		// SLAM reports a heading of zero along +X
+		return spatialmath.Compose(sb.pose(), spatialmath.PoseInverse(motion.SLAMOrientationAdjustment)), nil
+	}
+	slamSvc.PointCloudMapFunc = func(ctx context.Context, returnEditedMap bool) (func() ([]byte, error), error) {
+		return newChunkCallback(buf.Bytes()), nil
+	}
+	return slamSvc
+}
+
+func newChunkCallback(data []byte) func() ([]byte, error) {
+	done := false
+	return func() ([]byte, error) {
+		if done {
+			return nil, io.EOF
+		}
+		done = true
+		return data, nil
+	}
+}
+
+// room returns the points of the walls of a square room centered on the origin, with a wall across it at y=0 leaving a gap
+// at its +X end.
+func room(halfWidth float64, withWall bool) []r3.Vector {
+	var points []r3.Vector
+	for v := -halfWidth; v <= halfWidth; v += 50 {
+		points = append(points,
+			r3.Vector{X: v, Y: -halfWidth}, r3.Vector{X: v, Y: halfWidth},
+			r3.Vector{X: -halfWidth, Y: v}, r3.Vector{X: halfWidth, Y: v},
+		)
+		if withWall && v < halfWidth/2 {
+			points = append(points, r3.Vector{X: v})
+		}
+	}
+	return points
+}
+
+func newMoveOnMapService(t *testing.T, sb *simulatedBase, points []r3.Vector) motion.Service {
+	t.Helper()
+	ctx := context.Background()
+	deps := resource.Dependencies{
+		base.Named("test-base"): sb.injectBase(),
+		slam.Named("test-slam"): sb.injectSLAM(t, points),
+	}
+	ms, err := NewBuiltIn(ctx, deps, resource.Config{ConvertedAttributes: &Config{}}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() { test.That(t, ms.Close(context.Background()), test.ShouldBeNil) })
+	return ms
+}
+
+func TestMoveOnMap(t *testing.T) {
+	ctx := context.Background()
+	destination := spatialmath.NewPoseFromPoint(r3.Vector{Y: 1500})
+
+	t.Run("drives around the map to the destination", func(t *testing.T) {
+		sb := &simulatedBase{y: -1500}
+		ms := newMoveOnMapService(t, sb, room(2500, true))
+
+		executionID, err := ms.MoveOnMap(ctx, motion.MoveOnMapReq{
+			ComponentName: "test-base",
+			SlamName:      "test-slam",
+			Destination:   destination,
+			MotionCfg:     &motion.MotionConfiguration{PlanDeviationMM: 100},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, executionID, test.ShouldNotEqual, uuid.Nil)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		err = motion.PollHistoryUntilSuccessOrError(timeoutCtx, ms, time.Millisecond, motion.PlanHistoryReq{ComponentName: "test-base"})
+		test.That(t, err, test.ShouldBeNil)
+
+		// the wall across the room blocks the way unless the base goes through the gap
+		test.That(t, sb.moves, test.ShouldBeGreaterThan, 1)
+		test.That(t, distance2D(sb.pose().Point(), destination.Point()), test.ShouldBeLessThanOrEqualTo, 100)
+
+		statuses, err := ms.ListPlanStatuses(ctx, motion.ListPlanStatusesReq{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(statuses), test.ShouldEqual, 1)
+		test.That(t, statuses[0].ExecutionID, test.ShouldEqual, executionID)
+		test.That(t, statuses[0].Status.State, test.ShouldEqual, motion.PlanStateSucceeded)
+
+		statuses, err = ms.ListPlanStatuses(ctx, motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, statuses, test.ShouldBeEmpty)
+	})
+
+	t.Run("replans when the base drifts", func(t *testing.T) {
+		sb := &simulatedBase{y: -1500}
+		sb.onMove = func(sb *simulatedBase) {
+			if sb.moves == 1 {
+				sb.x += 500
+			}
+		}
+		ms := newMoveOnMapService(t, sb, room(2500, false))
+
+		_, err := ms.MoveOnMap(ctx, motion.MoveOnMapReq{
+			ComponentName: "test-base",
+			SlamName:      "test-slam",
+			Destination:   destination,
+			MotionCfg:     &motion.MotionConfiguration{PlanDeviationMM: 200},
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		err = motion.PollHistoryUntilSuccessOrError(timeoutCtx, ms, time.Millisecond, motion.PlanHistoryReq{ComponentName: "test-base"})
+		test.That(t, err, test.ShouldBeNil)
+
+		history, err := ms.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: "test-base"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(history), test.ShouldEqual, 2)
+		test.That(t, history[0].Plan.ExecutionID, test.ShouldEqual, history[1].Plan.ExecutionID)
+		test.That(t, history[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateSucceeded)
+		test.That(t, history[1].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateFailed)
+		test.That(t, *history[1].StatusHistory[0].Reason, test.ShouldContainSubstring, "strayed")
+
+		history, err = ms.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: "test-base", LastPlanOnly: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(history), test.ShouldEqual, 1)
+	})
+
+	t.Run("can be stopped", func(t *testing.T) {
+		sb := &simulatedBase{y: -1500, blockCtx: true}
+		ms := newMoveOnMapService(t, sb, room(2500, false))
+
+		executionID, err := ms.MoveOnMap(ctx, motion.MoveOnMapReq{
+			ComponentName: "test-base",
+			SlamName:      "test-slam",
+			Destination:   destination,
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		statuses, err := ms.ListPlanStatuses(ctx, motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(statuses), test.ShouldEqual, 1)
+		test.That(t, statuses[0].Status.State, test.ShouldEqual, motion.PlanStateInProgress)
+
+		test.That(t, ms.StopPlan(ctx, motion.StopPlanReq{ComponentName: "test-base"}), test.ShouldBeNil)
+		history, err := ms.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: "test-base", ExecutionID: executionID})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, len(history), test.ShouldEqual, 1)
+		test.That(t, history[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+		test.That(t, history[0].StatusHistory[1].State, test.ShouldEqual, motion.PlanStateInProgress)
+
+		err = ms.StopPlan(ctx, motion.StopPlanReq{ComponentName: "test-base"})
+		test.That(t, errors.Is(err, errNoPlanInProgress), test.ShouldBeTrue)
+	})
+
+	t.Run("stops the execution in progress before planning from where the base is", func(t *testing.T) {
+		sb := &simulatedBase{y: -1500}
+		b := sb.injectBase()
+		var driving atomic.Bool
+		var readsWhileDriving atomic.Int32
+		b.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+			driving.Store(true)
+			defer driving.Store(false)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		slamSvc := sb.injectSLAM(t, room(2500, false))
+		position := slamSvc.PositionFunc
+		slamSvc.PositionFunc = func(ctx context.Context) (spatialmath.Pose, error) {
+			if driving.Load() {
+				readsWhileDriving.Add(1)
+			}
+			return position(ctx)
+		}
+		ms, err := NewBuiltIn(ctx, resource.Dependencies{base.Named("test-base"): b, slam.Named("test-slam"): slamSvc},
+			resource.Config{ConvertedAttributes: &Config{}}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() { test.That(t, ms.Close(ctx), test.ShouldBeNil) }()
+
+		noPolling := 0.
+		req := motion.MoveOnMapReq{
+			ComponentName: "test-base",
+			SlamName:      "test-slam",
+			Destination:   destination,
+			MotionCfg:     &motion.MotionConfiguration{PositionPollingFreqHz: &noPolling, ObstaclePollingFreqHz: &noPolling},
+		}
+		firstID, err := ms.MoveOnMap(ctx, req)
+		test.That(t, err, test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, driving.Load(), test.ShouldBeTrue)
+		})
+
+		_, err = ms.MoveOnMap(ctx, req)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, readsWhileDriving.Load(), test.ShouldEqual, 0)
+		history, err := ms.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: "test-base", ExecutionID: firstID})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, history[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+	})
+
+	t.Run("stops the execution in progress when reconfigured", func(t *testing.T) {
+		sb := &simulatedBase{y: -1500, blockCtx: true}
+		deps := resource.Dependencies{
+			base.Named("test-base"): sb.injectBase(),
+			slam.Named("test-slam"): sb.injectSLAM(t, room(2500, false)),
+		}
+		ms, err := NewBuiltIn(ctx, deps, resource.Config{ConvertedAttributes: &Config{}}, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() { test.That(t, ms.Close(ctx), test.ShouldBeNil) }()
+
+		executionID, err := ms.MoveOnMap(ctx, motion.MoveOnMapReq{
+			ComponentName: "test-base",
+			SlamName:      "test-slam",
+			Destination:   destination,
+		})
+		test.That(t, err, test.ShouldBeNil)
+
+		test.That(t, ms.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &Config{}}), test.ShouldBeNil)
+		statuses, err := ms.ListPlanStatuses(ctx, motion.ListPlanStatusesReq{OnlyActivePlans: true})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, statuses, test.ShouldBeEmpty)
+		history, err := ms.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: "test-base", ExecutionID: executionID})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, history[0].StatusHistory[0].State, test.ShouldEqual, motion.PlanStateStopped)
+	})
+
+	t.Run("fails when already at the destination", func(t *testing.T) {
+		sb := &simulatedBase{y: 1400}
+		ms := newMoveOnMapService(t, sb, room(2500, false))
+
+		_, err := ms.MoveOnMap(ctx, motion.MoveOnMapReq{
+			ComponentName: "test-base",
+			SlamName:      "test-slam",
+			Destination:   destination,
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "already within")
+
+		_, err = ms.PlanHistory(ctx, motion.PlanHistoryReq{ComponentName: "test-base"})
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("fails with an unknown SLAM service", func(t *testing.T) {
+		ms := newMoveOnMapService(t, &simulatedBase{}, room(2500, false))
+
+		_, err := ms.MoveOnMap(ctx, motion.MoveOnMapReq{
+			ComponentName: "test-base",
+			SlamName:      "other-slam",
+			Destination:   destination,
+		})
+		test.That(t, err, test.ShouldBeError, resource.DependencyNotFoundError(slam.Named("other-slam")))
+	})
+}
@@ -0,0 +1,721 @@
+package builtin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/motionplan/armplanning"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/services/slam"
+	"go.viam.com/rdk/services/vision"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	defaultMaxReplans        = 10
+	defaultPositionPollingHz = 1.
+	defaultObstaclePollingHz = 1.
+	obstacleCheckStepMM      = 50.
+	minDriveDistanceMM       = 1.
+	minSpinDegs              = 0.5
+	maxReplansExtraKey       = "max_replans"
+	collisionBufferExtraKey  = "collision_buffer_mm"
+)
+
+// baseMoveRequest drives a base to a goal in the world frame of a localizer, planning in the plane around obstacles. The base
+// is modeled by a 2D mobile model frame whose inputs are the x and y position of the base in mm and its heading in radians.
+// Each step of a plan is executed by spinning towards the next waypoint and driving straight to it. If the base strays further
+// than planDeviationMM from the plan, or an obstacle is found in the way of the next waypoint, a new plan is made from where
+// it is. Obstacles are the static obstacles of the request along with whatever the obstacle detectors see at the time.
+type baseMoveRequest struct {
+	componentName   string
+	base            base.Base
+	geometry        spatialmath.Geometry
+	localizer       motion.Localizer
+	frameSystem     *referenceframe.FrameSystem
+	goal            spatialmath.Pose
+	obstacles       []spatialmath.Geometry
+	detectors       []obstacleDetector
+	boundingRegions []spatialmath.Geometry
+	extra           map[string]interface{}
+
+	planDeviationMM       float64
+	linearMMPerSec        float64
+	angularDegsPerSec     float64
+	positionPollingPeriod time.Duration
+	obstaclePollingPeriod time.Duration
+	maxReplans            int
+
+	logger logging.Logger
+}
+
+// obstacleDetector is a vision service segmenting the point clouds of a camera on the base into obstacles.
+type obstacleDetector struct {
+	name   motion.ObstacleDetectorName
+	vision vision.Service
+	// cameraPose is the pose of the camera in the frame of the base
+	cameraPose spatialmath.Pose
+}
+
+// newBaseMoveRequest creates a request to move the base to the goal, avoiding the given obstacles. The base must stay within
+// the given x and y limits, which are extended to contain where the base is and the goal, and, if there are any bounding
+// regions, the position of the base must stay within them.
+func newBaseMoveRequest(
+	ctx context.Context,
+	componentName string,
+	b base.Base,
+	localizer motion.Localizer,
+	goal spatialmath.Pose,
+	limits []referenceframe.Limit,
+	obstacles []spatialmath.Geometry,
+	detectors []obstacleDetector,
+	boundingRegions []spatialmath.Geometry,
+	motionCfg *motion.MotionConfiguration,
+	defaultPlanDeviationM float64,
+	extra map[string]interface{},
+	logger logging.Logger,
+) (*baseMoveRequest, error) {
+	mr := &baseMoveRequest{
+		componentName:         componentName,
+		base:                  b,
+		localizer:             localizer,
+		goal:                  goal,
+		obstacles:             obstacles,
+		detectors:             detectors,
+		boundingRegions:       boundingRegions,
+		extra:                 extra,
+		planDeviationMM:       defaultPlanDeviationM * 1e3,
+		linearMMPerSec:        defaultLinearMPerSec * 1e3,
+		angularDegsPerSec:     defaultAngularDegsPerSec,
+		positionPollingPeriod: time.Duration(float64(time.Second) / defaultPositionPollingHz),
+		obstaclePollingPeriod: time.Duration(float64(time.Second) / defaultObstaclePollingHz),
+		maxReplans:            defaultMaxReplans,
+		logger:                logger,
+	}
+	if motionCfg != nil {
+		if motionCfg.PlanDeviationMM < 0 || motionCfg.LinearMPerSec < 0 || motionCfg.AngularDegsPerSec < 0 {
+			return nil, errors.New("plan deviation, linear and angular speeds of the motion configuration cannot be negative")
+		}
+		if motionCfg.PlanDeviationMM != 0 {
+			mr.planDeviationMM = motionCfg.PlanDeviationMM
+		}
+		if motionCfg.LinearMPerSec != 0 {
+			mr.linearMMPerSec = motionCfg.LinearMPerSec * 1e3
+		}
+		if motionCfg.AngularDegsPerSec != 0 {
+			mr.angularDegsPerSec = motionCfg.AngularDegsPerSec
+		}
+		var err error
+		if mr.positionPollingPeriod, err = pollingPeriod(
+			"position", motionCfg.PositionPollingFreqHz, mr.positionPollingPeriod,
+		); err != nil {
+			return nil, err
+		}
+		if mr.obstaclePollingPeriod, err = pollingPeriod(
+			"obstacle", motionCfg.ObstaclePollingFreqHz, mr.obstaclePollingPeriod,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if maxReplans, ok := extra[maxReplansExtraKey]; ok {
+		// extras which come from protobuf hold numbers as float64
+		switch v := maxReplans.(type) {
+		case int:
+			mr.maxReplans = v
+		case float64:
+			mr.maxReplans = int(v)
+		default:
+			return nil, fmt.Errorf("extra %s must be a number, not %T", maxReplansExtraKey, maxReplans)
+		}
+		if mr.maxReplans < 0 {
+			return nil, fmt.Errorf("extra %s cannot be negative", maxReplansExtraKey)
+		}
+	}
+
+	start, _, err := mr.position(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if distance2D(start.Point(), goal.Point()) <= mr.planDeviationMM {
+		return nil, fmt.Errorf("%s is already within %.0fmm of the goal", componentName, mr.planDeviationMM)
+	}
+	if !mr.withinBoundingRegions(start.Point()) {
+		return nil, fmt.Errorf("%s is not within any of the bounding regions", componentName)
+	}
+	if !mr.withinBoundingRegions(goal.Point()) {
+		return nil, errors.New("the goal is not within any of the bounding regions")
+	}
+	if len(limits) != 2 {
+		return nil, fmt.Errorf("expected x and y limits, got %d limits", len(limits))
+	}
+	limits = []referenceframe.Limit{
+		{
+			Min: math.Min(limits[0].Min, math.Min(start.Point().X, goal.Point().X)),
+			Max: math.Max(limits[0].Max, math.Max(start.Point().X, goal.Point().X)),
+		},
+		{
+			Min: math.Min(limits[1].Min, math.Min(start.Point().Y, goal.Point().Y)),
+			Max: math.Max(limits[1].Max, math.Max(start.Point().Y, goal.Point().Y)),
+		},
+		{Min: -2 * math.Pi, Max: 2 * math.Pi},
+	}
+
+	geometries, err := b.Geometries(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(geometries) > 0 {
+		if len(geometries) > 1 {
+			logger.CWarnf(ctx, "%s has %d geometries, only the first is used for planning", componentName, len(geometries))
+		}
+		mr.geometry = geometries[0]
+	}
+	model, err := referenceframe.New2DMobileModelFrame(componentName, limits, mr.geometry)
+	if err != nil {
+		return nil, err
+	}
+	mr.frameSystem = referenceframe.NewEmptyFrameSystem("")
+	if err := mr.frameSystem.AddFrame(model, mr.frameSystem.World()); err != nil {
+		return nil, err
+	}
+	return mr, nil
+}
+
+// obstacleDetectors returns the obstacle detectors of the motion configuration, locating their cameras on the base with the
+// frame system.
+func (ms *builtIn) obstacleDetectors(
+	ctx context.Context, componentName string, motionCfg *motion.MotionConfiguration,
+) ([]obstacleDetector, error) {
+	if motionCfg == nil || len(motionCfg.ObstacleDetectors) == 0 {
+		return nil, nil
+	}
+	if ms.fsService == nil {
+		return nil, errors.New("obstacle detectors need the frame system service to locate their cameras")
+	}
+	detectors := make([]obstacleDetector, 0, len(motionCfg.ObstacleDetectors))
+	for _, name := range motionCfg.ObstacleDetectors {
+		visionSvc, ok := ms.visionServices[name.VisionServiceName]
+		if !ok {
+			return nil, resource.DependencyNotFoundError(vision.Named(name.VisionServiceName))
+		}
+		cameraPose, err := ms.fsService.GetPose(ctx, name.CameraName, componentName, nil, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to locate camera %s of obstacle detector %s on %s",
+				name.CameraName, name.VisionServiceName, componentName)
+		}
+		detectors = append(detectors, obstacleDetector{name: name, vision: visionSvc, cameraPose: cameraPose.Pose()})
+	}
+	return detectors, nil
+}
+
+// newMoveOnMapRequest creates the request for MoveOnMap, treating the point cloud map of the SLAM service as an obstacle.
+func (ms *builtIn) newMoveOnMapRequest(ctx context.Context, req motion.MoveOnMapReq) (*baseMoveRequest, error) {
+	if req.Destination == nil {
+		return nil, errors.New("destination cannot be nil")
+	}
+	b, err := ms.base(req.ComponentName)
+	if err != nil {
+		return nil, err
+	}
+	slamSvc, ok := ms.slamServices[req.SlamName]
+	if !ok {
+		return nil, resource.DependencyNotFoundError(slam.Named(req.SlamName))
+	}
+
+	data, err := slam.PointCloudMapFull(ctx, slamSvc, true)
+	if err != nil {
+		return nil, err
+	}
+	pc, err := pointcloud.ReadPCD(bytes.NewReader(data), pointcloud.BasicOctreeType)
+	if err != nil {
+		return nil, err
+	}
+	octree, ok := pc.(*pointcloud.BasicOctree)
+	if !ok {
+		return nil, fmt.Errorf("expected the map to be read as a BasicOctree, not a %T", pc)
+	}
+	octree.SetLabel(req.SlamName)
+	dims := octree.MetaData()
+	limits := []referenceframe.Limit{{Min: dims.MinX, Max: dims.MaxX}, {Min: dims.MinY, Max: dims.MaxY}}
+
+	// SLAM poses point along +X at theta zero while bases drive along +Y, so the destination is adjusted like the localizer is.
+	goal := spatialmath.Compose(req.Destination, motion.SLAMOrientationAdjustment)
+	obstacles := append([]spatialmath.Geometry{octree}, req.Obstacles...)
+	detectors, err := ms.obstacleDetectors(ctx, req.ComponentName, req.MotionCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newBaseMoveRequest(
+		ctx,
+		req.ComponentName,
+		b,
+		motion.TwoDLocalizer(motion.NewSLAMLocalizer(slamSvc)),
+		goal,
+		limits,
+		obstacles,
+		detectors,
+		nil,
+		req.MotionCfg,
+		defaultSlamPlanDeviationM,
+		req.Extra,
+		ms.logger,
+	)
+}
+
+// newMoveOnGlobeRequest creates the request for MoveOnGlobe, planning in a frame centered on where the movement sensor reports
+// the base to be, with +X pointing east and +Y north. It also returns the GPS position the frame is anchored to.
+func (ms *builtIn) newMoveOnGlobeRequest(
+	ctx context.Context, req motion.MoveOnGlobeReq,
+) (*baseMoveRequest, *spatialmath.GeoPose, error) {
+	if req.Destination == nil {
+		return nil, nil, errors.New("destination cannot be nil")
+	}
+	if math.IsNaN(req.Destination.Lat()) || math.IsNaN(req.Destination.Lng()) {
+		return nil, nil, errors.New("destination may not contain NaN")
+	}
+	b, err := ms.base(req.ComponentName)
+	if err != nil {
+		return nil, nil, err
+	}
+	movementSensor, ok := ms.movementSensors[req.MovementSensorName]
+	if !ok {
+		return nil, nil, resource.DependencyNotFoundError(movementsensor.Named(req.MovementSensorName))
+	}
+
+	origin, _, err := movementSensor.Position(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	goal := spatialmath.NewPoseFromPoint(spatialmath.GeoPointToPoint(req.Destination, origin))
+	straightLineDistance := goal.Point().Norm()
+	if straightLineDistance > maxTravelDistanceMM {
+		return nil, nil, fmt.Errorf("cannot move more than %.0f kilometers", maxTravelDistanceMM*1e-6)
+	}
+	// without bounding regions the base may wander up to three times as far as the destination in any direction
+	limits := []referenceframe.Limit{
+		{Min: -3 * straightLineDistance, Max: 3 * straightLineDistance},
+		{Min: -3 * straightLineDistance, Max: 3 * straightLineDistance},
+	}
+	detectors, err := ms.obstacleDetectors(ctx, req.ComponentName, req.MotionCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mr, err := newBaseMoveRequest(
+		ctx,
+		req.ComponentName,
+		b,
+		motion.TwoDLocalizer(motion.NewMovementSensorLocalizer(movementSensor, origin, nil)),
+		goal,
+		limits,
+		spatialmath.GeoGeometriesToGeometries(req.Obstacles, origin),
+		detectors,
+		spatialmath.GeoGeometriesToGeometries(req.BoundingRegions, origin),
+		req.MotionCfg,
+		defaultGlobePlanDeviationM,
+		req.Extra,
+		ms.logger,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mr, spatialmath.NewGeoPose(origin, 0), nil
+}
+
+// pollingPeriod returns the period of polling at the given frequency, the default period if no frequency is given, or zero
+// to disable polling if the frequency is zero.
+func pollingPeriod(name string, hz *float64, defaultPeriod time.Duration) (time.Duration, error) {
+	switch {
+	case hz == nil:
+		return defaultPeriod, nil
+	case *hz < 0:
+		return 0, fmt.Errorf("%s polling frequency cannot be negative", name)
+	case *hz == 0:
+		return 0, nil
+	default:
+		return time.Duration(float64(time.Second) / *hz), nil
+	}
+}
+
+// base returns the base with the given name.
+func (ms *builtIn) base(name string) (base.Base, error) {
+	r, ok := ms.components[name]
+	if !ok {
+		return nil, resource.DependencyNotFoundError(base.Named(name))
+	}
+	b, ok := r.(base.Base)
+	if !ok {
+		return nil, resource.DependencyTypeError[base.Base](base.Named(name), r)
+	}
+	return b, nil
+}
+
+// position returns where the localizer says the base is along with the corresponding inputs of the base's model frame.
+func (mr *baseMoveRequest) position(ctx context.Context) (spatialmath.Pose, []referenceframe.Input, error) {
+	pif, err := mr.localizer.CurrentPosition(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	pose := pif.Pose()
+	forward := spatialmath.Compose(
+		spatialmath.NewPoseFromOrientation(pose.Orientation()), spatialmath.NewPoseFromPoint(r3.Vector{Y: 1}),
+	).Point()
+	return pose, []referenceframe.Input{pose.Point().X, pose.Point().Y, math.Atan2(-forward.X, forward.Y)}, nil
+}
+
+// plan plans a path for the base from where it is to the goal, around the obstacles known and detected from there.
+func (mr *baseMoveRequest) plan(ctx context.Context) (motionplan.Plan, error) {
+	_, inputs, err := mr.position(ctx)
+	if err != nil {
+		return nil, err
+	}
+	obstacles, err := mr.currentObstacles(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	worldState, err := referenceframe.NewWorldState(
+		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, obstacles)}, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	planOpts, err := armplanning.NewPlannerOptionsFromExtra(mr.extra)
+	if err != nil {
+		return nil, err
+	}
+	// only the position of the base at the goal matters, it is free to face whichever way it arrives in
+	planOpts.GoalMetricType = motionplan.PositionOnly
+	if _, ok := mr.extra[collisionBufferExtraKey]; !ok {
+		planOpts.CollisionBufferMM = defaultCollisionBuffer
+	}
+
+	plan, _, err := armplanning.PlanMotion(ctx, mr.logger, &armplanning.PlanRequest{
+		FrameSystem: mr.frameSystem,
+		Goals: []*armplanning.PlanState{armplanning.NewPlanState(
+			referenceframe.FrameSystemPoses{mr.componentName: referenceframe.NewPoseInFrame(referenceframe.World, mr.goal)}, nil,
+		)},
+		StartState:     armplanning.NewPlanState(nil, referenceframe.FrameSystemInputs{mr.componentName: inputs}),
+		WorldState:     worldState,
+		PlannerOptions: planOpts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := mr.checkBoundingRegions(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// run executes the plan, replanning whenever the base deviates from the plan it is executing or finds an obstacle in its way.
+func (mr *baseMoveRequest) run(ctx context.Context, ex *execution, plan motionplan.Plan) error {
+	for replans := 0; ; replans++ {
+		execErr := mr.execute(ctx, plan)
+		var deviation *planDeviationError
+		var obstacle *obstacleError
+		if !(errors.As(execErr, &deviation) || errors.As(execErr, &obstacle)) || replans >= mr.maxReplans {
+			return execErr
+		}
+		mr.logger.CInfof(ctx, "replanning: %v", execErr)
+		var err error
+		plan, err = mr.plan(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "failed to replan after %v", execErr)
+		}
+		ex.replan(plan, execErr.Error())
+	}
+}
+
+// execute drives the base along the plan. The base is stopped if execution ends early.
+func (mr *baseMoveRequest) execute(ctx context.Context, plan motionplan.Plan) (err error) {
+	defer func() {
+		if err == nil {
+			return
+		}
+		if stopErr := mr.base.Stop(context.WithoutCancel(ctx), nil); stopErr != nil {
+			err = errors.Wrap(err, stopErr.Error())
+		}
+	}()
+
+	steps, err := plan.Trajectory().GetFrameInputs(mr.componentName)
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(steps); i++ {
+		if err := mr.step(ctx, steps[i-1], steps[i]); err != nil {
+			return err
+		}
+	}
+
+	pose, _, err := mr.position(ctx)
+	if err != nil {
+		return err
+	}
+	if distance := distance2D(pose.Point(), mr.goal.Point()); distance > mr.planDeviationMM {
+		return &planDeviationError{deviationMM: distance, planDeviationMM: mr.planDeviationMM, goal: true}
+	}
+	return nil
+}
+
+// step drives the base from where it is to the position of the next waypoint of the plan, polling its position on the way
+// to check that it does not stray from the segment between the waypoints.
+func (mr *baseMoveRequest) step(ctx context.Context, from, to []referenceframe.Input) error {
+	_, inputs, err := mr.position(ctx)
+	if err != nil {
+		return err
+	}
+	if err := mr.checkDeviation(inputs, from, to); err != nil {
+		return err
+	}
+	dx, dy := to[0]-inputs[0], to[1]-inputs[1]
+	distance := math.Hypot(dx, dy)
+	if distance < minDriveDistanceMM {
+		return nil
+	}
+
+	stepCtx, cancel := context.WithCancelCause(ctx)
+	var monitor sync.WaitGroup
+	defer func() {
+		cancel(nil)
+		monitor.Wait()
+	}()
+	// poll checks where the base is every period until the step ends, interrupting the step if the check fails
+	poll := func(period time.Duration, check func(inputs []referenceframe.Input) error) {
+		if period <= 0 {
+			return
+		}
+		monitor.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer monitor.Done()
+			for goutils.SelectContextOrWait(stepCtx, period) {
+				_, inputs, err := mr.position(stepCtx)
+				if err != nil {
+					mr.logger.CWarnf(stepCtx, "failed to poll position: %v", err)
+					continue
+				}
+				if err := check(inputs); err != nil {
+					cancel(err)
+					return
+				}
+			}
+		})
+	}
+	poll(mr.positionPollingPeriod, func(inputs []referenceframe.Input) error {
+		return mr.checkDeviation(inputs, from, to)
+	})
+	poll(mr.obstaclePollingPeriod, func(inputs []referenceframe.Input) error {
+		return mr.checkObstacles(stepCtx, inputs, to)
+	})
+	stepErr := func() error {
+		// the heading which drives the base along +Y of its frame towards the waypoint
+		turn := utils.RadToDeg(normalizeAngle(math.Atan2(-dx, dy) - inputs[2]))
+		if math.Abs(turn) > minSpinDegs {
+			if err := mr.base.Spin(stepCtx, turn, mr.angularDegsPerSec, nil); err != nil {
+				return err
+			}
+		}
+		return mr.base.MoveStraight(stepCtx, int(math.Round(distance)), mr.linearMMPerSec, nil)
+	}()
+	if cause := context.Cause(stepCtx); cause != nil && ctx.Err() == nil {
+		// the position monitor interrupted the step
+		return cause
+	}
+	if stepErr != nil {
+		return stepErr
+	}
+
+	_, inputs, err = mr.position(ctx)
+	if err != nil {
+		return err
+	}
+	return mr.checkDeviation(inputs, from, to)
+}
+
+// checkDeviation returns a planDeviationError if the base is further than the plan deviation from the segment of the plan
+// between two waypoints.
+func (mr *baseMoveRequest) checkDeviation(inputs, from, to []referenceframe.Input) error {
+	position := r3.Vector{X: inputs[0], Y: inputs[1]}
+	deviation := distanceToSegment2D(position, r3.Vector{X: from[0], Y: from[1]}, r3.Vector{X: to[0], Y: to[1]})
+	if deviation > mr.planDeviationMM {
+		return &planDeviationError{deviationMM: deviation, planDeviationMM: mr.planDeviationMM}
+	}
+	return nil
+}
+
+// currentObstacles returns the static obstacles along with the obstacles the obstacle detectors see from where the base is,
+// all in the world frame.
+func (mr *baseMoveRequest) currentObstacles(ctx context.Context, inputs []referenceframe.Input) ([]spatialmath.Geometry, error) {
+	if len(mr.detectors) == 0 {
+		return mr.obstacles, nil
+	}
+	basePose := spatialmath.NewPose(r3.Vector{X: inputs[0], Y: inputs[1]}, &spatialmath.OrientationVector{OZ: 1, Theta: inputs[2]})
+	obstacles := slices.Clone(mr.obstacles)
+	for _, detector := range mr.detectors {
+		objects, err := detector.vision.GetObjectPointClouds(ctx, detector.name.CameraName, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "obstacle detector %s failed to segment camera %s",
+				detector.name.VisionServiceName, detector.name.CameraName)
+		}
+		cameraPose := spatialmath.Compose(basePose, detector.cameraPose)
+		for _, object := range objects {
+			if object.Geometry == nil {
+				continue
+			}
+			obstacles = append(obstacles, object.Geometry.Transform(cameraPose))
+		}
+	}
+	return obstacles, nil
+}
+
+// checkObstacles returns an obstacleError if the base would hit an obstacle driving straight from where it is to the position
+// of the next waypoint. Obstacles which the base is already touching are ignored, like they are when planning.
+func (mr *baseMoveRequest) checkObstacles(ctx context.Context, inputs, to []referenceframe.Input) error {
+	if mr.geometry == nil {
+		return nil
+	}
+	allObstacles, err := mr.currentObstacles(ctx, inputs)
+	if err != nil {
+		return err
+	}
+	start, end := r3.Vector{X: inputs[0], Y: inputs[1]}, r3.Vector{X: to[0], Y: to[1]}
+	heading := inputs[2]
+	if segment := end.Sub(start); segment.Norm() >= minDriveDistanceMM {
+		heading = math.Atan2(-segment.X, segment.Y)
+	}
+	orientation := &spatialmath.OrientationVector{OZ: 1, Theta: heading}
+	geometryAt := func(position r3.Vector) spatialmath.Geometry {
+		return mr.geometry.Transform(spatialmath.NewPose(position, orientation))
+	}
+
+	current := geometryAt(start)
+	obstacles := make([]spatialmath.Geometry, 0, len(allObstacles))
+	for _, obstacle := range allObstacles {
+		collides, _, err := current.CollidesWith(obstacle, 0)
+		if err != nil {
+			return err
+		}
+		if !collides {
+			obstacles = append(obstacles, obstacle)
+		}
+	}
+
+	steps := int(math.Ceil(start.Distance(end) / obstacleCheckStepMM))
+	for i := 1; i <= steps; i++ {
+		geometry := geometryAt(start.Add(end.Sub(start).Mul(float64(i) / float64(steps))))
+		for _, obstacle := range obstacles {
+			collides, _, err := geometry.CollidesWith(obstacle, 0)
+			if err != nil {
+				return err
+			}
+			if collides {
+				return &obstacleError{obstacle: obstacle.Label()}
+			}
+		}
+	}
+	return nil
+}
+
+// withinBoundingRegions returns whether a point is within any of the bounding regions, or true if there are none.
+func (mr *baseMoveRequest) withinBoundingRegions(point r3.Vector) bool {
+	if len(mr.boundingRegions) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(mr.boundingRegions, func(region spatialmath.Geometry) bool {
+		collides, _, err := region.CollidesWith(spatialmath.NewPoint(point, ""), 0)
+		return err == nil && collides
+	})
+}
+
+// checkBoundingRegions returns an error if the base leaves the bounding regions while following the plan. Each segment of the
+// plan must lie within a single bounding region, which contains the segment entirely as long as the region is convex.
+func (mr *baseMoveRequest) checkBoundingRegions(plan motionplan.Plan) error {
+	if len(mr.boundingRegions) == 0 {
+		return nil
+	}
+	steps, err := plan.Trajectory().GetFrameInputs(mr.componentName)
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(steps); i++ {
+		from, to := r3.Vector{X: steps[i-1][0], Y: steps[i-1][1]}, r3.Vector{X: steps[i][0], Y: steps[i][1]}
+		if !slices.ContainsFunc(mr.boundingRegions, func(region spatialmath.Geometry) bool {
+			for _, point := range []r3.Vector{from, to} {
+				collides, _, err := region.CollidesWith(spatialmath.NewPoint(point, ""), 0)
+				if err != nil || !collides {
+					return false
+				}
+			}
+			return true
+		}) {
+			return fmt.Errorf("the plan leaves the bounding regions between waypoints %d and %d", i-1, i)
+		}
+	}
+	return nil
+}
+
+// obstacleError is returned when an obstacle is found in the way of the base.
+type obstacleError struct {
+	obstacle string
+}
+
+func (e *obstacleError) Error() string {
+	if e.obstacle == "" {
+		return "found an obstacle in the way of the next waypoint"
+	}
+	return fmt.Sprintf("found obstacle %q in the way of the next waypoint", e.obstacle)
+}
+
+// planDeviationError is returned when the base strays too far from the plan it is executing, or does not reach the goal.
+type planDeviationError struct {
+	deviationMM     float64
+	planDeviationMM float64
+	goal            bool
+}
+
+func (e *planDeviationError) Error() string {
+	if e.goal {
+		return fmt.Sprintf("ended %.0fmm from the goal, more than the allowed plan deviation of %.0fmm",
+			e.deviationMM, e.planDeviationMM)
+	}
+	return fmt.Sprintf("strayed %.0fmm from the plan, more than the allowed plan deviation of %.0fmm",
+		e.deviationMM, e.planDeviationMM)
+}
+
+// distance2D returns the distance between two points projected onto the XY plane.
+func distance2D(a, b r3.Vector) float64 {
+	return math.Hypot(a.X-b.X, a.Y-b.Y)
+}
+
+// distanceToSegment2D returns the distance from a point to the segment between start and end, projected onto the XY plane.
+func distanceToSegment2D(point, start, end r3.Vector) float64 {
+	point.Z, start.Z, end.Z = 0, 0, 0
+	segment := end.Sub(start)
+	length2 := segment.Norm2()
+	if length2 == 0 {
+		return point.Distance(start)
+	}
+	t := math.Max(0, math.Min(1, point.Sub(start).Dot(segment)/length2))
+	return point.Distance(start.Add(segment.Mul(t)))
+}
+
+// normalizeAngle wraps an angle in radians to [-pi, pi).
+func normalizeAngle(angle float64) float64 {
+	return angle - 2*math.Pi*math.Floor((angle+math.Pi)/(2*math.Pi))
+}
@@ -0,0 +1,575 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/golang/geo/r3"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/quat"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/motionplan/armplanning"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/session"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	defaultServoFrequencyHz       = 50.
+	defaultServoTimeout           = 100 * time.Millisecond
+	defaultServoLinearMMPerSec    = 100.
+	defaultServoAngularDegsPerSec = 30.
+	// servoPoseGain is how quickly pose targets are approached, the end effector moving at this many times its distance from
+	// the target per second until the speed limits are reached.
+	servoPoseGain          = 2.
+	servoPoseToleranceMM   = 0.1
+	servoPoseToleranceDegs = 0.05
+	// near singularities the jacobian is inverted with damping so that joint velocities stay bounded
+	servoSingularityThreshold = 1e-2
+	servoSingularityDamping   = 0.1
+)
+
+var _ motion.Servoer = &builtIn{}
+
+// Servo moves the end effector of a component following a stream of twist or pose targets, see motion.Servoer.
+func (ms *builtIn) Servo(ctx context.Context, req motion.ServoReq, targets <-chan motion.ServoTarget) error {
+	if req.Extra == nil {
+		req.Extra = make(map[string]any)
+	}
+	// the servo holds its own frame system and component, so the service is not locked while it runs
+	ms.mu.RLock()
+	ms.applyDefaultExtras(req.Extra)
+	s, err := ms.newServo(ctx, req)
+	ms.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return s.run(ctx, targets)
+}
+
+// servoCommand is the value of a DoServo command, see DoCommand.
+type servoCommand struct {
+	ComponentName        string     `mapstructure:"component_name"`
+	Linear               *r3.Vector `mapstructure:"linear"`
+	Angular              *r3.Vector `mapstructure:"angular"`
+	Pose                 *servoPose `mapstructure:"pose"`
+	Frame                string     `mapstructure:"frame"`
+	FrequencyHz          float64    `mapstructure:"frequency_hz"`
+	TimeoutMS            float64    `mapstructure:"timeout_ms"`
+	MaxLinearMMPerSec    float64    `mapstructure:"max_linear_mm_per_sec"`
+	MaxAngularDegsPerSec float64    `mapstructure:"max_angular_degs_per_sec"`
+	Stop                 bool       `mapstructure:"stop"`
+}
+
+// servoPose is a pose target of a DoServo command, its orientation an orientation vector in degrees.
+type servoPose struct {
+	X     float64 `mapstructure:"x"`
+	Y     float64 `mapstructure:"y"`
+	Z     float64 `mapstructure:"z"`
+	OX    float64 `mapstructure:"o_x"`
+	OY    float64 `mapstructure:"o_y"`
+	OZ    float64 `mapstructure:"o_z"`
+	Theta float64 `mapstructure:"theta"`
+}
+
+func (c *servoCommand) target() (motion.ServoTarget, error) {
+	if c.Pose != nil {
+		if c.Linear != nil || c.Angular != nil {
+			return motion.ServoTarget{}, errors.New("a servo command must have either a twist or a pose, not both")
+		}
+		frame := c.Frame
+		if frame == "" {
+			frame = referenceframe.World
+		}
+		pose := spatialmath.NewPose(
+			r3.Vector{X: c.Pose.X, Y: c.Pose.Y, Z: c.Pose.Z},
+			&spatialmath.OrientationVectorDegrees{OX: c.Pose.OX, OY: c.Pose.OY, OZ: c.Pose.OZ, Theta: c.Pose.Theta},
+		)
+		return motion.ServoTarget{Pose: referenceframe.NewPoseInFrame(frame, pose)}, nil
+	}
+	if c.Linear == nil && c.Angular == nil {
+		return motion.ServoTarget{}, errors.New("a servo command must have either a twist or a pose")
+	}
+	twist := &motion.Twist{}
+	if c.Linear != nil {
+		twist.Linear = *c.Linear
+	}
+	if c.Angular != nil {
+		twist.Angular = *c.Angular
+	}
+	return motion.ServoTarget{Twist: twist}, nil
+}
+
+// servoStream is a servo started by a DoServo command, which subsequent commands for the same component from the same
+// session send targets to.
+type servoStream struct {
+	// owner is the session which started the servo, or uuid.Nil if it was started without one
+	owner   uuid.UUID
+	targets chan motion.ServoTarget
+	cancel  context.CancelFunc
+	done    chan struct{}
+	// err is what the servo returned, set before done is closed
+	err error
+}
+
+// doServo handles a DoServo command.
+func (ms *builtIn) doServo(ctx context.Context, req interface{}) (map[string]interface{}, error) {
+	var cmd servoCommand
+	if err := mapstructure.Decode(req, &cmd); err != nil {
+		return nil, err
+	}
+	if cmd.ComponentName == "" {
+		return nil, errors.New("a servo command must have a component_name")
+	}
+
+	if cmd.Stop {
+		ms.servosMu.Lock()
+		stream, running := ms.servos[cmd.ComponentName]
+		ms.servosMu.Unlock()
+		if running {
+			stream.cancel()
+			<-stream.done
+		}
+		return map[string]interface{}{DoServo: true}, nil
+	}
+	target, err := cmd.target()
+	if err != nil {
+		return nil, err
+	}
+	// the component may be leased by another session, in which case only that session may servo it
+	ms.mu.RLock()
+	component, ok := ms.components[cmd.ComponentName]
+	ms.mu.RUnlock()
+	if ok {
+		if err := session.CheckLease(ctx, component.Name()); err != nil {
+			return nil, err
+		}
+	}
+	var owner uuid.UUID
+	if sess, ok := session.FromContext(ctx); ok {
+		owner = sess.ID()
+	}
+	for {
+		ms.servosMu.Lock()
+		stream, running := ms.servos[cmd.ComponentName]
+		if running && stream.owner != owner {
+			ms.servosMu.Unlock()
+			return nil, errors.Errorf("the servo of %s was started by another session", cmd.ComponentName)
+		}
+		if !running {
+			stream = ms.startServo(owner, motion.ServoReq{
+				ComponentName:        cmd.ComponentName,
+				FrequencyHz:          cmd.FrequencyHz,
+				Timeout:              time.Duration(cmd.TimeoutMS * float64(time.Millisecond)),
+				MaxLinearMMPerSec:    cmd.MaxLinearMMPerSec,
+				MaxAngularDegsPerSec: cmd.MaxAngularDegsPerSec,
+			})
+		}
+		ms.servosMu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case stream.targets <- target:
+			return map[string]interface{}{DoServo: true}, nil
+		case <-stream.done:
+			if running && errors.Is(stream.err, motion.ErrServoStreamLost) {
+				// the servo this target was meant for timed out just as it arrived. The servo is removed from
+				// servos before done is closed, so the next attempt starts a new one with this target.
+				continue
+			}
+			if stream.err == nil {
+				return nil, errors.Errorf("the servo of %s stopped", cmd.ComponentName)
+			}
+			return nil, stream.err
+		}
+	}
+}
+
+// startServo runs a servo for DoServo commands until it stops. servosMu must be held.
+func (ms *builtIn) startServo(owner uuid.UUID, req motion.ServoReq) *servoStream {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &servoStream{
+		owner:   owner,
+		targets: make(chan motion.ServoTarget),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	ms.servos[req.ComponentName] = stream
+	goutils.PanicCapturingGo(func() {
+		defer close(stream.done)
+		defer cancel()
+		err := ms.Servo(ctx, req, stream.targets)
+		ms.servosMu.Lock()
+		if ms.servos[req.ComponentName] == stream {
+			delete(ms.servos, req.ComponentName)
+		}
+		ms.servosMu.Unlock()
+		if err != nil && !errors.Is(err, motion.ErrServoStreamLost) && !errors.Is(err, context.Canceled) {
+			ms.logger.Warnw("servo stopped", "component", req.ComponentName, "error", err)
+		}
+		stream.err = err
+	})
+	return stream
+}
+
+// stopServos stops the servos started by DoServo commands and waits for them to return.
+func (ms *builtIn) stopServos() {
+	ms.servosMu.Lock()
+	streams := make([]*servoStream, 0, len(ms.servos))
+	for _, stream := range ms.servos {
+		streams = append(streams, stream)
+	}
+	ms.servosMu.Unlock()
+	for _, stream := range streams {
+		stream.cancel()
+		<-stream.done
+	}
+}
+
+// servo converts targets for the end effector of a component to joint motions, one small motion per cycle.
+type servo struct {
+	componentName string
+	component     framesystem.InputEnabled
+	frameSystem   *referenceframe.FrameSystem
+	// inputs are the inputs of every frame, those of the servoed component being refreshed each cycle
+	inputs  *referenceframe.LinearInputs
+	limits  []referenceframe.Limit
+	checker *motionplan.ConstraintChecker
+	// actuation holds the latest inputs for the component to go to. It is replaced every cycle, so a GoToInputs which
+	// takes longer than a cycle skips straight to the latest target.
+	actuation chan []referenceframe.Input
+
+	period            time.Duration
+	timeout           time.Duration
+	linearMMPerSec    float64
+	angularRadsPerSec float64
+
+	logger logging.Logger
+}
+
+func (ms *builtIn) newServo(ctx context.Context, req motion.ServoReq) (*servo, error) {
+	if req.FrequencyHz < 0 || req.Timeout < 0 || req.MaxLinearMMPerSec < 0 || req.MaxAngularDegsPerSec < 0 {
+		return nil, errors.New("frequency, timeout and speed limits of a servo request cannot be negative")
+	}
+	r, ok := ms.components[req.ComponentName]
+	if !ok {
+		return nil, fmt.Errorf("the motion service is not aware of a component named %s", req.ComponentName)
+	}
+	component, err := utils.AssertType[framesystem.InputEnabled](r)
+	if err != nil {
+		return nil, err
+	}
+	frameSys, err := ms.getFrameSystem(ctx, req.WorldState.Transforms())
+	if err != nil {
+		return nil, err
+	}
+	frame := frameSys.Frame(req.ComponentName)
+	if frame == nil {
+		return nil, fmt.Errorf("component named %s not found in robot frame system", req.ComponentName)
+	}
+	if len(frame.DoF()) == 0 {
+		return nil, fmt.Errorf("component named %s has no degrees of freedom to servo", req.ComponentName)
+	}
+	fsInputs, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inputs := fsInputs.ToLinearInputs()
+
+	planOpts, err := armplanning.NewPlannerOptionsFromExtra(req.Extra)
+	if err != nil {
+		return nil, err
+	}
+	moving, static, err := servoGeometries(frameSys, inputs, req.ComponentName)
+	if err != nil {
+		return nil, err
+	}
+	checker, err := motionplan.NewConstraintChecker(
+		planOpts.CollisionBufferMM,
+		nil,
+		nil, nil,
+		frameSys,
+		moving, static,
+		inputs,
+		req.WorldState,
+		ms.logger.Sublogger("constraint"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &servo{
+		componentName:     req.ComponentName,
+		component:         component,
+		frameSystem:       frameSys,
+		inputs:            inputs,
+		limits:            frame.DoF(),
+		checker:           checker,
+		actuation:         make(chan []referenceframe.Input, 1),
+		period:            time.Duration(float64(time.Second) / defaultServoFrequencyHz),
+		timeout:           defaultServoTimeout,
+		linearMMPerSec:    defaultServoLinearMMPerSec,
+		angularRadsPerSec: utils.DegToRad(defaultServoAngularDegsPerSec),
+		logger:            ms.logger,
+	}
+	if req.FrequencyHz != 0 {
+		s.period = time.Duration(float64(time.Second) / req.FrequencyHz)
+	}
+	if req.Timeout != 0 {
+		s.timeout = req.Timeout
+	}
+	if req.MaxLinearMMPerSec != 0 {
+		s.linearMMPerSec = req.MaxLinearMMPerSec
+	}
+	if req.MaxAngularDegsPerSec != 0 {
+		s.angularRadsPerSec = utils.DegToRad(req.MaxAngularDegsPerSec)
+	}
+	return s, nil
+}
+
+// servoGeometries splits the geometries of the frame system into those which move with the servoed component and those which
+// do not.
+func servoGeometries(
+	fs *referenceframe.FrameSystem, inputs *referenceframe.LinearInputs, componentName string,
+) (moving, static []spatialmath.Geometry, err error) {
+	geometries, err := referenceframe.FrameSystemGeometriesLinearInputs(fs, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, geometriesInFrame := range geometries {
+		chain, err := fs.TracebackFrame(fs.Frame(name))
+		if err != nil {
+			return nil, nil, err
+		}
+		if slices.ContainsFunc(chain, func(f referenceframe.Frame) bool { return f.Name() == componentName }) {
+			moving = append(moving, geometriesInFrame.Geometries()...)
+		} else {
+			static = append(static, geometriesInFrame.Geometries()...)
+		}
+	}
+	return moving, static, nil
+}
+
+// run follows the stream of targets until it is closed, the context is done, or the stream is lost, stopping the component
+// when it returns.
+func (s *servo) run(ctx context.Context, targets <-chan motion.ServoTarget) (err error) {
+	defer func() {
+		if actuator, ok := s.component.(resource.Actuator); ok {
+			if stopErr := actuator.Stop(context.WithoutCancel(ctx), nil); stopErr != nil {
+				if err == nil {
+					err = stopErr
+				} else {
+					err = errors.Wrap(err, stopErr.Error())
+				}
+			}
+		}
+	}()
+
+	actuationCtx, cancelActuation := context.WithCancel(ctx)
+	actuationErr := make(chan error, 1)
+	var actuating sync.WaitGroup
+	actuating.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer actuating.Done()
+		s.actuate(actuationCtx, actuationErr)
+	})
+	// the component is only stopped once it is no longer being moved
+	defer func() {
+		cancelActuation()
+		actuating.Wait()
+	}()
+
+	ticker := time.NewTicker(s.period)
+	defer ticker.Stop()
+	var twist *motion.Twist
+	var goal spatialmath.Pose
+	lastTarget := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-actuationErr:
+			return err
+		case target, ok := <-targets:
+			if !ok {
+				return nil
+			}
+			if (target.Twist == nil) == (target.Pose == nil) {
+				return errors.New("a servo target must have exactly one of a twist and a pose")
+			}
+			twist, goal = target.Twist, nil
+			if target.Pose != nil {
+				if goal, err = s.toWorld(target.Pose); err != nil {
+					return err
+				}
+			}
+			lastTarget = time.Now()
+			continue
+		case <-ticker.C:
+		}
+
+		if time.Since(lastTarget) > s.timeout {
+			return motion.ErrServoStreamLost
+		}
+		if twist == nil && goal == nil {
+			continue
+		}
+		if err := s.cycle(ctx, twist, goal); err != nil {
+			return err
+		}
+	}
+}
+
+// actuate moves the component to the latest inputs of each cycle until the context is done or moving fails, in which case
+// the error is sent to errCh.
+func (s *servo) actuate(ctx context.Context, errCh chan<- error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-s.actuation:
+			if err := s.component.GoToInputs(ctx, next); err != nil {
+				if ctx.Err() == nil {
+					errCh <- err
+				}
+				return
+			}
+		}
+	}
+}
+
+// cycle sends the motion of the component for one period towards the goal, or at the given twist if there is no goal, to be
+// actuated. Motions which would collide are not made and motions past the joint limits are cut short at them.
+func (s *servo) cycle(ctx context.Context, twist *motion.Twist, goal spatialmath.Pose) error {
+	current, err := s.component.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	s.inputs.Put(s.componentName, current)
+
+	var linear, angular r3.Vector
+	if goal != nil {
+		pose, err := s.toWorld(referenceframe.NewPoseInFrame(s.componentName, spatialmath.NewZeroPose()))
+		if err != nil {
+			return err
+		}
+		linear, angular = poseError(pose, goal)
+		if linear.Norm() < servoPoseToleranceMM && angular.Norm() < utils.DegToRad(servoPoseToleranceDegs) {
+			return nil
+		}
+		linear, angular = linear.Mul(servoPoseGain), angular.Mul(servoPoseGain)
+	} else {
+		linear, angular = twist.Linear, twist.Angular
+	}
+	linear, angular = clampNorm(linear, s.linearMMPerSec), clampNorm(angular, s.angularRadsPerSec)
+
+	jointVelocities, err := s.jointVelocities(linear, angular)
+	if err != nil {
+		return err
+	}
+	dt := s.period.Seconds()
+	// the fraction of the motion which keeps every joint within its limits
+	fraction := 1.
+	for i, v := range jointVelocities {
+		next := current[i] + v*dt
+		switch {
+		case next > s.limits[i].Max && v > 0:
+			fraction = math.Min(fraction, math.Max(0, (s.limits[i].Max-current[i])/(v*dt)))
+		case next < s.limits[i].Min && v < 0:
+			fraction = math.Min(fraction, math.Max(0, (s.limits[i].Min-current[i])/(v*dt)))
+		}
+	}
+	if fraction == 0 {
+		s.logger.CDebugf(ctx, "%s is at a joint limit, not servoing further", s.componentName)
+		return nil
+	}
+	next := make([]referenceframe.Input, len(current))
+	for i, v := range jointVelocities {
+		next[i] = current[i] + fraction*v*dt
+	}
+
+	nextInputs := referenceframe.NewLinearInputs()
+	for name, inputs := range s.inputs.Items() {
+		nextInputs.Put(name, inputs)
+	}
+	nextInputs.Put(s.componentName, next)
+	if _, err := s.checker.CheckStateFSConstraints(ctx, &motionplan.StateFS{Configuration: nextInputs, FS: s.frameSystem}); err != nil {
+		s.logger.CDebugf(ctx, "not servoing %s: %v", s.componentName, err)
+		return nil
+	}
+	// replace the inputs of a cycle which wasn't actuated yet, only this goroutine sends
+	select {
+	case <-s.actuation:
+	default:
+	}
+	s.actuation <- next
+	return nil
+}
+
+// jointVelocities returns the velocities of the joints of the component which move its end effector at the given twist.
+func (s *servo) jointVelocities(linear, angular r3.Vector) ([]float64, error) {
+	jacobian, err := s.frameSystem.Jacobian(s.inputs, s.componentName)
+	if err != nil {
+		return nil, err
+	}
+	// only the columns of the component's own joints are used, the rest of the frame system holds still
+	offset := 0
+	for name, inputs := range s.inputs.Items() {
+		if name == s.componentName {
+			break
+		}
+		offset += len(inputs)
+	}
+	columns := jacobian.Slice(0, 6, offset, offset+len(s.limits)).(*mat.Dense)
+
+	damping := 0.
+	if referenceframe.IsNearSingularity(columns, servoSingularityThreshold) {
+		damping = servoSingularityDamping
+	}
+	return referenceframe.JointVelocities(columns, linear, angular, damping)
+}
+
+// toWorld returns a pose in the world frame at the current inputs.
+func (s *servo) toWorld(pif *referenceframe.PoseInFrame) (spatialmath.Pose, error) {
+	tf, err := s.frameSystem.Transform(s.inputs, pif, referenceframe.World)
+	if err != nil {
+		return nil, err
+	}
+	return tf.(*referenceframe.PoseInFrame).Pose(), nil
+}
+
+// poseError returns the translation and the rotation, as a rotation vector in the world frame, which take from to to.
+func poseError(from, to spatialmath.Pose) (linear, angular r3.Vector) {
+	rotation := spatialmath.QuatToR4AA(quat.Mul(to.Orientation().Quaternion(), quat.Conj(from.Orientation().Quaternion())))
+	theta := rotation.Theta
+	// rotate the short way around
+	if theta > math.Pi {
+		theta -= 2 * math.Pi
+	} else if theta < -math.Pi {
+		theta += 2 * math.Pi
+	}
+	return to.Point().Sub(from.Point()), r3.Vector{X: rotation.RX, Y: rotation.RY, Z: rotation.RZ}.Mul(theta)
+}
+
+// clampNorm scales v down so that its norm is at most limit.
+func clampNorm(v r3.Vector, limit float64) r3.Vector {
+	if norm := v.Norm(); norm > limit {
+		return v.Mul(limit / norm)
+	}
+	return v
+}
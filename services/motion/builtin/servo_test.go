@@ -0,0 +1,263 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	fakearm "go.viam.com/rdk/components/arm/fake"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/session"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func newServoService(t *testing.T) (motion.Servoer, arm.Arm) {
+	t.Helper()
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	a, err := fakearm.NewArm(ctx, nil, resource.Config{
+		Name:                "test-arm",
+		API:                 arm.API,
+		ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	// bent at the elbow and wrist, away from the singularity of the outstretched arm
+	test.That(t, a.MoveToJointPositions(ctx, []referenceframe.Input{0, -1, 1.5, -2, -1.57, 0}, nil), test.ShouldBeNil)
+	model, err := a.Kinematics(ctx)
+	test.That(t, err, test.ShouldBeNil)
+
+	deps := resource.Dependencies{arm.Named("test-arm"): a}
+	fsSvc, err := framesystem.New(ctx, deps, logger)
+	test.That(t, err, test.ShouldBeNil)
+	parts := []*referenceframe.FrameSystemPart{{
+		FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewZeroPose(), "test-arm", nil),
+		ModelFrame:  model,
+	}}
+	test.That(t, fsSvc.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &framesystem.Config{Parts: parts}}), test.ShouldBeNil)
+	deps[fsSvc.Name()] = fsSvc
+
+	ms, err := NewBuiltIn(ctx, deps, resource.Config{ConvertedAttributes: &Config{}}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() { test.That(t, ms.Close(context.Background()), test.ShouldBeNil) })
+	servoer, ok := ms.(motion.Servoer)
+	test.That(t, ok, test.ShouldBeTrue)
+	return servoer, a
+}
+
+// stream sends the target every 10ms for the given duration and then closes the stream.
+func stream(target motion.ServoTarget, duration time.Duration) <-chan motion.ServoTarget {
+	targets := make(chan motion.ServoTarget)
+	go func() {
+		defer close(targets)
+		for start := time.Now(); time.Since(start) < duration; time.Sleep(10 * time.Millisecond) {
+			targets <- target
+		}
+	}()
+	return targets
+}
+
+func TestServo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("follows a twist", func(t *testing.T) {
+		servoer, a := newServoService(t)
+		start, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		twist := motion.ServoTarget{Twist: &motion.Twist{Linear: r3.Vector{Z: 50}}}
+		err = servoer.Servo(ctx, motion.ServoReq{ComponentName: "test-arm"}, stream(twist, 500*time.Millisecond))
+		test.That(t, err, test.ShouldBeNil)
+
+		end, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		moved := end.Point().Sub(start.Point())
+		test.That(t, moved.Z, test.ShouldBeBetween, 10, 30)
+		test.That(t, r3.Vector{X: moved.X, Y: moved.Y}.Norm(), test.ShouldBeLessThan, 1)
+		test.That(t, spatialmath.OrientationAlmostEqualEps(start.Orientation(), end.Orientation(), 1e-3), test.ShouldBeTrue)
+	})
+
+	t.Run("moves to a pose", func(t *testing.T) {
+		servoer, a := newServoService(t)
+		start, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		goal := spatialmath.Compose(spatialmath.NewPoseFromPoint(r3.Vector{X: 20, Y: -10}), start)
+
+		target := motion.ServoTarget{Pose: referenceframe.NewPoseInFrame(referenceframe.World, goal)}
+		err = servoer.Servo(ctx, motion.ServoReq{ComponentName: "test-arm"}, stream(target, 3*time.Second))
+		test.That(t, err, test.ShouldBeNil)
+
+		end, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostCoincidentEps(end, goal, 1), test.ShouldBeTrue)
+	})
+
+	t.Run("does not move into obstacles", func(t *testing.T) {
+		servoer, a := newServoService(t)
+		start, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		box, err := spatialmath.NewBox(
+			spatialmath.NewPoseFromPoint(start.Point().Add(r3.Vector{Z: 150})), r3.Vector{X: 100, Y: 100, Z: 10}, "ceiling",
+		)
+		test.That(t, err, test.ShouldBeNil)
+		worldState, err := referenceframe.NewWorldState(
+			[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{box})}, nil,
+		)
+		test.That(t, err, test.ShouldBeNil)
+
+		twist := motion.ServoTarget{Twist: &motion.Twist{Linear: r3.Vector{Z: 100}}}
+		err = servoer.Servo(ctx, motion.ServoReq{ComponentName: "test-arm", WorldState: worldState}, stream(twist, 2*time.Second))
+		test.That(t, err, test.ShouldBeNil)
+
+		end, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, end.Point().Z, test.ShouldBeGreaterThan, start.Point().Z)
+		test.That(t, end.Point().Z, test.ShouldBeLessThan, start.Point().Z+145)
+	})
+
+	t.Run("stops at joint limits", func(t *testing.T) {
+		servoer, a := newServoService(t)
+		model, err := a.Kinematics(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		limit := model.DoF()[0].Max
+		test.That(t, a.MoveToJointPositions(ctx, []referenceframe.Input{limit - 0.01, -1, 1.5, -2, -1.57, 0}, nil), test.ShouldBeNil)
+		start, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		// the direction the end effector moves in when the base joint turns towards its limit
+		direction := r3.Vector{Z: 1}.Cross(r3.Vector{X: start.Point().X, Y: start.Point().Y}).Normalize()
+		twist := motion.ServoTarget{Twist: &motion.Twist{Linear: direction.Mul(100)}}
+		err = servoer.Servo(ctx, motion.ServoReq{ComponentName: "test-arm"}, stream(twist, 500*time.Millisecond))
+		test.That(t, err, test.ShouldBeNil)
+
+		joints, err := a.JointPositions(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, joints[0], test.ShouldBeGreaterThan, limit-0.01)
+		test.That(t, joints[0], test.ShouldBeLessThanOrEqualTo, limit)
+	})
+
+	t.Run("stops when the stream is lost", func(t *testing.T) {
+		servoer, a := newServoService(t)
+		targets := make(chan motion.ServoTarget, 1)
+		targets <- motion.ServoTarget{Twist: &motion.Twist{Linear: r3.Vector{Z: 50}}}
+
+		begin := time.Now()
+		err := servoer.Servo(ctx, motion.ServoReq{ComponentName: "test-arm", Timeout: 50 * time.Millisecond}, targets)
+		test.That(t, errors.Is(err, motion.ErrServoStreamLost), test.ShouldBeTrue)
+		test.That(t, time.Since(begin), test.ShouldBeLessThan, time.Second)
+
+		// the arm does not keep moving once the stream is lost
+		stopped, err := a.JointPositions(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		time.Sleep(100 * time.Millisecond)
+		joints, err := a.JointPositions(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, joints, test.ShouldResemble, stopped)
+	})
+
+	t.Run("follows DoCommand targets", func(t *testing.T) {
+		servoer, a := newServoService(t)
+		svc := servoer.(motion.Service)
+		start, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+
+		cmd := map[string]interface{}{DoServo: map[string]interface{}{
+			"component_name": "test-arm",
+			"linear":         map[string]interface{}{"x": 0., "y": 0., "z": 50.},
+		}}
+		for begin := time.Now(); time.Since(begin) < 500*time.Millisecond; time.Sleep(10 * time.Millisecond) {
+			resp, err := svc.DoCommand(ctx, cmd)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, resp[DoServo], test.ShouldBeTrue)
+		}
+		_, err = svc.DoCommand(ctx, map[string]interface{}{DoServo: map[string]interface{}{"component_name": "test-arm", "stop": true}})
+		test.That(t, err, test.ShouldBeNil)
+
+		end, err := a.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		moved := end.Point().Sub(start.Point())
+		test.That(t, moved.Z, test.ShouldBeBetween, 10, 30)
+		test.That(t, r3.Vector{X: moved.X, Y: moved.Y}.Norm(), test.ShouldBeLessThan, 1)
+
+		_, err = svc.DoCommand(ctx, map[string]interface{}{DoServo: map[string]interface{}{"component_name": "test-arm"}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "either a twist or a pose")
+	})
+
+	t.Run("starts a new servo for DoCommand targets arriving as the stream times out", func(t *testing.T) {
+		servoer, _ := newServoService(t)
+		svc := servoer.(motion.Service)
+
+		cmd := map[string]interface{}{DoServo: map[string]interface{}{
+			"component_name": "test-arm",
+			"linear":         map[string]interface{}{"x": 0., "y": 0., "z": 10.},
+			"timeout_ms":     50.,
+		}}
+		// targets arrive just before, at and just after the timeout, none of them may be lost with the old servo
+		for i := 0; i < 30; i++ {
+			resp, err := svc.DoCommand(ctx, cmd)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, resp[DoServo], test.ShouldBeTrue)
+			time.Sleep(time.Duration(45+i%3*5) * time.Millisecond)
+		}
+		_, err := svc.DoCommand(ctx, map[string]interface{}{DoServo: map[string]interface{}{"component_name": "test-arm", "stop": true}})
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("only servos for the session which started the servo or leased the component", func(t *testing.T) {
+		servoer, _ := newServoService(t)
+		svc := servoer.(motion.Service)
+		fooCtx := session.ToContext(ctx, session.New(ctx, "", time.Minute, nil))
+		barSess := session.New(ctx, "", time.Minute, nil)
+		barCtx := session.ToContext(ctx, barSess)
+
+		cmd := map[string]interface{}{DoServo: map[string]interface{}{
+			"component_name": "test-arm",
+			"linear":         map[string]interface{}{"x": 0., "y": 0., "z": 10.},
+			"timeout_ms":     1000.,
+		}}
+		_, err := svc.DoCommand(fooCtx, cmd)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = svc.DoCommand(barCtx, cmd)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "started by another session")
+		// any session may stop the servo
+		_, err = svc.DoCommand(barCtx, map[string]interface{}{DoServo: map[string]interface{}{"component_name": "test-arm", "stop": true}})
+		test.That(t, err, test.ShouldBeNil)
+
+		leasedErr := session.NewResourceLeasedError(arm.Named("test-arm"), barSess.ID())
+		fooCtx = session.WithLeaseCheck(fooCtx, func(resourceName resource.Name) error {
+			if resourceName == arm.Named("test-arm") {
+				return leasedErr
+			}
+			return nil
+		})
+		_, err = svc.DoCommand(fooCtx, cmd)
+		test.That(t, err, test.ShouldBeError, leasedErr)
+		_, err = svc.DoCommand(barCtx, cmd)
+		test.That(t, err, test.ShouldBeNil)
+		_, err = svc.DoCommand(barCtx, map[string]interface{}{DoServo: map[string]interface{}{"component_name": "test-arm", "stop": true}})
+		test.That(t, err, test.ShouldBeNil)
+	})
+
+	t.Run("rejects invalid targets", func(t *testing.T) {
+		servoer, _ := newServoService(t)
+		targets := make(chan motion.ServoTarget, 1)
+		targets <- motion.ServoTarget{}
+		err := servoer.Servo(ctx, motion.ServoReq{ComponentName: "test-arm"}, targets)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "exactly one of a twist and a pose")
+
+		err = servoer.Servo(ctx, motion.ServoReq{ComponentName: "other-arm"}, targets)
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
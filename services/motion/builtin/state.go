@@ -0,0 +1,287 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// planHistoryTTL is how long plans which are no longer in progress are reported by ListPlanStatuses and PlanHistory.
+const planHistoryTTL = 24 * time.Hour
+
+// planRecord is a plan of an execution along with its status history, oldest status first.
+type planRecord struct {
+	plan     motion.PlanWithMetadata
+	statuses []motion.PlanStatus
+}
+
+func (p *planRecord) status() motion.PlanStatus {
+	return p.statuses[len(p.statuses)-1]
+}
+
+func (p *planRecord) setStatus(state motion.PlanState, reason *string) {
+	p.statuses = append(p.statuses, motion.PlanStatus{State: state, Timestamp: time.Now(), Reason: reason})
+}
+
+// execution is a request to move a component which runs in the background. Replanning adds a plan to the execution, the last
+// plan being the one which is executing or which the execution ended on.
+type execution struct {
+	id            motion.ExecutionID
+	componentName string
+	anchor        *spatialmath.GeoPose
+	plans         []*planRecord
+	cancel        context.CancelFunc
+	done          chan struct{}
+
+	// mu is the mutex of the executionState the execution belongs to.
+	mu *sync.Mutex
+}
+
+func (ex *execution) current() *planRecord {
+	return ex.plans[len(ex.plans)-1]
+}
+
+func (ex *execution) active() bool {
+	return ex.current().status().State == motion.PlanStateInProgress
+}
+
+func (ex *execution) addPlan(plan motionplan.Plan) {
+	record := &planRecord{plan: motion.PlanWithMetadata{
+		ID:            uuid.New(),
+		ComponentName: ex.componentName,
+		ExecutionID:   ex.id,
+		Plan:          plan,
+		AnchorGeoPose: ex.anchor,
+	}}
+	record.setStatus(motion.PlanStateInProgress, nil)
+	ex.plans = append(ex.plans, record)
+}
+
+// replan marks the plan being executed as failed for the given reason and starts executing the new plan.
+func (ex *execution) replan(plan motionplan.Plan, reason string) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if !ex.active() {
+		return
+	}
+	ex.current().setStatus(motion.PlanStateFailed, &reason)
+	ex.addPlan(plan)
+}
+
+// finish sets the terminal state of the plan being executed from the error the execution ended with.
+func (ex *execution) finish(ctx context.Context, err error) {
+	ex.mu.Lock()
+	defer ex.mu.Unlock()
+	if !ex.active() {
+		return
+	}
+	switch {
+	case err == nil:
+		ex.current().setStatus(motion.PlanStateSucceeded, nil)
+	case ctx.Err() != nil && errors.Is(err, context.Canceled):
+		ex.current().setStatus(motion.PlanStateStopped, nil)
+	default:
+		reason := err.Error()
+		ex.current().setStatus(motion.PlanStateFailed, &reason)
+	}
+}
+
+// lastUpdated returns when the status of the execution last changed.
+func (ex *execution) lastUpdated() time.Time {
+	return ex.current().status().Timestamp
+}
+
+// executionState keeps track of the executions started by MoveOnMap and MoveOnGlobe. Each component has at most one execution
+// in progress.
+type executionState struct {
+	mu sync.Mutex
+	// executions holds the executions of each component, oldest first
+	executions map[string][]*execution
+	workers    sync.WaitGroup
+}
+
+func newExecutionState() *executionState {
+	return &executionState{executions: map[string][]*execution{}}
+}
+
+// start runs the given function in the background as a new execution of the given plan, stopping the execution already in
+// progress for the component if there is one. The execution succeeds if run returns nil and fails otherwise.
+func (s *executionState) start(
+	ctx context.Context,
+	componentName string,
+	plan motionplan.Plan,
+	anchor *spatialmath.GeoPose,
+	run func(context.Context, *execution) error,
+) (motion.ExecutionID, error) {
+	if err := s.stopActive(componentName); err != nil {
+		return uuid.Nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+	if ex := s.activeExecution(componentName); ex != nil {
+		return uuid.Nil, fmt.Errorf("component %q started another execution %s", componentName, ex.id)
+	}
+
+	// The execution outlives the request which started it, so only keep the values of its context.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	ex := &execution{
+		id:            uuid.New(),
+		componentName: componentName,
+		anchor:        anchor,
+		cancel:        cancel,
+		done:          make(chan struct{}),
+		mu:            &s.mu,
+	}
+	ex.addPlan(plan)
+	s.executions[componentName] = append(s.executions[componentName], ex)
+
+	s.workers.Add(1)
+	goutils.PanicCapturingGo(func() {
+		defer s.workers.Done()
+		defer close(ex.done)
+		defer cancel()
+		ex.finish(ctx, run(ctx, ex))
+	})
+	return ex.id, nil
+}
+
+var errNoPlanInProgress = errors.New("no plan in progress")
+
+// stop stops the execution in progress for the component and waits for it to end.
+func (s *executionState) stop(componentName string) error {
+	s.mu.Lock()
+	ex := s.activeExecution(componentName)
+	if ex == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("component %q has %w", componentName, errNoPlanInProgress)
+	}
+	ex.current().setStatus(motion.PlanStateStopped, nil)
+	ex.cancel()
+	s.mu.Unlock()
+
+	<-ex.done
+	return nil
+}
+
+// stopActive stops the execution in progress for the component, if there is one, and waits for it to end.
+func (s *executionState) stopActive(componentName string) error {
+	if err := s.stop(componentName); err != nil && !errors.Is(err, errNoPlanInProgress) {
+		return err
+	}
+	return nil
+}
+
+// stopAll stops every execution in progress and waits for them to end.
+func (s *executionState) stopAll() {
+	s.mu.Lock()
+	for _, executions := range s.executions {
+		for _, ex := range executions {
+			if ex.active() {
+				ex.current().setStatus(motion.PlanStateStopped, nil)
+				ex.cancel()
+			}
+		}
+	}
+	s.mu.Unlock()
+	s.workers.Wait()
+}
+
+func (s *executionState) activeExecution(componentName string) *execution {
+	executions := s.executions[componentName]
+	if len(executions) == 0 || !executions[len(executions)-1].active() {
+		return nil
+	}
+	return executions[len(executions)-1]
+}
+
+// prune forgets the executions which ended more than planHistoryTTL ago.
+func (s *executionState) prune() {
+	cutoff := time.Now().Add(-planHistoryTTL)
+	for name, executions := range s.executions {
+		executions = slices.DeleteFunc(executions, func(ex *execution) bool {
+			return !ex.active() && ex.lastUpdated().Before(cutoff)
+		})
+		if len(executions) == 0 {
+			delete(s.executions, name)
+		} else {
+			s.executions[name] = executions
+		}
+	}
+}
+
+// listPlanStatuses returns the current status of every plan, or of only the plans in progress, ordered by when they started.
+func (s *executionState) listPlanStatuses(onlyActivePlans bool) []motion.PlanStatusWithID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+
+	var records []*planRecord
+	for _, executions := range s.executions {
+		for _, ex := range executions {
+			for _, record := range ex.plans {
+				if onlyActivePlans && record.status().State != motion.PlanStateInProgress {
+					continue
+				}
+				records = append(records, record)
+			}
+		}
+	}
+	slices.SortFunc(records, func(a, b *planRecord) int {
+		return a.statuses[0].Timestamp.Compare(b.statuses[0].Timestamp)
+	})
+
+	statuses := make([]motion.PlanStatusWithID, 0, len(records))
+	for _, record := range records {
+		statuses = append(statuses, motion.PlanStatusWithID{
+			PlanID:        record.plan.ID,
+			ComponentName: record.plan.ComponentName,
+			ExecutionID:   record.plan.ExecutionID,
+			Status:        record.status(),
+		})
+	}
+	return statuses
+}
+
+// planHistory returns the plans of the requested execution of a component, the most recent one by default. Both the plans and
+// their status histories are returned newest first.
+func (s *executionState) planHistory(req motion.PlanHistoryReq) ([]motion.PlanWithStatus, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prune()
+
+	executions := s.executions[req.ComponentName]
+	if len(executions) == 0 {
+		return nil, fmt.Errorf("no plan history for component %q", req.ComponentName)
+	}
+	ex := executions[len(executions)-1]
+	if req.ExecutionID != uuid.Nil {
+		idx := slices.IndexFunc(executions, func(ex *execution) bool { return ex.id == req.ExecutionID })
+		if idx < 0 {
+			return nil, fmt.Errorf("no execution %s for component %q", req.ExecutionID, req.ComponentName)
+		}
+		ex = executions[idx]
+	}
+
+	history := make([]motion.PlanWithStatus, 0, len(ex.plans))
+	for i := len(ex.plans) - 1; i >= 0; i-- {
+		statuses := slices.Clone(ex.plans[i].statuses)
+		slices.Reverse(statuses)
+		history = append(history, motion.PlanWithStatus{Plan: ex.plans[i].plan, StatusHistory: statuses})
+		if req.LastPlanOnly {
+			break
+		}
+	}
+	return history, nil
+}
@@ -0,0 +1,73 @@
+package motion
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+)
+
+// ErrServoStreamLost is returned by Servo when no target arrives within the timeout of the request. The component is stopped
+// before it is returned.
+var ErrServoStreamLost = errors.New("servo stream lost: no target received before the timeout")
+
+// Twist is a velocity of an end effector, expressed in the world frame.
+type Twist struct {
+	// Linear velocity in mm/s
+	Linear r3.Vector
+	// Angular velocity in rad/s, about the axes of the world frame
+	Angular r3.Vector
+}
+
+// ServoTarget is one element of the stream of targets given to Servo. Exactly one of Twist and Pose must be set.
+type ServoTarget struct {
+	// Twist is the velocity the end effector should move at until the next target arrives.
+	Twist *Twist
+	// Pose is where the end effector should move to, as fast as the speed limits of the request allow.
+	Pose *referenceframe.PoseInFrame
+}
+
+// ServoReq describes how Servo should move a component.
+type ServoReq struct {
+	// ComponentName of the component whose end effector is servoed. It must be able to go to inputs, like an arm.
+	ComponentName string
+	// WorldState holds obstacles which are checked for collisions every cycle.
+	WorldState *referenceframe.WorldState
+	// FrequencyHz is how many times a second targets are converted to joint motions, 50 if unset.
+	FrequencyHz float64
+	// Timeout is how long the component keeps following the last target before the stream is considered lost, 0.1s if unset.
+	Timeout time.Duration
+	// MaxLinearMMPerSec and MaxAngularDegsPerSec limit the speed of the end effector, 100mm/s and 30°/s if unset.
+	MaxLinearMMPerSec    float64
+	MaxAngularDegsPerSec float64
+	Extra                map[string]interface{}
+}
+
+// Servoer is implemented by motion services which can servo a component in real time. Rather than planning a whole motion
+// before executing it, each cycle the latest target is converted to a small joint motion which is checked against the joint
+// limits of the component and for collisions before it is sent.
+//
+// Servo example:
+//
+//	targets := make(chan motion.ServoTarget)
+//	go func() {
+//		defer close(targets)
+//		for range time.Tick(20 * time.Millisecond) {
+//			// move the end effector up at 10mm/s
+//			targets <- motion.ServoTarget{Twist: &motion.Twist{Linear: r3.Vector{Z: 10}}}
+//		}
+//	}()
+//	servoer, ok := motionService.(motion.Servoer)
+//	if ok {
+//		err := servoer.Servo(context.Background(), motion.ServoReq{ComponentName: "my_arm"}, targets)
+//	}
+type Servoer interface {
+	// Servo moves the end effector of a component following a stream of targets until the stream is closed, the context is
+	// done, or no target arrives within the timeout of the request, in which case ErrServoStreamLost is returned. Motions which
+	// would take the component past its joint limits are cut short at them and motions which would collide are not made. The
+	// component is stopped whenever Servo returns.
+	Servo(ctx context.Context, req ServoReq, targets <-chan ServoTarget) error
+}
@@ -3,6 +3,7 @@ package session
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"go.viam.com/utils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -20,6 +21,11 @@ var (
 	ErrNoSession = StatusNoSession.Err()
 )
 
+// NewResourceLeasedError returns an error for a resource which is leased by another session.
+func NewResourceLeasedError(resourceName resource.Name, holder uuid.UUID) error {
+	return status.Errorf(codes.FailedPrecondition, "resource %q is leased by session %s", resourceName, holder)
+}
+
 const (
 	// IDMetadataKey is the gRPC metadata key to use when transmitting session information.
 	IDMetadataKey = "viam-sid"
@@ -27,11 +33,22 @@ const (
 	// SafetyMonitoredResourceMetadataKey is the gRPC metadata key to use when transmitting
 	// safety monitored resource names in a response.
 	SafetyMonitoredResourceMetadataKey = "viam-smrn"
+
+	// LeaseMetadataKey is the gRPC metadata key to use when transmitting the names of resources
+	// to lease to a session in a request to start a session or send a heartbeat.
+	LeaseMetadataKey = "viam-lease"
+
+	// ReleaseLeaseMetadataKey is the gRPC metadata key to use when transmitting the names of
+	// resources whose leases a session releases in a request to send a heartbeat.
+	ReleaseLeaseMetadataKey = "viam-lease-release"
 )
 
 type ctxKey int
 
-const ctxKeySessionID = ctxKey(iota)
+const (
+	ctxKeySessionID = ctxKey(iota)
+	ctxKeyLeaseCheck
+)
 
 // ToContext attaches a session to the given context.
 func ToContext(ctx context.Context, sess *Session) context.Context {
@@ -47,6 +64,24 @@ func FromContext(ctx context.Context) (*Session, bool) {
 	return sess, true
 }
 
+// WithLeaseCheck attaches a check of whether the caller of a request may actuate a resource to the given context.
+func WithLeaseCheck(ctx context.Context, check func(resourceName resource.Name) error) context.Context {
+	return context.WithValue(ctx, ctxKeyLeaseCheck, check)
+}
+
+// CheckLease returns an error if the resource is leased by a session other than the one of the caller of the
+// current request. The builtin motion service calls this before servoing a component on behalf of a request
+// that started earlier; other actuating calls are only checked by the session interceptors when they arrive.
+// It returns nil if the context has no lease check, such as outside of a gRPC request or while no resource is
+// leased.
+func CheckLease(ctx context.Context, resourceName resource.Name) error {
+	check, ok := ctx.Value(ctxKeyLeaseCheck).(func(resourceName resource.Name) error)
+	if !ok {
+		return nil
+	}
+	return check(resourceName)
+}
+
 // SafetyMonitor signals to the session, if present, that the given target should be
 // safety monitored so that if the session ends and this session was the last
 // to monitor the target, it will attempt to be stopped.
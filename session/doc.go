@@ -138,6 +138,25 @@ response.
 Starting points:
   - [golang Server]
 
+# Resource Leases
+
+Safety monitoring does not prevent two clients from commanding the same resource at once. A client can opt in to
+exclusive control of a resource by leasing it to its session: the names of the resources to lease are sent in the
+"viam-lease" gRPC metadata of a StartSession or SendSessionHeartbeat request, and the names of the leases to give
+up in its "viam-lease-release" metadata. A lease lasts until the session expires or the lease is released. While a
+resource is leased, the session interceptor rejects calls which actuate it (such as Move, SetPower or Open) from
+anywhere but the holding session with a gRPC error with code "FailedPrecondition", including calls without a
+session. Calls to services are checked against the component named in their "component_name" field, so a motion
+Move of a leased arm is rejected the same way. A DoCommand is treated as actuating the resource it is sent to and
+any component named by a "component_name" anywhere in its command. Stop is never rejected, so that any client can
+stop a leased resource, and neither are calls which only read state. Requests leasing a resource held by another
+session fail the same way. The go robot client leases and releases resources for its session with AcquireLeases and
+ReleaseLeases. When the web server is started with lease admin entities, a request authenticated as one of them
+can list the leases with a GET request to the "/admin/leases" HTTP endpoint of the robot and revoke one with a POST
+request to "/admin/leases/revoke?resource=<name>", after which the session it was revoked from cannot lease that
+resource again. Leases are only supported by session managers implementing
+LeaseManager; lease requests to other robots fail with code "Unimplemented".
+
 # Remote Robot Considerations
 
 When connecting to a remote robot, the underlying client will maintain its own, single, session that is
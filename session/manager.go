@@ -21,6 +21,26 @@ type Manager interface {
 	ServerInterceptors() ServerInterceptors
 }
 
+// A LeaseManager is a Manager which can lease resources to sessions.
+type LeaseManager interface {
+	Manager
+
+	// StartWithLeases creates a new session which holds leases on the given resources. If any of
+	// them is leased by another session, no session is started.
+	StartWithLeases(ctx context.Context, ownerID string, resourceNames []resource.Name) (*Session, error)
+	// AcquireLease gives the session exclusive control of the resource until the session ends,
+	// the lease is released or it is revoked. Calls which change the resource from anywhere but
+	// the session are rejected in the meantime.
+	AcquireLease(id uuid.UUID, resourceName resource.Name) error
+	// ReleaseLease releases the lease of the session on the resource, if it has one.
+	ReleaseLease(id uuid.UUID, resourceName resource.Name)
+	// RevokeLease takes the lease on the resource away from the session holding it, which
+	// can't acquire it again. It returns whether the resource was leased.
+	RevokeLease(resourceName resource.Name) bool
+	// Leases returns the session holding each leased resource.
+	Leases() map[resource.Name]uuid.UUID
+}
+
 // ServerInterceptors provide gRPC interceptors to work with sessions.
 type ServerInterceptors struct {
 	UnaryServerInterceptor func(
@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -39,6 +40,7 @@ import (
 type Arguments struct {
 	AllowInsecureCreds         bool   `flag:"allow-insecure-creds,usage=allow connections to send credentials over plaintext"`
 	ConfigFile                 string `flag:"config,usage=machine configuration file"`
+	ConfigOverlayFile          string `flag:"config-overlay,usage=local file of changes applied on top of the machine configuration"`
 	CPUProfile                 string `flag:"cpuprofile,usage=write cpu profile to file"`
 	Debug                      bool   `flag:"debug"`
 	SharedDir                  string `flag:"shareddir,usage=web resource directory"`
@@ -51,6 +53,11 @@ type Arguments struct {
 	DisableMulticastDNS        bool   `flag:"disable-mdns,usage=disable server discovery through multicast DNS"`
 	DumpResourcesPath          string `flag:"dump-resources,usage=dump all resource registrations as json to the provided file path"`
 	EnableFTDC                 bool   `flag:"ftdc,default=true,usage=enable fulltime data capture for diagnostics"`
+	OperationAuditLog          bool   `flag:"operation-audit-log,usage=log every completed operation"`
+	OperationHistory           bool   `flag:"operation-history,usage=serve recently completed operations in http server"`
+	FTDCStream                 bool   `flag:"ftdc-stream,usage=serve live fulltime data capture diagnostics in http server"`
+	JobResults                 bool   `flag:"job-results,usage=serve recent job results in http server"`
+	LeaseAdminEntities         string `flag:"lease-admin-entities,usage=comma-separated entities allowed to manage leases in http server"`
 	OutputLogFile              string `flag:"log-file,usage=write logs to a file with log rotation"`
 	NoTLS                      bool   `flag:"no-tls,usage=starts an insecure http server without TLS certificates even if one exists"`
 	NetworkCheckOnly           bool   `flag:"network-check,usage=only runs normal network checks, logs results, and exits"`
@@ -314,6 +321,7 @@ func (s *robotServer) runServer(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	cfg.OverlayFilePath = s.args.ConfigOverlayFile
 	config.UpdateFileConfigDebug(cfg.Debug)
 
 	err = s.serveWeb(ctx, cfg)
@@ -330,6 +338,12 @@ func (s *robotServer) createWebOptions(cfg *config.Config) (weboptions.Options,
 		return weboptions.Options{}, err
 	}
 	options.Pprof = s.args.WebProfile || cfg.EnableWebProfile
+	options.FTDCStream = s.args.FTDCStream
+	options.OperationHistory = s.args.OperationHistory
+	options.JobResults = s.args.JobResults
+	if s.args.LeaseAdminEntities != "" {
+		options.LeaseAdminEntities = strings.Split(s.args.LeaseAdminEntities, ",")
+	}
 	options.SharedDir = s.args.SharedDir
 	options.Debug = s.args.Debug || cfg.Debug
 	options.PreferWebRTC = s.args.WebRTC
@@ -557,7 +571,19 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 		<-slowWatcher
 	}()
 	s.configLogger.CInfo(ctx, "Processing initial robot config...")
-	fullProcessedConfig, err := s.processConfig(cfg)
+	// the watcher applies the overlay to the configs it delivers, this is for the initial config
+	overlaidConfig := cfg
+	if cfg.OverlayFilePath != "" {
+		unprocessed, err := config.ReadUnprocessed(cfg)
+		if err == nil {
+			overlaidConfig, err = config.ApplyOverlayFile(cfg, unprocessed, s.configLogger)
+		}
+		if err != nil {
+			s.configLogger.CErrorw(ctx, "error applying config overlay, using the config without it", "error", err)
+			overlaidConfig = cfg
+		}
+	}
+	fullProcessedConfig, err := s.processConfig(overlaidConfig)
 	if err != nil {
 		return err
 	}
@@ -612,6 +638,10 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 		robotOptions = append(robotOptions, robotimpl.WithFTDC())
 	}
 
+	if s.args.OperationAuditLog {
+		robotOptions = append(robotOptions, robotimpl.WithOperationAuditLog())
+	}
+
 	// Create `minimalProcessedConfig`, a copy of `fullProcessedConfig`. Remove
 	// all components, services, remotes, modules, processes, packages, and jobs from
 	// `minimalProcessedConfig`. Create new robot with `minimalProcessedConfig`